@@ -2,11 +2,18 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/gophpeek/phpeek-pm/internal/autotune"
 	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/deps"
+	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var checkConfigCmd = &cobra.Command{
@@ -20,18 +27,77 @@ func init() {
 	checkConfigCmd.Flags().Bool("strict", false, "Fail on warnings (not just errors)")
 	checkConfigCmd.Flags().Bool("json", false, "Output validation results as JSON")
 	checkConfigCmd.Flags().Bool("quiet", false, "Show only summary (no detailed report)")
+	checkConfigCmd.Flags().StringArray("redact-sample", nil, "Show how a sample log line looks after each process's redaction (repeatable)")
+	checkConfigCmd.Flags().Bool("origins", false, "Show which file (or env override) set each configuration value")
+}
+
+// validateLoggingConfigs builds the log pipeline of every process so unknown
+// presets and invalid patterns are reported before the daemon starts
+func validateLoggingConfigs(cfg *config.Config) error {
+	discard := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for name, proc := range cfg.Processes {
+		if proc.Logging == nil {
+			continue
+		}
+		if _, err := logger.NewProcessWriter(discard, name, name+"-0", "stdout", proc.Logging); err != nil {
+			return fmt.Errorf("process %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// redactionSample is a sample log line run through a process's redactor
+type redactionSample struct {
+	Process string `json:"process"`
+	Input   string `json:"input"`
+	Output  string `json:"output"`
+}
+
+// buildRedactionSamples compiles the redaction rules of every process with
+// redaction enabled and applies them to the sample lines.
+func buildRedactionSamples(cfg *config.Config, samples []string) ([]redactionSample, error) {
+	names := make([]string, 0, len(cfg.Processes))
+	for name := range cfg.Processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []redactionSample
+	for _, name := range names {
+		proc := cfg.Processes[name]
+		if proc.Logging == nil || proc.Logging.Redaction == nil || !proc.Logging.Redaction.Enabled {
+			continue
+		}
+
+		redactor, err := logger.NewRedactor(proc.Logging.Redaction)
+		if err != nil {
+			return nil, fmt.Errorf("process %s: %w", name, err)
+		}
+
+		for _, sample := range samples {
+			results = append(results, redactionSample{
+				Process: name,
+				Input:   sample,
+				Output:  redactor.Redact(sample),
+			})
+		}
+	}
+
+	return results, nil
 }
 
 func runCheckConfig(cmd *cobra.Command, args []string) {
 	strict, _ := cmd.Flags().GetBool("strict")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	quiet, _ := cmd.Flags().GetBool("quiet")
+	samples, _ := cmd.Flags().GetStringArray("redact-sample")
+	showOrigins, _ := cmd.Flags().GetBool("origins")
 
 	// Get config path from persistent flag or default
 	cfgPath := getConfigPath()
 
 	// Load configuration
-	cfg, err := config.LoadWithEnvExpansion(cfgPath)
+	cfg, err := loadConfig(cfgPath)
 	if err != nil {
 		if jsonOutput {
 			fmt.Fprintf(os.Stderr, `{"error":"Configuration load failed: %v"}`+"\n", err)
@@ -51,18 +117,38 @@ func runCheckConfig(cmd *cobra.Command, args []string) {
 		if jsonOutput {
 			jsonData := config.FormatValidationJSON(result)
 			jsonData["config_path"] = cfgPath
-			fmt.Println(formatJSONOutput(jsonData))
+			fmt.Println(cfg.Secrets().Mask(formatJSONOutput(jsonData)))
 		} else if quiet {
 			fmt.Printf("❌ %s\n", config.FormatValidationSummary(result))
 		} else {
-			fmt.Print(config.FormatValidationReport(result))
+			fmt.Print(cfg.Secrets().Mask(config.FormatValidationReport(result)))
+		}
+		os.Exit(1)
+	}
+
+	// Reject job chains that would restart themselves forever
+	if _, err := deps.NewTriggerGraphFromConfig(cfg.Processes); err != nil {
+		if jsonOutput {
+			fmt.Fprintf(os.Stderr, `{"error":"Invalid job triggers: %v"}`+"\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "❌ Invalid job triggers: %v\n", err)
+		}
+		os.Exit(1)
+	}
+
+	// Resolve custom auto-tuning profiles so they can be selected below
+	if err := autotune.LoadCustomProfiles(cfg.Global.AutotuneProfiles()); err != nil {
+		if jsonOutput {
+			fmt.Fprintf(os.Stderr, `{"error":"Invalid autotune profile: %v"}`+"\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "❌ Invalid autotune profile: %v\n", err)
 		}
 		os.Exit(1)
 	}
 
 	// Check for auto-tuning profile if specified
 	autotuneProfile := os.Getenv("PHP_FPM_AUTOTUNE_PROFILE")
-	if cfg.Global.AutotuneMemoryThreshold > 0 && autotuneProfile != "" {
+	if autotuneProfile != "" {
 		profile := autotune.Profile(autotuneProfile)
 		if err := profile.Validate(); err != nil {
 			if jsonOutput {
@@ -74,6 +160,38 @@ func runCheckConfig(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Compile log pipelines (multiline, redaction, filters) and run sample lines through them
+	if err := validateLoggingConfigs(cfg); err != nil {
+		if jsonOutput {
+			fmt.Fprintf(os.Stderr, `{"error":"Invalid logging config: %v"}`+"\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "❌ Invalid logging config: %v\n", err)
+		}
+		os.Exit(1)
+	}
+	redacted, err := buildRedactionSamples(cfg, samples)
+	if err != nil {
+		if jsonOutput {
+			fmt.Fprintf(os.Stderr, `{"error":"Invalid redaction config: %v"}`+"\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "❌ Invalid redaction config: %v\n", err)
+		}
+		os.Exit(1)
+	}
+
+	learning, err := buildLearningReport(cfg, autotuneProfile)
+	if err != nil && !jsonOutput {
+		fmt.Fprintf(os.Stderr, "⚠️  Autotune learning: %v\n", err)
+	}
+
+	var plan *autotune.MemoryPlan
+	if len(autotune.ProcessConsumers(cfg)) > 0 {
+		plan, err = buildMemoryPlan(cfg, autotuneProfile, 0)
+		if err != nil && !jsonOutput {
+			fmt.Fprintf(os.Stderr, "⚠️  Memory plan: %v\n", err)
+		}
+	}
+
 	// Output results based on format
 	if jsonOutput {
 		// JSON output
@@ -81,10 +199,33 @@ func runCheckConfig(cmd *cobra.Command, args []string) {
 		jsonData["config_path"] = cfgPath
 		jsonData["version"] = cfg.Version
 		jsonData["process_count"] = len(cfg.Processes)
+		if cfg.Sources != nil {
+			sources := *cfg.Sources
+			if !showOrigins {
+				sources.Origins = nil
+			}
+			jsonData["sources"] = sources
+			if sources.Profile != "" {
+				jsonData["profile"] = sources.Profile
+			}
+		}
+		if refs := cfg.Secrets().Refs(); len(refs) > 0 {
+			jsonData["secrets"] = refs
+		}
 		if autotuneProfile != "" {
 			jsonData["php_fpm_profile"] = autotuneProfile
 		}
-		fmt.Println(formatJSONOutput(jsonData))
+		if len(samples) > 0 {
+			jsonData["redaction_samples"] = redacted
+		}
+		if learning != nil {
+			jsonData["autotune_learning"] = learning
+		}
+		if plan != nil {
+			jsonData["memory_plan"] = plan
+		}
+		// Resolved secrets never leave the process unmasked
+		fmt.Println(cfg.Secrets().Mask(formatJSONOutput(jsonData)))
 	} else if quiet {
 		// Quiet mode - just summary
 		if result.TotalIssues() == 0 {
@@ -95,7 +236,7 @@ func runCheckConfig(cmd *cobra.Command, args []string) {
 	} else {
 		// Full report mode
 		if result.TotalIssues() > 0 {
-			fmt.Print(config.FormatValidationReport(result))
+			fmt.Print(cfg.Secrets().Mask(config.FormatValidationReport(result)))
 		}
 
 		// Print configuration summary
@@ -105,11 +246,33 @@ func runCheckConfig(cmd *cobra.Command, args []string) {
 		fmt.Printf("   Processes: %d\n", len(cfg.Processes))
 		fmt.Printf("   Log Level: %s\n", cfg.Global.LogLevel)
 		fmt.Printf("   Shutdown Timeout: %ds\n", cfg.Global.ShutdownTimeout)
+		printConfigSources(os.Stdout, cfg.Sources, showOrigins)
+		printSecretRefs(os.Stdout, cfg.Secrets())
+
+		if cfg.Sources != nil && cfg.Sources.Profile != "" {
+			fmt.Printf("\n🧩 Effective Configuration (profile %s):\n", cfg.Sources.Profile)
+			if err := printEffectiveConfig(os.Stdout, cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Failed to render effective configuration: %v\n", err)
+			}
+		}
 
 		if autotuneProfile != "" {
 			fmt.Printf("   PHP-FPM Profile: %s (auto-tuned)\n", autotuneProfile)
 		}
 
+		if plan != nil {
+			fmt.Printf("\n📐 Memory Plan:\n")
+			printMemoryPlan(os.Stdout, plan)
+		}
+
+		if learning != nil {
+			printLearningReport(learning)
+		}
+
+		if len(samples) > 0 {
+			printRedactionSamples(redacted)
+		}
+
 		if result.TotalIssues() == 0 {
 			fmt.Println("\n✅ Configuration ready for use")
 		} else {
@@ -126,6 +289,136 @@ func runCheckConfig(cmd *cobra.Command, args []string) {
 	}
 }
 
+// printConfigSources lists the files the config was merged from when there
+// is more than one, and with showOrigins the source of every value
+func printConfigSources(w io.Writer, sources *config.Sources, showOrigins bool) {
+	if sources == nil {
+		return
+	}
+	if sources.Profile != "" {
+		fmt.Fprintf(w, "   Profile: %s\n", sources.Profile)
+	}
+	if len(sources.Files) > 1 || len(sources.ConfDirs) > 0 {
+		fmt.Fprintf(w, "   Files (merge order):\n")
+		for _, file := range sources.Files {
+			fmt.Fprintf(w, "     - %s\n", file)
+		}
+	}
+	if !showOrigins {
+		return
+	}
+
+	fmt.Fprintf(w, "\n🗂️ Value Origins:\n")
+	keys := sources.Keys()
+	if len(keys) == 0 {
+		fmt.Fprintf(w, "   All values are defaults\n")
+		return
+	}
+	for _, key := range keys {
+		fmt.Fprintf(w, "   %s: %s\n", key, sources.Origins[key])
+	}
+}
+
+// printSecretRefs lists the values resolved from secret references, without
+// the values
+func printSecretRefs(w io.Writer, secrets *config.Secrets) {
+	refs := secrets.Refs()
+	if len(refs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "   Secrets:\n")
+	for _, ref := range refs {
+		fmt.Fprintf(w, "     - %s: %s\n", ref.Path, ref.Reference)
+	}
+}
+
+// printEffectiveConfig prints cfg as YAML after includes, the profile and
+// environment overrides are merged. The profiles section is omitted and
+// secrets are masked.
+func printEffectiveConfig(w io.Writer, cfg *config.Config) error {
+	effective := cfg.Secrets().MaskConfig(cfg)
+	effective.Profiles = nil
+
+	data, err := yaml.Marshal(effective)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		fmt.Fprintf(w, "   %s\n", line)
+	}
+	return nil
+}
+
+// buildLearningReport compares persisted php-fpm worker memory samples with
+// the auto-tuning profile. It returns nil when learning is disabled.
+func buildLearningReport(cfg *config.Config, profileName string) (*autotune.LearningReport, error) {
+	learning := cfg.Global.AutotuneLearning
+	if learning == nil || !learning.Enabled {
+		return nil, nil
+	}
+
+	observer, err := autotune.LoadWorkerMemoryObserver(learning.StatePath, learning.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	var calc *autotune.Calculator
+	var calcErr error
+	if profileName != "" {
+		discard := slog.New(slog.DiscardHandler)
+		calc, calcErr = autotune.NewCalculator(autotune.Profile(profileName), cfg.Global.AutotuneMemoryThreshold, discard)
+	}
+
+	report := autotune.BuildLearningReport(observer, learning.Process, learning.Percentile, learning.MinSamples, calc, nil)
+	report.Apply = learning.Apply
+	if calcErr != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("No recommendation: %v", calcErr))
+	}
+	return report, nil
+}
+
+// printLearningReport prints observed worker memory and its drift from the profile
+func printLearningReport(report *autotune.LearningReport) {
+	fmt.Printf("\n🧠 Autotune Learning (%s):\n", report.Process)
+	if report.Stats.Samples == 0 {
+		fmt.Println("   No worker memory observed yet")
+		return
+	}
+
+	fmt.Printf("   Samples: %d (p50 %.1fMB, p95 %.1fMB, max %.1fMB)\n",
+		report.Stats.Samples, report.Stats.P50MB, report.Stats.P95MB, report.Stats.MaxMB)
+	if report.EstimateMB > 0 {
+		fmt.Printf("   Worker memory: %dMB observed (p%.0f) vs %dMB %s profile estimate (%+.1f%%)\n",
+			report.ObservedMB, report.Percentile, report.EstimateMB, report.Profile, report.DriftPercent)
+	} else {
+		fmt.Printf("   Worker memory: %dMB observed (p%.0f)\n", report.ObservedMB, report.Percentile)
+	}
+	if report.RecommendedMaxChildren > 0 {
+		action := "recommended"
+		if report.Apply {
+			action = "applied on next start"
+		}
+		fmt.Printf("   pm.max_children: %d → %d (%s)\n", report.CurrentMaxChildren, report.RecommendedMaxChildren, action)
+	}
+	for _, warning := range report.Warnings {
+		fmt.Printf("   ⚠️  %s\n", warning)
+	}
+}
+
+// printRedactionSamples prints sample lines before and after redaction
+func printRedactionSamples(samples []redactionSample) {
+	fmt.Printf("\n🔒 Redaction Preview:\n")
+	if len(samples) == 0 {
+		fmt.Println("   No process has redaction enabled")
+		return
+	}
+	for _, s := range samples {
+		fmt.Printf("   [%s]\n", s.Process)
+		fmt.Printf("     before: %s\n", s.Input)
+		fmt.Printf("     after:  %s\n", s.Output)
+	}
+}
+
 // formatJSONOutput formats data as JSON string
 func formatJSONOutput(data map[string]interface{}) string {
 	// Simple JSON formatting (could use json.Marshal for production)
@@ -153,6 +446,20 @@ func formatJSONOutput(data map[string]interface{}) string {
 	return fmt.Sprintf("%s\n", fmt.Sprint(parts))
 }
 
+// getConfigProfile returns the config profile from the persistent flag or
+// PHPEEK_PM_PROFILE ("" = none)
+func getConfigProfile() string {
+	if cfgProfile != "" {
+		return cfgProfile
+	}
+	return config.ProfileFromEnv()
+}
+
+// loadConfig loads the configuration at path with the selected profile
+func loadConfig(path string) (*config.Config, error) {
+	return config.LoadWithOptions(path, config.LoadOptions{Profile: getConfigProfile()})
+}
+
 // getConfigPath determines configuration file path with priority order
 func getConfigPath() string {
 	// 1. Try persistent flag (explicit, highest priority)
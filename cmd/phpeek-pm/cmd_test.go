@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"log/slog"
 	"os"
@@ -14,9 +15,12 @@ import (
 	"time"
 
 	"github.com/gophpeek/phpeek-pm/internal/audit"
+	"github.com/gophpeek/phpeek-pm/internal/autotune"
 	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/migrate"
 	"github.com/gophpeek/phpeek-pm/internal/process"
 	"github.com/gophpeek/phpeek-pm/internal/scaffold"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 	"github.com/spf13/cobra"
 )
 
@@ -839,6 +843,8 @@ func TestAllSubcommandsRegistered(t *testing.T) {
 		"tui",
 		"logs",
 		"scaffold",
+		"schema",
+		"migrate",
 	}
 
 	registeredCommands := make(map[string]bool)
@@ -876,6 +882,8 @@ func TestCheckConfigFlags(t *testing.T) {
 		"strict",
 		"json",
 		"quiet",
+		"redact-sample",
+		"origins",
 	}
 
 	for _, flagName := range expectedFlags {
@@ -886,6 +894,283 @@ func TestCheckConfigFlags(t *testing.T) {
 	}
 }
 
+func TestBuildRedactionSamples(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]*config.Process{
+			"web": {
+				Logging: &config.LoggingConfig{
+					Redaction: &config.RedactionConfig{
+						Enabled: true,
+						Presets: []string{"email", "credit_card"},
+					},
+				},
+			},
+			"worker": {
+				Logging: &config.LoggingConfig{},
+			},
+		},
+	}
+
+	samples, err := buildRedactionSamples(cfg, []string{"paid 4111 1111 1111 1111 by a@b.io"})
+	if err != nil {
+		t.Fatalf("buildRedactionSamples() error = %v", err)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("expected 1 sample (only web has redaction), got %d", len(samples))
+	}
+	if samples[0].Process != "web" || samples[0].Output != "paid *** by ***" {
+		t.Errorf("unexpected sample: %+v", samples[0])
+	}
+
+	// Unknown presets are reported even without samples
+	cfg.Processes["web"].Logging.Redaction.Presets = []string{"nope"}
+	if _, err := buildRedactionSamples(cfg, nil); err == nil || !strings.Contains(err.Error(), "process web") {
+		t.Errorf("expected unknown preset error for web, got %v", err)
+	}
+}
+
+func TestPrintConfigSources(t *testing.T) {
+	sources := &config.Sources{
+		Files:   []string{"/etc/phpeek-pm/base.yaml", "/etc/phpeek-pm/phpeek-pm.yaml"},
+		Origins: map[string]string{"global.log_level": "env", "processes.app.command": "/etc/phpeek-pm/base.yaml"},
+	}
+
+	var buf bytes.Buffer
+	printConfigSources(&buf, sources, false)
+	if !strings.Contains(buf.String(), "- /etc/phpeek-pm/base.yaml") || strings.Contains(buf.String(), "Value Origins") {
+		t.Errorf("expected file list without origins, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	printConfigSources(&buf, sources, true)
+	out := buf.String()
+	if !strings.Contains(out, "global.log_level: env") || strings.Index(out, "global.log_level") > strings.Index(out, "processes.app.command") {
+		t.Errorf("expected sorted origins, got:\n%s", out)
+	}
+
+	// The applied profile is always shown
+	buf.Reset()
+	printConfigSources(&buf, &config.Sources{Files: sources.Files[:1], Profile: "production"}, false)
+	if !strings.Contains(buf.String(), "Profile: production") {
+		t.Errorf("expected profile, got:\n%s", buf.String())
+	}
+
+	// A single file without conf.d prints nothing unless origins are requested
+	buf.Reset()
+	printConfigSources(&buf, &config.Sources{Files: []string{"/etc/phpeek-pm/phpeek-pm.yaml"}}, false)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for a single file, got:\n%s", buf.String())
+	}
+}
+
+func TestPrintEffectiveConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+	content := `
+global:
+  log_level: info
+processes:
+  queue:
+    command: ["php", "artisan", "queue:work"]
+profiles:
+  production:
+    global:
+      log_level: warn
+    processes:
+      queue:
+        scale: 8
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadWithOptions(path, config.LoadOptions{Profile: "production"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := printEffectiveConfig(&buf, cfg); err != nil {
+		t.Fatalf("printEffectiveConfig() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "log_level: warn") || !strings.Contains(out, "scale: 8") {
+		t.Errorf("expected profile values in effective config, got:\n%s", out)
+	}
+	if strings.Contains(out, "profiles:") {
+		t.Errorf("effective config should omit the profiles section, got:\n%s", out)
+	}
+	if len(cfg.Profiles) != 1 {
+		t.Error("printEffectiveConfig() must not modify the config")
+	}
+}
+
+func writeLearningState(t *testing.T, samples int, rssMB uint64) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "learning.json")
+	obs := autotune.NewWorkerMemoryObserver(1000)
+	for i := 0; i < samples; i++ {
+		obs.RecordWorkerRSS([]uint64{rssMB << 20})
+	}
+	if err := obs.Save(path); err != nil {
+		t.Fatalf("failed to save learning state: %v", err)
+	}
+	return path
+}
+
+func TestBuildLearningReport(t *testing.T) {
+	cfg := &config.Config{}
+	if report, err := buildLearningReport(cfg, "medium"); report != nil || err != nil {
+		t.Errorf("expected no report when learning is disabled, got %v, %v", report, err)
+	}
+
+	cfg.Global.AutotuneLearning = &config.LearningConfig{
+		Enabled:    true,
+		Process:    "php-fpm",
+		StatePath:  writeLearningState(t, 20, 80),
+		Percentile: 95,
+		MinSamples: 10,
+		Window:     1000,
+	}
+
+	report, err := buildLearningReport(cfg, "")
+	if err != nil {
+		t.Fatalf("buildLearningReport() error = %v", err)
+	}
+	if !report.Ready || report.ObservedMB != 80 || report.EstimateMB != 0 {
+		t.Errorf("unexpected report without profile: %+v", report)
+	}
+
+	// With a profile the drift against its estimate is reported
+	report, err = buildLearningReport(cfg, "medium")
+	if err != nil {
+		t.Fatalf("buildLearningReport() error = %v", err)
+	}
+	if report.EstimateMB == 0 || report.DriftPercent <= 0 {
+		t.Errorf("expected positive drift against profile estimate: %+v", report)
+	}
+}
+
+func TestApplyLearnedWorkerMemory(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	calc, err := autotune.NewCalculator(autotune.ProfileDev, 0, log)
+	if err != nil {
+		t.Skipf("cannot detect resources: %v", err)
+	}
+
+	learning := &config.LearningConfig{
+		StatePath:  writeLearningState(t, 5, 50),
+		Percentile: 95,
+		MinSamples: 10,
+		Window:     1000,
+	}
+
+	// Not enough samples: profile estimate is kept
+	applyLearnedWorkerMemory(calc, learning, log)
+	if tuned, err := calc.Calculate(); err == nil && tuned.Observed {
+		t.Error("expected profile estimate with too few samples")
+	}
+
+	learning.MinSamples = 5
+	applyLearnedWorkerMemory(calc, learning, log)
+	tuned, err := calc.Calculate()
+	if err != nil {
+		t.Skipf("insufficient memory for dev profile: %v", err)
+	}
+	if !tuned.Observed || tuned.WorkerMemory != 50 {
+		t.Errorf("expected observed 50MB per worker, got %dMB (observed=%v)", tuned.WorkerMemory, tuned.Observed)
+	}
+}
+
+func TestResolveMemoryThreshold(t *testing.T) {
+	cfg := &config.Config{}
+
+	t.Setenv("PHP_FPM_AUTOTUNE_MEMORY_THRESHOLD", "")
+	if threshold, source := resolveMemoryThreshold(0, cfg); threshold != 0 || source != "profile default" {
+		t.Errorf("got %.2f via %s, want profile default", threshold, source)
+	}
+
+	cfg.Global.AutotuneMemoryThreshold = 0.6
+	if threshold, source := resolveMemoryThreshold(0, cfg); threshold != 0.6 || source != "global config" {
+		t.Errorf("got %.2f via %s, want 0.6 via global config", threshold, source)
+	}
+
+	t.Setenv("PHP_FPM_AUTOTUNE_MEMORY_THRESHOLD", "0.7")
+	if threshold, source := resolveMemoryThreshold(0, cfg); threshold != 0.7 || source != "ENV variable" {
+		t.Errorf("got %.2f via %s, want 0.7 via ENV variable", threshold, source)
+	}
+
+	if threshold, source := resolveMemoryThreshold(0.8, cfg); threshold != 0.8 || source != "CLI flag" {
+		t.Errorf("got %.2f via %s, want 0.8 via CLI flag", threshold, source)
+	}
+}
+
+func TestRunProcessAutoTuning(t *testing.T) {
+	t.Setenv("PHP_FPM_AUTOTUNE_MEMORY_THRESHOLD", "")
+	newConfig := func() *config.Config {
+		return &config.Config{Processes: map[string]*config.Process{
+			"queue": {
+				Scale:    1,
+				MaxScale: 3,
+				Command:  []string{"php", "artisan", "queue:work"},
+				Autotune: &config.ProcessAutotune{Enabled: true, MemoryPerInstance: 1, Weight: 1, MinScale: 1},
+			},
+			"octane": {
+				Scale:    1,
+				Command:  []string{"php", "artisan", "octane:start", "--workers=1"},
+				Autotune: &config.ProcessAutotune{Enabled: true, MemoryPerInstance: 1, Weight: 1, MinScale: 1, MaxScale: 4, WorkersFlag: "--workers"},
+			},
+		}}
+	}
+
+	cfg := newConfig()
+	plan, err := runProcessAutoTuning(0, cfg)
+	if err != nil {
+		t.Skipf("cannot plan memory: %v", err)
+	}
+	if len(plan.Allocations) != 2 {
+		t.Fatalf("expected 2 allocations, got %+v", plan.Allocations)
+	}
+	// 1MB per instance always reaches the configured maximum
+	if cfg.Processes["queue"].Scale != 3 {
+		t.Errorf("queue scale = %d, want 3", cfg.Processes["queue"].Scale)
+	}
+	if got := cfg.Processes["octane"].Command[3]; got != "--workers=4" {
+		t.Errorf("octane workers flag = %s, want --workers=4", got)
+	}
+
+	// Reloaded configurations are tuned the same way
+	reloaded := newConfig()
+	if err := retuneOnReload(nil, "")(reloaded); err != nil {
+		t.Fatalf("retuneOnReload() error = %v", err)
+	}
+	if reloaded.Processes["queue"].Scale != 3 {
+		t.Errorf("reloaded queue scale = %d, want 3", reloaded.Processes["queue"].Scale)
+	}
+}
+
+func TestValidateLoggingConfigs(t *testing.T) {
+	cfg := &config.Config{
+		Processes: map[string]*config.Process{
+			"app": {
+				Logging: &config.LoggingConfig{
+					Multiline: &config.MultilineConfig{Enabled: true, Preset: "laravel"},
+				},
+			},
+			"plain": {},
+		},
+	}
+	if err := validateLoggingConfigs(cfg); err != nil {
+		t.Fatalf("validateLoggingConfigs() error = %v", err)
+	}
+
+	cfg.Processes["app"].Logging.Multiline.Preset = "cobol"
+	err := validateLoggingConfigs(cfg)
+	if err == nil || !strings.Contains(err.Error(), "unknown multiline preset") {
+		t.Errorf("expected unknown multiline preset error, got %v", err)
+	}
+}
+
 // TestScaffoldFlags tests that scaffold command has all expected flags
 func TestScaffoldFlags(t *testing.T) {
 	expectedFlags := []string{
@@ -921,6 +1206,79 @@ func TestLogsFlags(t *testing.T) {
 	}
 }
 
+// TestMigrateFlags tests that migrate command has all expected flags
+func TestMigrateFlags(t *testing.T) {
+	for _, flagName := range []string{"from", "output", "in-place", "strict"} {
+		if migrateCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("expected migrate command to have --%s flag", flagName)
+		}
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	defer func() { migrateFrom, migrateOutput, migrateInPlace, migrateStrict = "", "", false, false }()
+
+	dir := t.TempDir()
+	procfile := filepath.Join(dir, "Procfile")
+	if err := os.WriteFile(procfile, []byte("web: php -S 0.0.0.0:8080 -t public\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := captureOutput(func() {
+		rootCmd.SetArgs([]string{"migrate", procfile})
+		_ = rootCmd.Execute()
+	})
+	if !strings.Contains(stdout, "command: [php, -S, '0.0.0.0:8080', -t, public]") {
+		t.Errorf("stdout = %q, want the converted config", stdout)
+	}
+	if !strings.Contains(stderr, "Migrated "+procfile+" (procfile)") {
+		t.Errorf("stderr = %q, want the report", stderr)
+	}
+
+	// A legacy config is rewritten in place
+	legacy := filepath.Join(dir, "phpeek-pm.yaml")
+	if err := os.WriteFile(legacy, []byte("processes:\n  app:\n    command: [php-fpm]\n    stdout: false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr = captureOutput(func() {
+		rootCmd.SetArgs([]string{"migrate", legacy, "--in-place"})
+		_ = rootCmd.Execute()
+	})
+	data, err := os.ReadFile(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "logging:\n      stdout: false") {
+		t.Errorf("rewritten config = %q", data)
+	}
+	if !strings.Contains(stderr, "processes.app.stdout: moved to logging.stdout") {
+		t.Errorf("stderr = %q, want the moved setting", stderr)
+	}
+}
+
+func TestPrintMigrationReport(t *testing.T) {
+	var buf bytes.Buffer
+	printMigrationReport(&buf, "Procfile", "", &migrate.Result{Format: migrate.FormatProcfile})
+	if !strings.Contains(buf.String(), "Nothing to change") {
+		t.Errorf("report = %q", buf.String())
+	}
+
+	buf.Reset()
+	result := &migrate.Result{
+		Format: migrate.FormatSupervisord,
+		Notes: []migrate.Note{
+			{Source: "[program:a] stopsignal", Message: "converted"},
+			{Source: "[program:a] startsecs", Message: "not supported", Unmapped: true},
+		},
+	}
+	printMigrationReport(&buf, "app.conf", "phpeek-pm.yaml", result)
+	for _, want := range []string{"Migrated app.conf (supervisord) to phpeek-pm.yaml", "✓ [program:a] stopsignal: converted", "⚠️ [program:a] startsecs: not supported", "1 setting(s) could not be mapped"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
 // TestTUIFlags tests that tui command has all expected flags
 func TestTUIFlags(t *testing.T) {
 	flag := tuiCmd.Flags().Lookup("remote")
@@ -943,6 +1301,55 @@ func TestPersistentConfigFlag(t *testing.T) {
 	}
 }
 
+// TestPersistentProfileFlag tests the --profile flag and its env fallback
+func TestPersistentProfileFlag(t *testing.T) {
+	if rootCmd.PersistentFlags().Lookup("profile") == nil {
+		t.Fatal("expected persistent --profile flag on root command")
+	}
+
+	origProfile := cfgProfile
+	defer func() { cfgProfile = origProfile }()
+
+	t.Setenv("PHPEEK_PM_PROFILE", "staging")
+	cfgProfile = ""
+	if got := getConfigProfile(); got != "staging" {
+		t.Errorf("getConfigProfile() = %q, want staging from env", got)
+	}
+
+	cfgProfile = "production"
+	if got := getConfigProfile(); got != "production" {
+		t.Errorf("getConfigProfile() = %q, want flag to win over env", got)
+	}
+}
+
+func TestSchemaCommand(t *testing.T) {
+	origOutput := schemaOutput
+	defer func() { schemaOutput = origOutput }()
+	schemaOutput = ""
+
+	output := executeCommandCapture(t, rootCmd, "schema")
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &schema); err != nil {
+		t.Fatalf("schema output is not JSON: %v\n%s", err, output)
+	}
+	if schema["$schema"] != config.SchemaDraft {
+		t.Errorf("$schema = %v", schema["$schema"])
+	}
+
+	path := filepath.Join(t.TempDir(), "phpeek-pm.schema.json")
+	output = executeCommandCapture(t, rootCmd, "schema", "-o", path)
+	if !strings.Contains(output, "Schema written to "+path) {
+		t.Errorf("output = %q", output)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("schema file not written: %v", err)
+	}
+	if !json.Valid(data) {
+		t.Error("schema file is not valid JSON")
+	}
+}
+
 // TestCheckConfigWithVariousFormats tests check-config with different output formats
 // Uses subprocess because check-config calls os.Exit
 func TestCheckConfigWithVariousFormats(t *testing.T) {
@@ -2356,6 +2763,8 @@ func TestCommandRunFunctions(t *testing.T) {
 		"tui":          tuiCmd,
 		"logs":         logsCmd,
 		"scaffold":     scaffoldCmd,
+		"schema":       schemaCmd,
+		"migrate":      migrateCmd,
 	}
 
 	for name, cmd := range commands {
@@ -6218,7 +6627,7 @@ func TestRunAutoTuning(t *testing.T) {
 				},
 			}
 
-			err := runAutoTuning(tt.profile, tt.threshold, cfg)
+			_, _, err := runAutoTuning(tt.profile, tt.threshold, cfg)
 
 			if tt.mustErr && err == nil {
 				t.Errorf("runAutoTuning() must error for invalid profile %s", tt.profile)
@@ -6256,7 +6665,7 @@ func TestRunAutoTuningWithEnvThreshold(t *testing.T) {
 		Global:  config.GlobalConfig{},
 	}
 
-	err := runAutoTuning("dev", 0, cfg)
+	_, _, err := runAutoTuning("dev", 0, cfg)
 	// In test environment, memory detection returns 0 so this will fail
 	// But it tests the env threshold reading code path
 	if err == nil {
@@ -8148,7 +8557,7 @@ func TestRunAutoTuningWithConfigThreshold(t *testing.T) {
 	}
 
 	// This tests the config threshold path (finalThreshold == 0 && cfg.Global.AutotuneMemoryThreshold > 0)
-	err := runAutoTuning("dev", 0, cfg)
+	_, _, err := runAutoTuning("dev", 0, cfg)
 	// Will fail in test env due to memory detection, but exercises the code path
 	if err == nil {
 		t.Log("runAutoTuning succeeded")
@@ -8157,6 +8566,47 @@ func TestRunAutoTuningWithConfigThreshold(t *testing.T) {
 	}
 }
 
+// TestRunAutoTuningCustomProfile tests selecting a profile from global.autotune.profiles
+func TestRunAutoTuningCustomProfile(t *testing.T) {
+	origStderr := os.Stderr
+	_, errW, _ := os.Pipe()
+	os.Stderr = errW
+	defer func() {
+		errW.Close()
+		os.Stderr = origStderr
+	}()
+	defer func() { _ = autotune.LoadCustomProfiles(nil) }()
+
+	workerMB := 48
+	cfg := &config.Config{
+		Version: "1.0",
+		Global: config.GlobalConfig{
+			Autotune: &config.AutotuneConfig{
+				Profiles: map[string]*config.AutotuneProfile{
+					"tiny": {Extends: "dev", AvgMemoryPerWorker: &workerMB},
+				},
+			},
+		},
+	}
+
+	calc, tuned, err := runAutoTuning("tiny", 0, cfg)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			t.Fatalf("custom profile should be accepted: %v", err)
+		}
+		t.Skipf("insufficient memory in test env: %v", err)
+	}
+	if calc.ProfileWorkerMemory() != 48 || tuned.WorkerMemory != 48 {
+		t.Errorf("expected custom 48MB per worker, got %d", tuned.WorkerMemory)
+	}
+
+	// Invalid definitions are reported
+	cfg.Global.Autotune.Profiles["broken"] = &config.AutotuneProfile{Extends: "nope"}
+	if _, _, err := runAutoTuning("tiny", 0, cfg); err == nil || !strings.Contains(err.Error(), "custom profile") {
+		t.Errorf("expected custom profile error, got %v", err)
+	}
+}
+
 // TestRunAutoTuningAllProfiles tests all autotune profiles
 func TestRunAutoTuningAllProfiles(t *testing.T) {
 	profiles := []string{"dev", "light", "medium", "heavy", "bursty"}
@@ -8177,7 +8627,7 @@ func TestRunAutoTuningAllProfiles(t *testing.T) {
 				Global:  config.GlobalConfig{},
 			}
 
-			err := runAutoTuning(profile, 0, cfg)
+			_, _, err := runAutoTuning(profile, 0, cfg)
 			// Will fail in test env, but exercises code paths
 			if err == nil {
 				t.Logf("Profile %s succeeded", profile)
@@ -8205,7 +8655,7 @@ func TestRunAutoTuningWithCLIThreshold(t *testing.T) {
 	}
 
 	// CLI threshold > 0 path
-	err := runAutoTuning("dev", 0.9, cfg)
+	_, _, err := runAutoTuning("dev", 0.9, cfg)
 	if err == nil {
 		t.Log("runAutoTuning with CLI threshold succeeded")
 	} else {
@@ -8337,7 +8787,7 @@ func TestRunAutoTuningSourceDisplay(t *testing.T) {
 		Global:  config.GlobalConfig{},
 	}
 
-	err := runAutoTuning("dev", 0, cfg)
+	_, _, err := runAutoTuning("dev", 0, cfg)
 	// Error expected in test env, but code path executed
 	if err != nil {
 		t.Logf("runAutoTuning error (expected): %v", err)
@@ -8369,7 +8819,7 @@ func TestRunAutoTuningENVSource(t *testing.T) {
 		Global:  config.GlobalConfig{},
 	}
 
-	err := runAutoTuning("dev", 0, cfg)
+	_, _, err := runAutoTuning("dev", 0, cfg)
 	// Tests the "ENV var" source path
 	if err != nil {
 		t.Logf("runAutoTuning error (expected): %v", err)
@@ -8579,3 +9029,127 @@ func TestRunScaffoldWithComposeOnly(t *testing.T) {
 		t.Log("docker-compose.yml was generated")
 	}
 }
+
+// TestSchedulePreviewFlags tests that schedule preview has all expected flags
+func TestSchedulePreviewFlags(t *testing.T) {
+	for _, flagName := range []string{"count", "from", "json"} {
+		if schedulePreviewCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("expected schedule preview command to have --%s flag", flagName)
+		}
+	}
+}
+
+func TestSchedulePreviewCommand(t *testing.T) {
+	origConfig := cfgFile
+	defer func() {
+		cfgFile = origConfig
+		schedulePreviewCount, schedulePreviewFrom, schedulePreviewJSON = schedule.DefaultPreviewCount, "", false
+	}()
+
+	configPath := filepath.Join(t.TempDir(), "phpeek-pm.yaml")
+	configYAML := `version: "1.0"
+processes:
+  backup:
+    command: ["true"]
+    schedule: "0 2 * * *"
+    schedule_timeout: 30m
+  sync:
+    command: ["true"]
+    schedule: "*/10 * * * *"
+    schedule_timeout: 15m
+    schedule_overlap: queue
+  web:
+    command: ["true"]
+`
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := captureOutput(func() {
+		rootCmd.SetArgs([]string{"schedule", "preview", "--config", configPath, "--count", "2", "--from", "2026-01-05T00:00:00Z"})
+		_ = rootCmd.Execute()
+	})
+	if stderr != "" {
+		t.Errorf("stderr = %q", stderr)
+	}
+	for _, want := range []string{"backup: 0 2 * * * (UTC)", "2026-01-05 02:00:00 UTC", "sync: */10 * * * *", "the next run waits for it"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("stdout missing %q:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "web") {
+		t.Errorf("stdout should only list scheduled processes:\n%s", stdout)
+	}
+
+	stdout, _ = captureOutput(func() {
+		rootCmd.SetArgs([]string{"schedule", "preview", "backup", "--config", configPath, "--count", "3", "--from", "2026-01-05T00:00:00Z", "--json"})
+		_ = rootCmd.Execute()
+	})
+	var previews map[string]*schedule.Preview
+	if err := json.Unmarshal([]byte(stdout), &previews); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(previews) != 1 || len(previews["backup"].Runs) != 3 {
+		t.Errorf("previews = %+v, want 3 runs of backup", previews)
+	}
+}
+
+func TestBuildSchedulePreviews(t *testing.T) {
+	cfg := &config.Config{Processes: map[string]*config.Process{
+		"backup": {Command: []string{"true"}, Schedule: "0 2 * * *", ScheduleTimezone: "UTC"},
+		"web":    {Command: []string{"true"}},
+	}}
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	previews, err := buildSchedulePreviews(cfg, nil, 2, from)
+	if err != nil {
+		t.Fatalf("buildSchedulePreviews() error = %v", err)
+	}
+	if len(previews) != 1 || previews["backup"] == nil {
+		t.Errorf("previews = %v, want only backup", previews)
+	}
+
+	for _, name := range []string{"web", "missing"} {
+		if _, err := buildSchedulePreviews(cfg, []string{name}, 2, from); err == nil {
+			t.Errorf("buildSchedulePreviews(%s) should fail", name)
+		}
+	}
+}
+
+func TestParsePreviewFrom(t *testing.T) {
+	if from, err := parsePreviewFrom(""); err != nil || !from.IsZero() {
+		t.Errorf("parsePreviewFrom(\"\") = %v, %v, want zero time", from, err)
+	}
+	if from, err := parsePreviewFrom("2026-03-08T01:00:00Z"); err != nil || from.Hour() != 1 {
+		t.Errorf("parsePreviewFrom(RFC3339) = %v, %v", from, err)
+	}
+	if from, err := parsePreviewFrom("2026-03-08"); err != nil || from.Day() != 8 {
+		t.Errorf("parsePreviewFrom(date) = %v, %v", from, err)
+	}
+	if _, err := parsePreviewFrom("tomorrow"); err == nil {
+		t.Error("parsePreviewFrom() should fail for an invalid time")
+	}
+}
+
+func TestPrintSchedulePreview(t *testing.T) {
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	preview := &schedule.Preview{
+		Schedule: "30 2 * * *",
+		Timezone: "America/New_York",
+		Timeout:  time.Hour,
+		Runs: []schedule.PreviewRun{
+			{Time: from.Add(2 * time.Hour), WallClock: "2026-01-05 02:00:00 UTC", Overlap: true, Note: "may still run"},
+			{WallClock: "2026-03-08 02:30:00", DST: schedule.DSTSkipped, Note: "does not exist"},
+		},
+		Warnings: []string{"check me"},
+	}
+
+	var buf bytes.Buffer
+	printSchedulePreview(&buf, "backup", preview, from)
+	out := buf.String()
+	for _, want := range []string{"backup: 30 2 * * * (America/New_York), timeout 1h0m0s", "1  2026-01-05 02:00:00 UTC  (in 2h0m0s)", "⚠️  may still run", "⏭️  2026-03-08 02:30:00  does not exist", "⚠️  check me"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/gophpeek/phpeek-pm/internal/audit"
-	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/process"
 	"github.com/gophpeek/phpeek-pm/internal/setup"
@@ -64,7 +63,7 @@ func runLogs(cmd *cobra.Command, args []string) {
 	_ = validator.ValidateAll()
 
 	// Load configuration
-	cfg, err := config.LoadWithEnvExpansion(cfgPath)
+	cfg, err := loadConfig(cfgPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to load configuration: %v\n", err)
 		os.Exit(1)
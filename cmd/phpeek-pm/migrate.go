@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gophpeek/phpeek-pm/internal/migrate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom    string
+	migrateOutput  string
+	migrateInPlace bool
+	migrateStrict  bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [file]",
+	Short: "Convert legacy configs, supervisord configs and Procfiles",
+	Long: `Rewrite a configuration in the current phpeek-pm format.
+
+Supported inputs (detected from the file name and content, or set with --from):
+  phpeek-pm     Legacy settings are rewritten in place, keeping comments:
+                global.restart_backoff becomes restart_backoff_initial/max and
+                process stdout/stderr move under logging
+  supervisord   [program:x] sections become processes (command, autostart,
+                autorestart, numprocs, user, directory, environment,
+                stopsignal, stopwaitsecs, stdout/stderr_logfile)
+  procfile      "name: command" lines become processes
+
+The migrated config is written to stdout (or --output). Settings that could
+not be mapped are reported on stderr.
+
+Without a file argument the configured config file is migrated.`,
+	Example: `  phpeek-pm migrate /etc/supervisor/conf.d/app.conf -o phpeek-pm.yaml
+  phpeek-pm migrate Procfile > phpeek-pm.yaml
+  phpeek-pm migrate phpeek-pm.yaml --in-place`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runMigrate,
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", fmt.Sprintf("Input format: %s (default: detect)", strings.Join(migrate.Formats(), ", ")))
+	migrateCmd.Flags().StringVarP(&migrateOutput, "output", "o", "", "Write the migrated config to a file instead of stdout")
+	migrateCmd.Flags().BoolVarP(&migrateInPlace, "in-place", "i", false, "Rewrite the input file (phpeek-pm configs only)")
+	migrateCmd.Flags().BoolVar(&migrateStrict, "strict", false, "Exit with an error if any setting could not be mapped")
+}
+
+func runMigrate(cmd *cobra.Command, args []string) {
+	input := getConfigPath()
+	if len(args) > 0 {
+		input = args[0]
+	}
+
+	content, err := os.ReadFile(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to read %s: %v\n", input, err)
+		os.Exit(1)
+	}
+
+	result, err := migrate.Migrate(input, content, migrate.Format(migrateFrom))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Migration failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	output := migrateOutput
+	if migrateInPlace {
+		if result.Format != migrate.FormatPHPeek {
+			fmt.Fprintf(os.Stderr, "❌ --in-place only rewrites phpeek-pm configs, %s is a %s file (use --output)\n", input, result.Format)
+			os.Exit(1)
+		}
+		output = input
+	}
+
+	if output == "" {
+		os.Stdout.Write(result.YAML)
+	} else if err := os.WriteFile(output, result.YAML, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to write %s: %v\n", output, err)
+		os.Exit(1)
+	}
+
+	printMigrationReport(os.Stderr, input, output, result)
+	if migrateStrict && len(result.Unmapped()) > 0 {
+		os.Exit(1)
+	}
+}
+
+// printMigrationReport lists the changes made and the settings that could
+// not be mapped
+func printMigrationReport(w io.Writer, input, output string, result *migrate.Result) {
+	fmt.Fprintf(w, "🔁 Migrated %s (%s)", input, result.Format)
+	if output != "" {
+		fmt.Fprintf(w, " to %s", output)
+	}
+	fmt.Fprintln(w)
+
+	if len(result.Notes) == 0 {
+		fmt.Fprintln(w, "✅ Nothing to change")
+		return
+	}
+	for _, note := range result.Notes {
+		icon := "  ✓"
+		if note.Unmapped {
+			icon = "  ⚠️"
+		}
+		fmt.Fprintf(w, "%s %s\n", icon, note)
+	}
+
+	if unmapped := len(result.Unmapped()); unmapped > 0 {
+		fmt.Fprintf(w, "\n⚠️  %d setting(s) could not be mapped, review the migrated config\n", unmapped)
+	}
+}
@@ -10,7 +10,8 @@ import (
 const version = "1.0.0"
 
 var (
-	cfgFile string
+	cfgFile    string
+	cfgProfile string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -53,6 +54,7 @@ func Execute() {
 func init() {
 	// Global flags available to all subcommands
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "Path to configuration file")
+	rootCmd.PersistentFlags().StringVar(&cfgProfile, "profile", "", "Configuration profile to apply (overrides PHPEEK_PM_PROFILE)")
 
 	// Add subcommands
 	rootCmd.AddCommand(serveCmd)
@@ -61,6 +63,9 @@ func init() {
 	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(scaffoldCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(scheduleCmd)
 	// Process control commands (future):
 	// rootCmd.AddCommand(restartCmd)
 	// rootCmd.AddCommand(stopCmd)
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schedulePreviewCount int
+	schedulePreviewFrom  string
+	schedulePreviewJSON  bool
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Inspect scheduled processes",
+}
+
+var schedulePreviewCmd = &cobra.Command{
+	Use:   "preview [process]",
+	Short: "Show the upcoming runs of scheduled processes",
+	Long: `Compute the upcoming run times of scheduled processes from the config file,
+with the same parser and timezone handling as the scheduler. No daemon is needed.
+
+Runs are flagged when:
+  - a DST transition skips them (the wall-clock time does not exist)
+  - a DST transition repeats them (the wall-clock time occurs twice)
+  - schedule_timeout (plus schedule_jitter) reaches into the next run
+
+Without a process argument every scheduled process is previewed.`,
+	Example: `  phpeek-pm schedule preview
+  phpeek-pm schedule preview backup --count 20
+  phpeek-pm schedule preview backup --from 2026-03-07 --json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runSchedulePreview,
+}
+
+func init() {
+	schedulePreviewCmd.Flags().IntVarP(&schedulePreviewCount, "count", "n", schedule.DefaultPreviewCount, fmt.Sprintf("Number of runs per process (max %d)", schedule.MaxPreviewCount))
+	schedulePreviewCmd.Flags().StringVar(&schedulePreviewFrom, "from", "", "Preview runs after this time (RFC3339 or YYYY-MM-DD, default: now)")
+	schedulePreviewCmd.Flags().BoolVar(&schedulePreviewJSON, "json", false, "Output the previews as JSON")
+	scheduleCmd.AddCommand(schedulePreviewCmd)
+}
+
+func runSchedulePreview(cmd *cobra.Command, args []string) {
+	if schedulePreviewCount < 1 || schedulePreviewCount > schedule.MaxPreviewCount {
+		fmt.Fprintf(os.Stderr, "❌ --count must be between 1 and %d\n", schedule.MaxPreviewCount)
+		os.Exit(1)
+	}
+	from, err := parsePreviewFrom(schedulePreviewFrom)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig(getConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	previews, err := buildSchedulePreviews(cfg, args, schedulePreviewCount, from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if schedulePreviewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(previews)
+		return
+	}
+
+	if len(previews) == 0 {
+		fmt.Println("No scheduled processes configured")
+		return
+	}
+	names := make([]string, 0, len(previews))
+	for name := range previews {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		printSchedulePreview(os.Stdout, name, previews[name], from)
+	}
+}
+
+// parsePreviewFrom parses the --from flag (zero time when empty)
+func parsePreviewFrom(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --from %q (use RFC3339 like 2026-03-08T00:00:00Z or a date like 2026-03-08)", value)
+}
+
+// buildSchedulePreviews previews the named scheduled process, or all of them
+// when names is empty
+func buildSchedulePreviews(cfg *config.Config, names []string, count int, from time.Time) (map[string]*schedule.Preview, error) {
+	if len(names) == 0 {
+		for name, proc := range cfg.Processes {
+			if proc.Schedule != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	previews := make(map[string]*schedule.Preview, len(names))
+	for _, name := range names {
+		proc, exists := cfg.Processes[name]
+		if !exists {
+			return nil, fmt.Errorf("process %s not found", name)
+		}
+		if proc.Schedule == "" {
+			return nil, fmt.Errorf("process %s has no schedule", name)
+		}
+
+		opts, err := schedule.PreviewOptionsFromConfig(proc)
+		if err != nil {
+			return nil, fmt.Errorf("process %s: %w", name, err)
+		}
+		opts.Count = count
+		opts.From = from
+		preview, err := schedule.PreviewSchedule(proc.Schedule, opts)
+		if err != nil {
+			return nil, fmt.Errorf("process %s: %w", name, err)
+		}
+		previews[name] = preview
+	}
+	return previews, nil
+}
+
+// printSchedulePreview writes the upcoming runs of one process
+func printSchedulePreview(w io.Writer, name string, preview *schedule.Preview, from time.Time) {
+	if from.IsZero() {
+		from = time.Now()
+	}
+
+	fmt.Fprintf(w, "🗓️  %s: %s (%s)", name, preview.Schedule, preview.Timezone)
+	if preview.Timeout > 0 {
+		fmt.Fprintf(w, ", timeout %s", preview.Timeout)
+	}
+	if preview.Jitter > 0 {
+		fmt.Fprintf(w, ", jitter %s", preview.Jitter)
+	}
+	fmt.Fprintln(w)
+
+	n := 0
+	for _, run := range preview.Runs {
+		if run.DST == schedule.DSTSkipped {
+			fmt.Fprintf(w, "   ⏭️  %s  %s\n", run.WallClock, run.Note)
+			continue
+		}
+		n++
+		fmt.Fprintf(w, "  %3d  %s  (in %s)\n", n, run.WallClock, run.Time.Sub(from).Round(time.Second))
+		if run.Note != "" {
+			fmt.Fprintf(w, "       ⚠️  %s\n", run.Note)
+		}
+	}
+	for _, warning := range preview.Warnings {
+		fmt.Fprintf(w, "  ⚠️  %s\n", warning)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var schemaOutput string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file",
+	Long: `Print the JSON Schema of phpeek-pm.yaml, generated from the configuration types.
+
+Editors use it for completion and validation, e.g. with the YAML language server:
+
+  phpeek-pm schema -o phpeek-pm.schema.json
+
+and as the first line of phpeek-pm.yaml:
+
+  # yaml-language-server: $schema=./phpeek-pm.schema.json`,
+	Run: runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "Write the schema to a file instead of stdout")
+}
+
+func runSchema(cmd *cobra.Command, args []string) {
+	data, err := config.SchemaJSON()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to generate schema: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+
+	if schemaOutput == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(schemaOutput, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to write schema: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Schema written to %s\n", schemaOutput)
+}
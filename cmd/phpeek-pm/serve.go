@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -43,7 +45,7 @@ var (
 
 func init() {
 	serveCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate configuration without starting processes")
-	serveCmd.Flags().StringVar(&phpFPMProfile, "php-fpm-profile", "", "Auto-tune PHP-FPM workers (dev|light|medium|heavy|bursty)")
+	serveCmd.Flags().StringVar(&phpFPMProfile, "php-fpm-profile", "", "Auto-tune PHP-FPM workers (dev|light|medium|heavy|bursty or a custom profile from global.autotune.profiles)")
 	serveCmd.Flags().Float64Var(&memoryThreshold, "autotune-memory-threshold", 0, "Override memory threshold (0.5=50%, 1.0=100%, 1.3=130%)")
 	serveCmd.Flags().BoolVar(&watchMode, "watch", false, "Enable watch mode: automatically reload changed services when config file changes")
 }
@@ -76,7 +78,7 @@ func runServe(cmd *cobra.Command, args []string) {
 	}
 
 	// Load configuration
-	cfg, err := config.LoadWithEnvExpansion(cfgPath)
+	cfg, err := loadConfig(cfgPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to load configuration: %v\n", err)
 		os.Exit(1)
@@ -88,8 +90,16 @@ func runServe(cmd *cobra.Command, args []string) {
 		autotuneProfile = os.Getenv("PHP_FPM_AUTOTUNE_PROFILE")
 	}
 
+	var autotuneCalc *autotune.Calculator
+	var autotuneResult *autotune.PHPFPMConfig
 	if autotuneProfile != "" {
-		if err := runAutoTuning(autotuneProfile, memoryThreshold, cfg); err != nil {
+		autotuneCalc, autotuneResult, err = runAutoTuning(autotuneProfile, memoryThreshold, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Auto-tuning failed: %v\n", err)
+			os.Exit(1)
+		}
+	} else if len(autotune.ProcessConsumers(cfg)) > 0 {
+		if _, err := runProcessAutoTuning(memoryThreshold, cfg); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Auto-tuning failed: %v\n", err)
 			os.Exit(1)
 		}
@@ -152,6 +162,12 @@ func runServe(cmd *cobra.Command, args []string) {
 	// Create process manager
 	pm := process.NewManager(cfg, log, auditLogger)
 	pm.SetConfigPath(cfgPath) // Set config path for saving
+	if autotuneCalc != nil {
+		pm.SetAutotuneResult(autotuneCalc, autotuneResult)
+	}
+	if autotuneProfile != "" || len(autotune.ProcessConsumers(cfg)) > 0 {
+		pm.SetConfigTransform(retuneOnReload(pm, autotuneProfile))
+	}
 
 	// Start metrics server
 	var metricsServer *metrics.Server
@@ -187,9 +203,10 @@ func runServe(cmd *cobra.Command, args []string) {
 
 		configWatcher, err = watcher.New(watcher.Config{
 			ConfigPath: cfgPath,
+			Paths:      cfg.Sources.WatchPaths(),
 			Handler: func() error {
 				// Reload configuration
-				newCfg, err := config.LoadWithEnvExpansion(cfgPath)
+				newCfg, err := loadConfig(cfgPath)
 				if err != nil {
 					return fmt.Errorf("failed to reload config: %w", err)
 				}
@@ -199,6 +216,11 @@ func runServe(cmd *cobra.Command, args []string) {
 					return fmt.Errorf("invalid config: %w", err)
 				}
 
+				// Follow added or removed includes and conf.d files
+				if err := configWatcher.SetPaths(newCfg.Sources.WatchPaths()); err != nil {
+					slog.Warn("Failed to update watched config files", "error", err)
+				}
+
 				// Trigger reload via channel
 				select {
 				case reloadChan <- struct{}{}:
@@ -252,44 +274,49 @@ func runServe(cmd *cobra.Command, args []string) {
 }
 
 // runAutoTuning performs PHP-FPM auto-tuning calculations
-func runAutoTuning(profileName string, threshold float64, cfg *config.Config) error {
+func runAutoTuning(profileName string, threshold float64, cfg *config.Config) (*autotune.Calculator, *autotune.PHPFPMConfig, error) {
 	autotuneLog := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
 
+	// Custom profiles from global.autotune.profiles (replaced on every run so
+	// reloads pick up changes)
+	if err := autotune.LoadCustomProfiles(cfg.Global.AutotuneProfiles()); err != nil {
+		return nil, nil, fmt.Errorf("invalid custom profile: %w", err)
+	}
+
 	profile := autotune.Profile(profileName)
 	if err := profile.Validate(); err != nil {
-		return fmt.Errorf("invalid profile: %w", err)
+		return nil, nil, fmt.Errorf("invalid profile: %w", err)
 	}
 
 	// Determine memory threshold
-	finalThreshold := threshold
-	thresholdSource := "profile default"
-
-	if finalThreshold == 0 {
-		if envThreshold := os.Getenv("PHP_FPM_AUTOTUNE_MEMORY_THRESHOLD"); envThreshold != "" {
-			if parsed, err := strconv.ParseFloat(envThreshold, 64); err == nil {
-				finalThreshold = parsed
-				thresholdSource = "ENV variable"
-			}
-		}
-	} else {
-		thresholdSource = "CLI flag"
-	}
-
-	if finalThreshold == 0 && cfg.Global.AutotuneMemoryThreshold > 0 {
-		finalThreshold = cfg.Global.AutotuneMemoryThreshold
-		thresholdSource = "global config"
-	}
+	finalThreshold, thresholdSource := resolveMemoryThreshold(threshold, cfg)
 
 	// Create calculator
 	calc, err := autotune.NewCalculator(profile, finalThreshold, autotuneLog)
 	if err != nil {
-		return fmt.Errorf("failed to create calculator: %w", err)
+		return nil, nil, fmt.Errorf("failed to create calculator: %w", err)
 	}
 
-	// Calculate configuration
-	phpfpmCfg, err := calc.Calculate()
-	if err != nil {
-		return err
+	// Adaptive tuning: replace the profile estimate with observed worker memory
+	if learningApplyEnabled(cfg) {
+		applyLearnedWorkerMemory(calc, cfg.Global.AutotuneLearning, autotuneLog)
+	}
+
+	// Calculate configuration, sharing the budget with auto-tuned processes
+	var phpfpmCfg *autotune.PHPFPMConfig
+	var plan *autotune.MemoryPlan
+	if consumers := autotune.ProcessConsumers(cfg); len(consumers) > 0 {
+		plan, err = autotune.PlanMemory(calc, nil, autotune.NewPlanOptions(cfg, finalThreshold), consumers)
+		if err != nil {
+			return nil, nil, err
+		}
+		plan.Apply(cfg)
+		phpfpmCfg = plan.PHPFPM
+	} else {
+		phpfpmCfg, err = calc.Calculate()
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Set environment variables
@@ -309,6 +336,10 @@ func runAutoTuning(profileName string, threshold float64, cfg *config.Config) er
 		fmt.Fprintf(os.Stderr, "   Memory threshold: %.1f%% (via %s)\n", finalThreshold*100, thresholdSource)
 	}
 
+	if phpfpmCfg.Observed {
+		fmt.Fprintf(os.Stderr, "   Worker memory: %dMB observed (profile estimate %dMB)\n", phpfpmCfg.WorkerMemory, calc.ProfileWorkerMemory())
+	}
+
 	fmt.Fprintf(os.Stderr, "   pm = %s\n", phpfpmCfg.ProcessManager)
 	fmt.Fprintf(os.Stderr, "   pm.max_children = %d\n", phpfpmCfg.MaxChildren)
 	if phpfpmCfg.ProcessManager == "dynamic" {
@@ -325,8 +356,165 @@ func runAutoTuning(profileName string, threshold float64, cfg *config.Config) er
 	if len(phpfpmCfg.Warnings) > 0 {
 		fmt.Fprintf(os.Stderr, "   ⚠️  Warnings: %d (see logs for details)\n", len(phpfpmCfg.Warnings))
 	}
+	if plan != nil {
+		printMemoryPlan(os.Stderr, plan)
+	}
+
+	if pool := cfg.Global.PHPFPMPool; pool != nil && pool.Enabled {
+		if err := renderPoolConfig(phpfpmCfg, pool, autotuneLog); err != nil {
+			return nil, nil, err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+
+	return calc, phpfpmCfg, nil
+}
+
+// resolveMemoryThreshold returns the memory threshold override (0 = profile
+// default) and where it came from: CLI flag, ENV variable, or global config
+func resolveMemoryThreshold(threshold float64, cfg *config.Config) (float64, string) {
+	if threshold > 0 {
+		return threshold, "CLI flag"
+	}
+	if envThreshold := os.Getenv("PHP_FPM_AUTOTUNE_MEMORY_THRESHOLD"); envThreshold != "" {
+		if parsed, err := strconv.ParseFloat(envThreshold, 64); err == nil && parsed > 0 {
+			return parsed, "ENV variable"
+		}
+	}
+	if cfg.Global.AutotuneMemoryThreshold > 0 {
+		return cfg.Global.AutotuneMemoryThreshold, "global config"
+	}
+	return 0, "profile default"
+}
+
+// runProcessAutoTuning sizes auto-tuned processes from the container memory
+// budget when no php-fpm profile is selected
+func runProcessAutoTuning(threshold float64, cfg *config.Config) (*autotune.MemoryPlan, error) {
+	plan, err := buildMemoryPlan(cfg, "", threshold)
+	if err != nil {
+		return nil, err
+	}
+	plan.Apply(cfg)
+
+	fmt.Fprintf(os.Stderr, "🎯 Processes auto-tuned:\n")
+	printMemoryPlan(os.Stderr, plan)
 	fmt.Fprintf(os.Stderr, "\n")
+	return plan, nil
+}
+
+// buildMemoryPlan splits the memory budget across php-fpm (when profileName
+// is set) and auto-tuned processes without modifying cfg
+func buildMemoryPlan(cfg *config.Config, profileName string, threshold float64) (*autotune.MemoryPlan, error) {
+	finalThreshold, _ := resolveMemoryThreshold(threshold, cfg)
+	opts := autotune.NewPlanOptions(cfg, finalThreshold)
+	consumers := autotune.ProcessConsumers(cfg)
 
+	if profileName == "" {
+		resources, err := autotune.DetectContainerResources()
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect container resources: %w", err)
+		}
+		return autotune.PlanMemory(nil, resources, opts, consumers)
+	}
+
+	calc, err := autotune.NewCalculator(autotune.Profile(profileName), finalThreshold, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calculator: %w", err)
+	}
+	if learningApplyEnabled(cfg) {
+		applyLearnedWorkerMemory(calc, cfg.Global.AutotuneLearning, slog.New(slog.DiscardHandler))
+	}
+	return autotune.PlanMemory(calc, nil, opts, consumers)
+}
+
+// printMemoryPlan prints how the memory budget is split across processes
+func printMemoryPlan(w io.Writer, plan *autotune.MemoryPlan) {
+	for _, line := range strings.Split(strings.TrimSuffix(plan.String(), "\n"), "\n") {
+		fmt.Fprintf(w, "   %s\n", line)
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(w, "   ⚠️  %s\n", warning)
+	}
+}
+
+// learningApplyEnabled reports whether learned worker memory should replace
+// the profile estimate
+func learningApplyEnabled(cfg *config.Config) bool {
+	learning := cfg.Global.AutotuneLearning
+	return learning != nil && learning.Enabled && learning.Apply
+}
+
+// applyLearnedWorkerMemory loads persisted worker memory samples and, once
+// enough were observed, uses the configured percentile for the calculation
+func applyLearnedWorkerMemory(calc *autotune.Calculator, learning *config.LearningConfig, log *slog.Logger) {
+	observer, err := autotune.LoadWorkerMemoryObserver(learning.StatePath, learning.Window)
+	if err != nil {
+		log.Warn("Failed to load learned worker memory, using profile estimate", "error", err)
+		return
+	}
+
+	stats := observer.Stats()
+	if stats.Samples < learning.MinSamples {
+		log.Info("Not enough worker memory samples yet, using profile estimate",
+			"samples", stats.Samples,
+			"min_samples", learning.MinSamples,
+		)
+		return
+	}
+
+	calc.SetObservedWorkerMemory(observer.PercentileMB(learning.Percentile))
+}
+
+// retuneOnReload re-runs auto-tuning on reloaded configurations so tuned
+// process scales survive reloads and php-fpm picks up learned worker memory
+// and profile changes on its next restart
+func retuneOnReload(pm *process.Manager, profileName string) process.ConfigTransform {
+	return func(newCfg *config.Config) error {
+		if profileName == "" {
+			if len(autotune.ProcessConsumers(newCfg)) == 0 {
+				return nil
+			}
+			_, err := runProcessAutoTuning(memoryThreshold, newCfg)
+			return err
+		}
+
+		if learningApplyEnabled(newCfg) {
+			if err := pm.SaveWorkerMemory(); err != nil {
+				slog.Warn("Failed to persist worker memory samples", "error", err)
+			}
+		}
+		calc, tuned, err := runAutoTuning(profileName, memoryThreshold, newCfg)
+		if err != nil {
+			return fmt.Errorf("auto-tuning failed: %w", err)
+		}
+		pm.SetAutotuneResult(calc, tuned)
+		return nil
+	}
+}
+
+// renderPoolConfig writes the php-fpm pool file from auto-tuning results
+// (printed instead of written in dry-run mode)
+func renderPoolConfig(phpfpmCfg *autotune.PHPFPMConfig, pool *config.FPMPoolConfig, log *slog.Logger) error {
+	var warnings []string
+	if dryRun {
+		content, w, err := phpfpmCfg.RenderPoolConfig(pool)
+		if err != nil {
+			return fmt.Errorf("failed to render pool config: %w", err)
+		}
+		warnings = w
+		fmt.Fprintf(os.Stderr, "   Pool config (dry run, not written to %s):\n\n%s", pool.Path, content)
+	} else {
+		w, err := phpfpmCfg.WritePoolConfig(pool)
+		if err != nil {
+			return fmt.Errorf("failed to write pool config: %w", err)
+		}
+		warnings = w
+		fmt.Fprintf(os.Stderr, "   Pool config: %s [%s]\n", pool.Path, pool.Name)
+	}
+
+	for _, warning := range warnings {
+		log.Warn("Pool config warning", "message", warning)
+	}
 	return nil
 }
 
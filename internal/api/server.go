@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"regexp"
 	"runtime/debug"
 	"strconv"
 	"strings"
@@ -18,7 +19,9 @@ import (
 	"github.com/gophpeek/phpeek-pm/internal/acl"
 	"github.com/gophpeek/phpeek-pm/internal/audit"
 	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 	tlsmgr "github.com/gophpeek/phpeek-pm/internal/tls"
 )
 
@@ -260,6 +263,7 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("/api/v1/processes", s.wrapHandler(s.handleProcesses, true))
 	mux.HandleFunc("/api/v1/processes/", s.wrapHandler(s.handleProcessAction, true))
 	mux.HandleFunc("/api/v1/logs", s.wrapHandler(s.handleStackLogs, true))
+	mux.HandleFunc("/api/v1/logging", s.wrapHandler(s.handleLogLevel, true))
 	// Config management endpoints
 	mux.HandleFunc("/api/v1/config/save", s.wrapHandler(s.handleConfigSave, true))
 	mux.HandleFunc("/api/v1/config/reload", s.wrapHandler(s.handleConfigReload, true))
@@ -267,6 +271,10 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("/api/v1/metrics/history", s.wrapHandler(s.handleMetricsHistory, true))
 	// Oneshot history endpoint
 	mux.HandleFunc("/api/v1/oneshot/history", s.wrapHandler(s.handleOneshotHistory, true))
+	// Job chain runs across the oneshot and schedule histories
+	mux.HandleFunc("/api/v1/chains/", s.wrapHandler(s.handleChain, true))
+	// Adaptive auto-tuning report
+	mux.HandleFunc("/api/v1/autotune", s.wrapHandler(s.handleAutotune, true))
 
 	// Wrap mux with ACL middleware if enabled (applied to all routes, TCP only)
 	var tcpHandler http.Handler = mux
@@ -665,6 +673,11 @@ func (s *Server) parseProcessActionPath(path string) (processName, action string
 
 // routeGetRequest routes GET requests to the appropriate handler.
 func (s *Server) routeGetRequest(w http.ResponseWriter, r *http.Request, processName, action string) {
+	if rest, ok := strings.CutPrefix(action, "schedule/history/"); ok {
+		s.handleGetExecutionLogs(w, r, processName, rest)
+		return
+	}
+
 	switch action {
 	case "":
 		s.handleGetProcess(w, r, processName)
@@ -674,6 +687,12 @@ func (s *Server) routeGetRequest(w http.ResponseWriter, r *http.Request, process
 		s.handleGetScheduleStatus(w, r, processName)
 	case "schedule/history":
 		s.handleGetScheduleHistory(w, r, processName)
+	case "schedule/preview":
+		s.handleGetSchedulePreview(w, r, processName)
+	case "logging":
+		s.handleGetLogSettings(w, r, processName)
+	case "crashes":
+		s.handleGetCrashes(w, r, processName)
 	default:
 		s.respondError(w, http.StatusBadRequest, "unknown GET action")
 	}
@@ -701,8 +720,12 @@ func (s *Server) routePostRequest(w http.ResponseWriter, r *http.Request, proces
 		s.handleScheduleResume(w, r, processName)
 	case "schedule/trigger":
 		s.handleScheduleTrigger(w, r, processName)
+	case "logging":
+		s.handleSetLogSettings(w, r, processName)
+	case "logging/reset":
+		s.handleResetLogSettings(w, r, processName)
 	default:
-		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown action: %s (valid: start|stop|restart|scale|schedule/pause|schedule/resume|schedule/trigger)", action))
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown action: %s (valid: start|stop|restart|scale|schedule/pause|schedule/resume|schedule/trigger|logging|logging/reset)", action))
 	}
 }
 
@@ -901,33 +924,247 @@ func (s *Server) handleGetScheduleHistory(w http.ResponseWriter, r *http.Request
 	})
 }
 
+// handleGetExecutionLogs returns the captured output of one execution of a
+// scheduled job. rest is the path after schedule/history/, i.e. "{id}/logs".
+func (s *Server) handleGetExecutionLogs(w http.ResponseWriter, r *http.Request, processName, rest string) {
+	idStr, ok := strings.CutSuffix(rest, "/logs")
+	if !ok {
+		s.respondError(w, http.StatusBadRequest, "unknown GET action")
+		return
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid execution id: %s", idStr))
+		return
+	}
+
+	logs, err := s.manager.GetScheduleOutput(processName, id)
+	if err != nil {
+		s.respondError(w, httpStatusFromError(err), fmt.Sprintf("failed to get execution logs: %v", err))
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"process":   processName,
+		"execution": logs,
+	})
+}
+
+// handleGetSchedulePreview returns the upcoming runs of a scheduled job
+// Supports optional ?count=N (default: 10, max: 1000) and ?from=RFC3339.
+func (s *Server) handleGetSchedulePreview(w http.ResponseWriter, r *http.Request, processName string) {
+	count := schedule.DefaultPreviewCount
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		parsed, err := strconv.Atoi(countStr)
+		if err != nil || parsed < 1 || parsed > schedule.MaxPreviewCount {
+			s.respondError(w, http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %d", schedule.MaxPreviewCount))
+			return
+		}
+		count = parsed
+	}
+
+	var from time.Time
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, "from must be an RFC3339 time (e.g. 2026-03-08T00:00:00Z)")
+			return
+		}
+		from = parsed
+	}
+
+	preview, err := s.manager.PreviewSchedule(processName, count, from)
+	if err != nil {
+		s.respondError(w, httpStatusFromError(err), fmt.Sprintf("failed to preview schedule: %v", err))
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"process": processName,
+		"preview": preview,
+	})
+}
+
 // handleGetLogs retrieves logs for a process
-// Supports optional ?limit=N query parameter (default: 100)
+// Supports optional ?limit=N query parameter (default: 100) plus the search
+// filters understood by parseLogQuery.
 func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request, processName string) {
-	// Parse optional limit query parameter
-	limit := 100 // Default limit
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	query, err := parseLogQuery(r)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	// Get logs from manager
-	logs, err := s.manager.GetLogs(processName, limit)
+	logs, err := s.manager.QueryLogs(processName, query)
 	if err != nil {
 		s.respondError(w, http.StatusNotFound, fmt.Sprintf("failed to get logs: %v", err))
 		return
 	}
 
 	// Return logs as JSON array
-	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"process": processName,
-		"limit":   limit,
+		"limit":   query.Limit,
 		"count":   len(logs),
 		"logs":    logs,
+	}
+	if next := query.NextCursor(logs); next > 0 {
+		response["next_cursor"] = strconv.FormatUint(next, 10)
+	}
+	s.respondJSON(w, http.StatusOK, response)
+}
+
+// handleGetCrashes returns recent crashes of a process with captured output
+// Supports optional ?limit=N query parameter (default: all retained crashes)
+func (s *Server) handleGetCrashes(w http.ResponseWriter, r *http.Request, processName string) {
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	crashes, err := s.manager.GetRecentCrashes(processName, limit)
+	if err != nil {
+		s.respondError(w, httpStatusFromError(err), fmt.Sprintf("failed to get crashes: %v", err))
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"process": processName,
+		"count":   len(crashes),
+		"crashes": crashes,
 	})
 }
 
+// handleGetLogSettings returns the log filtering applied to a process
+func (s *Server) handleGetLogSettings(w http.ResponseWriter, _ *http.Request, processName string) {
+	settings, err := s.manager.GetProcessLogSettings(processName)
+	if err != nil {
+		s.respondError(w, httpStatusFromError(err), fmt.Sprintf("failed to get log settings: %v", err))
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, settings)
+}
+
+// handleSetLogSettings changes a process's min level and filters at runtime
+// Body: {"min_level": "debug", "filters": {"exclude": [...]}, "ttl": "15m"}
+func (s *Server) handleSetLogSettings(w http.ResponseWriter, r *http.Request, processName string) {
+	var req struct {
+		MinLevel string               `json:"min_level"`
+		Filters  *config.FilterConfig `json:"filters"`
+		TTL      string               `json:"ttl"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ttl, err := parseTTL(req.TTL)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	update := process.LogSettingsUpdate{
+		MinLevel: req.MinLevel,
+		Filters:  req.Filters,
+	}
+	settings, err := s.manager.SetProcessLogSettings(processName, update, ttl)
+	if err != nil {
+		status := httpStatusFromError(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadRequest
+		}
+		s.respondError(w, status, fmt.Sprintf("failed to update log settings: %v", err))
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, settings)
+}
+
+// handleResetLogSettings restores the configured log filtering of a process
+func (s *Server) handleResetLogSettings(w http.ResponseWriter, _ *http.Request, processName string) {
+	settings, err := s.manager.ResetProcessLogSettings(processName)
+	if err != nil {
+		s.respondError(w, httpStatusFromError(err), fmt.Sprintf("failed to reset log settings: %v", err))
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, settings)
+}
+
+// handleLogLevel reads or changes the daemon log level
+// GET returns the level, PUT sets it ({"level": "debug", "ttl": "10m"}),
+// DELETE restores the configured level.
+func (s *Server) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.respondJSON(w, http.StatusOK, s.manager.GetLogLevel())
+	case http.MethodPut:
+		var req struct {
+			Level string `json:"level"`
+			TTL   string `json:"ttl"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		ttl, err := parseTTL(req.TTL)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		settings, err := s.manager.SetLogLevel(req.Level, ttl)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.respondJSON(w, http.StatusOK, settings)
+	case http.MethodDelete:
+		s.respondJSON(w, http.StatusOK, s.manager.ResetLogLevel())
+	default:
+		s.respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleAutotune returns observed php-fpm worker memory, its drift from the
+// profile estimate and the recommended max_children
+// GET /api/v1/autotune
+func (s *Server) handleAutotune(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	report, err := s.manager.GetAutotuneReport()
+	if err != nil {
+		s.respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, report)
+}
+
+// parseTTL parses an optional auto-revert duration such as "15m".
+func parseTTL(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid ttl: %s", value)
+	}
+	return ttl, nil
+}
+
 // handleGetProcess returns process configuration details
 func (s *Server) handleGetProcess(w http.ResponseWriter, _ *http.Request, processName string) {
 	cfg, err := s.manager.GetProcessConfig(processName)
@@ -938,7 +1175,7 @@ func (s *Server) handleGetProcess(w http.ResponseWriter, _ *http.Request, proces
 
 	s.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"process": processName,
-		"config":  cfg,
+		"config":  s.manager.Secrets().MaskProcess(processName, cfg),
 	})
 }
 
@@ -949,21 +1186,102 @@ func (s *Server) handleStackLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit := 100
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	query, err := parseLogQuery(r)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	logs := s.manager.GetStackLogs(limit)
+	logs := s.manager.QueryStackLogs(query)
 
-	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"scope": "stack",
-		"limit": limit,
+		"limit": query.Limit,
 		"count": len(logs),
 		"logs":  logs,
-	})
+	}
+	if next := query.NextCursor(logs); next > 0 {
+		response["next_cursor"] = strconv.FormatUint(next, 10)
+	}
+	s.respondJSON(w, http.StatusOK, response)
+}
+
+// parseLogQuery builds a log query from request parameters:
+//
+//	limit    maximum entries (default 100, invalid values fall back to default)
+//	search   case-insensitive substring match on the message
+//	regex    RE2 regular expression matched against the message
+//	level    comma-separated levels (e.g. error,warn)
+//	stream   stdout, stderr, combined or event
+//	instance exact instance ID
+//	since    RFC3339 or Unix timestamp lower bound
+//	until    RFC3339 or Unix timestamp upper bound
+//	cursor   next_cursor value from a previous page
+func parseLogQuery(r *http.Request) (logger.LogQuery, error) {
+	params := r.URL.Query()
+
+	query := logger.LogQuery{
+		Limit:    100,
+		Contains: params.Get("search"),
+		Stream:   params.Get("stream"),
+		Instance: params.Get("instance"),
+	}
+
+	if limitStr := params.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			query.Limit = parsedLimit
+		}
+	}
+
+	if pattern := params.Get("regex"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return query, fmt.Errorf("invalid regex parameter: %v", err)
+		}
+		query.Pattern = re
+	}
+
+	if levels := params.Get("level"); levels != "" {
+		for _, level := range strings.Split(levels, ",") {
+			if level = strings.TrimSpace(level); level != "" {
+				query.Levels = append(query.Levels, level)
+			}
+		}
+	}
+
+	var err error
+	if query.Since, err = parseTimeParam(params.Get("since")); err != nil {
+		return query, fmt.Errorf("invalid since parameter format (use RFC3339 or Unix timestamp)")
+	}
+	if query.Until, err = parseTimeParam(params.Get("until")); err != nil {
+		return query, fmt.Errorf("invalid until parameter format (use RFC3339 or Unix timestamp)")
+	}
+
+	if cursor := params.Get("cursor"); cursor != "" {
+		before, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return query, fmt.Errorf("invalid cursor parameter")
+		}
+		query.Before = before
+	}
+
+	return query, nil
+}
+
+// parseTimeParam parses an RFC3339 or Unix timestamp query value.
+// An empty value yields the zero time.
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	unixTime, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(unixTime, 0), nil
 }
 
 // handleAddProcess adds a new process
@@ -1226,3 +1544,30 @@ func (s *Server) handleOneshotHistory(w http.ResponseWriter, r *http.Request) {
 		"limit":      limit,
 	})
 }
+
+// handleChain returns the runs of a job chain
+// GET /api/v1/chains/{id}
+func (s *Server) handleChain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	chainID := strings.TrimPrefix(r.URL.Path, "/api/v1/chains/")
+	if chainID == "" || strings.Contains(chainID, "/") {
+		s.respondError(w, http.StatusBadRequest, "Chain ID required")
+		return
+	}
+
+	steps := s.manager.GetChainHistory(chainID)
+	if len(steps) == 0 {
+		s.respondError(w, http.StatusNotFound, fmt.Sprintf("Chain %s not found", chainID))
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"chain_id": chainID,
+		"steps":    steps,
+		"count":    len(steps),
+	})
+}
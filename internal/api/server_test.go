@@ -23,6 +23,7 @@ import (
 	"github.com/gophpeek/phpeek-pm/internal/audit"
 	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // createTestManager creates a real manager with minimal config for testing
@@ -2594,6 +2595,24 @@ func TestServer_RouteGetRequest(t *testing.T) {
 			action:         "schedule/history",
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "get schedule preview",
+			processName:    "scheduled-process",
+			action:         "schedule/preview",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get unknown execution logs",
+			processName:    "scheduled-process",
+			action:         "schedule/history/999/logs",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "get execution without logs suffix",
+			processName:    "scheduled-process",
+			action:         "schedule/history/1",
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "unknown action",
 			processName:    "test-process",
@@ -2616,6 +2635,134 @@ func TestServer_RouteGetRequest(t *testing.T) {
 	}
 }
 
+// TestServer_HandleGetSchedulePreview tests the schedule preview endpoint
+func TestServer_HandleGetSchedulePreview(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	mgr := createTestManagerWithSchedule(t)
+	server := NewServer(9180, "", "", nil, nil, false, 0, mgr, logger)
+
+	tests := []struct {
+		name           string
+		processName    string
+		query          string
+		expectedStatus int
+		expectedRuns   int
+	}{
+		{name: "default count", processName: "scheduled-process", expectedStatus: http.StatusOK, expectedRuns: 10},
+		{name: "custom count", processName: "scheduled-process", query: "?count=3", expectedStatus: http.StatusOK, expectedRuns: 3},
+		{name: "from time", processName: "scheduled-process", query: "?count=2&from=2026-03-08T00:00:00Z", expectedStatus: http.StatusOK, expectedRuns: 2},
+		{name: "zero count", processName: "scheduled-process", query: "?count=0", expectedStatus: http.StatusBadRequest},
+		{name: "count too large", processName: "scheduled-process", query: "?count=5000", expectedStatus: http.StatusBadRequest},
+		{name: "invalid from", processName: "scheduled-process", query: "?from=tomorrow", expectedStatus: http.StatusBadRequest},
+		{name: "not scheduled", processName: "test-process", expectedStatus: http.StatusNotFound},
+		{name: "unknown process", processName: "nonexistent", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/processes/"+tt.processName+"/schedule/preview"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			server.handleGetSchedulePreview(w, req, tt.processName)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Process string `json:"process"`
+				Preview struct {
+					Schedule string `json:"schedule"`
+					Runs     []struct {
+						Time string `json:"time"`
+					} `json:"runs"`
+				} `json:"preview"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Preview.Runs) != tt.expectedRuns {
+				t.Errorf("Expected %d runs, got %d", tt.expectedRuns, len(resp.Preview.Runs))
+			}
+			if resp.Preview.Schedule == "" {
+				t.Error("Expected the schedule expression in the preview")
+			}
+		})
+	}
+}
+
+func TestServer_HandleGetExecutionLogs(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	mgr := createTestManagerWithSchedule(t)
+	server := NewServer(9180, "", "", nil, nil, false, 0, mgr, logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := mgr.TriggerScheduleSync(ctx, "scheduled-process"); err != nil {
+		t.Fatalf("TriggerScheduleSync() error = %v", err)
+	}
+	history, err := mgr.GetScheduleHistory("scheduled-process", 1)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("GetScheduleHistory() = %v, %v", history, err)
+	}
+	id := fmt.Sprint(history[0].ID)
+
+	tests := []struct {
+		name           string
+		processName    string
+		rest           string
+		expectedStatus int
+	}{
+		{name: "logs of execution", processName: "scheduled-process", rest: id + "/logs", expectedStatus: http.StatusOK},
+		{name: "unknown execution", processName: "scheduled-process", rest: "999/logs", expectedStatus: http.StatusNotFound},
+		{name: "invalid id", processName: "scheduled-process", rest: "abc/logs", expectedStatus: http.StatusBadRequest},
+		{name: "zero id", processName: "scheduled-process", rest: "0/logs", expectedStatus: http.StatusBadRequest},
+		{name: "unknown process", processName: "nonexistent", rest: "1/logs", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/processes/"+tt.processName+"/schedule/history/"+tt.rest, nil)
+			w := httptest.NewRecorder()
+
+			server.handleGetExecutionLogs(w, req, tt.processName, tt.rest)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Process   string `json:"process"`
+				Execution struct {
+					ExecutionID int64 `json:"execution_id"`
+					Lines       []struct {
+						Stream  string
+						Message string
+					} `json:"lines"`
+				} `json:"execution"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			found := false
+			for _, line := range resp.Execution.Lines {
+				if line.Stream == "stdout" && line.Message == "test" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected the echo output in the execution logs, got %+v", resp.Execution.Lines)
+			}
+		})
+	}
+}
+
 // TestServer_RoutePostRequest tests the POST request router
 func TestServer_RoutePostRequest(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
@@ -4733,3 +4880,337 @@ func generateTestCertificate(certFile, keyFile string) error {
 
 	return nil
 }
+
+// TestServer_HandleLogs_SearchParams tests log search query parsing
+func TestServer_HandleLogs_SearchParams(t *testing.T) {
+	tests := []struct {
+		name           string
+		queryParams    string
+		expectedStatus int
+	}{
+		{"substring search", "?search=exception", http.StatusOK},
+		{"regex search", "?regex=SQLSTATE%5C%5B%5Cw%2B%5C%5D", http.StatusOK},
+		{"invalid regex", "?regex=%28unclosed", http.StatusBadRequest},
+		{"level stream instance", "?level=error,warn&stream=stderr&instance=test-process-0", http.StatusOK},
+		{"since until RFC3339", "?since=2024-01-01T00:00:00Z&until=2024-01-02T00:00:00Z", http.StatusOK},
+		{"since unix", "?since=1700000000", http.StatusOK},
+		{"invalid since", "?since=yesterday", http.StatusBadRequest},
+		{"invalid until", "?until=tomorrow", http.StatusBadRequest},
+		{"cursor", "?cursor=42", http.StatusOK},
+		{"invalid cursor", "?cursor=abc", http.StatusBadRequest},
+	}
+
+	server := createTestServer(t, "", nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/processes/test-process/logs"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+			server.handleGetLogs(w, req, "test-process")
+			if w.Code != tt.expectedStatus {
+				t.Errorf("process logs: expected status %d, got %d (%s)", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			req = httptest.NewRequest(http.MethodGet, "/api/v1/logs"+tt.queryParams, nil)
+			w = httptest.NewRecorder()
+			server.handleStackLogs(w, req)
+			if w.Code != tt.expectedStatus {
+				t.Errorf("stack logs: expected status %d, got %d (%s)", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestParseLogQuery tests conversion of query parameters into a log query
+func TestParseLogQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/logs?limit=25&search=Boom&regex=%5Eerr&level=error,+warn&stream=stderr&instance=web-0&since=1700000000&cursor=99", nil)
+
+	query, err := parseLogQuery(req)
+	if err != nil {
+		t.Fatalf("parseLogQuery() error = %v", err)
+	}
+
+	if query.Limit != 25 {
+		t.Errorf("Limit = %d, want 25", query.Limit)
+	}
+	if query.Contains != "Boom" {
+		t.Errorf("Contains = %q, want Boom", query.Contains)
+	}
+	if query.Pattern == nil || query.Pattern.String() != "^err" {
+		t.Errorf("Pattern = %v, want ^err", query.Pattern)
+	}
+	if len(query.Levels) != 2 || query.Levels[0] != "error" || query.Levels[1] != "warn" {
+		t.Errorf("Levels = %v, want [error warn]", query.Levels)
+	}
+	if query.Stream != "stderr" || query.Instance != "web-0" {
+		t.Errorf("Stream/Instance = %q/%q", query.Stream, query.Instance)
+	}
+	if !query.Since.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Since = %v", query.Since)
+	}
+	if query.Before != 99 {
+		t.Errorf("Before = %d, want 99", query.Before)
+	}
+}
+
+// TestServer_ProcessLogSettings tests runtime log settings for a process
+func TestServer_ProcessLogSettings(t *testing.T) {
+	server := createTestServer(t, "", nil)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedLevel  string
+	}{
+		{"get settings", http.MethodGet, "/api/v1/processes/test-process/logging", "", http.StatusOK, "info"},
+		{"set debug with ttl", http.MethodPost, "/api/v1/processes/test-process/logging", `{"min_level":"debug","ttl":"10m"}`, http.StatusOK, "debug"},
+		{"set filters", http.MethodPost, "/api/v1/processes/test-process/logging", `{"filters":{"exclude":["health"]}}`, http.StatusOK, "debug"},
+		{"reset", http.MethodPost, "/api/v1/processes/test-process/logging/reset", "", http.StatusOK, "info"},
+		{"invalid level", http.MethodPost, "/api/v1/processes/test-process/logging", `{"min_level":"verbose"}`, http.StatusBadRequest, ""},
+		{"invalid pattern", http.MethodPost, "/api/v1/processes/test-process/logging", `{"filters":{"include":["("]}}`, http.StatusBadRequest, ""},
+		{"invalid ttl", http.MethodPost, "/api/v1/processes/test-process/logging", `{"min_level":"debug","ttl":"soon"}`, http.StatusBadRequest, ""},
+		{"invalid body", http.MethodPost, "/api/v1/processes/test-process/logging", `{`, http.StatusBadRequest, ""},
+		{"unknown process", http.MethodGet, "/api/v1/processes/missing/logging", "", http.StatusNotFound, ""},
+		{"unknown process set", http.MethodPost, "/api/v1/processes/missing/logging", `{"min_level":"debug"}`, http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.handleProcessAction(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedLevel == "" {
+				return
+			}
+
+			var settings process.LogSettings
+			if err := json.NewDecoder(w.Body).Decode(&settings); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if settings.MinLevel != tt.expectedLevel {
+				t.Errorf("MinLevel = %q, want %q", settings.MinLevel, tt.expectedLevel)
+			}
+		})
+	}
+}
+
+// TestServer_HandleLogLevel tests reading and changing the daemon log level
+func TestServer_HandleLogLevel(t *testing.T) {
+	server := createTestServer(t, "", nil)
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedLevel  string
+	}{
+		{"reset to configured", http.MethodDelete, "", http.StatusOK, "error"},
+		{"get level", http.MethodGet, "", http.StatusOK, "error"},
+		{"set debug", http.MethodPut, `{"level":"debug","ttl":"5m"}`, http.StatusOK, "debug"},
+		{"reset", http.MethodDelete, "", http.StatusOK, "error"},
+		{"invalid level", http.MethodPut, `{"level":"loud"}`, http.StatusBadRequest, ""},
+		{"negative ttl", http.MethodPut, `{"level":"debug","ttl":"-1m"}`, http.StatusBadRequest, ""},
+		{"invalid body", http.MethodPut, `nope`, http.StatusBadRequest, ""},
+		{"method not allowed", http.MethodPost, "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/logging", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.handleLogLevel(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedLevel == "" {
+				return
+			}
+
+			var settings process.LogLevelSettings
+			if err := json.NewDecoder(w.Body).Decode(&settings); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if settings.Level != tt.expectedLevel {
+				t.Errorf("Level = %q, want %q", settings.Level, tt.expectedLevel)
+			}
+		})
+	}
+}
+
+// TestServer_HandleGetCrashes tests the recent crashes endpoint
+func TestServer_HandleGetCrashes(t *testing.T) {
+	server := createTestServer(t, "", nil)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"known process", "/api/v1/processes/test-process/crashes", http.StatusOK},
+		{"with limit", "/api/v1/processes/test-process/crashes?limit=5", http.StatusOK},
+		{"invalid limit falls back", "/api/v1/processes/test-process/crashes?limit=abc", http.StatusOK},
+		{"unknown process", "/api/v1/processes/missing/crashes", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			server.handleProcessAction(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Process string                `json:"process"`
+				Count   int                   `json:"count"`
+				Crashes []process.CrashRecord `json:"crashes"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Process != "test-process" || response.Count != 0 || response.Crashes == nil {
+				t.Errorf("unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+// TestServer_HandleAutotune tests the adaptive auto-tuning report endpoint
+func TestServer_HandleAutotune(t *testing.T) {
+	t.Run("learning disabled", func(t *testing.T) {
+		server := createTestServer(t, "", nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/autotune", nil)
+		w := httptest.NewRecorder()
+		server.handleAutotune(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("learning enabled", func(t *testing.T) {
+		cfg := &config.Config{
+			Global: config.GlobalConfig{
+				ShutdownTimeout:           30,
+				ResourceMetricsInterval:   5,
+				ResourceMetricsMaxSamples: 10,
+				AutotuneLearning: &config.LearningConfig{
+					Enabled:    true,
+					Process:    "php-fpm",
+					StatePath:  t.TempDir() + "/learning.json",
+					Percentile: 95,
+					MinSamples: 100,
+					Window:     1000,
+					Apply:      true,
+				},
+			},
+			Processes: map[string]*config.Process{},
+		}
+		cfg.Global.SetResourceMetricsEnabled(true)
+
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+		mgr := process.NewManager(cfg, logger, audit.NewLogger(logger, false))
+		t.Cleanup(func() { _ = mgr.Shutdown(context.Background()) })
+		server := NewServer(9180, "", "", nil, nil, false, 0, mgr, logger)
+
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			req := httptest.NewRequest(method, "/api/v1/autotune", nil)
+			w := httptest.NewRecorder()
+			server.handleAutotune(w, req)
+
+			if method == http.MethodPost {
+				if w.Code != http.StatusMethodNotAllowed {
+					t.Errorf("POST: expected status 405, got %d", w.Code)
+				}
+				continue
+			}
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d (%s)", w.Code, w.Body.String())
+			}
+
+			var report struct {
+				Process    string `json:"process"`
+				Ready      bool   `json:"ready"`
+				Apply      bool   `json:"apply"`
+				MinSamples int    `json:"min_samples"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if report.Process != "php-fpm" || report.Ready || !report.Apply || report.MinSamples != 100 {
+				t.Errorf("unexpected report: %+v", report)
+			}
+		}
+	})
+}
+
+// TestServer_Chain tests the job chain endpoint
+func TestServer_Chain(t *testing.T) {
+	server := createTestServer(t, "", nil)
+
+	chain := schedule.NewChain("extract")
+	history := server.manager.GetOneshotHistory()
+	id := history.Record("extract", "extract-0", "startup")
+	history.SetChain(id, chain)
+	history.Complete(id, 0, nil)
+	id = history.Record("transform", "transform-0", schedule.TriggeredChain)
+	history.SetChain(id, chain.Next("extract"))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedSteps  int
+	}{
+		{"get chain", http.MethodGet, "/api/v1/chains/" + chain.ID, http.StatusOK, 2},
+		{"unknown chain", http.MethodGet, "/api/v1/chains/missing-1", http.StatusNotFound, 0},
+		{"missing id", http.MethodGet, "/api/v1/chains/", http.StatusBadRequest, 0},
+		{"post not allowed", http.MethodPost, "/api/v1/chains/" + chain.ID, http.StatusMethodNotAllowed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			server.handleChain(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				ChainID string              `json:"chain_id"`
+				Steps   []process.ChainStep `json:"steps"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.ChainID != chain.ID || len(resp.Steps) != tt.expectedSteps {
+				t.Fatalf("Expected %d steps of chain %s, got %+v", tt.expectedSteps, chain.ID, resp)
+			}
+			if resp.Steps[0].Process != "extract" || resp.Steps[1].TriggeredBy != "extract" || !resp.Steps[1].Running {
+				t.Errorf("Unexpected steps: %+v", resp.Steps)
+			}
+		})
+	}
+}
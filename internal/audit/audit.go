@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 )
 
@@ -75,10 +76,18 @@ type Event struct {
 	Context   map[string]interface{} `json:"context,omitempty"`
 }
 
+// Masker hides sensitive values, such as resolved config secrets, in text
+type Masker interface {
+	Mask(text string) string
+}
+
 // Logger provides structured audit logging
 type Logger struct {
 	logger  *slog.Logger
 	enabled bool
+
+	maskMu sync.RWMutex
+	masker Masker // Applied to the strings of every event (nil = none)
 }
 
 // NewLogger creates a new audit logger
@@ -89,12 +98,26 @@ func NewLogger(log *slog.Logger, enabled bool) *Logger {
 	}
 }
 
+// SetMasker sets the masker applied to events before they are logged
+func (l *Logger) SetMasker(masker Masker) {
+	l.maskMu.Lock()
+	defer l.maskMu.Unlock()
+	l.masker = masker
+}
+
 // Log logs an audit event
 func (l *Logger) Log(event Event) {
 	if !l.enabled {
 		return
 	}
 
+	l.maskMu.RLock()
+	masker := l.masker
+	l.maskMu.RUnlock()
+	if masker != nil {
+		event = maskEvent(event, masker)
+	}
+
 	// Set timestamp if not provided
 	if event.Timestamp.IsZero() {
 		event.Timestamp = time.Now()
@@ -128,6 +151,33 @@ func (l *Logger) Log(event Event) {
 	}
 }
 
+// maskEvent returns event with masker applied to its strings
+func maskEvent(event Event, masker Masker) Event {
+	event.Actor.ID = masker.Mask(event.Actor.ID)
+	event.Resource.ID = masker.Mask(event.Resource.ID)
+	event.Resource.Name = masker.Mask(event.Resource.Name)
+	event.Message = masker.Mask(event.Message)
+	if event.Context != nil {
+		context := make(map[string]interface{}, len(event.Context))
+		for key, value := range event.Context {
+			switch v := value.(type) {
+			case string:
+				context[key] = masker.Mask(v)
+			case []string:
+				masked := make([]string, len(v))
+				for i, s := range v {
+					masked[i] = masker.Mask(s)
+				}
+				context[key] = masked
+			default:
+				context[key] = value
+			}
+		}
+		event.Context = context
+	}
+	return event
+}
+
 // LogAPIRequest logs an API request
 func (l *Logger) LogAPIRequest(ip, method, path, auth string) {
 	l.Log(Event{
@@ -287,7 +337,26 @@ func (l *Logger) LogProcessStop(processName string, pid int, reason string) {
 
 // LogProcessCrash logs process crash
 func (l *Logger) LogProcessCrash(processName string, pid int, exitCode int, signal string) {
-	l.Log(Event{
+	l.LogProcessCrashWithOutput(processName, pid, exitCode, signal, nil)
+}
+
+// LogProcessCrashWithOutput logs process crash with the last output lines
+// captured from the dying instance
+func (l *Logger) LogProcessCrashWithOutput(processName string, pid int, exitCode int, signal string, lastOutput []string) {
+	l.Log(processCrashEvent(processName, pid, exitCode, signal, lastOutput))
+}
+
+// LogProcessOOMKill logs a process crash attributed to the kernel OOM killer
+func (l *Logger) LogProcessOOMKill(processName string, pid int, exitCode int, signal string, lastOutput []string) {
+	event := processCrashEvent(processName, pid, exitCode, signal, lastOutput)
+	event.Message = "Process killed by the OOM killer"
+	event.Context["oom_killed"] = true
+	l.Log(event)
+}
+
+// processCrashEvent builds a process crash event
+func processCrashEvent(processName string, pid int, exitCode int, signal string, lastOutput []string) Event {
+	event := Event{
 		EventType: EventProcessCrash,
 		Actor: Actor{
 			Type: "system",
@@ -306,7 +375,11 @@ func (l *Logger) LogProcessCrash(processName string, pid int, exitCode int, sign
 			"exit_code": exitCode,
 			"signal":    signal,
 		},
-	})
+	}
+	if len(lastOutput) > 0 {
+		event.Context["last_output"] = lastOutput
+	}
+	return event
 }
 
 // LogProcessRestart logs process restart
@@ -221,6 +221,74 @@ func TestLogger_ProcessCrash(t *testing.T) {
 	}
 }
 
+// TestLogger_ProcessCrashWithOutput tests crash audit logging with captured output
+func TestLogger_ProcessCrashWithOutput(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(handler)
+
+	auditLogger := NewLogger(logger, true)
+	auditLogger.LogProcessCrashWithOutput("horizon", 9999, 255, "exit status 255", []string{
+		"[stdout] Processing job",
+		"[stderr] PHP Fatal error: Allowed memory size exhausted",
+	})
+
+	var logEntry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to parse log output: %v", err)
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(logEntry["event_json"].(string)), &event); err != nil {
+		t.Fatalf("Failed to parse event_json: %v", err)
+	}
+
+	lastOutput, ok := event.Context["last_output"].([]interface{})
+	if !ok || len(lastOutput) != 2 {
+		t.Fatalf("Expected 2 last_output lines, got: %v", event.Context["last_output"])
+	}
+	if !strings.Contains(lastOutput[1].(string), "Allowed memory size") {
+		t.Errorf("Unexpected last_output: %v", lastOutput)
+	}
+
+	// Without output the context key is omitted
+	buf.Reset()
+	auditLogger.LogProcessCrash("horizon", 9999, 1, "")
+	if strings.Contains(buf.String(), "last_output") {
+		t.Errorf("Expected no last_output without captured lines, got: %s", buf.String())
+	}
+}
+
+// TestLogger_ProcessOOMKill tests that OOM kills are logged as tagged crashes
+func TestLogger_ProcessOOMKill(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(handler)
+
+	auditLogger := NewLogger(logger, true)
+	auditLogger.LogProcessOOMKill("queue", 4242, -1, "signal: killed", nil)
+
+	var logEntry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("Failed to parse log output: %v", err)
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(logEntry["event_json"].(string)), &event); err != nil {
+		t.Fatalf("Failed to parse event_json: %v", err)
+	}
+
+	if event.EventType != EventProcessCrash {
+		t.Errorf("Expected event type %s, got %s", EventProcessCrash, event.EventType)
+	}
+	if event.Context["oom_killed"] != true {
+		t.Errorf("Expected oom_killed=true, got: %v", event.Context["oom_killed"])
+	}
+	if event.Message != "Process killed by the OOM killer" {
+		t.Errorf("Unexpected message: %s", event.Message)
+	}
+}
+
 // TestLogger_ProcessRestart tests process restart audit logging
 func TestLogger_ProcessRestart(t *testing.T) {
 	var buf bytes.Buffer
@@ -813,3 +881,32 @@ func TestLogger_ConfigReloaded(t *testing.T) {
 		t.Errorf("Expected event_json to contain 'reloaded', got: %s", eventJSON)
 	}
 }
+
+// replaceMasker masks one value for tests
+type replaceMasker struct{ secret string }
+
+func (m replaceMasker) Mask(text string) string {
+	return strings.ReplaceAll(text, m.secret, "***")
+}
+
+// TestLogger_Masker tests that secrets are masked in logged events
+func TestLogger_Masker(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	auditLogger := NewLogger(logger, true)
+	auditLogger.SetMasker(replaceMasker{secret: "hunter2"})
+
+	command := []string{"php", "worker.php", "--password=hunter2"}
+	auditLogger.LogProcessAdded("worker", command, 1)
+
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("secret leaked into audit log: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "--password=***") {
+		t.Errorf("expected masked command, got: %s", buf.String())
+	}
+	if command[2] != "--password=hunter2" {
+		t.Error("masking must not modify the caller's values")
+	}
+}
@@ -23,6 +23,8 @@ type PHPFPMConfig struct {
 	MemoryReserved  int // MB reserved for system/Nginx
 	MemoryTotal     int // MB total available
 	CPUs            int
+	WorkerMemory    int  // MB per worker used for the calculation
+	Observed        bool // WorkerMemory comes from observed worker RSS
 	Warnings        []string
 }
 
@@ -31,6 +33,8 @@ type Calculator struct {
 	resources       *ContainerResources
 	profile         ProfileConfig
 	memoryThreshold float64 // Override for profile.MaxMemoryUsage (0.0 = use profile default)
+	workerMemoryMB  int     // Observed per-worker memory (0 = use profile.AvgMemoryPerWorker)
+	workerBudgetMB  int     // Share of the worker pool for php-fpm (0 = whole pool, see PlanMemory)
 	logger          *slog.Logger
 }
 
@@ -54,14 +58,66 @@ func NewCalculator(profile Profile, memoryThreshold float64, logger *slog.Logger
 	}, nil
 }
 
+// SetObservedWorkerMemory replaces the profile's per-worker memory estimate
+// with an observed value in MB (0 restores the profile estimate)
+func (c *Calculator) SetObservedWorkerMemory(mb int) {
+	c.workerMemoryMB = mb
+}
+
+// ProfileWorkerMemory returns the profile's static per-worker memory estimate in MB
+func (c *Calculator) ProfileWorkerMemory() int {
+	return c.profile.AvgMemoryPerWorker
+}
+
+// SetWorkerBudget limits php-fpm workers to a share of the worker memory pool
+// in MB (0 restores the whole pool)
+func (c *Calculator) SetWorkerBudget(mb int) {
+	c.workerBudgetMB = mb
+}
+
+// Threshold returns the effective memory threshold (override or profile default)
+func (c *Calculator) Threshold() float64 {
+	if c.memoryThreshold > 0 {
+		return c.memoryThreshold
+	}
+	return c.profile.MaxMemoryUsage
+}
+
+// ReservedMemory returns memory kept out of the worker pool in MB
+// (system/Nginx reserve plus shared OPcache)
+func (c *Calculator) ReservedMemory() int {
+	return c.profile.ReservedMemoryMB + c.profile.OPcacheMemoryMB
+}
+
+// Resources returns the detected container resources
+func (c *Calculator) Resources() *ContainerResources {
+	return c.resources
+}
+
+// Recommend calculates the configuration for an observed per-worker memory
+// without logging or modifying the calculator
+func (c *Calculator) Recommend(workerMemoryMB int) (*PHPFPMConfig, error) {
+	quiet := *c
+	quiet.workerMemoryMB = workerMemoryMB
+	quiet.logger = slog.New(slog.DiscardHandler)
+	return quiet.Calculate()
+}
+
 // Calculate computes optimal PHP-FPM configuration with safety validations
 func (c *Calculator) Calculate() (*PHPFPMConfig, error) {
+	perWorker := c.profile.AvgMemoryPerWorker
+	if c.workerMemoryMB > 0 {
+		perWorker = c.workerMemoryMB
+	}
+
 	cfg := &PHPFPMConfig{
 		Profile:        Profile(c.profile.Name),
 		MemoryTotal:    c.resources.MemoryLimitMB,
 		MemoryOPcache:  c.profile.OPcacheMemoryMB,
 		MemoryReserved: c.profile.ReservedMemoryMB,
 		CPUs:           c.resources.CPULimit,
+		WorkerMemory:   perWorker,
+		Observed:       c.workerMemoryMB > 0,
 		Warnings:       []string{},
 	}
 
@@ -81,17 +137,16 @@ func (c *Calculator) Calculate() (*PHPFPMConfig, error) {
 	}
 
 	// Validate minimum memory requirement
-	minRequired := c.profile.ReservedMemoryMB + c.profile.OPcacheMemoryMB + c.profile.AvgMemoryPerWorker
+	minRequired := c.profile.ReservedMemoryMB + c.profile.OPcacheMemoryMB + perWorker
 	if c.resources.MemoryLimitMB < minRequired {
 		return nil, fmt.Errorf("insufficient memory: %dMB (minimum %dMB required: %dMB reserved + %dMB OPcache + %dMB per worker)",
-			c.resources.MemoryLimitMB, minRequired, c.profile.ReservedMemoryMB, c.profile.OPcacheMemoryMB, c.profile.AvgMemoryPerWorker)
+			c.resources.MemoryLimitMB, minRequired, c.profile.ReservedMemoryMB, c.profile.OPcacheMemoryMB, perWorker)
 	}
 
 	// Determine memory threshold (allow override of profile default)
-	threshold := c.profile.MaxMemoryUsage
+	threshold := c.Threshold()
 	thresholdSource := "profile"
 	if c.memoryThreshold > 0 {
-		threshold = c.memoryThreshold
 		thresholdSource = "override"
 
 		// WARNING: Oversubscription (>1.0) is dangerous but allowed for experts
@@ -112,8 +167,12 @@ func (c *Calculator) Calculate() (*PHPFPMConfig, error) {
 	// Calculate available memory for PHP-FPM workers
 	// Formula: (Total × Threshold%) - Reserved - OPcache (shared) = Worker Memory Pool
 	availableMemory := int(float64(c.resources.MemoryLimitMB) * threshold)
-	totalReserved := c.profile.ReservedMemoryMB + c.profile.OPcacheMemoryMB
+	totalReserved := c.ReservedMemory()
 	workerMemory := availableMemory - totalReserved
+	if c.workerBudgetMB > 0 && c.workerBudgetMB < workerMemory {
+		// Remaining pool is shared with other processes (see PlanMemory)
+		workerMemory = c.workerBudgetMB
+	}
 
 	c.logger.Debug("Memory calculation",
 		"threshold", fmt.Sprintf("%.1f%%", threshold*100),
@@ -124,13 +183,13 @@ func (c *Calculator) Calculate() (*PHPFPMConfig, error) {
 		"worker_pool", workerMemory,
 	)
 
-	if workerMemory < c.profile.AvgMemoryPerWorker {
+	if workerMemory < perWorker {
 		return nil, fmt.Errorf("insufficient memory for workers: %dMB available after reserving %dMB (system: %dMB + OPcache: %dMB), need at least %dMB per worker",
-			workerMemory, totalReserved, c.profile.ReservedMemoryMB, c.profile.OPcacheMemoryMB, c.profile.AvgMemoryPerWorker)
+			workerMemory, totalReserved, c.profile.ReservedMemoryMB, c.profile.OPcacheMemoryMB, perWorker)
 	}
 
 	// Calculate max_children based on available memory
-	maxChildren := workerMemory / c.profile.AvgMemoryPerWorker
+	maxChildren := workerMemory / perWorker
 
 	// Apply CPU-based limit: max 4 workers per CPU core (industry standard)
 	cpuBasedMax := c.resources.CPULimit * 4
@@ -164,7 +223,7 @@ func (c *Calculator) Calculate() (*PHPFPMConfig, error) {
 	cfg.MaxChildren = maxChildren
 	cfg.ProcessManager = c.profile.ProcessManagerType
 	cfg.MaxRequests = c.profile.MaxRequestsPerChild
-	cfg.MemoryAllocated = maxChildren * c.profile.AvgMemoryPerWorker
+	cfg.MemoryAllocated = maxChildren * perWorker
 
 	// Calculate dynamic PM settings
 	if cfg.ProcessManager == "dynamic" {
@@ -243,7 +302,8 @@ func (c *Calculator) logCalculation(cfg *PHPFPMConfig) {
 		"memory_reserved", fmt.Sprintf("%dMB", cfg.MemoryReserved),
 		"memory_total", fmt.Sprintf("%dMB", cfg.MemoryTotal),
 		"cpus", cfg.CPUs,
-		"avg_memory_per_worker", fmt.Sprintf("%dMB", c.profile.AvgMemoryPerWorker),
+		"avg_memory_per_worker", fmt.Sprintf("%dMB", cfg.WorkerMemory),
+		"observed", cfg.Observed,
 		"warnings", len(cfg.Warnings),
 	)
 
@@ -0,0 +1,248 @@
+package autotune
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+var (
+	// customProfiles holds profiles defined in global.autotune.profiles.
+	// They take precedence over built-in profiles of the same name.
+	customProfiles   = map[Profile]ProfileConfig{}
+	customProfilesMu sync.RWMutex
+
+	// profileNamePattern restricts custom profile names to env/CLI friendly values
+	profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+)
+
+// lookupProfile returns a custom or built-in profile
+func lookupProfile(p Profile) (ProfileConfig, bool) {
+	customProfilesMu.RLock()
+	cfg, ok := customProfiles[p]
+	customProfilesMu.RUnlock()
+	if ok {
+		return cfg, true
+	}
+	cfg, ok = Profiles[p]
+	return cfg, ok
+}
+
+// AvailableProfiles returns the names of all selectable profiles, built-in first
+func AvailableProfiles() []string {
+	names := builtinProfileNames()
+
+	customProfilesMu.RLock()
+	var custom []string
+	for name := range customProfiles {
+		if _, builtin := Profiles[name]; !builtin {
+			custom = append(custom, string(name))
+		}
+	}
+	customProfilesMu.RUnlock()
+
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// LoadCustomProfiles resolves profiles from global.autotune.profiles and makes
+// them selectable via Profile. Each profile inherits from its extends target
+// (a built-in or another custom profile); a profile named like a built-in one
+// without extends overrides that built-in. Previously loaded custom profiles
+// are replaced; nothing changes if any profile is invalid.
+func LoadCustomProfiles(defs map[string]*config.AutotuneProfile) error {
+	resolved, err := ResolveProfiles(defs)
+	if err != nil {
+		return err
+	}
+
+	customProfilesMu.Lock()
+	customProfiles = resolved
+	customProfilesMu.Unlock()
+	return nil
+}
+
+// ResolveProfiles resolves and validates custom profile definitions without
+// registering them
+func ResolveProfiles(defs map[string]*config.AutotuneProfile) (map[Profile]ProfileConfig, error) {
+	r := &profileResolver{
+		defs:     defs,
+		resolved: make(map[Profile]ProfileConfig, len(defs)),
+		visiting: make(map[string]bool),
+	}
+
+	// Sorted for deterministic error messages
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := r.resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return r.resolved, nil
+}
+
+// profileResolver resolves extends chains with cycle detection
+type profileResolver struct {
+	defs     map[string]*config.AutotuneProfile
+	resolved map[Profile]ProfileConfig
+	visiting map[string]bool
+}
+
+func (r *profileResolver) resolve(name string, chain []string) (ProfileConfig, error) {
+	if cfg, ok := r.resolved[Profile(name)]; ok {
+		return cfg, nil
+	}
+
+	def, ok := r.defs[name]
+	if !ok || def == nil {
+		return ProfileConfig{}, fmt.Errorf("autotune profile %s: empty definition", name)
+	}
+	if !profileNamePattern.MatchString(name) {
+		return ProfileConfig{}, fmt.Errorf("autotune profile %s: invalid name (use lowercase letters, digits, '-' and '_')", name)
+	}
+
+	chain = append(chain, name)
+	if r.visiting[name] {
+		return ProfileConfig{}, fmt.Errorf("autotune profile %s: circular extends (%s)", name, strings.Join(chain, " -> "))
+	}
+	r.visiting[name] = true
+	defer delete(r.visiting, name)
+
+	base, err := r.base(name, def.Extends, chain)
+	if err != nil {
+		return ProfileConfig{}, err
+	}
+
+	cfg := applyProfileOverrides(base, def)
+	cfg.Name = name
+	if def.Description != "" {
+		cfg.Description = def.Description
+	} else {
+		cfg.Description = fmt.Sprintf("Custom profile based on %s", base.Name)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return ProfileConfig{}, fmt.Errorf("autotune profile %s: %w", name, err)
+	}
+
+	r.resolved[Profile(name)] = cfg
+	return cfg, nil
+}
+
+// base returns the profile a definition inherits from
+func (r *profileResolver) base(name, extends string, chain []string) (ProfileConfig, error) {
+	if extends == "" {
+		builtin, ok := Profiles[Profile(name)]
+		if !ok {
+			return ProfileConfig{}, fmt.Errorf("autotune profile %s: extends is required for new profiles (built-in: %s)",
+				name, strings.Join(builtinProfileNames(), ", "))
+		}
+		return builtin, nil
+	}
+
+	// Extending a built-in by its own name refers to the built-in itself
+	if _, custom := r.defs[extends]; custom && extends != name {
+		return r.resolve(extends, chain)
+	}
+	if builtin, ok := Profiles[Profile(extends)]; ok {
+		return builtin, nil
+	}
+	if extends == name {
+		return ProfileConfig{}, fmt.Errorf("autotune profile %s: cannot extend itself", name)
+	}
+	return ProfileConfig{}, fmt.Errorf("autotune profile %s: extends unknown profile %s", name, extends)
+}
+
+// applyProfileOverrides returns base with all fields set in def applied
+func applyProfileOverrides(base ProfileConfig, def *config.AutotuneProfile) ProfileConfig {
+	cfg := base
+	if def.ProcessManager != "" {
+		cfg.ProcessManagerType = def.ProcessManager
+	}
+	setInt := func(dst *int, src *int) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setFloat := func(dst *float64, src *float64) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setInt(&cfg.AvgMemoryPerWorker, def.AvgMemoryPerWorker)
+	setInt(&cfg.OPcacheMemoryMB, def.OPcacheMemoryMB)
+	setInt(&cfg.ReservedMemoryMB, def.ReservedMemoryMB)
+	setInt(&cfg.MinWorkers, def.MinWorkers)
+	setInt(&cfg.MaxWorkers, def.MaxWorkers)
+	setFloat(&cfg.SpareMinRatio, def.SpareMinRatio)
+	setFloat(&cfg.SpareMaxRatio, def.SpareMaxRatio)
+	setFloat(&cfg.StartServersRatio, def.StartServersRatio)
+	setInt(&cfg.MaxRequestsPerChild, def.MaxRequestsPerChild)
+	setFloat(&cfg.MaxMemoryUsage, def.MaxMemoryUsage)
+	return cfg
+}
+
+// builtinProfileNames returns the built-in profile names in order of size
+func builtinProfileNames() []string {
+	return []string{string(ProfileDev), string(ProfileLight), string(ProfileMedium), string(ProfileHeavy), string(ProfileBursty)}
+}
+
+// Validate checks that a profile produces a consistent PHP-FPM configuration
+func (pc ProfileConfig) Validate() error {
+	switch pc.ProcessManagerType {
+	case "static", "dynamic", "ondemand":
+	default:
+		return fmt.Errorf("process_manager must be static, dynamic or ondemand (got %q)", pc.ProcessManagerType)
+	}
+
+	if pc.AvgMemoryPerWorker <= 0 {
+		return fmt.Errorf("avg_memory_per_worker must be positive (got %d)", pc.AvgMemoryPerWorker)
+	}
+	if pc.OPcacheMemoryMB < 0 || pc.ReservedMemoryMB < 0 {
+		return fmt.Errorf("opcache_memory_mb and reserved_memory_mb must not be negative")
+	}
+	if pc.MinWorkers < 1 {
+		return fmt.Errorf("min_workers must be at least 1 (got %d)", pc.MinWorkers)
+	}
+	if pc.MaxWorkers < 0 {
+		return fmt.Errorf("max_workers must not be negative (got %d)", pc.MaxWorkers)
+	}
+	if pc.MaxWorkers > 0 && pc.MaxWorkers < pc.MinWorkers {
+		return fmt.Errorf("max_workers (%d) must be 0 (auto) or at least min_workers (%d)", pc.MaxWorkers, pc.MinWorkers)
+	}
+	if pc.MaxRequestsPerChild < 0 {
+		return fmt.Errorf("max_requests_per_child must not be negative (got %d)", pc.MaxRequestsPerChild)
+	}
+	if pc.MaxMemoryUsage <= 0 || pc.MaxMemoryUsage > 1 {
+		return fmt.Errorf("max_memory_usage must be between 0 and 1 (got %.2f)", pc.MaxMemoryUsage)
+	}
+
+	for field, ratio := range map[string]float64{
+		"spare_min_ratio":     pc.SpareMinRatio,
+		"spare_max_ratio":     pc.SpareMaxRatio,
+		"start_servers_ratio": pc.StartServersRatio,
+	} {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("%s must be between 0 and 1 (got %.2f)", field, ratio)
+		}
+	}
+	if pc.ProcessManagerType == "dynamic" {
+		if pc.SpareMaxRatio == 0 || pc.StartServersRatio == 0 {
+			return fmt.Errorf("dynamic process manager requires spare_max_ratio and start_servers_ratio above 0")
+		}
+		if pc.SpareMinRatio > pc.SpareMaxRatio {
+			return fmt.Errorf("spare_min_ratio (%.2f) must not exceed spare_max_ratio (%.2f)", pc.SpareMinRatio, pc.SpareMaxRatio)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package autotune
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+// loadTestProfiles registers custom profiles and removes them after the test
+func loadTestProfiles(t *testing.T, defs map[string]*config.AutotuneProfile) error {
+	t.Helper()
+	t.Cleanup(func() { _ = LoadCustomProfiles(nil) })
+	return LoadCustomProfiles(defs)
+}
+
+func TestBuiltinProfilesValid(t *testing.T) {
+	for name, cfg := range Profiles {
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("built-in profile %s invalid: %v", name, err)
+		}
+	}
+}
+
+func TestLoadCustomProfiles_Extends(t *testing.T) {
+	err := loadTestProfiles(t, map[string]*config.AutotuneProfile{
+		"symfony-api": {
+			Extends:            "medium",
+			AvgMemoryPerWorker: intPtr(64),
+			MaxWorkers:         intPtr(16),
+		},
+		"symfony-api-static": {
+			Extends:        "symfony-api",
+			ProcessManager: "static",
+			Description:    "Fixed pool for benchmarks",
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoadCustomProfiles() error = %v", err)
+	}
+
+	cfg, err := Profile("symfony-api").GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	medium := Profiles[ProfileMedium]
+	if cfg.AvgMemoryPerWorker != 64 || cfg.MaxWorkers != 16 {
+		t.Errorf("overrides not applied: %+v", cfg)
+	}
+	if cfg.OPcacheMemoryMB != medium.OPcacheMemoryMB || cfg.MaxRequestsPerChild != medium.MaxRequestsPerChild {
+		t.Errorf("inherited fields lost: %+v", cfg)
+	}
+	if cfg.Name != "symfony-api" || !strings.Contains(cfg.Description, "Medium") {
+		t.Errorf("unexpected name/description: %q / %q", cfg.Name, cfg.Description)
+	}
+
+	chained, err := Profile("symfony-api-static").GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	if chained.ProcessManagerType != "static" || chained.AvgMemoryPerWorker != 64 || chained.Description != "Fixed pool for benchmarks" {
+		t.Errorf("chained profile not resolved: %+v", chained)
+	}
+
+	available := AvailableProfiles()
+	if len(available) != 7 || available[0] != "dev" || available[5] != "symfony-api" {
+		t.Errorf("AvailableProfiles() = %v", available)
+	}
+
+	// Custom profiles work with the calculator
+	calc, err := NewCalculator("symfony-api", 0, mockCalculator(ProfileDev, 512, 1).logger)
+	if err != nil {
+		t.Fatalf("NewCalculator() error = %v", err)
+	}
+	if calc.ProfileWorkerMemory() != 64 {
+		t.Errorf("calculator uses %dMB per worker, want 64", calc.ProfileWorkerMemory())
+	}
+}
+
+func TestLoadCustomProfiles_OverrideBuiltin(t *testing.T) {
+	err := loadTestProfiles(t, map[string]*config.AutotuneProfile{
+		"medium": {MaxRequestsPerChild: intPtr(250), SpareMinRatio: floatPtr(0)},
+	})
+	if err != nil {
+		t.Fatalf("LoadCustomProfiles() error = %v", err)
+	}
+
+	cfg, _ := ProfileMedium.GetConfig()
+	if cfg.MaxRequestsPerChild != 250 || cfg.SpareMinRatio != 0 {
+		t.Errorf("built-in override not applied: %+v", cfg)
+	}
+	if Profiles[ProfileMedium].MaxRequestsPerChild == 250 {
+		t.Error("built-in profile table must not be modified")
+	}
+	if len(AvailableProfiles()) != 5 {
+		t.Errorf("overridden built-ins should not be listed twice: %v", AvailableProfiles())
+	}
+
+	// Reloading without definitions restores the built-in
+	if err := LoadCustomProfiles(nil); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, _ := ProfileMedium.GetConfig(); cfg.MaxRequestsPerChild != Profiles[ProfileMedium].MaxRequestsPerChild {
+		t.Errorf("expected built-in after reset, got %d", cfg.MaxRequestsPerChild)
+	}
+}
+
+func TestLoadCustomProfiles_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		defs    map[string]*config.AutotuneProfile
+		wantErr string
+	}{
+		{
+			name:    "new profile without extends",
+			defs:    map[string]*config.AutotuneProfile{"api": {AvgMemoryPerWorker: intPtr(50)}},
+			wantErr: "extends is required",
+		},
+		{
+			name:    "unknown extends",
+			defs:    map[string]*config.AutotuneProfile{"api": {Extends: "huge"}},
+			wantErr: "extends unknown profile huge",
+		},
+		{
+			name: "circular extends",
+			defs: map[string]*config.AutotuneProfile{
+				"a": {Extends: "b"},
+				"b": {Extends: "a"},
+			},
+			wantErr: "circular extends (a -> b -> a)",
+		},
+		{
+			name:    "self extends",
+			defs:    map[string]*config.AutotuneProfile{"api": {Extends: "api"}},
+			wantErr: "cannot extend itself",
+		},
+		{
+			name:    "invalid name",
+			defs:    map[string]*config.AutotuneProfile{"My Profile": {Extends: "light"}},
+			wantErr: "invalid name",
+		},
+		{
+			name:    "invalid process manager",
+			defs:    map[string]*config.AutotuneProfile{"api": {Extends: "light", ProcessManager: "auto"}},
+			wantErr: "process_manager",
+		},
+		{
+			name:    "zero worker memory",
+			defs:    map[string]*config.AutotuneProfile{"api": {Extends: "light", AvgMemoryPerWorker: intPtr(0)}},
+			wantErr: "avg_memory_per_worker",
+		},
+		{
+			name:    "max below min workers",
+			defs:    map[string]*config.AutotuneProfile{"api": {Extends: "heavy", MaxWorkers: intPtr(4)}},
+			wantErr: "max_workers (4)",
+		},
+		{
+			name:    "ratio out of range",
+			defs:    map[string]*config.AutotuneProfile{"api": {Extends: "light", SpareMaxRatio: floatPtr(1.5)}},
+			wantErr: "spare_max_ratio",
+		},
+		{
+			name:    "memory usage out of range",
+			defs:    map[string]*config.AutotuneProfile{"api": {Extends: "light", MaxMemoryUsage: floatPtr(1.2)}},
+			wantErr: "max_memory_usage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadTestProfiles(t, tt.defs)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if len(AvailableProfiles()) != 5 {
+				t.Error("invalid definitions must not register any profile")
+			}
+		})
+	}
+}
+
+func TestProfileValidate_ListsCustomProfiles(t *testing.T) {
+	if err := loadTestProfiles(t, map[string]*config.AutotuneProfile{"api": {Extends: "light"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Profile("unknown").Validate()
+	if err == nil || !strings.Contains(err.Error(), "bursty, api") {
+		t.Errorf("expected custom profile in valid list, got %v", err)
+	}
+	if err := Profile("api").Validate(); err != nil {
+		t.Errorf("custom profile should validate: %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+package autotune
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkerMemoryObserver records php-fpm worker RSS samples in a sliding window.
+// It implements metrics.ChildMemoryRecorder so the resource collector can feed
+// it, and persists its window so learning survives restarts.
+type WorkerMemoryObserver struct {
+	mu        sync.Mutex
+	window    int
+	samples   []uint64 // RSS in bytes, ring buffer
+	next      int      // Next write position once the window is full
+	total     uint64   // Samples recorded over the observer's lifetime
+	updatedAt time.Time
+	dirty     bool
+}
+
+// WorkerMemoryStats summarizes the observed worker memory
+type WorkerMemoryStats struct {
+	Samples   int       `json:"samples"`
+	Total     uint64    `json:"total_samples"`
+	P50MB     float64   `json:"p50_mb"`
+	P90MB     float64   `json:"p90_mb"`
+	P95MB     float64   `json:"p95_mb"`
+	P99MB     float64   `json:"p99_mb"`
+	MaxMB     float64   `json:"max_mb"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// workerMemoryState is the persisted form of the observer
+type workerMemoryState struct {
+	Version   int       `json:"version"`
+	Total     uint64    `json:"total_samples"`
+	UpdatedAt time.Time `json:"updated_at"`
+	RSSBytes  []uint64  `json:"rss_bytes"`
+}
+
+const workerMemoryStateVersion = 1
+
+// NewWorkerMemoryObserver creates an observer keeping the most recent window samples
+func NewWorkerMemoryObserver(window int) *WorkerMemoryObserver {
+	if window <= 0 {
+		window = 20000
+	}
+	return &WorkerMemoryObserver{
+		window:  window,
+		samples: make([]uint64, 0, min(window, 1024)),
+	}
+}
+
+// LoadWorkerMemoryObserver restores an observer from path. A missing file
+// yields an empty observer.
+func LoadWorkerMemoryObserver(path string, window int) (*WorkerMemoryObserver, error) {
+	obs := NewWorkerMemoryObserver(window)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return obs, nil
+		}
+		return obs, fmt.Errorf("failed to read worker memory state: %w", err)
+	}
+
+	var state workerMemoryState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return obs, fmt.Errorf("failed to parse worker memory state %s: %w", path, err)
+	}
+	if state.Version != workerMemoryStateVersion {
+		return obs, fmt.Errorf("unsupported worker memory state version %d in %s", state.Version, path)
+	}
+
+	obs.record(state.RSSBytes, state.UpdatedAt)
+	obs.total = max(state.Total, uint64(len(state.RSSBytes)))
+	obs.dirty = false
+	return obs, nil
+}
+
+// RecordWorkerRSS adds one RSS sample per worker
+func (o *WorkerMemoryObserver) RecordWorkerRSS(rss []uint64) {
+	if len(rss) == 0 {
+		return
+	}
+	o.record(rss, time.Now())
+}
+
+func (o *WorkerMemoryObserver) record(rss []uint64, at time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, v := range rss {
+		if v == 0 {
+			continue
+		}
+		if len(o.samples) < o.window {
+			o.samples = append(o.samples, v)
+		} else {
+			o.samples[o.next] = v
+			o.next = (o.next + 1) % o.window
+		}
+		o.total++
+	}
+	o.updatedAt = at
+	o.dirty = true
+}
+
+// Stats returns percentiles over the current window
+func (o *WorkerMemoryObserver) Stats() WorkerMemoryStats {
+	sorted := o.sortedSamples()
+
+	o.mu.Lock()
+	stats := WorkerMemoryStats{
+		Samples:   len(sorted),
+		Total:     o.total,
+		UpdatedAt: o.updatedAt,
+	}
+	o.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return stats
+	}
+	stats.P50MB = bytesToMB(percentile(sorted, 50))
+	stats.P90MB = bytesToMB(percentile(sorted, 90))
+	stats.P95MB = bytesToMB(percentile(sorted, 95))
+	stats.P99MB = bytesToMB(percentile(sorted, 99))
+	stats.MaxMB = bytesToMB(sorted[len(sorted)-1])
+	return stats
+}
+
+// PercentileMB returns the RSS at percentile p (0-100) in whole MB, rounded up,
+// or 0 when nothing has been observed
+func (o *WorkerMemoryObserver) PercentileMB(p float64) int {
+	sorted := o.sortedSamples()
+	if len(sorted) == 0 {
+		return 0
+	}
+	return int(math.Ceil(bytesToMB(percentile(sorted, p))))
+}
+
+// Save atomically writes the window to path if it changed since the last save
+func (o *WorkerMemoryObserver) Save(path string) error {
+	o.mu.Lock()
+	if !o.dirty {
+		o.mu.Unlock()
+		return nil
+	}
+	state := workerMemoryState{
+		Version:   workerMemoryStateVersion,
+		Total:     o.total,
+		UpdatedAt: o.updatedAt,
+		RSSBytes:  o.orderedLocked(),
+	}
+	o.dirty = false
+	o.mu.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode worker memory state: %w", err)
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		o.mu.Lock()
+		o.dirty = true
+		o.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// orderedLocked returns samples oldest first. Caller must hold o.mu.
+func (o *WorkerMemoryObserver) orderedLocked() []uint64 {
+	ordered := make([]uint64, 0, len(o.samples))
+	ordered = append(ordered, o.samples[o.next:]...)
+	return append(ordered, o.samples[:o.next]...)
+}
+
+func (o *WorkerMemoryObserver) sortedSamples() []uint64 {
+	o.mu.Lock()
+	sorted := make([]uint64, len(o.samples))
+	copy(sorted, o.samples)
+	o.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []uint64, p float64) uint64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
+
+func bytesToMB(b uint64) float64 {
+	return math.Round(float64(b)/1024/1024*10) / 10
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".phpeek-state-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// LearningReport compares observed worker memory with the profile estimate
+// and the max_children it leads to
+type LearningReport struct {
+	Process                string            `json:"process"`
+	Profile                Profile           `json:"profile,omitempty"`
+	Percentile             float64           `json:"percentile"`
+	Stats                  WorkerMemoryStats `json:"stats"`
+	MinSamples             int               `json:"min_samples"`
+	Ready                  bool              `json:"ready"`                 // Enough samples for a recommendation
+	ObservedMB             int               `json:"observed_mb"`           // Per-worker RSS at Percentile
+	EstimateMB             int               `json:"estimate_mb,omitempty"` // Profile's static per-worker estimate
+	DriftPercent           float64           `json:"drift_percent"`         // (observed - estimate) / estimate
+	CurrentMaxChildren     int               `json:"current_max_children,omitempty"`
+	RecommendedMaxChildren int               `json:"recommended_max_children,omitempty"`
+	Applied                bool              `json:"applied"` // Current tuning already uses observed memory
+	Apply                  bool              `json:"apply"`   // Learned value is used on next start or reload
+	Warnings               []string          `json:"warnings,omitempty"`
+}
+
+// BuildLearningReport summarizes the observer against the calculator's
+// profile. calc and current may be nil when auto-tuning is not active.
+func BuildLearningReport(obs *WorkerMemoryObserver, process string, percentile float64, minSamples int, calc *Calculator, current *PHPFPMConfig) *LearningReport {
+	report := &LearningReport{
+		Process:    process,
+		Percentile: percentile,
+		Stats:      obs.Stats(),
+		MinSamples: minSamples,
+		ObservedMB: obs.PercentileMB(percentile),
+	}
+	report.Ready = report.Stats.Samples >= minSamples && report.ObservedMB > 0
+
+	if current != nil {
+		report.Profile = current.Profile
+		report.CurrentMaxChildren = current.MaxChildren
+		report.Applied = current.Observed
+	}
+	if calc == nil {
+		return report
+	}
+
+	report.Profile = Profile(calc.profile.Name)
+	report.EstimateMB = calc.ProfileWorkerMemory()
+	if report.ObservedMB > 0 && report.EstimateMB > 0 {
+		drift := float64(report.ObservedMB-report.EstimateMB) / float64(report.EstimateMB) * 100
+		report.DriftPercent = math.Round(drift*10) / 10
+	}
+
+	if !report.Ready {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("Collecting samples: %d of %d required for a recommendation", report.Stats.Samples, minSamples))
+		return report
+	}
+
+	recommended, err := calc.Recommend(report.ObservedMB)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("No recommendation: %v", err))
+		return report
+	}
+	report.RecommendedMaxChildren = recommended.MaxChildren
+	if current == nil {
+		if base, err := calc.Recommend(0); err == nil {
+			report.CurrentMaxChildren = base.MaxChildren
+		}
+	}
+
+	switch {
+	case report.DriftPercent >= 25:
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("Workers use %dMB, %.0f%% more than the %s profile estimate (%dMB): risk of OOM kills",
+				report.ObservedMB, report.DriftPercent, report.Profile, report.EstimateMB))
+	case report.DriftPercent <= -25:
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("Workers use %dMB, %.0f%% less than the %s profile estimate (%dMB): memory is left unused",
+				report.ObservedMB, -report.DriftPercent, report.Profile, report.EstimateMB))
+	}
+
+	return report
+}
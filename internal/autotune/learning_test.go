@@ -0,0 +1,187 @@
+package autotune
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mb = 1024 * 1024
+
+func TestWorkerMemoryObserver_Stats(t *testing.T) {
+	obs := NewWorkerMemoryObserver(1000)
+	var rss []uint64
+	for i := 1; i <= 100; i++ {
+		rss = append(rss, uint64(i)*mb)
+	}
+	obs.RecordWorkerRSS(rss)
+	obs.RecordWorkerRSS([]uint64{0}) // ignored
+
+	stats := obs.Stats()
+	if stats.Samples != 100 || stats.Total != 100 {
+		t.Fatalf("samples = %d/%d, want 100/100", stats.Samples, stats.Total)
+	}
+	if stats.P50MB != 50 || stats.P95MB != 95 || stats.P99MB != 99 || stats.MaxMB != 100 {
+		t.Errorf("unexpected percentiles: %+v", stats)
+	}
+	if got := obs.PercentileMB(90); got != 90 {
+		t.Errorf("PercentileMB(90) = %d, want 90", got)
+	}
+	if stats.UpdatedAt.IsZero() {
+		t.Error("expected UpdatedAt to be set")
+	}
+}
+
+func TestWorkerMemoryObserver_Window(t *testing.T) {
+	obs := NewWorkerMemoryObserver(10)
+	for i := 0; i < 10; i++ {
+		obs.RecordWorkerRSS([]uint64{200 * mb})
+	}
+	for i := 0; i < 10; i++ {
+		obs.RecordWorkerRSS([]uint64{40 * mb})
+	}
+
+	stats := obs.Stats()
+	if stats.Samples != 10 || stats.Total != 20 {
+		t.Errorf("samples = %d/%d, want 10/20", stats.Samples, stats.Total)
+	}
+	if stats.MaxMB != 40 {
+		t.Errorf("old samples should be evicted, max = %.1f", stats.MaxMB)
+	}
+}
+
+func TestWorkerMemoryObserver_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "learning.json")
+
+	obs := NewWorkerMemoryObserver(5)
+	obs.RecordWorkerRSS([]uint64{10 * mb, 20 * mb, 30 * mb, 40 * mb, 50 * mb, 60 * mb})
+	if err := obs.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadWorkerMemoryObserver(path, 5)
+	if err != nil {
+		t.Fatalf("LoadWorkerMemoryObserver() error = %v", err)
+	}
+	stats := loaded.Stats()
+	if stats.Samples != 5 || stats.Total != 6 || stats.MaxMB != 60 || stats.P50MB != 40 {
+		t.Errorf("unexpected stats after load: %+v", stats)
+	}
+
+	// Window order is preserved: the next sample evicts the oldest (20MB)
+	loaded.RecordWorkerRSS([]uint64{70 * mb})
+	if got := loaded.Stats().P50MB; got != 50 {
+		t.Errorf("P50 after eviction = %.1f, want 50", got)
+	}
+}
+
+func TestLoadWorkerMemoryObserver_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	obs, err := LoadWorkerMemoryObserver(filepath.Join(dir, "missing.json"), 10)
+	if err != nil {
+		t.Fatalf("missing state should not be an error: %v", err)
+	}
+	if obs.Stats().Samples != 0 {
+		t.Error("expected empty observer")
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWorkerMemoryObserver(bad, 10); err == nil {
+		t.Error("expected parse error")
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"version": 99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWorkerMemoryObserver(future, 10); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("expected version error, got %v", err)
+	}
+}
+
+func TestBuildLearningReport(t *testing.T) {
+	// Medium profile estimates 42MB per worker
+	calc := mockCalculator(ProfileMedium, 4096, 16)
+	current, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+
+	obs := NewWorkerMemoryObserver(1000)
+	for i := 0; i < 200; i++ {
+		obs.RecordWorkerRSS([]uint64{84 * mb})
+	}
+
+	report := BuildLearningReport(obs, "php-fpm", 95, 100, calc, current)
+	if !report.Ready {
+		t.Fatalf("expected report to be ready: %+v", report)
+	}
+	if report.ObservedMB != 84 || report.EstimateMB != calc.ProfileWorkerMemory() {
+		t.Errorf("observed/estimate = %d/%d", report.ObservedMB, report.EstimateMB)
+	}
+	if report.DriftPercent <= 0 {
+		t.Errorf("expected positive drift, got %.1f", report.DriftPercent)
+	}
+	if report.RecommendedMaxChildren == 0 || report.RecommendedMaxChildren >= report.CurrentMaxChildren {
+		t.Errorf("expected fewer workers: current %d, recommended %d", report.CurrentMaxChildren, report.RecommendedMaxChildren)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "OOM") {
+		t.Errorf("expected OOM drift warning, got %v", report.Warnings)
+	}
+
+	// Recommend must not change the calculator
+	again, _ := calc.Calculate()
+	if again.MaxChildren != current.MaxChildren || again.Observed {
+		t.Error("Recommend() modified the calculator")
+	}
+}
+
+func TestBuildLearningReport_NotReady(t *testing.T) {
+	obs := NewWorkerMemoryObserver(1000)
+	obs.RecordWorkerRSS([]uint64{30 * mb})
+
+	report := BuildLearningReport(obs, "php-fpm", 95, 100, mockCalculator(ProfileLight, 2048, 4), nil)
+	if report.Ready || report.RecommendedMaxChildren != 0 {
+		t.Errorf("expected no recommendation: %+v", report)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "1 of 100") {
+		t.Errorf("expected sample count warning, got %v", report.Warnings)
+	}
+
+	// Without a calculator only observations are reported
+	report = BuildLearningReport(obs, "php-fpm", 95, 1, nil, nil)
+	if !report.Ready || report.EstimateMB != 0 || report.DriftPercent != 0 {
+		t.Errorf("unexpected report without calculator: %+v", report)
+	}
+}
+
+func TestCalculator_ObservedWorkerMemory(t *testing.T) {
+	calc := mockCalculator(ProfileMedium, 4096, 16)
+	base, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+	if base.Observed || base.WorkerMemory != calc.ProfileWorkerMemory() {
+		t.Errorf("expected profile estimate, got %dMB observed=%v", base.WorkerMemory, base.Observed)
+	}
+
+	calc.SetObservedWorkerMemory(base.WorkerMemory * 2)
+	observed, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+	if !observed.Observed || observed.WorkerMemory != base.WorkerMemory*2 {
+		t.Errorf("expected observed memory, got %dMB observed=%v", observed.WorkerMemory, observed.Observed)
+	}
+	if observed.MaxChildren >= base.MaxChildren {
+		t.Errorf("doubling worker memory should reduce max_children: %d -> %d", base.MaxChildren, observed.MaxChildren)
+	}
+	if observed.MemoryAllocated != observed.MaxChildren*observed.WorkerMemory {
+		t.Errorf("MemoryAllocated = %d, want %d", observed.MemoryAllocated, observed.MaxChildren*observed.WorkerMemory)
+	}
+}
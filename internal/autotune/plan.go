@@ -0,0 +1,279 @@
+package autotune
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// DefaultPlanThreshold is the memory threshold used when planning without a
+// php-fpm profile
+const DefaultPlanThreshold = 0.75
+
+// Allocation kinds
+const (
+	AllocationPHPFPM  = "php-fpm" // php-fpm pm.max_children
+	AllocationScale   = "scale"   // Process instances (scale)
+	AllocationWorkers = "workers" // Workers inside each instance (workers_flag)
+)
+
+// PoolConsumer is a process sized from the shared memory budget
+type PoolConsumer struct {
+	Name              string
+	MemoryPerInstance int     // MB per instance (per worker with WorkersFlag)
+	Weight            float64 // Share of the budget relative to other consumers
+	MinInstances      int
+	MaxInstances      int    // 0 = unlimited
+	WorkersFlag       string // Set this flag instead of scale
+	Replicas          int    // Instances each running WorkersFlag workers (WorkersFlag only)
+}
+
+// PoolAllocation is the planned size of one consumer
+type PoolAllocation struct {
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"`
+	Weight      float64 `json:"weight"`
+	ShareMB     int     `json:"share_mb"`
+	PerInstance int     `json:"memory_per_instance_mb"`
+	Instances   int     `json:"instances"` // Scale, workers per instance, or pm.max_children
+	MemoryMB    int     `json:"memory_mb"`
+	WorkersFlag string  `json:"workers_flag,omitempty"`
+}
+
+// MemoryPlan splits the container memory budget across php-fpm and
+// auto-tuned processes
+type MemoryPlan struct {
+	TotalMB     int              `json:"total_mb"`
+	Threshold   float64          `json:"threshold"`
+	ReservedMB  int              `json:"reserved_mb"`
+	BudgetMB    int              `json:"budget_mb"`
+	AllocatedMB int              `json:"allocated_mb"`
+	PHPFPM      *PHPFPMConfig    `json:"-"`
+	Allocations []PoolAllocation `json:"allocations"`
+	Warnings    []string         `json:"warnings,omitempty"`
+}
+
+// PlanOptions configures planning without a php-fpm calculator
+type PlanOptions struct {
+	Threshold    float64 // Fraction of container memory to use (0 = DefaultPlanThreshold)
+	ReservedMB   int     // Memory kept for the system
+	PHPFPMWeight float64 // php-fpm share relative to consumers (0 = 1)
+}
+
+// NewPlanOptions returns planning options from global.autotune with the given
+// memory threshold (0 = DefaultPlanThreshold)
+func NewPlanOptions(cfg *config.Config, threshold float64) PlanOptions {
+	opts := PlanOptions{Threshold: threshold, ReservedMB: 128, PHPFPMWeight: 1}
+	if at := cfg.Global.Autotune; at != nil {
+		opts.ReservedMB = at.ReservedMemoryMB
+		opts.PHPFPMWeight = at.PHPFPMWeight
+	}
+	return opts
+}
+
+// PlanMemory splits the memory budget across php-fpm (when calc is set) and
+// consumers by weight. With a calculator, its resources, threshold and
+// reserved memory are used and php-fpm is sized from its share; memory
+// php-fpm cannot use (CPU or profile limits) goes to the consumers.
+// Without a calculator, resources and opts define the budget.
+func PlanMemory(calc *Calculator, resources *ContainerResources, opts PlanOptions, consumers []PoolConsumer) (*MemoryPlan, error) {
+	plan := &MemoryPlan{}
+
+	if calc != nil {
+		resources = calc.Resources()
+		plan.Threshold = calc.Threshold()
+		plan.ReservedMB = calc.ReservedMemory()
+	} else {
+		plan.Threshold = opts.Threshold
+		if plan.Threshold <= 0 {
+			plan.Threshold = DefaultPlanThreshold
+		}
+		plan.ReservedMB = opts.ReservedMB
+	}
+	if resources == nil {
+		return nil, fmt.Errorf("container resources are required")
+	}
+
+	plan.TotalMB = resources.MemoryLimitMB
+	plan.BudgetMB = int(float64(plan.TotalMB)*plan.Threshold) - plan.ReservedMB
+	if plan.BudgetMB <= 0 {
+		return nil, fmt.Errorf("insufficient memory: %dMB at %.0f%% leaves nothing after reserving %dMB",
+			plan.TotalMB, plan.Threshold*100, plan.ReservedMB)
+	}
+
+	totalWeight := 0.0
+	for _, c := range consumers {
+		totalWeight += c.Weight
+	}
+
+	remaining := plan.BudgetMB
+	if calc != nil {
+		fpmWeight := opts.PHPFPMWeight
+		if fpmWeight <= 0 {
+			fpmWeight = 1
+		}
+		share := int(float64(plan.BudgetMB) * fpmWeight / (fpmWeight + totalWeight))
+
+		calc.SetWorkerBudget(share)
+		phpfpm, err := calc.Calculate()
+		if err != nil {
+			return nil, fmt.Errorf("php-fpm (%dMB share): %w", share, err)
+		}
+		plan.PHPFPM = phpfpm
+		plan.Allocations = append(plan.Allocations, PoolAllocation{
+			Name:        AllocationPHPFPM,
+			Kind:        AllocationPHPFPM,
+			Weight:      fpmWeight,
+			ShareMB:     share,
+			PerInstance: phpfpm.WorkerMemory,
+			Instances:   phpfpm.MaxChildren,
+			MemoryMB:    phpfpm.MemoryAllocated,
+		})
+		remaining -= phpfpm.MemoryAllocated
+	}
+
+	for _, c := range consumers {
+		share := 0
+		if totalWeight > 0 {
+			share = int(float64(remaining) * c.Weight / totalWeight)
+		}
+		plan.Allocations = append(plan.Allocations, plan.allocate(c, share))
+	}
+
+	for _, a := range plan.Allocations {
+		plan.AllocatedMB += a.MemoryMB
+	}
+	if plan.AllocatedMB > plan.BudgetMB {
+		plan.Warnings = append(plan.Warnings,
+			fmt.Sprintf("Planned processes use %dMB, exceeding the %dMB budget: risk of OOM kills", plan.AllocatedMB, plan.BudgetMB))
+	}
+
+	return plan, nil
+}
+
+// allocate sizes one consumer from its share, honoring min/max
+func (p *MemoryPlan) allocate(c PoolConsumer, share int) PoolAllocation {
+	replicas := 1
+	kind := AllocationScale
+	if c.WorkersFlag != "" {
+		kind = AllocationWorkers
+		replicas = max(c.Replicas, 1)
+	}
+	perInstance := c.MemoryPerInstance * replicas
+
+	instances := 0
+	if perInstance > 0 {
+		instances = share / perInstance
+	}
+	if instances < c.MinInstances {
+		p.Warnings = append(p.Warnings,
+			fmt.Sprintf("%s: %dMB share fits %d instances of %dMB, using minimum %d", c.Name, share, instances, perInstance, c.MinInstances))
+		instances = c.MinInstances
+	}
+	if c.MaxInstances > 0 && instances > c.MaxInstances {
+		instances = c.MaxInstances
+	}
+
+	return PoolAllocation{
+		Name:        c.Name,
+		Kind:        kind,
+		Weight:      c.Weight,
+		ShareMB:     share,
+		PerInstance: c.MemoryPerInstance,
+		Instances:   instances,
+		MemoryMB:    instances * perInstance,
+		WorkersFlag: c.WorkersFlag,
+	}
+}
+
+// ProcessConsumers returns the long-running processes with auto-tuning
+// enabled, sorted by name
+func ProcessConsumers(cfg *config.Config) []PoolConsumer {
+	var consumers []PoolConsumer
+	for name, proc := range cfg.Processes {
+		at := proc.Autotune
+		if at == nil || !at.Enabled || proc.Schedule != "" || proc.Type == "oneshot" {
+			continue
+		}
+
+		c := PoolConsumer{
+			Name:              name,
+			MemoryPerInstance: at.MemoryPerInstance,
+			Weight:            at.Weight,
+			MinInstances:      at.MinScale,
+			MaxInstances:      at.MaxScale,
+			WorkersFlag:       at.WorkersFlag,
+		}
+		if c.WorkersFlag != "" {
+			c.Replicas = proc.Scale
+		} else if proc.MaxScale > 0 && (c.MaxInstances == 0 || proc.MaxScale < c.MaxInstances) {
+			c.MaxInstances = proc.MaxScale
+		}
+		consumers = append(consumers, c)
+	}
+
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].Name < consumers[j].Name })
+	return consumers
+}
+
+// Apply sets scale, or the workers flag in the command, of each planned
+// process. The values are recorded as auto-tuned so config.Save keeps the
+// configured ones.
+func (p *MemoryPlan) Apply(cfg *config.Config) {
+	for _, a := range p.Allocations {
+		proc, ok := cfg.Processes[a.Name]
+		if !ok {
+			continue
+		}
+		switch a.Kind {
+		case AllocationScale:
+			cfg.SetAutotuned(a.Name, a.Instances, proc.Command)
+		case AllocationWorkers:
+			cfg.SetAutotuned(a.Name, proc.Scale, SetWorkersFlag(proc.Command, a.WorkersFlag, a.Instances))
+		}
+	}
+}
+
+// SetWorkersFlag returns command with flag set to n, replacing "flag=X" or
+// "flag X" or appending "flag=n"
+func SetWorkersFlag(command []string, flag string, n int) []string {
+	value := strconv.Itoa(n)
+	result := make([]string, len(command))
+	copy(result, command)
+
+	for i, arg := range result {
+		if strings.HasPrefix(arg, flag+"=") {
+			result[i] = flag + "=" + value
+			return result
+		}
+		if arg == flag && i+1 < len(result) {
+			result[i+1] = value
+			return result
+		}
+	}
+	return append(result, flag+"="+value)
+}
+
+// String returns a human-readable representation of the plan
+func (p *MemoryPlan) String() string {
+	s := fmt.Sprintf("Memory plan: %dMB budget (%dMB × %.0f%% - %dMB reserved)\n",
+		p.BudgetMB, p.TotalMB, p.Threshold*100, p.ReservedMB)
+	for _, a := range p.Allocations {
+		var size string
+		switch a.Kind {
+		case AllocationPHPFPM:
+			size = fmt.Sprintf("pm.max_children = %d", a.Instances)
+		case AllocationWorkers:
+			size = fmt.Sprintf("%s=%d", a.WorkersFlag, a.Instances)
+		default:
+			size = fmt.Sprintf("scale = %d", a.Instances)
+		}
+		s += fmt.Sprintf("  %s: %s (%dMB per instance, %dMB of %dMB share, weight %.1f)\n",
+			a.Name, size, a.PerInstance, a.MemoryMB, a.ShareMB, a.Weight)
+	}
+	s += fmt.Sprintf("  Total: %dMB of %dMB budget\n", p.AllocatedMB, p.BudgetMB)
+	return s
+}
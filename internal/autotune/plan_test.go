@@ -0,0 +1,186 @@
+package autotune
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+func TestPlanMemory_Weights(t *testing.T) {
+	// 4096MB × 75% - 128MB reserved = 2944MB budget
+	consumers := []PoolConsumer{
+		{Name: "horizon", MemoryPerInstance: 128, Weight: 1, MinInstances: 1},
+		{Name: "queue", MemoryPerInstance: 64, Weight: 3, MinInstances: 1},
+	}
+	plan, err := PlanMemory(nil, mockResources(4096, 4), PlanOptions{ReservedMB: 128}, consumers)
+	if err != nil {
+		t.Fatalf("PlanMemory() error = %v", err)
+	}
+
+	if plan.BudgetMB != 2944 || plan.Threshold != DefaultPlanThreshold {
+		t.Errorf("budget = %dMB at %.2f, want 2944MB at %.2f", plan.BudgetMB, plan.Threshold, DefaultPlanThreshold)
+	}
+	if len(plan.Allocations) != 2 || plan.PHPFPM != nil {
+		t.Fatalf("expected 2 process allocations, got %+v", plan.Allocations)
+	}
+
+	horizon, queue := plan.Allocations[0], plan.Allocations[1]
+	if horizon.ShareMB != 736 || horizon.Instances != 5 || horizon.MemoryMB != 640 {
+		t.Errorf("horizon = %+v, want 736MB share and 5 instances", horizon)
+	}
+	if queue.ShareMB != 2208 || queue.Instances != 34 || queue.Kind != AllocationScale {
+		t.Errorf("queue = %+v, want 2208MB share and 34 instances", queue)
+	}
+	if plan.AllocatedMB != 640+34*64 || len(plan.Warnings) != 0 {
+		t.Errorf("allocated = %dMB, warnings = %v", plan.AllocatedMB, plan.Warnings)
+	}
+}
+
+func TestPlanMemory_Clamps(t *testing.T) {
+	consumers := []PoolConsumer{
+		{Name: "big", MemoryPerInstance: 2048, Weight: 1, MinInstances: 1},
+		{Name: "small", MemoryPerInstance: 32, Weight: 1, MinInstances: 1, MaxInstances: 4},
+	}
+	plan, err := PlanMemory(nil, mockResources(2048, 2), PlanOptions{Threshold: 0.5}, consumers)
+	if err != nil {
+		t.Fatalf("PlanMemory() error = %v", err)
+	}
+
+	if plan.Allocations[0].Instances != 1 || plan.Allocations[1].Instances != 4 {
+		t.Errorf("expected min/max clamps, got %+v", plan.Allocations)
+	}
+	// Minimum forces big over the budget: a clamp warning plus an OOM warning
+	if len(plan.Warnings) != 2 || !strings.Contains(plan.Warnings[1], "exceeding") {
+		t.Errorf("unexpected warnings: %v", plan.Warnings)
+	}
+}
+
+func TestPlanMemory_WithPHPFPM(t *testing.T) {
+	calc := mockCalculator(ProfileMedium, 4096, 16)
+	full, err := calc.Calculate()
+	if err != nil {
+		t.Fatalf("Calculate() failed: %v", err)
+	}
+
+	consumers := []PoolConsumer{{Name: "queue", MemoryPerInstance: 128, Weight: 1, MinInstances: 1}}
+	plan, err := PlanMemory(calc, nil, PlanOptions{PHPFPMWeight: 1}, consumers)
+	if err != nil {
+		t.Fatalf("PlanMemory() error = %v", err)
+	}
+
+	if plan.PHPFPM == nil || plan.Allocations[0].Kind != AllocationPHPFPM {
+		t.Fatalf("expected php-fpm allocation first, got %+v", plan.Allocations)
+	}
+	if plan.PHPFPM.MaxChildren >= full.MaxChildren {
+		t.Errorf("sharing the budget should reduce max_children: %d -> %d", full.MaxChildren, plan.PHPFPM.MaxChildren)
+	}
+	if plan.PHPFPM.MemoryAllocated > plan.Allocations[0].ShareMB {
+		t.Errorf("php-fpm uses %dMB, more than its %dMB share", plan.PHPFPM.MemoryAllocated, plan.Allocations[0].ShareMB)
+	}
+
+	// The queue gets whatever php-fpm left of the budget
+	queue := plan.Allocations[1]
+	if queue.ShareMB != plan.BudgetMB-plan.PHPFPM.MemoryAllocated {
+		t.Errorf("queue share = %dMB, want %dMB", queue.ShareMB, plan.BudgetMB-plan.PHPFPM.MemoryAllocated)
+	}
+	if plan.AllocatedMB > plan.BudgetMB {
+		t.Errorf("allocated %dMB exceeds budget %dMB", plan.AllocatedMB, plan.BudgetMB)
+	}
+}
+
+func TestPlanMemory_Insufficient(t *testing.T) {
+	if _, err := PlanMemory(nil, mockResources(128, 1), PlanOptions{ReservedMB: 256}, nil); err == nil {
+		t.Error("expected error when reserved memory exceeds the budget")
+	}
+	if _, err := PlanMemory(nil, nil, PlanOptions{}, nil); err == nil {
+		t.Error("expected error without resources")
+	}
+}
+
+func TestProcessConsumers(t *testing.T) {
+	cfg := &config.Config{Processes: map[string]*config.Process{
+		"queue": {
+			Scale:    2,
+			MaxScale: 8,
+			Autotune: &config.ProcessAutotune{Enabled: true, MemoryPerInstance: 64, Weight: 2, MinScale: 1},
+		},
+		"octane": {
+			Scale:    2,
+			Autotune: &config.ProcessAutotune{Enabled: true, MemoryPerInstance: 100, Weight: 1, MinScale: 1, WorkersFlag: "--workers"},
+		},
+		"disabled": {Autotune: &config.ProcessAutotune{MemoryPerInstance: 64}},
+		"cron":     {Schedule: "* * * * *", Autotune: &config.ProcessAutotune{Enabled: true, MemoryPerInstance: 64}},
+		"nginx":    {},
+	}}
+
+	consumers := ProcessConsumers(cfg)
+	if len(consumers) != 2 || consumers[0].Name != "octane" || consumers[1].Name != "queue" {
+		t.Fatalf("unexpected consumers: %+v", consumers)
+	}
+	if consumers[0].Replicas != 2 || consumers[0].MaxInstances != 0 {
+		t.Errorf("octane = %+v, want 2 replicas and no max", consumers[0])
+	}
+	if consumers[1].MaxInstances != 8 {
+		t.Errorf("queue max = %d, want process max_scale 8", consumers[1].MaxInstances)
+	}
+
+	plan, err := PlanMemory(nil, mockResources(2048, 2), PlanOptions{Threshold: 0.5, ReservedMB: 24}, consumers)
+	if err != nil {
+		t.Fatalf("PlanMemory() error = %v", err)
+	}
+	plan.Apply(cfg)
+
+	// 1000MB budget: octane 333MB over 2 replicas, queue 666MB
+	if got := cfg.Processes["queue"].Scale; got != 8 {
+		t.Errorf("queue scale = %d, want 8 (max_scale)", got)
+	}
+	if got := cfg.Processes["octane"].Command; !reflect.DeepEqual(got, []string{"--workers=1"}) {
+		t.Errorf("octane command = %v", got)
+	}
+	if got := cfg.Processes["octane"].Scale; got != 2 {
+		t.Errorf("octane scale should be unchanged, got %d", got)
+	}
+}
+
+func TestSetWorkersFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		want    []string
+	}{
+		{"append", []string{"php", "artisan", "octane:start"}, []string{"php", "artisan", "octane:start", "--workers=6"}},
+		{"equals", []string{"rr", "serve", "--workers=2", "--port=8000"}, []string{"rr", "serve", "--workers=6", "--port=8000"}},
+		{"separate", []string{"php", "artisan", "octane:start", "--workers", "2"}, []string{"php", "artisan", "octane:start", "--workers", "6"}},
+		{"trailing flag", []string{"octane", "--workers"}, []string{"octane", "--workers", "--workers=6"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]string(nil), tt.command...)
+			got := SetWorkersFlag(tt.command, "--workers", 6)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetWorkersFlag() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.command, original) {
+				t.Error("SetWorkersFlag() modified its input")
+			}
+		})
+	}
+}
+
+func TestMemoryPlan_String(t *testing.T) {
+	plan, err := PlanMemory(nil, mockResources(1024, 1), PlanOptions{Threshold: 0.5}, []PoolConsumer{
+		{Name: "octane", MemoryPerInstance: 64, Weight: 1, MinInstances: 1, WorkersFlag: "--workers", Replicas: 1},
+	})
+	if err != nil {
+		t.Fatalf("PlanMemory() error = %v", err)
+	}
+	s := plan.String()
+	for _, want := range []string{"512MB budget", "octane: --workers=8", "Total: 512MB"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected %q in:\n%s", want, s)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package autotune
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// tunedPoolDirectives are calculated by auto-tuning and always win over the
+// include file, otherwise tuning would silently have no effect
+var tunedPoolDirectives = map[string]bool{
+	"pm":                   true,
+	"pm.max_children":      true,
+	"pm.start_servers":     true,
+	"pm.min_spare_servers": true,
+	"pm.max_spare_servers": true,
+	"pm.max_requests":      true,
+}
+
+// poolDirective is a single "key = value" line of a php-fpm pool
+type poolDirective struct {
+	key   string
+	value string
+}
+
+// RenderPoolConfig renders a complete php-fpm pool file from the calculated
+// values. Directives from pool.Include are preserved and replace the generated
+// defaults (listen, status paths, timeouts, ...); auto-tuned pm.* directives in
+// the include file are skipped and reported as warnings.
+func (cfg *PHPFPMConfig) RenderPoolConfig(pool *config.FPMPoolConfig) (string, []string, error) {
+	var included []poolDirective
+	if pool.Include != "" {
+		var err error
+		included, err = readPoolDirectives(pool.Include)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	overridden := make(map[string]bool, len(included))
+	var preserved []poolDirective
+	var warnings []string
+	for _, d := range included {
+		if tunedPoolDirectives[d.key] {
+			warnings = append(warnings, fmt.Sprintf("%s from %s ignored: value is auto-tuned", d.key, pool.Include))
+			continue
+		}
+		overridden[d.key] = true
+		preserved = append(preserved, d)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "; Generated by phpeek-pm from the %s auto-tuning profile. Do not edit:\n", cfg.Profile)
+	b.WriteString("; this file is rewritten on every start.")
+	if pool.Include != "" {
+		fmt.Fprintf(&b, " Add custom directives to %s instead.", pool.Include)
+	}
+	fmt.Fprintf(&b, "\n\n[%s]\n", pool.Name)
+
+	write := func(key, value string) {
+		if value == "" || overridden[key] {
+			return
+		}
+		fmt.Fprintf(&b, "%s = %s\n", key, value)
+	}
+
+	write("listen", pool.Listen)
+	if strings.HasPrefix(pool.Listen, "/") {
+		write("listen.owner", pool.ListenOwner)
+		write("listen.group", pool.ListenGroup)
+		write("listen.mode", pool.ListenMode)
+	}
+
+	b.WriteString("\n")
+	write("pm", cfg.ProcessManager)
+	write("pm.max_children", fmt.Sprintf("%d", cfg.MaxChildren))
+	if cfg.ProcessManager == "dynamic" {
+		write("pm.start_servers", fmt.Sprintf("%d", cfg.StartServers))
+		write("pm.min_spare_servers", fmt.Sprintf("%d", cfg.MinSpare))
+		write("pm.max_spare_servers", fmt.Sprintf("%d", cfg.MaxSpare))
+	}
+	write("pm.max_requests", fmt.Sprintf("%d", cfg.MaxRequests))
+
+	b.WriteString("\n")
+	write("pm.status_path", pool.StatusPath)
+	write("ping.path", pool.PingPath)
+	write("slowlog", pool.Slowlog)
+	if pool.Slowlog != "" {
+		write("request_slowlog_timeout", pool.RequestSlowlogTimeout)
+	}
+	write("request_terminate_timeout", pool.RequestTerminateTimeout)
+
+	if len(preserved) > 0 {
+		fmt.Fprintf(&b, "\n; Preserved from %s\n", pool.Include)
+		for _, d := range preserved {
+			fmt.Fprintf(&b, "%s = %s\n", d.key, d.value)
+		}
+	}
+
+	return b.String(), warnings, nil
+}
+
+// WritePoolConfig renders the pool file and atomically writes it to pool.Path
+func (cfg *PHPFPMConfig) WritePoolConfig(pool *config.FPMPoolConfig) ([]string, error) {
+	content, warnings, err := cfg.RenderPoolConfig(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(pool.Path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create pool config directory: %w", err)
+	}
+
+	// Write to a temp file and rename so php-fpm never reads a partial file
+	tmp, err := os.CreateTemp(dir, ".phpeek-pool-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pool config: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.WriteString(content); err != nil {
+		_ = tmp.Close()
+		return nil, fmt.Errorf("failed to write pool config: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return nil, fmt.Errorf("failed to write pool config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write pool config: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), pool.Path); err != nil {
+		return nil, fmt.Errorf("failed to write pool config: %w", err)
+	}
+
+	return warnings, nil
+}
+
+// readPoolDirectives parses "key = value" directives from a pool include file.
+// Comments, blank lines and section headers are skipped.
+func readPoolDirectives(path string) ([]poolDirective, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pool include: %w", err)
+	}
+	defer f.Close()
+
+	var directives []poolDirective
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid directive in %s line %d: %q", path, lineNum, line)
+		}
+		directives = append(directives, poolDirective{
+			key:   strings.TrimSpace(key),
+			value: strings.TrimSpace(value),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pool include: %w", err)
+	}
+
+	return directives, nil
+}
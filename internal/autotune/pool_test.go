@@ -0,0 +1,190 @@
+package autotune
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+func testPoolConfig() *config.FPMPoolConfig {
+	return &config.FPMPoolConfig{
+		Enabled:    true,
+		Name:       "www",
+		Listen:     "9000",
+		StatusPath: "/status",
+		PingPath:   "/ping",
+	}
+}
+
+func TestRenderPoolConfig_Dynamic(t *testing.T) {
+	cfg := &PHPFPMConfig{
+		Profile:        ProfileMedium,
+		ProcessManager: "dynamic",
+		MaxChildren:    12,
+		StartServers:   4,
+		MinSpare:       3,
+		MaxSpare:       6,
+		MaxRequests:    1000,
+	}
+	pool := testPoolConfig()
+	pool.Slowlog = "/proc/self/fd/2"
+	pool.RequestSlowlogTimeout = "5s"
+	pool.RequestTerminateTimeout = "60s"
+
+	content, warnings, err := cfg.RenderPoolConfig(pool)
+	if err != nil {
+		t.Fatalf("RenderPoolConfig() error = %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	for _, want := range []string{
+		"[www]\n",
+		"listen = 9000\n",
+		"pm = dynamic\n",
+		"pm.max_children = 12\n",
+		"pm.start_servers = 4\n",
+		"pm.min_spare_servers = 3\n",
+		"pm.max_spare_servers = 6\n",
+		"pm.max_requests = 1000\n",
+		"pm.status_path = /status\n",
+		"ping.path = /ping\n",
+		"slowlog = /proc/self/fd/2\n",
+		"request_slowlog_timeout = 5s\n",
+		"request_terminate_timeout = 60s\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected pool config to contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "listen.mode") {
+		t.Error("listen.mode should only be rendered for unix sockets")
+	}
+}
+
+func TestRenderPoolConfig_StaticSocket(t *testing.T) {
+	cfg := &PHPFPMConfig{
+		Profile:        ProfileDev,
+		ProcessManager: "static",
+		MaxChildren:    2,
+		StartServers:   2,
+		MaxRequests:    100,
+	}
+	pool := testPoolConfig()
+	pool.Listen = "/run/php-fpm.sock"
+	pool.ListenOwner = "www-data"
+	pool.ListenMode = "0660"
+
+	content, _, err := cfg.RenderPoolConfig(pool)
+	if err != nil {
+		t.Fatalf("RenderPoolConfig() error = %v", err)
+	}
+
+	for _, want := range []string{"listen = /run/php-fpm.sock\n", "listen.owner = www-data\n", "listen.mode = 0660\n", "pm = static\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected pool config to contain %q, got:\n%s", want, content)
+		}
+	}
+	for _, notWant := range []string{"pm.start_servers", "listen.group", "slowlog"} {
+		if strings.Contains(content, notWant) {
+			t.Errorf("did not expect %q in static pool config:\n%s", notWant, content)
+		}
+	}
+}
+
+func TestRenderPoolConfig_Include(t *testing.T) {
+	include := filepath.Join(t.TempDir(), "custom.conf")
+	directives := `; custom pool settings
+[www]
+php_admin_value[memory_limit] = 256M
+ping.path = /healthz
+pm.max_children = 100
+clear_env = no
+`
+	if err := os.WriteFile(include, []byte(directives), 0644); err != nil {
+		t.Fatalf("failed to write include: %v", err)
+	}
+
+	cfg := &PHPFPMConfig{Profile: ProfileLight, ProcessManager: "dynamic", MaxChildren: 8, StartServers: 2, MinSpare: 2, MaxSpare: 4, MaxRequests: 500}
+	pool := testPoolConfig()
+	pool.Include = include
+
+	content, warnings, err := cfg.RenderPoolConfig(pool)
+	if err != nil {
+		t.Fatalf("RenderPoolConfig() error = %v", err)
+	}
+
+	// User directives are preserved and replace generated defaults
+	for _, want := range []string{"php_admin_value[memory_limit] = 256M\n", "ping.path = /healthz\n", "clear_env = no\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected preserved directive %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "ping.path = /ping") {
+		t.Error("generated ping.path should be replaced by the include file")
+	}
+
+	// Auto-tuned values win over the include file
+	if !strings.Contains(content, "pm.max_children = 8\n") || strings.Contains(content, "pm.max_children = 100") {
+		t.Errorf("expected auto-tuned pm.max_children, got:\n%s", content)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "pm.max_children") {
+		t.Errorf("expected warning about ignored pm.max_children, got %v", warnings)
+	}
+}
+
+func TestRenderPoolConfig_InvalidInclude(t *testing.T) {
+	cfg := &PHPFPMConfig{ProcessManager: "static", MaxChildren: 2}
+
+	pool := testPoolConfig()
+	pool.Include = filepath.Join(t.TempDir(), "missing.conf")
+	if _, _, err := cfg.RenderPoolConfig(pool); err == nil {
+		t.Error("expected error for missing include file")
+	}
+
+	include := filepath.Join(t.TempDir(), "bad.conf")
+	if err := os.WriteFile(include, []byte("php_flag display_errors\n"), 0644); err != nil {
+		t.Fatalf("failed to write include: %v", err)
+	}
+	pool.Include = include
+	_, _, err := cfg.RenderPoolConfig(pool)
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected invalid directive error, got %v", err)
+	}
+}
+
+func TestWritePoolConfig(t *testing.T) {
+	cfg := &PHPFPMConfig{Profile: ProfileDev, ProcessManager: "static", MaxChildren: 2, MaxRequests: 100}
+	pool := testPoolConfig()
+	pool.Path = filepath.Join(t.TempDir(), "php-fpm.d", "zz-phpeek-pm.conf")
+
+	if _, err := cfg.WritePoolConfig(pool); err != nil {
+		t.Fatalf("WritePoolConfig() error = %v", err)
+	}
+
+	data, err := os.ReadFile(pool.Path)
+	if err != nil {
+		t.Fatalf("failed to read pool config: %v", err)
+	}
+	if !strings.Contains(string(data), "pm.max_children = 2\n") {
+		t.Errorf("unexpected pool config:\n%s", data)
+	}
+
+	info, err := os.Stat(pool.Path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v, want 0644", info.Mode().Perm())
+	}
+
+	// No temp files left behind
+	entries, _ := os.ReadDir(filepath.Dir(pool.Path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the pool file in directory, got %d entries", len(entries))
+	}
+}
@@ -1,6 +1,9 @@
 package autotune
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Profile represents an application workload profile for PHP-FPM tuning
 type Profile string
@@ -127,10 +130,10 @@ var Profiles = map[Profile]ProfileConfig{
 	},
 }
 
-// Validate ensures the profile exists
+// Validate ensures the profile exists (built-in or loaded via LoadCustomProfiles)
 func (p Profile) Validate() error {
-	if _, exists := Profiles[p]; !exists {
-		return fmt.Errorf("invalid profile: %s (valid: dev, light, medium, heavy, bursty)", p)
+	if _, exists := lookupProfile(p); !exists {
+		return fmt.Errorf("invalid profile: %s (valid: %s)", p, strings.Join(AvailableProfiles(), ", "))
 	}
 	return nil
 }
@@ -140,7 +143,8 @@ func (p Profile) GetConfig() (ProfileConfig, error) {
 	if err := p.Validate(); err != nil {
 		return ProfileConfig{}, err
 	}
-	return Profiles[p], nil
+	cfg, _ := lookupProfile(p)
+	return cfg, nil
 }
 
 // String returns the string representation of the Profile
@@ -0,0 +1,236 @@
+// Package cgroup reads memory events and pressure stall information (PSI)
+// of the container cgroup and watches them at runtime.
+//
+// # Sources
+//
+// cgroup v2 (unified hierarchy):
+//   - memory.events: low, high, max, oom and oom_kill counters
+//   - memory.pressure, cpu.pressure: PSI of the cgroup
+//
+// cgroup v1:
+//   - memory/memory.oom_control: oom_kill counter (kernel 4.13+)
+//   - memory/memory.failcnt: times the memory limit was hit
+//   - /proc/pressure/memory, /proc/pressure/cpu: system-wide PSI when the
+//     kernel supports it (v1 has no per-cgroup PSI)
+//
+// The watcher exports these as Prometheus metrics, attributes SIGKILLed
+// instances to the OOM killer, and reports when pressure exceeds the
+// configured thresholds.
+package cgroup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultRoot is where the cgroup filesystem is mounted
+	DefaultRoot = "/sys/fs/cgroup"
+
+	// DefaultProcRoot is where procfs is mounted (system-wide PSI for cgroup v1)
+	DefaultProcRoot = "/proc"
+)
+
+// MemoryEvents are the cgroup memory event counters
+type MemoryEvents struct {
+	Low     uint64 `json:"low"`      // Reclaimed below memory.low (v2 only)
+	High    uint64 `json:"high"`     // Throttled above memory.high (v2 only)
+	Max     uint64 `json:"max"`      // Hit the memory limit
+	OOM     uint64 `json:"oom"`      // Out of memory at the limit (v2 only)
+	OOMKill uint64 `json:"oom_kill"` // Processes killed by the OOM killer
+}
+
+// PressureLine is one line of a PSI file
+type PressureLine struct {
+	Avg10  float64 `json:"avg10"`  // % of time stalled over the last 10s
+	Avg60  float64 `json:"avg60"`  // ... 60s
+	Avg300 float64 `json:"avg300"` // ... 300s
+	Total  uint64  `json:"total"`  // Total stall time in microseconds
+}
+
+// Pressure is the content of a PSI file. Some is the share of time at least
+// one task was stalled, Full the share of time all tasks were stalled.
+type Pressure struct {
+	Some PressureLine `json:"some"`
+	Full PressureLine `json:"full"`
+}
+
+// Stats is a snapshot of the cgroup
+type Stats struct {
+	Version        int          `json:"version"`
+	MemoryEvents   MemoryEvents `json:"memory_events"`
+	MemoryPressure *Pressure    `json:"memory_pressure,omitempty"` // nil when PSI is unavailable
+	CPUPressure    *Pressure    `json:"cpu_pressure,omitempty"`
+}
+
+// Reader reads cgroup statistics from a cgroup and procfs mount. Both can be
+// pointed at fake trees for tests.
+type Reader struct {
+	root     string
+	procRoot string
+	version  int
+}
+
+// NewReader creates a reader for the cgroup mounted at root and detects its
+// version. Empty paths use DefaultRoot and DefaultProcRoot.
+func NewReader(root, procRoot string) *Reader {
+	if root == "" {
+		root = DefaultRoot
+	}
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+
+	r := &Reader{root: root, procRoot: procRoot}
+	switch {
+	case fileExists(filepath.Join(root, "cgroup.controllers")):
+		r.version = 2
+	case fileExists(filepath.Join(root, "memory", "memory.oom_control")):
+		r.version = 1
+	}
+	return r
+}
+
+// Version returns the detected cgroup version (0 = no cgroup found)
+func (r *Reader) Version() int {
+	return r.version
+}
+
+// Read returns the current memory events and pressure
+func (r *Reader) Read() (Stats, error) {
+	stats := Stats{Version: r.version}
+
+	switch r.version {
+	case 2:
+		content, err := os.ReadFile(filepath.Join(r.root, "memory.events"))
+		if err != nil {
+			return stats, fmt.Errorf("failed to read memory events: %w", err)
+		}
+		stats.MemoryEvents = parseMemoryEventsV2(string(content))
+		stats.MemoryPressure = readPressure(filepath.Join(r.root, "memory.pressure"))
+		stats.CPUPressure = readPressure(filepath.Join(r.root, "cpu.pressure"))
+	case 1:
+		content, err := os.ReadFile(filepath.Join(r.root, "memory", "memory.oom_control"))
+		if err != nil {
+			return stats, fmt.Errorf("failed to read oom control: %w", err)
+		}
+		stats.MemoryEvents.OOMKill = parseFlatKeyed(string(content))["oom_kill"]
+		if failcnt, err := os.ReadFile(filepath.Join(r.root, "memory", "memory.failcnt")); err == nil {
+			stats.MemoryEvents.Max, _ = strconv.ParseUint(strings.TrimSpace(string(failcnt)), 10, 64)
+		}
+		stats.MemoryPressure = readPressure(filepath.Join(r.procRoot, "pressure", "memory"))
+		stats.CPUPressure = readPressure(filepath.Join(r.procRoot, "pressure", "cpu"))
+	default:
+		return stats, fmt.Errorf("no cgroup found at %s", r.root)
+	}
+
+	return stats, nil
+}
+
+// parseMemoryEventsV2 parses cgroup v2 memory.events content
+func parseMemoryEventsV2(content string) MemoryEvents {
+	values := parseFlatKeyed(content)
+	return MemoryEvents{
+		Low:     values["low"],
+		High:    values["high"],
+		Max:     values["max"],
+		OOM:     values["oom"],
+		OOMKill: values["oom_kill"],
+	}
+}
+
+// parseFlatKeyed parses "key value" lines, ignoring malformed ones
+func parseFlatKeyed(content string) map[string]uint64 {
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+	return values
+}
+
+// readPressure reads a PSI file, returning nil when it is missing or invalid
+// (PSI disabled or unsupported by the kernel)
+func readPressure(path string) *Pressure {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	p, err := ParsePressure(string(content))
+	if err != nil {
+		return nil
+	}
+	return &p
+}
+
+// ParsePressure parses PSI file content:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//
+// The full line is optional (cpu.pressure on older kernels).
+func ParsePressure(content string) (Pressure, error) {
+	var p Pressure
+	found := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var line *PressureLine
+		switch fields[0] {
+		case "some":
+			line = &p.Some
+		case "full":
+			line = &p.Full
+		default:
+			return p, fmt.Errorf("unexpected pressure line %q", scanner.Text())
+		}
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return p, fmt.Errorf("invalid pressure field %q", field)
+			}
+			var err error
+			switch key {
+			case "avg10":
+				line.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				line.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				line.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				line.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return p, fmt.Errorf("invalid pressure field %q: %w", field, err)
+			}
+		}
+		found = true
+	}
+
+	if !found {
+		return p, errors.New("empty pressure file")
+	}
+	return p, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,140 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+const testPressure = `some avg10=12.50 avg60=4.00 avg300=1.25 total=123456
+full avg10=3.00 avg60=1.00 avg300=0.50 total=6543
+`
+
+// writeFiles creates files relative to root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// fakeV2 creates a cgroup v2 tree with the given oom_kill count
+func fakeV2(t *testing.T, root string, oomKills int, memoryPressure string) {
+	t.Helper()
+	writeFiles(t, root, map[string]string{
+		"cgroup.controllers": "cpu memory pids\n",
+		"memory.events":      "low 1\nhigh 2\nmax 3\noom 4\noom_kill " + strconv.Itoa(oomKills) + "\n",
+		"memory.pressure":    memoryPressure,
+		"cpu.pressure":       "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	})
+}
+
+func TestReader_V2(t *testing.T) {
+	root := t.TempDir()
+	fakeV2(t, root, 2, testPressure)
+
+	r := NewReader(root, t.TempDir())
+	if r.Version() != 2 {
+		t.Fatalf("Version() = %d, want 2", r.Version())
+	}
+
+	stats, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := MemoryEvents{Low: 1, High: 2, Max: 3, OOM: 4, OOMKill: 2}
+	if stats.MemoryEvents != want {
+		t.Errorf("MemoryEvents = %+v, want %+v", stats.MemoryEvents, want)
+	}
+	if stats.MemoryPressure == nil || stats.MemoryPressure.Some.Avg10 != 12.5 || stats.MemoryPressure.Full.Total != 6543 {
+		t.Errorf("MemoryPressure = %+v", stats.MemoryPressure)
+	}
+	if stats.CPUPressure == nil || stats.CPUPressure.Some.Avg10 != 0 {
+		t.Errorf("CPUPressure = %+v", stats.CPUPressure)
+	}
+}
+
+func TestReader_V2WithoutPSI(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"cgroup.controllers": "memory\n",
+		"memory.events":      "oom_kill 1\n",
+	})
+
+	stats, err := NewReader(root, "").Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if stats.MemoryEvents.OOMKill != 1 || stats.MemoryPressure != nil || stats.CPUPressure != nil {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestReader_V1(t *testing.T) {
+	root, procRoot := t.TempDir(), t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"memory/memory.oom_control": "oom_kill_disable 0\nunder_oom 0\noom_kill 3\n",
+		"memory/memory.failcnt":     "17\n",
+	})
+	writeFiles(t, procRoot, map[string]string{
+		"pressure/memory": testPressure,
+		"pressure/cpu":    "some avg10=90.00 avg60=50.00 avg300=10.00 total=1\n",
+	})
+
+	r := NewReader(root, procRoot)
+	if r.Version() != 1 {
+		t.Fatalf("Version() = %d, want 1", r.Version())
+	}
+
+	stats, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if stats.MemoryEvents.OOMKill != 3 || stats.MemoryEvents.Max != 17 {
+		t.Errorf("MemoryEvents = %+v, want oom_kill 3 and max 17", stats.MemoryEvents)
+	}
+	if stats.CPUPressure == nil || stats.CPUPressure.Some.Avg10 != 90 {
+		t.Errorf("CPUPressure = %+v", stats.CPUPressure)
+	}
+}
+
+func TestReader_NoCgroup(t *testing.T) {
+	r := NewReader(t.TempDir(), t.TempDir())
+	if r.Version() != 0 {
+		t.Errorf("Version() = %d, want 0", r.Version())
+	}
+	if _, err := r.Read(); err == nil {
+		t.Error("expected error without a cgroup")
+	}
+}
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"some and full", testPressure, false},
+		{"some only", "some avg10=1.00 avg60=0.00 avg300=0.00 total=5\n", false},
+		{"empty", "", true},
+		{"unknown line", "other avg10=1.00\n", true},
+		{"invalid value", "some avg10=abc\n", true},
+		{"missing equals", "some avg10\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePressure(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePressure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
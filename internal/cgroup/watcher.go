@@ -0,0 +1,195 @@
+package cgroup
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/metrics"
+)
+
+// OOMKillWindow is how long an OOM kill seen by the watcher can be attributed
+// to an instance that exits. Kills no exit claims within the window, e.g. of
+// a child process the instance survived, are dropped.
+const OOMKillWindow = 5 * time.Second
+
+// Watcher periodically reads the cgroup, exports its counters and pressure
+// as metrics and tracks whether pressure is above the configured thresholds.
+//
+// Watcher is safe for concurrent use.
+type Watcher struct {
+	cfg    *config.CgroupConfig
+	reader *Reader
+	logger *slog.Logger
+
+	mu            sync.Mutex
+	stats         Stats
+	seenOOMKills  uint64      // oom_kill count at the last read
+	oomKills      []time.Time // When each unattributed OOM kill was seen
+	underPressure bool
+	reason        string
+}
+
+// NewWatcher creates a watcher for the cgroup read by reader. OOM kills that
+// happened before the watcher was created are not attributed to instances.
+func NewWatcher(cfg *config.CgroupConfig, reader *Reader, logger *slog.Logger) *Watcher {
+	w := &Watcher{
+		cfg:    cfg,
+		reader: reader,
+		logger: logger.With("component", "cgroup"),
+	}
+
+	if stats, err := reader.Read(); err == nil {
+		w.stats = stats
+		w.seenOOMKills = stats.MemoryEvents.OOMKill
+	}
+	return w
+}
+
+// Run polls the cgroup every configured interval until stop is closed
+func (w *Watcher) Run(stop <-chan struct{}) {
+	interval := time.Duration(w.cfg.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.Poll()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.Poll()
+		}
+	}
+}
+
+// Poll reads the cgroup once, updates metrics and logs new OOM kills and
+// pressure transitions
+func (w *Watcher) Poll() {
+	stats, err := w.reader.Read()
+	if err != nil {
+		w.logger.Debug("Failed to read cgroup", "error", err)
+		return
+	}
+
+	events := stats.MemoryEvents
+	metrics.SetCgroupMemoryEvent("low", events.Low)
+	metrics.SetCgroupMemoryEvent("high", events.High)
+	metrics.SetCgroupMemoryEvent("max", events.Max)
+	metrics.SetCgroupMemoryEvent("oom", events.OOM)
+	metrics.SetCgroupMemoryEvent("oom_kill", events.OOMKill)
+	setPressureMetrics("memory", stats.MemoryPressure)
+	setPressureMetrics("cpu", stats.CPUPressure)
+
+	high, reason := w.evaluate(stats)
+	metrics.SetCgroupUnderPressure(high)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if events.OOMKill > w.seenOOMKills {
+		w.logger.Warn("Kernel OOM killer terminated processes in the container",
+			"oom_kills", events.OOMKill-w.seenOOMKills,
+			"total", events.OOMKill,
+		)
+	}
+	now := time.Now()
+	w.observeOOMKills(events.OOMKill, now)
+	w.dropOOMKills(now.Add(-OOMKillWindow))
+
+	if high && !w.underPressure {
+		w.logger.Warn("Container under resource pressure", "reason", reason)
+	} else if !high && w.underPressure {
+		w.logger.Info("Container resource pressure recovered")
+	}
+
+	w.stats = stats
+	w.underPressure = high
+	w.reason = reason
+}
+
+// evaluate returns whether stats exceed the pressure thresholds and why
+func (w *Watcher) evaluate(stats Stats) (bool, string) {
+	if p := stats.MemoryPressure; p != nil && w.cfg.MemoryPressureThreshold >= 0 && p.Some.Avg10 >= w.cfg.MemoryPressureThreshold {
+		return true, fmt.Sprintf("memory pressure %.1f%% >= %.1f%%", p.Some.Avg10, w.cfg.MemoryPressureThreshold)
+	}
+	if p := stats.CPUPressure; p != nil && w.cfg.CPUPressureThreshold >= 0 && p.Some.Avg10 >= w.cfg.CPUPressureThreshold {
+		return true, fmt.Sprintf("cpu pressure %.1f%% >= %.1f%%", p.Some.Avg10, w.cfg.CPUPressureThreshold)
+	}
+	return false, ""
+}
+
+// Stats returns the snapshot from the last poll
+func (w *Watcher) Stats() Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
+
+// UnderPressure returns whether pressure was above the thresholds at the
+// last poll, with the reason
+func (w *Watcher) UnderPressure() (bool, string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.underPressure, w.reason
+}
+
+// ConsumeOOMKill reports whether the cgroup recorded an OOM kill within
+// OOMKillWindow before exitedAt that has not been attributed to an instance
+// yet, and attributes it. The counter is read fresh so a SIGKILLed instance
+// is matched even before the next poll.
+func (w *Watcher) ConsumeOOMKill(exitedAt time.Time) bool {
+	stats, err := w.reader.Read()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err == nil {
+		w.observeOOMKills(stats.MemoryEvents.OOMKill, time.Now())
+	}
+	w.dropOOMKills(exitedAt.Add(-OOMKillWindow))
+
+	if len(w.oomKills) == 0 {
+		return false
+	}
+	w.oomKills = w.oomKills[1:]
+	return true
+}
+
+// observeOOMKills records the kills counted since the last read as seen at
+// now. Must be called with w.mu held.
+func (w *Watcher) observeOOMKills(count uint64, now time.Time) {
+	if count < w.seenOOMKills {
+		w.seenOOMKills = count // Counter reset, e.g. a new cgroup
+	}
+	for ; w.seenOOMKills < count; w.seenOOMKills++ {
+		w.oomKills = append(w.oomKills, now)
+	}
+}
+
+// dropOOMKills forgets the unattributed kills seen before cutoff. Must be
+// called with w.mu held.
+func (w *Watcher) dropOOMKills(cutoff time.Time) {
+	i := 0
+	for i < len(w.oomKills) && w.oomKills[i].Before(cutoff) {
+		i++
+	}
+	w.oomKills = w.oomKills[i:]
+}
+
+func setPressureMetrics(resource string, p *Pressure) {
+	if p == nil {
+		return
+	}
+	for kind, line := range map[string]PressureLine{"some": p.Some, "full": p.Full} {
+		metrics.SetCgroupPressure(resource, kind, "avg10", line.Avg10)
+		metrics.SetCgroupPressure(resource, kind, "avg60", line.Avg60)
+		metrics.SetCgroupPressure(resource, kind, "avg300", line.Avg300)
+	}
+}
@@ -0,0 +1,134 @@
+package cgroup
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+const calmPressure = "some avg10=1.00 avg60=0.50 avg300=0.10 total=10\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"
+
+func testCgroupConfig() *config.CgroupConfig {
+	return &config.CgroupConfig{Interval: 1, MemoryPressureThreshold: 10, CPUPressureThreshold: 80}
+}
+
+func TestWatcher_ConsumeOOMKill(t *testing.T) {
+	root := t.TempDir()
+	fakeV2(t, root, 1, calmPressure)
+
+	w := NewWatcher(testCgroupConfig(), NewReader(root, ""), slog.New(slog.DiscardHandler))
+
+	// Kills before the watcher started are not attributed
+	if w.ConsumeOOMKill(time.Now()) {
+		t.Fatal("pre-existing OOM kill should not be attributed")
+	}
+
+	// Two new kills are attributed once each, without waiting for a poll
+	fakeV2(t, root, 3, calmPressure)
+	if !w.ConsumeOOMKill(time.Now()) || !w.ConsumeOOMKill(time.Now()) {
+		t.Fatal("expected two new OOM kills to be attributed")
+	}
+	if w.ConsumeOOMKill(time.Now()) {
+		t.Error("OOM kill attributed twice")
+	}
+}
+
+func TestWatcher_ConsumeOOMKill_Window(t *testing.T) {
+	root := t.TempDir()
+	fakeV2(t, root, 0, calmPressure)
+
+	w := NewWatcher(testCgroupConfig(), NewReader(root, ""), slog.New(slog.DiscardHandler))
+
+	// A kill seen by a poll long before the exit is not credited to it
+	fakeV2(t, root, 1, calmPressure)
+	w.Poll()
+	if w.ConsumeOOMKill(time.Now().Add(OOMKillWindow + time.Second)) {
+		t.Fatal("OOM kill outside the window should not be attributed")
+	}
+
+	// ...and is dropped rather than credited to a later exit
+	if w.ConsumeOOMKill(time.Now()) {
+		t.Error("stale OOM kill should have been dropped")
+	}
+
+	// A kill seen just before the exit is credited
+	fakeV2(t, root, 2, calmPressure)
+	w.Poll()
+	if !w.ConsumeOOMKill(time.Now()) {
+		t.Error("expected recent OOM kill to be attributed")
+	}
+}
+
+func TestWatcher_Pressure(t *testing.T) {
+	root := t.TempDir()
+	fakeV2(t, root, 0, calmPressure)
+
+	w := NewWatcher(testCgroupConfig(), NewReader(root, ""), slog.New(slog.DiscardHandler))
+	w.Poll()
+	if high, _ := w.UnderPressure(); high {
+		t.Fatal("calm cgroup reported under pressure")
+	}
+
+	fakeV2(t, root, 0, testPressure) // memory some avg10 = 12.5
+	w.Poll()
+	high, reason := w.UnderPressure()
+	if !high || reason != "memory pressure 12.5% >= 10.0%" {
+		t.Errorf("UnderPressure() = %v, %q", high, reason)
+	}
+	if got := w.Stats().MemoryPressure.Some.Avg10; got != 12.5 {
+		t.Errorf("Stats() memory avg10 = %.1f, want 12.5", got)
+	}
+
+	fakeV2(t, root, 0, calmPressure)
+	w.Poll()
+	if high, _ := w.UnderPressure(); high {
+		t.Error("pressure should have recovered")
+	}
+}
+
+func TestWatcher_IgnoredThreshold(t *testing.T) {
+	root := t.TempDir()
+	fakeV2(t, root, 0, testPressure)
+
+	cfg := testCgroupConfig()
+	cfg.MemoryPressureThreshold = -1
+	w := NewWatcher(cfg, NewReader(root, ""), slog.New(slog.DiscardHandler))
+	w.Poll()
+	if high, _ := w.UnderPressure(); high {
+		t.Error("memory pressure should be ignored with threshold -1")
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	root := t.TempDir()
+	fakeV2(t, root, 0, testPressure)
+
+	w := NewWatcher(testCgroupConfig(), NewReader(root, ""), slog.New(slog.DiscardHandler))
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		w.Run(stop)
+		close(done)
+	}()
+
+	// Run polls immediately
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if high, _ := w.UnderPressure(); high {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Run() did not poll")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() did not stop")
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import "slices"
+
+// autotunedProcess is a process sized by auto-tuning at startup
+type autotunedProcess struct {
+	userScale   int      // Scale from the config files
+	userCommand []string // Command from the config files
+	scale       int      // Scale chosen by auto-tuning
+	command     []string // Command chosen by auto-tuning
+}
+
+// SetAutotuned sets the scale and command auto-tuning chose for the named
+// process. Save writes the values from the config files back while the
+// process still runs with the auto-tuned ones.
+func (c *Config) SetAutotuned(name string, scale int, command []string) {
+	proc, ok := c.Processes[name]
+	if !ok {
+		return
+	}
+	if c.autotuned == nil {
+		c.autotuned = make(map[string]*autotunedProcess)
+	}
+	tuned, ok := c.autotuned[name]
+	if !ok {
+		tuned = &autotunedProcess{userScale: proc.Scale, userCommand: proc.Command}
+		c.autotuned[name] = tuned
+	}
+	tuned.scale = scale
+	tuned.command = command
+	proc.Scale = scale
+	proc.Command = command
+}
+
+// withoutAutotuned returns cfg with the auto-tuned scale and command of each
+// process replaced by the values from the config files. Values changed since
+// auto-tuning (e.g. scaled through the API) are kept.
+func withoutAutotuned(cfg *Config) *Config {
+	if len(cfg.autotuned) == 0 {
+		return cfg
+	}
+	out := *cfg
+	out.Processes = make(map[string]*Process, len(cfg.Processes))
+	for name, proc := range cfg.Processes {
+		tuned, ok := cfg.autotuned[name]
+		if !ok || proc == nil {
+			out.Processes[name] = proc
+			continue
+		}
+		restored := *proc
+		if restored.Scale == tuned.scale {
+			restored.Scale = tuned.userScale
+		}
+		if slices.Equal(restored.Command, tuned.command) {
+			restored.Command = tuned.userCommand
+		}
+		out.Processes[name] = &restored
+	}
+	return &out
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -81,6 +82,34 @@ func (c *Config) validateGlobal() error {
 	if c.Global.LogFormat != "json" && c.Global.LogFormat != "text" {
 		return fmt.Errorf("invalid log_format: %s", c.Global.LogFormat)
 	}
+	if c.Global.ProcessParallelism < 0 {
+		return fmt.Errorf("process_parallelism must not be negative (got %d)", c.Global.ProcessParallelism)
+	}
+	return c.validateGlobalScheduleLock()
+}
+
+// validateGlobalScheduleLock validates the backend of the schedule lock
+func (c *Config) validateGlobalScheduleLock() error {
+	lock := c.Global.ScheduleLock
+	if lock == nil {
+		return nil
+	}
+	switch lock.Backend {
+	case "file":
+		if lock.File == nil || lock.File.Dir == "" {
+			return fmt.Errorf("schedule_lock.file.dir is required for the file backend")
+		}
+	case "redis":
+		if lock.Redis == nil || lock.Redis.Address == "" {
+			return fmt.Errorf("schedule_lock.redis.address is required for the redis backend")
+		}
+	case "kubernetes":
+	default:
+		return fmt.Errorf("invalid schedule_lock.backend: %q (must be file, redis or kubernetes)", lock.Backend)
+	}
+	if lock.TTL < time.Second {
+		return fmt.Errorf("schedule_lock.ttl must be at least 1s (got %s)", lock.TTL)
+	}
 	return nil
 }
 
@@ -106,6 +135,63 @@ func (c *Config) validateProcess(name string, proc *Process) error {
 		return err
 	}
 
+	// Dependency condition validation
+	if err := c.validateProcessDependencies(name, proc); err != nil {
+		return err
+	}
+
+	// Job chain validation
+	if err := c.validateProcessTriggers(name, proc); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateProcessDependencies validates the options of long form depends_on
+// entries. Missing dependencies and cycles are detected by the dependency
+// graph.
+func (c *Config) validateProcessDependencies(name string, proc *Process) error {
+	for depName, dep := range proc.DependsOnOptions {
+		if dep == nil {
+			continue
+		}
+		if !contains(proc.DependsOn, depName) {
+			return fmt.Errorf("process %s has depends_on options for %s, which it does not depend on", name, depName)
+		}
+		if dep.Timeout < 0 {
+			return fmt.Errorf("process %s: depends_on.%s.timeout must not be negative", name, depName)
+		}
+
+		if err := dep.CheckCondition(c.Processes[depName]); err != nil {
+			return fmt.Errorf("process %s: depends_on.%s: %w", name, depName, err)
+		}
+	}
+	return nil
+}
+
+// validateProcessTriggers validates the processes started when a job
+// completes. Trigger cycles are detected when the manager starts.
+func (c *Config) validateProcessTriggers(name string, proc *Process) error {
+	targets := proc.Triggers.Targets()
+	if len(targets) == 0 {
+		return nil
+	}
+	if !proc.IsJob() {
+		return fmt.Errorf("process %s has triggers but is not a oneshot or scheduled process", name)
+	}
+	for _, target := range targets {
+		if target == name {
+			return fmt.Errorf("process %s triggers itself", name)
+		}
+		targetProc, exists := c.Processes[target]
+		if !exists {
+			return fmt.Errorf("process %s triggers non-existent process %q", name, target)
+		}
+		if !targetProc.IsJob() {
+			return fmt.Errorf("process %s triggers %s, which is not a oneshot or scheduled process", name, target)
+		}
+	}
 	return nil
 }
 
@@ -173,8 +259,7 @@ func (c *Config) validateProcessSchedule(name string, proc *Process) error {
 		return nil
 	}
 
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	if _, err := parser.Parse(proc.Schedule); err != nil {
+	if _, err := scheduleParser.Parse(proc.Schedule); err != nil {
 		return fmt.Errorf("process %s has invalid schedule expression %q: %w", name, proc.Schedule, err)
 	}
 
@@ -195,6 +280,79 @@ func (c *Config) validateProcessSchedule(name string, proc *Process) error {
 		return fmt.Errorf("process %s has invalid schedule_max_concurrent: %d (must be >= 0)", name, proc.ScheduleMaxConcurrent)
 	}
 
+	// Validate overlap policy
+	switch proc.ScheduleOverlap {
+	case "", "skip", "queue", "replace", "allow":
+	default:
+		return fmt.Errorf("process %s has invalid schedule_overlap: %s (must be skip, queue, replace or allow)", name, proc.ScheduleOverlap)
+	}
+	if proc.ScheduleQueueSize < 0 {
+		return fmt.Errorf("process %s has invalid schedule_queue_size: %d (must be >= 0)", name, proc.ScheduleQueueSize)
+	}
+
+	if proc.ScheduleDisableAfter < 0 {
+		return fmt.Errorf("process %s has invalid schedule_disable_after: %d (must be >= 0)", name, proc.ScheduleDisableAfter)
+	}
+
+	// Validate jitter and calendar constraints
+	if proc.ScheduleJitter < 0 {
+		return fmt.Errorf("process %s has negative schedule_jitter: %s", name, proc.ScheduleJitter)
+	}
+	for _, window := range proc.ScheduleBlackout {
+		if err := parseScheduleWindow(window); err != nil {
+			return fmt.Errorf("process %s has invalid schedule_blackout: %w", name, err)
+		}
+	}
+	for _, date := range proc.ScheduleSkipDates {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("process %s has invalid schedule_skip_dates entry %q (expected YYYY-MM-DD)", name, date)
+		}
+	}
+
+	if retry := proc.ScheduleRetry; retry != nil {
+		if retry.MaxAttempts < 1 {
+			return fmt.Errorf("process %s has invalid schedule_retry.max_attempts: %d (must be >= 1)", name, retry.MaxAttempts)
+		}
+		if retry.Backoff < 0 || retry.MaxBackoff < 0 {
+			return fmt.Errorf("process %s has negative schedule_retry backoff", name)
+		}
+		if retry.Jitter > 1 || (retry.Jitter < 0 && retry.Jitter != -1) {
+			return fmt.Errorf("process %s has invalid schedule_retry.jitter: %.2f (must be 0-1 or -1)", name, retry.Jitter)
+		}
+	}
+
+	if output := proc.ScheduleOutput; output != nil {
+		if output.HeadLines < 0 || output.TailLines < 0 || output.MaxLineLength < 0 {
+			return fmt.Errorf("process %s has negative schedule_output limits", name)
+		}
+	}
+
+	return nil
+}
+
+// scheduleParser accepts the same expressions as the scheduler: 5 fields,
+// 6 fields with leading seconds and descriptors like "@every 90s"
+var scheduleParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// parseScheduleWindow checks a schedule_blackout window in "HH:MM-HH:MM" format
+func parseScheduleWindow(window string) error {
+	from, to, ok := strings.Cut(window, "-")
+	if !ok {
+		return fmt.Errorf("%q (expected HH:MM-HH:MM)", window)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return fmt.Errorf("%q: start must be HH:MM", window)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return fmt.Errorf("%q: end must be HH:MM", window)
+	}
+	if start.Equal(end) {
+		return fmt.Errorf("%q: start and end are equal", window)
+	}
 	return nil
 }
 
@@ -240,6 +398,14 @@ func (c *Config) hasCycle(name string, visited, recStack map[string]bool) bool {
 // Save writes the configuration to a YAML file at the specified path.
 // The file is created with 0644 permissions. Existing files are overwritten.
 func Save(path string, cfg *Config) error {
+	// Write the configured sizing instead of the auto-tuned one
+	cfg = withoutAutotuned(cfg)
+
+	// Write secret references back instead of the resolved values
+	if secrets := cfg.Secrets(); secrets.Len() > 0 {
+		cfg = copyStrings(cfg, "", secrets.unresolveField)
+	}
+
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -116,6 +117,22 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid log_level",
 		},
+		{
+			name: "negative process parallelism",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					ProcessParallelism: -1,
+				},
+				Processes: map[string]*Process{
+					"test": {Command: []string{"sleep", "1"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "process_parallelism must not be negative",
+		},
 		{
 			name: "invalid log format",
 			config: &Config{
@@ -556,6 +573,44 @@ func TestSave(t *testing.T) {
 	}
 }
 
+func TestSave_KeepsConfiguredSizing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "phpeek-pm.yaml")
+	cfg := &Config{
+		Version: "1.0",
+		Processes: map[string]*Process{
+			"octane": {Enabled: true, Type: "longrun", Command: []string{"php", "artisan", "octane:start"}, Scale: 1},
+			"queue":  {Enabled: true, Type: "longrun", Command: []string{"php", "artisan", "queue:work"}, Scale: 2},
+			"worker": {Enabled: true, Type: "longrun", Command: []string{"php", "worker.php"}, Scale: 1},
+		},
+	}
+
+	cfg.SetAutotuned("octane", 1, []string{"php", "artisan", "octane:start", "--workers=6"})
+	cfg.SetAutotuned("queue", 8, cfg.Processes["queue"].Command)
+	cfg.SetAutotuned("worker", 4, cfg.Processes["worker"].Command)
+	cfg.Processes["worker"].Scale = 3 // Scaled after auto-tuning
+
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	saved, err := LoadWithEnvExpansion(path)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+
+	if got := saved.Processes["octane"].Command; strings.Join(got, " ") != "php artisan octane:start" {
+		t.Errorf("octane command = %v, want the configured command", got)
+	}
+	if got := saved.Processes["queue"].Scale; got != 2 {
+		t.Errorf("queue scale = %d, want configured 2", got)
+	}
+	if got := saved.Processes["worker"].Scale; got != 3 {
+		t.Errorf("worker scale = %d, want 3 set after auto-tuning", got)
+	}
+	if cfg.Processes["queue"].Scale != 8 || len(cfg.Processes["octane"].Command) != 4 {
+		t.Error("Save() must not modify the config")
+	}
+}
+
 func TestValidate_ProcessSchedule(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -713,6 +768,213 @@ func TestValidate_ProcessSchedule(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid schedule_retry with defaults",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"retry-job": {
+						Enabled:       true,
+						Type:          "oneshot",
+						Command:       []string{"echo", "hello"},
+						Schedule:      "0 * * * *",
+						ScheduleRetry: &ScheduleRetry{},
+						Restart:       "never",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid schedule_retry jitter",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"jitter-job": {
+						Enabled:       true,
+						Type:          "oneshot",
+						Command:       []string{"echo", "hello"},
+						Schedule:      "0 * * * *",
+						ScheduleRetry: &ScheduleRetry{Jitter: 2},
+						Restart:       "never",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid negative schedule_disable_after",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"breaker-job": {
+						Enabled:              true,
+						Type:                 "oneshot",
+						Command:              []string{"echo", "hello"},
+						Schedule:             "0 * * * *",
+						ScheduleDisableAfter: -1,
+						Restart:              "never",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid schedule_overlap queue",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"queued-job": {
+						Enabled:           true,
+						Type:              "oneshot",
+						Command:           []string{"echo", "hello"},
+						Schedule:          "0 * * * *",
+						ScheduleOverlap:   "queue",
+						ScheduleQueueSize: 3,
+						Restart:           "never",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid schedule_overlap",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"overlap-job": {
+						Enabled:         true,
+						Type:            "oneshot",
+						Command:         []string{"echo", "hello"},
+						Schedule:        "0 * * * *",
+						ScheduleOverlap: "parallel",
+						Restart:         "never",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid seconds schedule",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"calendar-job": {
+						Enabled:  true,
+						Type:     "oneshot",
+						Command:  []string{"echo", "hello"},
+						Schedule: "*/30 * * * * *",
+						Restart:  "never",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid every schedule",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"calendar-job": {
+						Enabled:  true,
+						Type:     "oneshot",
+						Command:  []string{"echo", "hello"},
+						Schedule: "@every 90s",
+						Restart:  "never",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid calendar and jitter",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"calendar-job": {
+						Enabled:              true,
+						Type:                 "oneshot",
+						Command:              []string{"echo", "hello"},
+						Schedule:             "0 * * * *",
+						ScheduleJitter:       30 * time.Second,
+						ScheduleBlackout:     []string{"22:00-06:00"},
+						ScheduleWeekdaysOnly: true,
+						ScheduleSkipDates:    []string{"2025-12-25"},
+						Restart:              "never",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid schedule_blackout",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"calendar-job": {
+						Enabled:          true,
+						Type:             "oneshot",
+						Command:          []string{"echo", "hello"},
+						Schedule:         "0 * * * *",
+						ScheduleBlackout: []string{"22:00"},
+						Restart:          "never",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid schedule_skip_dates",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"calendar-job": {
+						Enabled:           true,
+						Type:              "oneshot",
+						Command:           []string{"echo", "hello"},
+						Schedule:          "0 * * * *",
+						ScheduleSkipDates: []string{"25.12.2025"},
+						Restart:           "never",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid negative schedule_jitter",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"calendar-job": {
+						Enabled:        true,
+						Type:           "oneshot",
+						Command:        []string{"echo", "hello"},
+						Schedule:       "0 * * * *",
+						ScheduleJitter: -time.Second,
+						Restart:        "never",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid negative schedule_queue_size",
+			config: &Config{
+				Version: "1.0",
+				Processes: map[string]*Process{
+					"queued-job": {
+						Enabled:           true,
+						Type:              "oneshot",
+						Command:           []string{"echo", "hello"},
+						Schedule:          "0 * * * *",
+						ScheduleOverlap:   "queue",
+						ScheduleQueueSize: -1,
+						Restart:           "never",
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -725,3 +987,176 @@ func TestValidate_ProcessSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_ScheduleLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		lock    *ScheduleLockConfig
+		wantErr string
+	}{
+		{name: "no lock"},
+		{name: "file", lock: &ScheduleLockConfig{Backend: "file", TTL: 30 * time.Second, File: &FileLockConfig{Dir: "/shared/locks"}}},
+		{name: "redis", lock: &ScheduleLockConfig{Backend: "redis", TTL: 30 * time.Second, Redis: &RedisLockConfig{Address: "redis:6379"}}},
+		{name: "kubernetes in cluster", lock: &ScheduleLockConfig{Backend: "kubernetes", TTL: 30 * time.Second}},
+		{name: "file without dir", lock: &ScheduleLockConfig{Backend: "file", TTL: 30 * time.Second}, wantErr: "schedule_lock.file.dir is required"},
+		{name: "redis without address", lock: &ScheduleLockConfig{Backend: "redis", TTL: 30 * time.Second, Redis: &RedisLockConfig{}}, wantErr: "schedule_lock.redis.address is required"},
+		{name: "unknown backend", lock: &ScheduleLockConfig{Backend: "etcd", TTL: 30 * time.Second}, wantErr: "invalid schedule_lock.backend"},
+		{name: "ttl too short", lock: &ScheduleLockConfig{Backend: "kubernetes", TTL: 500 * time.Millisecond}, wantErr: "schedule_lock.ttl must be at least 1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Global: GlobalConfig{LogLevel: "info", LogFormat: "json", ScheduleLock: tt.lock},
+				Processes: map[string]*Process{
+					"job": {Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"true"}, Restart: "never", Scale: 1, Schedule: "* * * * *"},
+				},
+			}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ScheduleOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  *ScheduleOutput
+		wantErr bool
+	}{
+		{name: "no output config"},
+		{name: "valid", output: &ScheduleOutput{HeadLines: 10, TailLines: 20, MaxLineLength: 512}},
+		{name: "negative head lines", output: &ScheduleOutput{HeadLines: -1, TailLines: 20, MaxLineLength: 512}, wantErr: true},
+		{name: "negative line length", output: &ScheduleOutput{HeadLines: 10, TailLines: 20, MaxLineLength: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Global: GlobalConfig{LogLevel: "info", LogFormat: "json"},
+				Processes: map[string]*Process{
+					"job": {Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"true"}, Restart: "never", Scale: 1, Schedule: "* * * * *", ScheduleOutput: tt.output},
+				},
+			}
+			err := cfg.Validate()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "negative schedule_output limits") {
+					t.Errorf("Validate() error = %v, want negative schedule_output limits", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_DependencyConditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]*Dependency
+		wantErr string
+	}{
+		{name: "short form"},
+		{name: "all conditions", options: map[string]*Dependency{
+			"migrate": {Condition: DependencyCompletedSuccessfully, Timeout: 10 * time.Minute},
+			"redis":   {Condition: DependencyHealthy},
+			"web":     {Condition: DependencyStarted},
+		}},
+		{name: "unknown condition", options: map[string]*Dependency{"redis": {Condition: "ready"}}, wantErr: `depends_on.redis: invalid condition "ready"`},
+		{name: "negative timeout", options: map[string]*Dependency{"redis": {Timeout: -time.Second}}, wantErr: "must not be negative"},
+		{name: "not a dependency", options: map[string]*Dependency{"ghost": {}}, wantErr: "which it does not depend on"},
+		{name: "healthy without health check", options: map[string]*Dependency{"web": {Condition: DependencyHealthy}}, wantErr: "depends_on.web: cannot become healthy: no health check"},
+		{name: "completed on longrun", options: map[string]*Dependency{"web": {Condition: DependencyCompletedSuccessfully}}, wantErr: "depends_on.web: cannot complete successfully: not a oneshot process"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Global: GlobalConfig{LogLevel: "info", LogFormat: "json"},
+				Processes: map[string]*Process{
+					"app": {
+						Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1,
+						DependsOn:        []string{"migrate", "redis", "web"},
+						DependsOnOptions: tt.options,
+					},
+					"migrate": {Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"true"}, Restart: "never", Scale: 1},
+					"redis": {
+						Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1,
+						HealthCheck: &HealthCheck{Type: "tcp", Address: "127.0.0.1:6379", Period: 10, Timeout: 5, FailureThreshold: 3},
+					},
+					"web": {Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1},
+				},
+			}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_Triggers(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   *Process
+		triggers *ProcessTriggers
+		wantErr  string
+	}{
+		{name: "oneshot to oneshot", triggers: &ProcessTriggers{OnSuccess: []string{"load"}, OnFailure: []string{"report"}}},
+		{name: "empty triggers", triggers: &ProcessTriggers{}},
+		{name: "missing target", triggers: &ProcessTriggers{OnSuccess: []string{"ghost"}}, wantErr: "non-existent process"},
+		{name: "self trigger", triggers: &ProcessTriggers{OnFailure: []string{"extract"}}, wantErr: "triggers itself"},
+		{name: "longrun target", triggers: &ProcessTriggers{OnSuccess: []string{"web"}}, wantErr: "not a oneshot or scheduled process"},
+		{
+			name:     "longrun source",
+			source:   &Process{Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1},
+			triggers: &ProcessTriggers{OnSuccess: []string{"load"}},
+			wantErr:  "has triggers but is not a oneshot or scheduled process",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			if source == nil {
+				source = &Process{Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"true"}, Restart: "never", Scale: 1}
+			}
+			source.Triggers = tt.triggers
+			cfg := &Config{
+				Global: GlobalConfig{LogLevel: "info", LogFormat: "json"},
+				Processes: map[string]*Process{
+					"extract": source,
+					"load":    {Enabled: true, Type: "oneshot", InitialState: "stopped", Command: []string{"true"}, Restart: "never", Scale: 1},
+					"report":  {Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"true"}, Restart: "never", Scale: 1, Schedule: "0 0 * * *"},
+					"web":     {Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1},
+				},
+			}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
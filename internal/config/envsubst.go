@@ -12,53 +12,73 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// envPattern matches ${VAR}, ${VAR:-default} and ${VAR:?message}, each
+// optionally written with an explicit env: scheme (${env:VAR:?message})
+var envPattern = regexp.MustCompile(`\$\{(?:env:)?([^}:]+)(?::([-?])([^}]*))?\}`)
+
 // ExpandEnv expands environment variables in config content
-// Supports ${VAR:-default} and ${VAR} syntax
+// Supports ${VAR:-default}, ${VAR} and ${VAR:?message} syntax. Missing
+// required variables expand to "" (see expandEnv for the error).
 func ExpandEnv(content string) string {
-	// Pattern: ${VAR:-default} or ${VAR}
-	pattern := regexp.MustCompile(`\$\{([^}:]+)(?::-([^}]*))?\}`)
+	expanded, _ := expandEnv(content)
+	return expanded
+}
 
-	return pattern.ReplaceAllStringFunc(content, func(match string) string {
-		parts := pattern.FindStringSubmatch(match)
-		if len(parts) < 2 {
-			return match
-		}
+// expandEnv expands environment variables in config content and fails on
+// unset or empty ${VAR:?message} variables
+func expandEnv(content string) (string, error) {
+	var missing []string
 
-		varName := parts[1]
-		defaultValue := ""
-		if len(parts) >= 3 {
-			defaultValue = parts[2]
-		}
+	expanded := envPattern.ReplaceAllStringFunc(content, func(match string) string {
+		parts := envPattern.FindStringSubmatch(match)
+		varName, op, arg := parts[1], parts[2], parts[3]
 
 		// Get from environment or use default
 		if value := os.Getenv(varName); value != "" {
 			return value
 		}
+		if op == "?" {
+			if arg == "" {
+				arg = "required variable is not set"
+			}
+			missing = append(missing, fmt.Sprintf("%s: %s", varName, arg))
+			return ""
+		}
 
-		return defaultValue
+		return arg
 	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%s", strings.Join(missing, "; "))
+	}
+	return expanded, nil
 }
 
-// LoadWithEnvExpansion loads config file, expands env vars, and applies ENV overrides
+// LoadWithEnvExpansion loads config file with its includes and conf.d drop-ins,
+// expands env vars, applies the profile selected by PHPEEK_PM_PROFILE and
+// applies ENV overrides
 func LoadWithEnvExpansion(path string) (*Config, error) {
-	rawConfig := map[string]interface{}{}
+	return load(path, LoadOptions{Profile: ProfileFromEnv()})
+}
 
-	content, err := os.ReadFile(path)
+// load reads, merges, decodes, defaults and validates a configuration
+func load(path string, opts LoadOptions) (*Config, error) {
+	rawConfig, sources, err := loadConfigTree(path)
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to read config: %w", err)
-		}
-		fmt.Fprintf(os.Stderr, "ℹ️  No config file found at %s, using environment variables only\n", path)
-	} else {
-		expanded := ExpandEnv(string(content))
-		if err := yaml.Unmarshal([]byte(expanded), &rawConfig); err != nil {
-			return nil, fmt.Errorf("failed to parse config: %w", err)
+		return nil, err
+	}
+
+	if opts.Profile != "" {
+		if err := applyProfile(rawConfig, opts.Profile, sources); err != nil {
+			return nil, err
 		}
 	}
 
+	before := flattenTree(rawConfig, "")
 	if err := applyEnvOverridesMap(rawConfig); err != nil {
 		return nil, err
 	}
+	recordOrigins(before, rawConfig, OriginEnv, sources.Origins)
 
 	mergedBytes, err := yaml.Marshal(rawConfig)
 	if err != nil {
@@ -74,6 +94,8 @@ func LoadWithEnvExpansion(path string) (*Config, error) {
 		cfg.Processes = make(map[string]*Process)
 	}
 
+	cfg.Sources = sources
+
 	cfg.SetDefaults()
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -173,6 +173,54 @@ processes:
 	}
 }
 
+func TestLoadWithEnvExpansion_AutotuneProfiles(t *testing.T) {
+	configContent := `version: "1.0"
+global:
+  autotune:
+    profiles:
+      symfony-api:
+        extends: medium
+        avg_memory_per_worker: ${API_WORKER_MB:-64}
+        max_workers: 0
+        spare_min_ratio: 0.1
+
+processes:
+  php-fpm:
+    enabled: true
+    command: ["php-fpm", "-F"]
+`
+
+	configPath := filepath.Join(t.TempDir(), "test-config.yaml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadWithEnvExpansion(configPath)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+
+	profile := cfg.Global.AutotuneProfiles()["symfony-api"]
+	if profile == nil || profile.Extends != "medium" {
+		t.Fatalf("expected symfony-api profile extending medium, got %+v", profile)
+	}
+	if profile.AvgMemoryPerWorker == nil || *profile.AvgMemoryPerWorker != 64 {
+		t.Errorf("AvgMemoryPerWorker = %v, want 64", profile.AvgMemoryPerWorker)
+	}
+	// Explicit zero is distinguishable from unset
+	if profile.MaxWorkers == nil || *profile.MaxWorkers != 0 {
+		t.Errorf("MaxWorkers = %v, want explicit 0", profile.MaxWorkers)
+	}
+	if profile.MinWorkers != nil {
+		t.Errorf("MinWorkers should be unset, got %v", *profile.MinWorkers)
+	}
+
+	var empty *GlobalConfig
+	if empty.AutotuneProfiles() != nil {
+		t.Error("AutotuneProfiles() should be nil-safe")
+	}
+}
+
 func TestLoadWithEnvExpansion_InvalidFile(t *testing.T) {
 	_, err := LoadWithEnvExpansion("/nonexistent/config.yaml")
 	if err == nil {
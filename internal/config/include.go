@@ -0,0 +1,413 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config files are assembled from several sources, merged in this order
+// (later sources win). Each file has ${VAR} expanded and secret references
+// resolved (see secrets.go) before it is merged. Secrets in profiles are only
+// resolved for the selected profile.
+//  1. Files listed under "include:" (relative to the including file, globs
+//     allowed), each merged before the file that includes it
+//  2. The main config file
+//  3. /etc/phpeek-pm/conf.d/*.yaml and *.yml, then those of the conf.d next
+//     to the main file if that is another directory, each directory in
+//     lexical order and each file with its own includes
+//  4. The selected profile from the "profiles:" section (see Profile)
+//  5. PHPEEK_PM_* environment variable overrides
+//
+// Maps are merged key by key, so processes are merged by name. Scalars and
+// lists replace the previous value. An explicit null deletes the key, e.g.
+// "processes: {horizon: null}" removes a process defined by an earlier file.
+
+const (
+	// includeKey is the top-level key listing files to include
+	includeKey = "include"
+
+	// profilesKey is the top-level key holding per-environment overlays
+	profilesKey = "profiles"
+
+	// confDirName is the drop-in directory next to the main config file
+	confDirName = "conf.d"
+
+	// OriginEnv is the origin of values set by environment variable overrides
+	OriginEnv = "env"
+)
+
+// systemConfDir is the drop-in directory merged into every configuration,
+// wherever the main file is
+var systemConfDir = "/etc/phpeek-pm/conf.d"
+
+// Sources records the files a configuration was assembled from and which
+// source set each value.
+type Sources struct {
+	Files    []string          `json:"files"`               // Loaded files in merge order
+	ConfDirs []string          `json:"conf_dirs,omitempty"` // Merged drop-in directories in merge order
+	Profile  string            `json:"profile,omitempty"`   // Applied profile ("" = none)
+	Origins  map[string]string `json:"origins"`             // Dotted key path -> file (or OriginEnv) that set it last
+
+	secrets *Secrets // Secret values resolved from the files
+}
+
+// Origin returns the source that set the value at the dotted key path (e.g.
+// "processes.queue.command"), falling back to the closest parent that was
+// set as a whole. Returns "" for defaults.
+func (s *Sources) Origin(path string) string {
+	if s == nil {
+		return ""
+	}
+	for p := path; p != ""; {
+		if origin, ok := s.Origins[p]; ok {
+			return origin
+		}
+		idx := strings.LastIndex(p, ".")
+		if idx < 0 {
+			break
+		}
+		p = p[:idx]
+	}
+	return ""
+}
+
+// Keys returns the dotted key paths with a recorded origin, sorted
+func (s *Sources) Keys() []string {
+	if s == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(s.Origins))
+	for key := range s.Origins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WatchPaths returns the files and directories to watch for changes
+func (s *Sources) WatchPaths() []string {
+	if s == nil {
+		return nil
+	}
+	paths := append([]string(nil), s.Files...)
+	return append(paths, s.ConfDirs...)
+}
+
+// CheckSave returns an error if writing the config back to the main file
+// would lose how it was assembled, i.e. copy values from includes, drop-ins
+// or the applied profile into it
+func (s *Sources) CheckSave() error {
+	if s == nil {
+		return nil
+	}
+	if len(s.Files) > 1 {
+		return fmt.Errorf("config is merged from %d files (includes or conf.d), edit them instead", len(s.Files))
+	}
+	if s.Profile != "" {
+		return fmt.Errorf("profile %q is applied, edit the config file instead", s.Profile)
+	}
+	return nil
+}
+
+// sourceLoader reads config files and merges them into one raw tree
+type sourceLoader struct {
+	sources  *Sources
+	loading  map[string]bool        // Files being loaded, for include cycle detection
+	profiles map[string]interface{} // Profile overlays, merged across files
+}
+
+// loadConfigTree reads the main config file with its includes and conf.d
+// drop-ins and merges them into one raw tree. A missing main file is not an
+// error (environment variables only).
+func loadConfigTree(path string) (map[string]interface{}, *Sources, error) {
+	l := &sourceLoader{
+		sources:  &Sources{Origins: make(map[string]string), secrets: newSecrets()},
+		loading:  make(map[string]bool),
+		profiles: make(map[string]interface{}),
+	}
+	raw := map[string]interface{}{}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+
+	if _, err := os.Stat(absPath); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("failed to read config: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "ℹ️  No config file found at %s, using environment variables only\n", path)
+	} else if err := l.loadFile(absPath, raw); err != nil {
+		return nil, nil, err
+	}
+
+	for _, confDir := range confDirs(absPath) {
+		if info, err := os.Stat(confDir); err != nil || !info.IsDir() {
+			continue
+		}
+		l.sources.ConfDirs = append(l.sources.ConfDirs, confDir)
+		files, err := confDirFiles(confDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, file := range files {
+			if err := l.loadFile(file, raw); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if len(l.profiles) > 0 {
+		raw[profilesKey] = l.profiles
+	}
+	return raw, l.sources, nil
+}
+
+// loadFile merges the includes of path and then path itself into raw
+func (l *sourceLoader) loadFile(path string, raw map[string]interface{}) error {
+	if l.loading[path] {
+		return fmt.Errorf("include cycle detected at %s", path)
+	}
+	l.loading[path] = true
+	defer delete(l.loading, path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	doc := map[string]interface{}{}
+	expanded, err := expandEnv(string(content))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := yaml.Unmarshal([]byte(expanded), &doc); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	// Profiles are overlays: their nulls must survive until one is applied,
+	// and their secrets are only resolved if it is
+	if err := l.collectProfiles(doc[profilesKey]); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	delete(doc, profilesKey)
+
+	if err := l.sources.secrets.resolveTree(doc, ""); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	includes, err := includePatterns(doc[includeKey])
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	delete(doc, includeKey)
+
+	for _, pattern := range includes {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid include pattern %q: %w", path, pattern, err)
+		}
+		if len(matches) == 0 && !hasGlobMeta(pattern) {
+			return fmt.Errorf("%s: included file not found: %s", path, pattern)
+		}
+		for _, match := range matches {
+			if err := l.loadFile(match, raw); err != nil {
+				return err
+			}
+		}
+	}
+
+	l.sources.Files = append(l.sources.Files, path)
+	mergeTree(raw, doc, "", path, l.sources.Origins)
+	return nil
+}
+
+// collectProfiles merges the profiles section of a file into l.profiles.
+// Profiles with the same name in several files are deep-merged.
+func (l *sourceLoader) collectProfiles(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	profiles, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("profiles must be a mapping of profile names, got %T", value)
+	}
+	for name, overlay := range profiles {
+		l.profiles[name] = mergeOverlay(l.profiles[name], overlay)
+	}
+	return nil
+}
+
+// includePatterns returns the include list, which may be a single string
+func includePatterns(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		patterns := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok || s == "" {
+				return nil, fmt.Errorf("include entries must be file paths, got %v", item)
+			}
+			patterns = append(patterns, s)
+		}
+		return patterns, nil
+	default:
+		return nil, fmt.Errorf("include must be a path or a list of paths, got %T", value)
+	}
+}
+
+// confDirs returns the drop-in directories for the main file at path, in
+// merge order
+func confDirs(path string) []string {
+	system := filepath.Clean(systemConfDir)
+	local := filepath.Join(filepath.Dir(path), confDirName)
+	if local == system {
+		return []string{system}
+	}
+	return []string{system, local}
+}
+
+// confDirFiles returns the YAML files of a drop-in directory in lexical order
+func confDirFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	return files, nil
+}
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// mergeTree deep-merges src into dst, recording file as the origin of every
+// value it sets. Maps merge key by key, other values replace, nil deletes.
+func mergeTree(dst, src map[string]interface{}, prefix, file string, origins map[string]string) {
+	for key, value := range src {
+		path := joinKeyPath(prefix, key)
+
+		if value == nil {
+			delete(dst, key)
+			deleteOrigins(origins, path)
+			continue
+		}
+
+		if srcMap, ok := value.(map[string]interface{}); ok {
+			dstMap, ok := dst[key].(map[string]interface{})
+			if !ok {
+				deleteOrigins(origins, path)
+				dstMap = map[string]interface{}{}
+				dst[key] = dstMap
+			}
+			mergeTree(dstMap, srcMap, path, file, origins)
+			continue
+		}
+
+		deleteOrigins(origins, path)
+		dst[key] = value
+		origins[path] = file
+	}
+}
+
+// mergeOverlay deep-merges two overlays, keeping nulls so they still delete
+// keys when the result is applied with mergeTree
+func mergeOverlay(dst, src interface{}) interface{} {
+	dstMap, dstOK := dst.(map[string]interface{})
+	srcMap, srcOK := src.(map[string]interface{})
+	if !dstOK || !srcOK {
+		return src
+	}
+	merged := make(map[string]interface{}, len(dstMap)+len(srcMap))
+	for key, value := range dstMap {
+		merged[key] = value
+	}
+	for key, value := range srcMap {
+		merged[key] = mergeOverlay(merged[key], value)
+	}
+	return merged
+}
+
+// copyTree returns a deep copy of the maps and lists of a raw tree
+func copyTree(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = copyTree(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = copyTree(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// recordOrigins sets origin for every leaf of raw that differs from before
+func recordOrigins(before, after map[string]interface{}, origin string, origins map[string]string) {
+	previous := flattenTree(before, "")
+	for path, value := range flattenTree(after, "") {
+		if old, ok := previous[path]; !ok || !reflect.DeepEqual(old, value) {
+			origins[path] = origin
+		}
+	}
+}
+
+// flattenTree returns the leaves of a raw tree keyed by dotted path
+func flattenTree(tree map[string]interface{}, prefix string) map[string]interface{} {
+	leaves := make(map[string]interface{})
+	for key, value := range tree {
+		path := joinKeyPath(prefix, key)
+		if m, ok := value.(map[string]interface{}); ok {
+			for p, v := range flattenTree(m, path) {
+				leaves[p] = v
+			}
+			continue
+		}
+		leaves[path] = value
+	}
+	return leaves
+}
+
+// deleteOrigins removes the origin of path and everything below it
+func deleteOrigins(origins map[string]string, path string) {
+	delete(origins, path)
+	for key := range origins {
+		if strings.HasPrefix(key, path+".") {
+			delete(origins, key)
+		}
+	}
+}
+
+func joinKeyPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
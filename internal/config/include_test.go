@@ -0,0 +1,258 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfigFiles creates files relative to dir
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// setSystemConfDir points the system drop-in directory at dir for one test
+func setSystemConfDir(t *testing.T, dir string) {
+	t.Helper()
+	prev := systemConfDir
+	systemConfDir = dir
+	t.Cleanup(func() { systemConfDir = prev })
+}
+
+func TestLoadWithEnvExpansion_IncludesAndConfDir(t *testing.T) {
+	dir := t.TempDir()
+	setSystemConfDir(t, filepath.Join(dir, "missing"))
+	writeConfigFiles(t, dir, map[string]string{
+		"base/global.yaml": `
+global:
+  log_level: warn
+  shutdown_timeout: 45
+processes:
+  php-fpm:
+    command: ["php-fpm", "-F"]
+    env:
+      APP_ENV: production
+  horizon:
+    command: ["php", "artisan", "horizon"]
+`,
+		"phpeek-pm.yaml": `
+include:
+  - base/*.yaml
+global:
+  log_level: info
+processes:
+  php-fpm:
+    command: ["php-fpm", "-F", "-R"]
+`,
+		"conf.d/10-app.yaml": `
+processes:
+  horizon: null
+  queue:
+    command: ["php", "artisan", "queue:work"]
+    scale: 2
+  php-fpm:
+    env:
+      APP_DEBUG: "false"
+`,
+		"conf.d/README.md": "not a config file",
+	})
+
+	cfg, err := LoadWithEnvExpansion(filepath.Join(dir, "phpeek-pm.yaml"))
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+
+	// Scalars: the main file overrides its include
+	if cfg.Global.LogLevel != "info" || cfg.Global.ShutdownTimeout != 45 {
+		t.Errorf("log_level = %s, shutdown_timeout = %d", cfg.Global.LogLevel, cfg.Global.ShutdownTimeout)
+	}
+
+	// Processes merge by name, null deletes
+	if _, ok := cfg.Processes["horizon"]; ok {
+		t.Error("horizon should be deleted by conf.d null")
+	}
+	if cfg.Processes["queue"] == nil || cfg.Processes["queue"].Scale != 2 {
+		t.Errorf("queue should be added by conf.d: %+v", cfg.Processes["queue"])
+	}
+
+	// Lists replace, maps merge
+	fpm := cfg.Processes["php-fpm"]
+	if !reflect.DeepEqual(fpm.Command, []string{"php-fpm", "-F", "-R"}) {
+		t.Errorf("php-fpm command = %v", fpm.Command)
+	}
+	if fpm.Env["APP_ENV"] != "production" || fpm.Env["APP_DEBUG"] != "false" {
+		t.Errorf("php-fpm env = %v, want merged env", fpm.Env)
+	}
+
+	// Sources and origins
+	src := cfg.Sources
+	wantFiles := []string{
+		filepath.Join(dir, "base/global.yaml"),
+		filepath.Join(dir, "phpeek-pm.yaml"),
+		filepath.Join(dir, "conf.d/10-app.yaml"),
+	}
+	if !reflect.DeepEqual(src.Files, wantFiles) {
+		t.Errorf("Files = %v, want %v", src.Files, wantFiles)
+	}
+	if !reflect.DeepEqual(src.ConfDirs, []string{filepath.Join(dir, "conf.d")}) || len(src.WatchPaths()) != 4 {
+		t.Errorf("ConfDirs = %v, WatchPaths = %v", src.ConfDirs, src.WatchPaths())
+	}
+
+	origins := map[string]string{
+		"global.log_level":                 wantFiles[1],
+		"global.shutdown_timeout":          wantFiles[0],
+		"processes.php-fpm.command":        wantFiles[1],
+		"processes.php-fpm.env.APP_ENV":    wantFiles[0],
+		"processes.php-fpm.env.APP_DEBUG":  wantFiles[2],
+		"processes.queue.scale":            wantFiles[2],
+		"processes.queue.command":          wantFiles[2],
+		"processes.horizon.command":        "",
+		"global.shutdown_timeout.whatever": wantFiles[0],
+	}
+	for path, want := range origins {
+		if got := src.Origin(path); got != want {
+			t.Errorf("Origin(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestLoadWithEnvExpansion_EnvOrigin(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+	writeConfigFiles(t, dir, map[string]string{
+		"phpeek-pm.yaml": `
+global:
+  log_level: info
+processes:
+  app:
+    command: ["sleep", "60"]
+`,
+	})
+	t.Setenv("PHPEEK_PM_GLOBAL_LOG_LEVEL", "debug")
+
+	cfg, err := LoadWithEnvExpansion(path)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+	if got := cfg.Sources.Origin("global.log_level"); got != OriginEnv {
+		t.Errorf("Origin(global.log_level) = %q, want env", got)
+	}
+	if got := cfg.Sources.Origin("processes.app.command"); got != path {
+		t.Errorf("Origin(processes.app.command) = %q, want %s", got, path)
+	}
+	if keys := cfg.Sources.Keys(); len(keys) != 2 || keys[0] != "global.log_level" {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
+
+func TestLoadWithEnvExpansion_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "missing include",
+			files: map[string]string{
+				"phpeek-pm.yaml": "include: missing.yaml\n",
+			},
+			wantErr: "included file not found",
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"phpeek-pm.yaml": "include: a.yaml\n",
+				"a.yaml":         "include: phpeek-pm.yaml\n",
+			},
+			wantErr: "include cycle detected",
+		},
+		{
+			name: "invalid include type",
+			files: map[string]string{
+				"phpeek-pm.yaml": "include: {a: b}\n",
+			},
+			wantErr: "include must be a path or a list of paths",
+		},
+		{
+			name: "invalid yaml in include",
+			files: map[string]string{
+				"phpeek-pm.yaml": "include: [broken.yaml]\n",
+				"broken.yaml":    "processes: [unclosed\n",
+			},
+			wantErr: "broken.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tt.files)
+
+			_, err := LoadWithEnvExpansion(filepath.Join(dir, "phpeek-pm.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadWithEnvExpansion() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadWithEnvExpansion_SystemConfDir(t *testing.T) {
+	dir := t.TempDir()
+	setSystemConfDir(t, filepath.Join(dir, "etc/conf.d"))
+	writeConfigFiles(t, dir, map[string]string{
+		"etc/conf.d/10-base.yaml": "global:\n  log_level: warn\n  shutdown_timeout: 45\n",
+		"app/phpeek-pm.yaml":      "global:\n  log_level: info\n  shutdown_timeout: 20\nprocesses:\n  app:\n    command: [\"sleep\", \"60\"]\n",
+		"app/conf.d/20-app.yaml":  "global:\n  log_level: debug\n",
+	})
+
+	cfg, err := LoadWithEnvExpansion(filepath.Join(dir, "app/phpeek-pm.yaml"))
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+
+	// The system drop-ins merge over the main file, the local ones last
+	if cfg.Global.ShutdownTimeout != 45 || cfg.Global.LogLevel != "debug" {
+		t.Errorf("shutdown_timeout = %d, log_level = %s", cfg.Global.ShutdownTimeout, cfg.Global.LogLevel)
+	}
+	wantDirs := []string{filepath.Join(dir, "etc/conf.d"), filepath.Join(dir, "app/conf.d")}
+	if !reflect.DeepEqual(cfg.Sources.ConfDirs, wantDirs) {
+		t.Errorf("ConfDirs = %v, want %v", cfg.Sources.ConfDirs, wantDirs)
+	}
+
+	// A main file next to the system directory merges it once
+	writeConfigFiles(t, dir, map[string]string{"etc/phpeek-pm.yaml": "processes:\n  app:\n    command: [\"sleep\", \"60\"]\n"})
+	cfg, err = LoadWithEnvExpansion(filepath.Join(dir, "etc/phpeek-pm.yaml"))
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+	if len(cfg.Sources.ConfDirs) != 1 || len(cfg.Sources.Files) != 2 {
+		t.Errorf("ConfDirs = %v, Files = %v", cfg.Sources.ConfDirs, cfg.Sources.Files)
+	}
+}
+
+func TestLoadWithEnvExpansion_ConfDirWithoutMainFile(t *testing.T) {
+	dir := t.TempDir()
+	setSystemConfDir(t, filepath.Join(dir, "missing"))
+	writeConfigFiles(t, dir, map[string]string{
+		"conf.d/app.yml": "processes:\n  app:\n    command: [\"sleep\", \"60\"]\n",
+	})
+
+	cfg, err := LoadWithEnvExpansion(filepath.Join(dir, "phpeek-pm.yaml"))
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+	if cfg.Processes["app"] == nil || len(cfg.Sources.Files) != 1 {
+		t.Errorf("expected app from conf.d, got processes %v, files %v", cfg.Processes, cfg.Sources.Files)
+	}
+}
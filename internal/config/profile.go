@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ProfileEnvVar selects the config profile when --profile is not given
+const ProfileEnvVar = "PHPEEK_PM_PROFILE"
+
+// Profile is a per-environment overlay of the configuration, deep-merged
+// over the config files like a conf.d drop-in:
+//
+//	profiles:
+//	  production:
+//	    global:
+//	      log_level: warn
+//	    processes:
+//	      queue:
+//	        scale: 10
+//	      debugbar: null
+//
+// A profile is applied after ${VAR} expansion, includes and conf.d, and
+// before PHPEEK_PM_* environment overrides. Profiles with the same name in
+// several files are deep-merged.
+type Profile map[string]interface{}
+
+// LoadOptions configures LoadWithOptions
+type LoadOptions struct {
+	Profile string // Profile to apply ("" = none)
+}
+
+// LoadWithOptions loads a config file like LoadWithEnvExpansion, applying
+// the profile given in opts instead of the one selected by PHPEEK_PM_PROFILE
+func LoadWithOptions(path string, opts LoadOptions) (*Config, error) {
+	return load(path, opts)
+}
+
+// ProfileFromEnv returns the profile selected by PHPEEK_PM_PROFILE
+func ProfileFromEnv() string {
+	return strings.TrimSpace(os.Getenv(ProfileEnvVar))
+}
+
+// ProfileOrigin returns the origin recorded for values set by a profile
+func ProfileOrigin(name string) string {
+	return "profile:" + name
+}
+
+// applyProfile merges the named profile of raw over raw
+func applyProfile(raw map[string]interface{}, name string, sources *Sources) error {
+	profiles, _ := raw[profilesKey].(map[string]interface{})
+
+	overlay, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile %q: no profiles defined", name)
+		}
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	tree, ok := overlay.(map[string]interface{})
+	if !ok {
+		if overlay == nil {
+			sources.Profile = name
+			return nil // Empty profile
+		}
+		return fmt.Errorf("profile %q must be a mapping, got %T", name, overlay)
+	}
+	for _, key := range []string{profilesKey, includeKey} {
+		if _, ok := tree[key]; ok {
+			return fmt.Errorf("profile %q cannot contain %q", name, key)
+		}
+	}
+
+	// Resolve the secrets of a copy: the profiles section keeps the references
+	tree = copyTree(tree).(map[string]interface{})
+	if err := sources.secrets.resolveTree(tree, ""); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+
+	mergeTree(raw, tree, "", ProfileOrigin(name), sources.Origins)
+	sources.Profile = name
+	return nil
+}
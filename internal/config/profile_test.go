@@ -0,0 +1,198 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const profileTestConfig = `
+global:
+  log_level: info
+  shutdown_timeout: 30
+processes:
+  app:
+    command: ["php", "artisan", "serve"]
+    env:
+      APP_ENV: ${APP_ENV:-local}
+  debugbar:
+    command: ["php", "artisan", "debugbar:serve"]
+profiles:
+  production:
+    global:
+      log_level: warn
+    processes:
+      app:
+        scale: 3
+        env:
+          APP_ENV: production
+      debugbar: null
+  empty:
+`
+
+func TestLoadWithOptions_Profile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+	writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": profileTestConfig})
+
+	cfg, err := LoadWithOptions(path, LoadOptions{Profile: "production"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	if cfg.Global.LogLevel != "warn" || cfg.Global.ShutdownTimeout != 30 {
+		t.Errorf("log_level = %s, shutdown_timeout = %d", cfg.Global.LogLevel, cfg.Global.ShutdownTimeout)
+	}
+	app := cfg.Processes["app"]
+	if app == nil || app.Scale != 3 || app.Env["APP_ENV"] != "production" || len(app.Command) != 3 {
+		t.Errorf("app = %+v, want profile merged over base", app)
+	}
+	if _, ok := cfg.Processes["debugbar"]; ok {
+		t.Error("debugbar should be deleted by the profile")
+	}
+	if len(cfg.Profiles) != 2 {
+		t.Errorf("Profiles = %v, want production and empty", cfg.Profiles)
+	}
+
+	src := cfg.Sources
+	if src.Profile != "production" {
+		t.Errorf("Sources.Profile = %q, want production", src.Profile)
+	}
+	origins := map[string]string{
+		"global.log_level":           ProfileOrigin("production"),
+		"processes.app.scale":        ProfileOrigin("production"),
+		"global.shutdown_timeout":    path,
+		"processes.app.command":      path,
+		"processes.debugbar.command": "",
+	}
+	for key, want := range origins {
+		if got := src.Origin(key); got != want {
+			t.Errorf("Origin(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLoadWithOptions_NoProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+	writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": profileTestConfig})
+
+	cfg, err := LoadWithOptions(path, LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Global.LogLevel != "info" || cfg.Processes["debugbar"] == nil || cfg.Sources.Profile != "" {
+		t.Errorf("profile applied without being selected: log_level = %s, profile = %q", cfg.Global.LogLevel, cfg.Sources.Profile)
+	}
+
+	// An empty profile is valid and changes nothing
+	cfg, err = LoadWithOptions(path, LoadOptions{Profile: "empty"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions(empty) error = %v", err)
+	}
+	if cfg.Global.LogLevel != "info" || cfg.Sources.Profile != "empty" {
+		t.Errorf("empty profile: log_level = %s, profile = %q", cfg.Global.LogLevel, cfg.Sources.Profile)
+	}
+}
+
+func TestLoadWithEnvExpansion_ProfileFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+	writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": profileTestConfig})
+	t.Setenv(ProfileEnvVar, "production")
+	t.Setenv("PHPEEK_PM_GLOBAL_LOG_LEVEL", "debug")
+
+	cfg, err := LoadWithEnvExpansion(path)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+	if cfg.Sources.Profile != "production" || cfg.Processes["app"].Scale != 3 {
+		t.Errorf("profile from %s not applied: profile = %q", ProfileEnvVar, cfg.Sources.Profile)
+	}
+
+	// Environment overrides win over the profile
+	if cfg.Global.LogLevel != "debug" || cfg.Sources.Origin("global.log_level") != OriginEnv {
+		t.Errorf("log_level = %s (origin %q), want debug from env", cfg.Global.LogLevel, cfg.Sources.Origin("global.log_level"))
+	}
+}
+
+func TestLoadWithOptions_ProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		profile string
+		wantErr string
+	}{
+		{
+			name:    "unknown profile",
+			config:  profileTestConfig,
+			profile: "staging",
+			wantErr: `unknown profile "staging" (available: empty, production)`,
+		},
+		{
+			name:    "no profiles defined",
+			config:  "processes:\n  app:\n    command: [\"sleep\", \"60\"]\n",
+			profile: "production",
+			wantErr: "no profiles defined",
+		},
+		{
+			name:    "profile not a mapping",
+			config:  "profiles:\n  production: [a, b]\n",
+			profile: "production",
+			wantErr: "must be a mapping",
+		},
+		{
+			name:    "nested profiles",
+			config:  "profiles:\n  production:\n    profiles: {}\n",
+			profile: "production",
+			wantErr: `cannot contain "profiles"`,
+		},
+		{
+			name:    "include in profile",
+			config:  "profiles:\n  production:\n    include: prod.yaml\n",
+			profile: "production",
+			wantErr: `cannot contain "include"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": tt.config})
+
+			_, err := LoadWithOptions(filepath.Join(dir, "phpeek-pm.yaml"), LoadOptions{Profile: tt.profile})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadWithOptions() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadWithOptions_ProfileAcrossConfDir(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"phpeek-pm.yaml": profileTestConfig,
+		"conf.d/prod.yaml": `
+profiles:
+  production:
+    global:
+      shutdown_timeout: 60
+    processes:
+      worker:
+        command: ["php", "artisan", "queue:work"]
+`,
+	})
+
+	cfg, err := LoadWithOptions(filepath.Join(dir, "phpeek-pm.yaml"), LoadOptions{Profile: "production"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	// Both files contribute to the same profile, including its null
+	if cfg.Global.LogLevel != "warn" || cfg.Global.ShutdownTimeout != 60 {
+		t.Errorf("log_level = %s, shutdown_timeout = %d", cfg.Global.LogLevel, cfg.Global.ShutdownTimeout)
+	}
+	if cfg.Processes["worker"] == nil || cfg.Processes["debugbar"] != nil {
+		t.Errorf("processes = %v, want worker added and debugbar deleted", cfg.Processes)
+	}
+}
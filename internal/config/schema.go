@@ -0,0 +1,244 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The JSON Schema of the config file is generated from the yaml tags of
+// Config and the types it contains, so it cannot drift from what the loader
+// accepts. String fields with a fixed set of values carry an enum tag, e.g.
+//
+//	Restart string `yaml:"restart" json:"restart" enum:"always,on-failure,never"`
+//
+// which both the schema and ValidateComprehensive use.
+
+const (
+	// SchemaDraft is the JSON Schema dialect of Schema
+	SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	// SchemaID identifies the config schema
+	SchemaID = "https://github.com/gophpeek/phpeek-pm/schema/phpeek-pm.schema.json"
+
+	// envRefPattern matches a value that is a single ${VAR} reference, which
+	// is accepted for non-string fields since it is expanded at load time
+	envRefPattern = `^\$\{[^}]+\}$`
+
+	// durationPattern matches Go durations such as "30s" or "1h30m"
+	durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	profileType  = reflect.TypeOf(Profile(nil))
+)
+
+// Schema returns the JSON Schema of the config file
+func Schema() map[string]interface{} {
+	b := &schemaBuilder{defs: map[string]interface{}{
+		"envRef": map[string]interface{}{
+			"type":        "string",
+			"pattern":     envRefPattern,
+			"description": "Environment variable reference, expanded at load time",
+		},
+	}}
+	b.typeSchema(reflect.TypeOf(Config{}))
+
+	// Top-level only keys, see include.go and profile.go
+	root := b.defs["Config"].(map[string]interface{})
+	properties := root["properties"].(map[string]interface{})
+	properties[includeKey] = map[string]interface{}{
+		"description": "Files to merge before this one (globs allowed)",
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}
+	root["patternProperties"] = map[string]interface{}{"^" + extensionPrefix: map[string]interface{}{}}
+
+	// depends_on takes a list of names or a map of names to options, see
+	// Process.UnmarshalYAML
+	processProps := b.defs["Process"].(map[string]interface{})["properties"].(map[string]interface{})
+	processProps["depends_on"] = map[string]interface{}{
+		"description": "Processes started first, as a list of names or a map of names to options",
+		"oneOf": []interface{}{
+			processProps["depends_on"],
+			b.typeSchema(dependsOnOptionsType),
+		},
+	}
+
+	profileProps := make(map[string]interface{}, len(properties))
+	for name, prop := range properties {
+		if name != includeKey && name != profilesKey {
+			profileProps[name] = prop
+		}
+	}
+	b.defs["Profile"] = map[string]interface{}{
+		"type":                 "object",
+		"description":          "Overrides applied when the profile is selected with --profile",
+		"properties":           profileProps,
+		"additionalProperties": false,
+	}
+
+	return map[string]interface{}{
+		"$schema":     SchemaDraft,
+		"$id":         SchemaID,
+		"title":       "PHPeek PM configuration",
+		"$ref":        "#/$defs/Config",
+		"$defs":       b.defs,
+		"description": "Configuration file of the PHPeek process manager (phpeek-pm.yaml)",
+	}
+}
+
+// SchemaJSON returns the JSON Schema of the config file, indented
+func SchemaJSON() ([]byte, error) {
+	return json.MarshalIndent(Schema(), "", "  ")
+}
+
+// schemaBuilder generates schemas for Go types, one $defs entry per struct
+type schemaBuilder struct {
+	defs map[string]interface{}
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == durationType:
+		return scalarSchema(map[string]interface{}{"type": "string", "pattern": durationPattern}, map[string]interface{}{"type": "integer"})
+	case t == profileType:
+		return map[string]interface{}{"$ref": "#/$defs/Profile"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.typeSchema(t.Elem())
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := b.defs[name]; !ok {
+			b.defs[name] = nil // Reserve the name for recursive types
+			b.defs[name] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	case reflect.Map:
+		// Map entries may be null to delete an entry of an earlier file
+		return map[string]interface{}{
+			"type": "object",
+			"additionalProperties": map[string]interface{}{
+				"anyOf": []interface{}{b.typeSchema(t.Elem()), map[string]interface{}{"type": "null"}},
+			},
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return scalarSchema(map[string]interface{}{"type": "boolean"})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return scalarSchema(map[string]interface{}{"type": "integer"})
+	case reflect.Float32, reflect.Float64:
+		return scalarSchema(map[string]interface{}{"type": "number"})
+	default:
+		return map[string]interface{}{} // Any value
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, field := range yamlFields(t) {
+		prop := b.typeSchema(field.Type)
+		if values := enumValues(field); len(values) > 0 {
+			prop = map[string]interface{}{"type": "string", "enum": values}
+		}
+		properties[yamlName(field)] = prop
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// scalarSchema accepts any of the given schemas or a ${VAR} reference
+func scalarSchema(schemas ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"anyOf": append(schemas, map[string]interface{}{"$ref": "#/$defs/envRef"}),
+	}
+}
+
+// yamlFields returns the fields of struct type t that are read from YAML,
+// sorted by their YAML name
+func yamlFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || yamlName(field) == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return yamlName(fields[i]) < yamlName(fields[j]) })
+	return fields
+}
+
+// yamlName returns the YAML key of a struct field
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// enumValues returns the allowed values of a field from its enum tag
+func enumValues(field reflect.StructField) []string {
+	tag := field.Tag.Get("enum")
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+// fieldEnum returns the allowed values of the named field of a struct value,
+// e.g. fieldEnum(Process{}, "Restart")
+func fieldEnum(v interface{}, name string) []string {
+	field, ok := reflect.TypeOf(v).FieldByName(name)
+	if !ok {
+		panic("config: no field " + name)
+	}
+	return enumValues(field)
+}
+
+// walkEnums calls fn with the dotted key path, value and allowed values of
+// every enum field below v
+func walkEnums(v reflect.Value, path string, fn func(path, value string, allowed []string)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			walkEnums(v.Elem(), path, fn)
+		}
+	case reflect.Struct:
+		for _, field := range yamlFields(v.Type()) {
+			fv := v.FieldByIndex(field.Index)
+			fieldPath := joinKeyPath(path, yamlName(field))
+			if values := enumValues(field); values != nil && fv.Kind() == reflect.String {
+				fn(fieldPath, fv.String(), values)
+				continue
+			}
+			walkEnums(fv, fieldPath, fn)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			walkEnums(v.MapIndex(key), joinKeyPath(path, key.String()), fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkEnums(v.Index(i), joinKeyPath(path, strconv.Itoa(i)), fn)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// schemaDef returns the $defs entry of a struct type
+func schemaDef(t *testing.T, schema map[string]interface{}, name string) map[string]interface{} {
+	t.Helper()
+	def, ok := schema["$defs"].(map[string]interface{})[name].(map[string]interface{})
+	if !ok {
+		t.Fatalf("$defs has no %s", name)
+	}
+	return def
+}
+
+func schemaProperty(t *testing.T, def map[string]interface{}, name string) map[string]interface{} {
+	t.Helper()
+	prop, ok := def["properties"].(map[string]interface{})[name].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no property %s", name)
+	}
+	return prop
+}
+
+func TestSchema(t *testing.T) {
+	data, err := SchemaJSON()
+	if err != nil {
+		t.Fatalf("SchemaJSON() error = %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema["$schema"] != SchemaDraft || schema["$ref"] != "#/$defs/Config" {
+		t.Errorf("$schema = %v, $ref = %v", schema["$schema"], schema["$ref"])
+	}
+
+	root := schemaDef(t, schema, "Config")
+	if root["additionalProperties"] != false {
+		t.Error("Config should not allow unknown keys")
+	}
+	for _, key := range []string{"version", "global", "hooks", "processes", "profiles", "include"} {
+		schemaProperty(t, root, key)
+	}
+	if _, ok := root["properties"].(map[string]interface{})["Sources"]; ok {
+		t.Error("yaml:\"-\" fields must not be in the schema")
+	}
+
+	// Processes are a map of Process, where null deletes an entry
+	processes := schemaProperty(t, root, "processes")
+	entry := processes["additionalProperties"].(map[string]interface{})["anyOf"].([]interface{})
+	if entry[0].(map[string]interface{})["$ref"] != "#/$defs/Process" {
+		t.Errorf("processes entries = %v, want Process", entry)
+	}
+
+	// Enums come from the enum tags
+	enums := map[string]map[string][]interface{}{
+		"Process":         {"restart": {"always", "on-failure", "never"}, "type": {"oneshot", "longrun"}},
+		"HealthCheck":     {"type": {"tcp", "http", "exec"}, "mode": {"liveness", "readiness", "both"}},
+		"ReadinessConfig": {"mode": {"all_healthy", "all_running"}},
+		"GlobalConfig":    {"log_level": {"debug", "info", "warn", "error"}},
+		"Dependency":      {"condition": {"started", "healthy", "completed_successfully"}},
+	}
+	for def, props := range enums {
+		for name, want := range props {
+			if got := schemaProperty(t, schemaDef(t, schema, def), name)["enum"]; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s.%s enum = %v, want %v", def, name, got, want)
+			}
+		}
+	}
+
+	// depends_on is a list of names or a map of names to options
+	dependsOn := schemaProperty(t, schemaDef(t, schema, "Process"), "depends_on")
+	if oneOf, _ := dependsOn["oneOf"].([]interface{}); len(oneOf) != 2 {
+		t.Errorf("depends_on = %v, want list or map", dependsOn)
+	} else if !strings.Contains(string(mustJSON(t, oneOf[1])), "#/$defs/Dependency") {
+		t.Errorf("depends_on map = %v, want Dependency entries", oneOf[1])
+	}
+
+	// Durations accept "30s", nanoseconds and ${VAR}
+	timeout := schemaProperty(t, schemaDef(t, schema, "GlobalConfig"), "dependency_timeout")
+	if anyOf, _ := timeout["anyOf"].([]interface{}); len(anyOf) != 3 {
+		t.Errorf("dependency_timeout = %v, want string, integer or env reference", timeout)
+	}
+	scale := schemaProperty(t, schemaDef(t, schema, "Process"), "scale")
+	if !strings.Contains(string(mustJSON(t, scale)), "#/$defs/envRef") {
+		t.Errorf("scale = %v, want integer or env reference", scale)
+	}
+
+	// Profiles hold the same settings, without nesting
+	profile := schemaDef(t, schema, "Profile")
+	props := profile["properties"].(map[string]interface{})
+	if _, ok := props["processes"]; !ok {
+		t.Error("Profile should allow processes")
+	}
+	if _, ok := props["profiles"]; ok {
+		t.Error("Profile should not allow profiles")
+	}
+	if _, ok := props["include"]; ok {
+		t.Error("Profile should not allow include")
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestFieldEnum(t *testing.T) {
+	if got := fieldEnum(Process{}, "Restart"); !reflect.DeepEqual(got, []string{"always", "on-failure", "never"}) {
+		t.Errorf("fieldEnum(Process, Restart) = %v", got)
+	}
+	if got := fieldEnum(Process{}, "Command"); got != nil {
+		t.Errorf("fieldEnum(Process, Command) = %v, want nil", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("fieldEnum should panic for an unknown field")
+		}
+	}()
+	fieldEnum(Process{}, "Restrat")
+}
+
+func TestValidateComprehensive_Enums(t *testing.T) {
+	cfg := &Config{
+		Global: GlobalConfig{
+			APIACL: &ACLConfig{Enabled: true, Mode: "whitelist"},
+		},
+		Processes: map[string]*Process{
+			"app": {
+				Enabled: true,
+				Command: []string{"php-fpm"},
+				Restart: "sometimes",
+				HealthCheck: &HealthCheck{
+					Type:    "tcp",
+					Address: "127.0.0.1:9000",
+					Mode:    "startup",
+				},
+			},
+		},
+	}
+	cfg.SetDefaults()
+
+	result, _ := cfg.ValidateComprehensive()
+	errors := make(map[string]int)
+	for _, issue := range result.Errors {
+		errors[issue.Field]++
+	}
+
+	for _, field := range []string{"global.api_acl.mode", "processes.app.health_check.mode"} {
+		if errors[field] != 1 {
+			t.Errorf("want one error for %s, got %d (%v)", field, errors[field], result.Errors)
+		}
+	}
+	// Already reported with a specific message
+	if errors["processes.app.restart"] != 1 {
+		t.Errorf("want one error for processes.app.restart, got %d", errors["processes.app.restart"])
+	}
+}
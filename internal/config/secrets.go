@@ -0,0 +1,377 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Secret references are resolved at load time from string values of the
+// parsed config files, after ${VAR} expansion:
+//
+//	${file:/run/secrets/db_password}      File contents, trailing newline trimmed
+//	${vault:secret/data/app#db_password}  Vault KV (v1 or v2) field
+//	${ssm:/app/prod/db_password}          AWS SSM parameter (decrypted)
+//
+// Resolved values are sensitive: Secrets masks them in output and Save
+// writes the references back instead of the values.
+
+// SecretMask replaces secret values in masked output
+const SecretMask = "***"
+
+// minMaskLength is the shortest secret value Mask replaces in free text.
+// Shorter values (e.g. a port or "true") would mask unrelated text.
+const minMaskLength = 6
+
+// secretPattern matches ${scheme:reference}
+var secretPattern = regexp.MustCompile(`\$\{([a-z][a-z0-9]*):([^}]+)\}`)
+
+// SecretProvider resolves the references of one scheme, e.g. the
+// "secret/data/app#db_password" of ${vault:secret/data/app#db_password}
+type SecretProvider interface {
+	Resolve(ref string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to SecretProvider
+type SecretProviderFunc func(ref string) (string, error)
+
+// Resolve calls f(ref)
+func (f SecretProviderFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"file":  SecretProviderFunc(resolveFileSecret),
+		"vault": NewVaultProvider(),
+		"ssm":   NewSSMProvider(),
+	}
+)
+
+// RegisterSecretProvider makes provider resolve ${scheme:...} references,
+// replacing any provider registered for scheme. The env scheme is reserved.
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	if provider == nil {
+		delete(secretProviders, scheme)
+		return
+	}
+	secretProviders[scheme] = provider
+}
+
+func lookupSecretProvider(scheme string) (SecretProvider, bool) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	provider, ok := secretProviders[scheme]
+	return provider, ok
+}
+
+// resolveFileSecret reads a secret file such as a Docker or Kubernetes secret
+func resolveFileSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// SecretRef is a config value resolved from a secret reference
+type SecretRef struct {
+	Path      string `json:"path"`      // Dotted key path of the value
+	Reference string `json:"reference"` // Reference it was resolved from, e.g. ${vault:app#key}
+}
+
+// Secrets records the secret values resolved while loading a configuration.
+// A nil *Secrets has no secrets.
+type Secrets struct {
+	values map[string]string      // Resolved value -> reference
+	paths  map[string]string      // Dotted key path -> reference
+	fields map[string]secretValue // Dotted key path -> string value holding secrets
+	cache  map[string]string      // Reference -> resolved value, per load
+}
+
+// secretValue is a string config value that held secrets when it was loaded
+type secretValue struct {
+	raw      string // Value with the references, e.g. mysql://app:${file:/run/secrets/db}@db/app
+	resolved string // Value with the secrets
+	masked   string // Value with SecretMask in place of the secrets
+}
+
+func newSecrets() *Secrets {
+	return &Secrets{
+		values: make(map[string]string),
+		paths:  make(map[string]string),
+		fields: make(map[string]secretValue),
+		cache:  make(map[string]string),
+	}
+}
+
+// Secrets returns the secret values resolved while loading the config (nil
+// if it was not loaded from files)
+func (c *Config) Secrets() *Secrets {
+	if c.Sources == nil {
+		return nil
+	}
+	return c.Sources.secrets
+}
+
+// Len returns the number of distinct secret values
+func (s *Secrets) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.values)
+}
+
+// Refs returns the values resolved from secret references, sorted by path
+func (s *Secrets) Refs() []SecretRef {
+	if s == nil {
+		return nil
+	}
+	refs := make([]SecretRef, 0, len(s.paths))
+	for path, ref := range s.paths {
+		refs = append(refs, SecretRef{Path: path, Reference: ref})
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Path < refs[j].Path })
+	return refs
+}
+
+// Mask replaces the secret values in free text, e.g. an audit message, with
+// SecretMask. Values shorter than minMaskLength are left as is.
+func (s *Secrets) Mask(text string) string {
+	return s.replace(text, minMaskLength)
+}
+
+// MaskConfig returns a deep copy of cfg with the secrets in the values they
+// were resolved into masked
+func (s *Secrets) MaskConfig(cfg *Config) *Config {
+	return copyStrings(cfg, "", s.maskField)
+}
+
+// MaskProcess returns a deep copy of the named process with the secrets in
+// the values they were resolved into masked
+func (s *Secrets) MaskProcess(name string, proc *Process) *Process {
+	return copyStrings(proc, joinKeyPath("processes", name), s.maskField)
+}
+
+// maskField masks the secrets in the value at path. A value changed since it
+// was loaded has the secrets it held replaced instead.
+func (s *Secrets) maskField(path, value string) string {
+	if s == nil {
+		return value
+	}
+	field, ok := s.fields[path]
+	switch {
+	case !ok:
+		return value
+	case value == field.resolved:
+		return field.masked
+	default:
+		return s.replace(value, 0)
+	}
+}
+
+// unresolveField returns the value at path with its references in place of
+// the secrets, unless it changed since it was loaded
+func (s *Secrets) unresolveField(path, value string) string {
+	if s == nil {
+		return value
+	}
+	if field, ok := s.fields[path]; ok && value == field.resolved {
+		return field.raw
+	}
+	return value
+}
+
+// replace masks the secret values of at least minLength bytes in text,
+// longest first so a secret that contains another is masked whole
+func (s *Secrets) replace(text string, minLength int) string {
+	if s.Len() == 0 || text == "" {
+		return text
+	}
+	values := make([]string, 0, len(s.values))
+	for value := range s.values {
+		if len(value) >= minLength {
+			values = append(values, value)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	for _, value := range values {
+		text = strings.ReplaceAll(text, value, SecretMask)
+	}
+	return text
+}
+
+// resolveTree resolves the secret references in the string values of a
+// parsed config file, recording their key paths below prefix
+func (s *Secrets) resolveTree(tree map[string]interface{}, prefix string) error {
+	for key, value := range tree {
+		resolved, err := s.resolveValue(value, joinKeyPath(prefix, key))
+		if err != nil {
+			return err
+		}
+		tree[key] = resolved
+	}
+	return nil
+}
+
+func (s *Secrets) resolveValue(value interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, s.resolveTree(v, path)
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := s.resolveValue(item, joinKeyPath(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	case string:
+		return s.resolveString(v, path)
+	default:
+		return value, nil
+	}
+}
+
+// resolveString resolves the references in a string value. A value that is
+// a single reference takes the type of the secret (e.g. an integer port).
+func (s *Secrets) resolveString(value, path string) (interface{}, error) {
+	var resolveErr error
+	whole := false
+	masked := value
+
+	resolved := secretPattern.ReplaceAllStringFunc(value, func(match string) string {
+		parts := secretPattern.FindStringSubmatch(match)
+		provider, ok := lookupSecretProvider(parts[1])
+		if !ok || resolveErr != nil {
+			return match // Not a secret reference (e.g. shell ${var:offset})
+		}
+		masked = strings.Replace(masked, match, SecretMask, 1)
+
+		secret, cached := s.cache[match]
+		if !cached {
+			var err error
+			secret, err = provider.Resolve(parts[2])
+			if err != nil {
+				resolveErr = fmt.Errorf("%s: failed to resolve %s: %w", path, match, err)
+				return match
+			}
+			s.cache[match] = secret
+		}
+
+		if secret != "" {
+			s.values[secret] = match
+		}
+		s.paths[path] = match
+		whole = match == value
+		return secret
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	if masked != value {
+		s.fields[path] = secretValue{raw: value, resolved: resolved, masked: masked}
+	}
+	if whole {
+		return secretScalar(resolved), nil
+	}
+	return resolved, nil
+}
+
+// secretScalar types a whole-value secret so it can fill integer and boolean
+// fields. Only canonical forms convert, so "007" stays a string.
+func secretScalar(value string) interface{} {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(i, 10) == value {
+		return i
+	}
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	return value
+}
+
+// copyStrings returns a deep copy of v with fn applied to every string,
+// except map keys, along with its dotted key path below prefix. Unexported
+// fields and fields not in the YAML are copied as is.
+func copyStrings[T any](v T, prefix string, fn func(path, value string) string) T {
+	out := copyValue(reflect.ValueOf(&v).Elem(), prefix, fn)
+	return out.Interface().(T)
+}
+
+func copyValue(v reflect.Value, path string, fn func(path, value string) string) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(fn(path, v.String()))
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(copyValue(v.Elem(), path, fn))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(copyValue(v.Elem(), path, fn))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			key, ok := yamlKey(v.Type().Field(i))
+			if ok && out.Field(i).CanSet() {
+				out.Field(i).Set(copyValue(v.Field(i), joinKeyPath(path, key), fn))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(copyValue(v.Index(i), joinKeyPath(path, strconv.Itoa(i)), fn))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			out.SetMapIndex(iter.Key(), copyValue(iter.Value(), joinKeyPath(path, key), fn))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// yamlKey returns the YAML key of a struct field, false if it has none
+func yamlKey(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return strings.ToLower(field.Name), true
+	default:
+		return name, true
+	}
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// secretHTTPTimeout bounds each request to a secret provider
+const secretHTTPTimeout = 10 * time.Second
+
+// VaultProvider resolves ${vault:path#field} references from the HashiCorp
+// Vault KV secrets engine (v1 and v2) over HTTP. The address and token are
+// read from VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE on every lookup.
+// The field may be omitted for secrets with a single field.
+type VaultProvider struct {
+	Client *http.Client
+}
+
+// NewVaultProvider creates a Vault provider configured from the environment
+func NewVaultProvider() *VaultProvider {
+	return &VaultProvider{Client: &http.Client{Timeout: secretHTTPTimeout}}
+}
+
+// Resolve reads the secret at path and returns its field
+func (p *VaultProvider) Resolve(ref string) (string, error) {
+	addr := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+
+	path, field, _ := strings.Cut(ref, "#")
+	req, err := http.NewRequest(http.MethodGet, addr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	var body struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []string               `json:"errors"`
+	}
+	status, err := doSecretRequest(p.Client, req, &body)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("vault returned %d: %s", status, strings.Join(body.Errors, "; "))
+	}
+
+	// KV v2 nests the fields under data.data next to data.metadata
+	data := body.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	return secretField(data, field)
+}
+
+// secretField returns field of data, or the only field when field is empty
+func secretField(data map[string]interface{}, field string) (string, error) {
+	if field == "" {
+		if len(data) != 1 {
+			fields := make([]string, 0, len(data))
+			for name := range data {
+				fields = append(fields, name)
+			}
+			sort.Strings(fields)
+			return "", fmt.Errorf("secret has fields %s, select one with #field", strings.Join(fields, ", "))
+		}
+		for name := range data {
+			field = name
+		}
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", field)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// SSMProvider resolves ${ssm:name} references with the AWS Systems Manager
+// GetParameter API, decrypting SecureString parameters. The endpoint is
+// AWS_ENDPOINT_URL_SSM or AWS_ENDPOINT_URL when set (for SSM-compatible
+// services), otherwise the regional AWS endpoint of AWS_REGION or
+// AWS_DEFAULT_REGION. Requests are signed with AWS Signature Version 4 when
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are set.
+type SSMProvider struct {
+	Client *http.Client
+	Now    func() time.Time // Signing time (default time.Now)
+}
+
+// NewSSMProvider creates an SSM provider configured from the environment
+func NewSSMProvider() *SSMProvider {
+	return &SSMProvider{Client: &http.Client{Timeout: secretHTTPTimeout}, Now: time.Now}
+}
+
+// Resolve returns the value of the parameter name
+func (p *SSMProvider) Resolve(name string) (string, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SSM")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if endpoint == "" {
+		if region == "" {
+			return "", fmt.Errorf("AWS_REGION or AWS_ENDPOINT_URL_SSM must be set")
+		}
+		endpoint = "https://ssm." + region + ".amazonaws.com"
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"Name": name, "WithDecryption": true})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonSSM.GetParameter")
+
+	if accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); accessKey != "" && secretKey != "" {
+		now := time.Now
+		if p.Now != nil {
+			now = p.Now
+		}
+		signAWSv4(req, payload, accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), region, "ssm", now())
+	}
+
+	var body struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	status, err := doSecretRequest(p.Client, req, &body)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("ssm returned %d: %s %s", status, body.Type, body.Message)
+	}
+	return body.Parameter.Value, nil
+}
+
+// doSecretRequest sends req and decodes a JSON response into out
+func doSecretRequest(client *http.Client, req *http.Request, out interface{}) (int, error) {
+	if client == nil {
+		client = &http.Client{Timeout: secretHTTPTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil && resp.StatusCode == http.StatusOK {
+			return 0, fmt.Errorf("invalid response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// signAWSv4 adds AWS Signature Version 4 headers to req
+func signAWSv4(req *http.Request, payload []byte, accessKey, secretKey, sessionToken, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(payload),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
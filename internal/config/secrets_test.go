@@ -0,0 +1,433 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadWithEnvExpansion_SecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"secrets/db_password": "s3cr3t-pw\n",
+		"secrets/api_token":   "tok-123456",
+		"secrets/api_port":    "9191\n",
+	})
+	secretsDir := filepath.Join(dir, "secrets")
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+	writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": fmt.Sprintf(`
+global:
+  api_port: ${file:%[1]s/api_port}
+  api_auth: ${file:%[1]s/api_token}
+processes:
+  app:
+    command: ["sh", "-c", "echo ${path:1} && php artisan serve"]
+    env:
+      DB_PASSWORD: ${file:%[1]s/db_password}
+      DATABASE_URL: mysql://app:${file:%[1]s/db_password}@db/app
+      # DB_OLD: ${file:/does/not/exist}
+`, secretsDir)})
+
+	cfg, err := LoadWithEnvExpansion(path)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+
+	env := cfg.Processes["app"].Env
+	if env["DB_PASSWORD"] != "s3cr3t-pw" || env["DATABASE_URL"] != "mysql://app:s3cr3t-pw@db/app" {
+		t.Errorf("env = %v, want resolved secrets", env)
+	}
+	if cfg.Global.APIPort != 9191 || cfg.Global.APIAuth != "tok-123456" {
+		t.Errorf("api_port = %d, api_auth = %q", cfg.Global.APIPort, cfg.Global.APIAuth)
+	}
+	if got := cfg.Processes["app"].Command[2]; got != "echo ${path:1} && php artisan serve" {
+		t.Errorf("unknown scheme should be left as is, got %q", got)
+	}
+
+	secrets := cfg.Secrets()
+	if secrets.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", secrets.Len())
+	}
+	refs := secrets.Refs()
+	if len(refs) != 4 || refs[0].Path != "global.api_auth" || refs[3].Path != "processes.app.env.DB_PASSWORD" {
+		t.Errorf("Refs() = %+v", refs)
+	}
+
+	masked := secrets.MaskConfig(cfg)
+	if masked.Processes["app"].Env["DATABASE_URL"] != "mysql://app:***@db/app" || masked.Global.APIAuth != "***" {
+		t.Errorf("MaskConfig() env = %v, api_auth = %q", masked.Processes["app"].Env, masked.Global.APIAuth)
+	}
+	if cfg.Processes["app"].Env["DB_PASSWORD"] != "s3cr3t-pw" {
+		t.Error("MaskConfig() must not modify the config")
+	}
+	if got := secrets.Mask("login with tok-123456"); got != "login with ***" {
+		t.Errorf("Mask() = %q", got)
+	}
+}
+
+func TestSecrets_MaskByPath(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"secrets/queue": "default",
+		"secrets/pin":   "42",
+	})
+	secretsDir := filepath.Join(dir, "secrets")
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+	writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": fmt.Sprintf(`
+processes:
+  app:
+    command: ["php", "artisan", "queue:work", "--queue=default"]
+    env:
+      QUEUE: ${file:%[1]s/queue}
+      PIN: "${file:%[1]s/pin}"
+`, secretsDir)})
+
+	cfg, err := LoadWithEnvExpansion(path)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+	secrets := cfg.Secrets()
+
+	// Only the values resolved from references are masked
+	masked := secrets.MaskProcess("app", cfg.Processes["app"])
+	if masked.Env["QUEUE"] != SecretMask || masked.Command[3] != "--queue=default" {
+		t.Errorf("MaskProcess() env = %v, command = %v", masked.Env, masked.Command)
+	}
+
+	// A value changed since loading still has its secret masked
+	cfg.Processes["app"].Env["QUEUE"] = "default,high"
+	if got := secrets.MaskConfig(cfg).Processes["app"].Env["QUEUE"]; got != "***,high" {
+		t.Errorf("MaskConfig() QUEUE = %q, want secret masked", got)
+	}
+
+	// Free text masks secrets of at least minMaskLength only
+	if got := secrets.Mask("started 42 workers on default"); got != "started 42 workers on ***" {
+		t.Errorf("Mask() = %q", got)
+	}
+
+	// Save writes references back only where the value is unchanged
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"--queue=default", "QUEUE: default,high", "${file:" + secretsDir + "/pin}"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("saved config missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestLoadWithOptions_ProfileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "db_password")
+	writeConfigFiles(t, dir, map[string]string{
+		"db_password": "s3cr3t-pw",
+		"phpeek-pm.yaml": `
+processes:
+  app:
+    command: ["php", "artisan", "serve"]
+profiles:
+  staging:
+    processes:
+      app:
+        env:
+          DB_PASSWORD: ${file:` + secretPath + `}
+  production:
+    processes:
+      app:
+        env:
+          DB_PASSWORD: ${file:/does/not/exist}
+`,
+	})
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+
+	// Secrets of profiles that are not selected are not resolved
+	cfg, err := LoadWithOptions(path, LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if cfg.Secrets().Len() != 0 {
+		t.Errorf("Refs() = %+v, want none", cfg.Secrets().Refs())
+	}
+
+	cfg, err = LoadWithOptions(path, LoadOptions{Profile: "staging"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	if got := cfg.Processes["app"].Env["DB_PASSWORD"]; got != "s3cr3t-pw" {
+		t.Errorf("DB_PASSWORD = %q, want resolved secret", got)
+	}
+	refs := cfg.Secrets().Refs()
+	if len(refs) != 1 || refs[0].Path != "processes.app.env.DB_PASSWORD" {
+		t.Errorf("Refs() = %+v, want the effective key path", refs)
+	}
+	if profiles := fmt.Sprint(cfg.Profiles); strings.Contains(profiles, "s3cr3t-pw") || !strings.Contains(profiles, "${file:"+secretPath+"}") {
+		t.Errorf("profiles section should keep the reference, got %s", profiles)
+	}
+
+	if _, err := LoadWithOptions(path, LoadOptions{Profile: "production"}); err == nil || !strings.Contains(err.Error(), `profile "production"`) {
+		t.Errorf("LoadWithOptions(production) error = %v, want unresolvable secret", err)
+	}
+}
+
+func TestSave_WritesSecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "db_password")
+	writeConfigFiles(t, dir, map[string]string{
+		"db_password": "s3cr3t-pw",
+		"phpeek-pm.yaml": `
+processes:
+  app:
+    command: ["php", "artisan", "serve"]
+    env:
+      DB_PASSWORD: ${file:` + secretPath + `}
+`,
+	})
+	path := filepath.Join(dir, "phpeek-pm.yaml")
+
+	cfg, err := LoadWithEnvExpansion(path)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "s3cr3t-pw") || !strings.Contains(string(data), "${file:"+secretPath+"}") {
+		t.Errorf("saved config should contain the reference, got:\n%s", data)
+	}
+	if cfg.Processes["app"].Env["DB_PASSWORD"] != "s3cr3t-pw" {
+		t.Error("Save() must not modify the config")
+	}
+
+	// The saved file loads to the same value
+	reloaded, err := LoadWithEnvExpansion(path)
+	if err != nil || reloaded.Processes["app"].Env["DB_PASSWORD"] != "s3cr3t-pw" {
+		t.Errorf("reloaded env = %v, err = %v", reloaded.Processes["app"].Env, err)
+	}
+}
+
+func TestLoadWithEnvExpansion_SecretErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "missing secret file",
+			config:  "processes:\n  app:\n    command: [\"${file:/does/not/exist}\"]\n",
+			wantErr: "processes.app.command.0: failed to resolve ${file:/does/not/exist}",
+		},
+		{
+			name:    "required variable",
+			config:  "global:\n  api_auth: ${PHPEEK_TEST_UNSET_TOKEN:?set the API token}\n",
+			wantErr: "PHPEEK_TEST_UNSET_TOKEN: set the API token",
+		},
+		{
+			name:    "required variable with env scheme",
+			config:  "global:\n  api_auth: ${env:PHPEEK_TEST_UNSET_TOKEN:?}\n",
+			wantErr: "PHPEEK_TEST_UNSET_TOKEN: required variable is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": tt.config})
+
+			_, err := LoadWithEnvExpansion(filepath.Join(dir, "phpeek-pm.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadWithEnvExpansion() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpandEnv_EnvScheme(t *testing.T) {
+	t.Setenv("PHPEEK_TEST_TOKEN", "abc")
+
+	got, err := expandEnv("${env:PHPEEK_TEST_TOKEN} ${env:PHPEEK_TEST_MISSING:-def} ${PHPEEK_TEST_TOKEN:?required}")
+	if err != nil || got != "abc def abc" {
+		t.Errorf("expandEnv() = %q, %v", got, err)
+	}
+}
+
+func TestRegisterSecretProvider(t *testing.T) {
+	calls := 0
+	RegisterSecretProvider("test", SecretProviderFunc(func(ref string) (string, error) {
+		calls++
+		return "value-of-" + ref, nil
+	}))
+	defer RegisterSecretProvider("test", nil)
+
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{"phpeek-pm.yaml": `
+processes:
+  app:
+    command: ["php", "artisan", "serve"]
+    env:
+      A: ${test:one}
+      B: ${test:one}
+`})
+
+	cfg, err := LoadWithEnvExpansion(filepath.Join(dir, "phpeek-pm.yaml"))
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+	if cfg.Processes["app"].Env["A"] != "value-of-one" || cfg.Processes["app"].Env["B"] != "value-of-one" {
+		t.Errorf("env = %v", cfg.Processes["app"].Env)
+	}
+	if calls != 1 {
+		t.Errorf("provider called %d times, want 1 (cached per load)", calls)
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app": // KV v2
+			_, _ = w.Write([]byte(`{"data":{"data":{"db_password":"kv2-pw","port":5432},"metadata":{"version":3}}}`))
+		case "/v1/kv/app": // KV v1
+			_, _ = w.Write([]byte(`{"data":{"token":"kv1-token"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "root-token")
+	p := NewVaultProvider()
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{ref: "secret/data/app#db_password", want: "kv2-pw"},
+		{ref: "secret/data/app#port", want: "5432"},
+		{ref: "kv/app", want: "kv1-token"},
+		{ref: "secret/data/app", wantErr: "select one with #field"},
+		{ref: "secret/data/app#missing", wantErr: `no field "missing"`},
+		{ref: "secret/data/other#x", wantErr: "vault returned 404"},
+	}
+	for _, tt := range tests {
+		got, err := p.Resolve(tt.ref)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q) error = %v, want %q", tt.ref, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	t.Setenv("VAULT_TOKEN", "wrong")
+	if _, err := p.Resolve("kv/app"); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected permission error, got %v", err)
+	}
+
+	t.Setenv("VAULT_TOKEN", "")
+	if _, err := p.Resolve("kv/app"); err == nil {
+		t.Error("expected error without VAULT_TOKEN")
+	}
+}
+
+func TestSSMProvider(t *testing.T) {
+	var lastAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastAuth = r.Header.Get("Authorization")
+		if r.Header.Get("X-Amz-Target") != "AmazonSSM.GetParameter" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Name           string
+			WithDecryption bool
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.WithDecryption {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Name != "/app/prod/db_password" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ParameterNotFound","message":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Parameter":{"Name":"/app/prod/db_password","Type":"SecureString","Value":"ssm-pw"}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ENDPOINT_URL_SSM", server.URL)
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	p := NewSSMProvider()
+	p.Now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	got, err := p.Resolve("/app/prod/db_password")
+	if err != nil || got != "ssm-pw" {
+		t.Fatalf("Resolve() = %q, %v", got, err)
+	}
+	wantPrefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260102/eu-west-1/ssm/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-target, Signature="
+	if !strings.HasPrefix(lastAuth, wantPrefix) {
+		t.Errorf("Authorization = %q, want prefix %q", lastAuth, wantPrefix)
+	}
+
+	if _, err := p.Resolve("/app/prod/missing"); err == nil || !strings.Contains(err.Error(), "ParameterNotFound") {
+		t.Errorf("expected ParameterNotFound, got %v", err)
+	}
+
+	// Unsigned without credentials (e.g. a local SSM-compatible endpoint)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	if _, err := p.Resolve("/app/prod/db_password"); err != nil || lastAuth != "" {
+		t.Errorf("unsigned request: err = %v, Authorization = %q", err, lastAuth)
+	}
+}
+
+func TestSignAWSv4(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sign := func(secretKey string) string {
+		req := newSSMRequest(t)
+		signAWSv4(req, []byte(`{}`), "AKID", secretKey, "session", "us-east-1", "ssm", now)
+		if req.Header.Get("X-Amz-Security-Token") != "session" || req.Header.Get("X-Amz-Date") != "20260102T030405Z" {
+			t.Errorf("missing signing headers: %v", req.Header)
+		}
+		return req.Header.Get("Authorization")
+	}
+
+	// Deterministic for the same input, different for another key
+	if a, b := sign("secret"), sign("secret"); a != b {
+		t.Errorf("signature not deterministic:\n%s\n%s", a, b)
+	}
+	if sign("secret") == sign("other") {
+		t.Error("signature should depend on the secret key")
+	}
+}
+
+func newSSMRequest(t *testing.T) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "https://ssm.us-east-1.amazonaws.com/", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	return req
+}
@@ -1,6 +1,13 @@
 package config
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
 
 // Config represents the complete phpeek-pm configuration
 type Config struct {
@@ -8,53 +15,65 @@ type Config struct {
 	Global    GlobalConfig        `yaml:"global" json:"global"`
 	Hooks     HooksConfig         `yaml:"hooks" json:"hooks"`
 	Processes map[string]*Process `yaml:"processes" json:"processes"`
+	Profiles  map[string]Profile  `yaml:"profiles,omitempty" json:"profiles,omitempty"` // Per-environment overrides (see profile.go)
+	Sources   *Sources            `yaml:"-" json:"-"`                                   // Files the config was loaded from (nil if not loaded from files)
+
+	autotuned map[string]*autotunedProcess // Processes sized by auto-tuning (see SetAutotuned)
 }
 
 // GlobalConfig contains global settings for the process manager
 type GlobalConfig struct {
-	ShutdownTimeout           int              `yaml:"shutdown_timeout" json:"shutdown_timeout"`                         // seconds
-	HealthCheckInterval       int              `yaml:"health_check_interval" json:"health_check_interval"`               // seconds
-	RestartPolicy             string           `yaml:"restart_policy" json:"restart_policy"`                             // always | on-failure | never
-	MaxRestartAttempts        int              `yaml:"max_restart_attempts" json:"max_restart_attempts"`                 //
-	RestartBackoff            int              `yaml:"restart_backoff" json:"restart_backoff"`                           // seconds (legacy, prefer restart_backoff_initial/max)
-	RestartBackoffInitial     time.Duration    `yaml:"restart_backoff_initial" json:"restart_backoff_initial"`           // initial duration (supports "5s" style)
-	RestartBackoffMax         time.Duration    `yaml:"restart_backoff_max" json:"restart_backoff_max"`                   // max duration
-	AutotuneMemoryThreshold   float64          `yaml:"autotune_memory_threshold" json:"autotune_memory_threshold"`       // 0.0-2.0, overrides profile MaxMemoryUsage
-	LogFormat                 string           `yaml:"log_format" json:"log_format"`                                     // json | text
-	LogLevel                  string           `yaml:"log_level" json:"log_level"`                                       // debug | info | warn | error
-	LogTimestamps             bool             `yaml:"log_timestamps" json:"log_timestamps"`                             //
-	MetricsEnabled            *bool            `yaml:"metrics_enabled" json:"metrics_enabled"`                           //
-	MetricsPort               int              `yaml:"metrics_port" json:"metrics_port"`                                 //
-	MetricsPath               string           `yaml:"metrics_path" json:"metrics_path"`                                 //
-	APIEnabled                *bool            `yaml:"api_enabled" json:"api_enabled"`                                   //
-	APIPort                   int              `yaml:"api_port" json:"api_port"`                                         //
-	APISocket                 string           `yaml:"api_socket" json:"api_socket"`                                     // Unix socket path (e.g. /var/run/phpeek-pm.sock)
-	APIAuth                   string           `yaml:"api_auth" json:"api_auth"`                                         // Bearer token
-	APITLS                    *TLSConfig       `yaml:"api_tls" json:"api_tls"`                                           // TLS configuration for API
-	APIACL                    *ACLConfig       `yaml:"api_acl" json:"api_acl"`                                           // IP ACL for API
-	MetricsTLS                *TLSConfig       `yaml:"metrics_tls" json:"metrics_tls"`                                   // TLS configuration for metrics
-	MetricsACL                *ACLConfig       `yaml:"metrics_acl" json:"metrics_acl"`                                   // IP ACL for metrics
-	ResourceMetricsEnabled    *bool            `yaml:"resource_metrics_enabled" json:"resource_metrics_enabled"`         // Enable CPU/RAM collection
-	ResourceMetricsInterval   int              `yaml:"resource_metrics_interval" json:"resource_metrics_interval"`       // seconds (default: 5)
-	ResourceMetricsMaxSamples int              `yaml:"resource_metrics_max_samples" json:"resource_metrics_max_samples"` // Per-instance buffer size (default: 720 = 1h at 5s)
-	AuditEnabled              bool             `yaml:"audit_enabled" json:"audit_enabled"`                               // Enable audit logging
-	TracingEnabled            bool             `yaml:"tracing_enabled" json:"tracing_enabled"`                           // Enable distributed tracing
-	TracingExporter           string           `yaml:"tracing_exporter" json:"tracing_exporter"`                         // otlp-grpc | otlp-http | stdout | jaeger | zipkin
-	TracingEndpoint           string           `yaml:"tracing_endpoint" json:"tracing_endpoint"`                         // Exporter endpoint (e.g., localhost:4317)
-	TracingSampleRate         float64          `yaml:"tracing_sample_rate" json:"tracing_sample_rate"`                   // 0.0-1.0 (default: 1.0 = 100%)
-	TracingServiceName        string           `yaml:"tracing_service_name" json:"tracing_service_name"`                 // Service name for traces (default: phpeek-pm)
-	TracingUseTLS             bool             `yaml:"tracing_use_tls" json:"tracing_use_tls"`                           // Enable TLS for production (default: false)
-	ScheduleHistorySize       int              `yaml:"schedule_history_size" json:"schedule_history_size"`               // Max execution history entries per job (default: 100)
-	OneshotHistoryMaxEntries  int              `yaml:"oneshot_history_max_entries" json:"oneshot_history_max_entries"`   // Max oneshot history entries per process (default: 5000)
-	OneshotHistoryMaxAge      time.Duration    `yaml:"oneshot_history_max_age" json:"oneshot_history_max_age"`           // Max age of oneshot history entries (default: 24h)
-	Readiness                 *ReadinessConfig `yaml:"readiness" json:"readiness"`                                       // Container readiness file config for K8s
-	HealthCheckStrict         bool             `yaml:"health_check_strict" json:"health_check_strict"`                   // Fail process startup if health monitor creation fails (default: false)
-	DependencyTimeout         time.Duration    `yaml:"dependency_timeout" json:"dependency_timeout"`                     // Max time to wait for dependencies to become ready (default: 5m)
-	ProcessStartTimeout       time.Duration    `yaml:"process_start_timeout" json:"process_start_timeout"`               // Timeout for starting a single process (default: 30s)
-	ProcessStopTimeout        time.Duration    `yaml:"process_stop_timeout" json:"process_stop_timeout"`                 // Timeout for stopping a single process (default: 60s)
-	MaxProcessScale           int              `yaml:"max_process_scale" json:"max_process_scale"`                       // Maximum instances per process (default: 100)
-	APIMaxRequestBody         int64            `yaml:"api_max_request_body" json:"api_max_request_body"`                 // Max request body size in bytes (default: 8MB)
-	ZombieReapInterval        time.Duration    `yaml:"zombie_reap_interval" json:"zombie_reap_interval"`                 // Interval for zombie process reaping (default: 1s)
+	ShutdownTimeout           int                 `yaml:"shutdown_timeout" json:"shutdown_timeout"`                                                 // seconds
+	HealthCheckInterval       int                 `yaml:"health_check_interval" json:"health_check_interval"`                                       // seconds
+	RestartPolicy             string              `yaml:"restart_policy" json:"restart_policy" enum:"always,on-failure,never"`                      // always | on-failure | never
+	MaxRestartAttempts        int                 `yaml:"max_restart_attempts" json:"max_restart_attempts"`                                         //
+	RestartBackoff            int                 `yaml:"restart_backoff" json:"restart_backoff"`                                                   // seconds (legacy, prefer restart_backoff_initial/max)
+	RestartBackoffInitial     time.Duration       `yaml:"restart_backoff_initial" json:"restart_backoff_initial"`                                   // initial duration (supports "5s" style)
+	RestartBackoffMax         time.Duration       `yaml:"restart_backoff_max" json:"restart_backoff_max"`                                           // max duration
+	AutotuneMemoryThreshold   float64             `yaml:"autotune_memory_threshold" json:"autotune_memory_threshold"`                               // 0.0-2.0, overrides profile MaxMemoryUsage
+	PHPFPMPool                *FPMPoolConfig      `yaml:"php_fpm_pool" json:"php_fpm_pool"`                                                         // Render php-fpm pool config from auto-tuning results
+	AutotuneLearning          *LearningConfig     `yaml:"autotune_learning" json:"autotune_learning"`                                               // Learn per-worker memory from observed php-fpm RSS
+	Autotune                  *AutotuneConfig     `yaml:"autotune" json:"autotune"`                                                                 // Custom auto-tuning profiles
+	LogFormat                 string              `yaml:"log_format" json:"log_format" enum:"json,text"`                                            // json | text
+	LogLevel                  string              `yaml:"log_level" json:"log_level" enum:"debug,info,warn,error"`                                  // debug | info | warn | error
+	LogTimestamps             bool                `yaml:"log_timestamps" json:"log_timestamps"`                                                     //
+	MetricsEnabled            *bool               `yaml:"metrics_enabled" json:"metrics_enabled"`                                                   //
+	MetricsPort               int                 `yaml:"metrics_port" json:"metrics_port"`                                                         //
+	MetricsPath               string              `yaml:"metrics_path" json:"metrics_path"`                                                         //
+	APIEnabled                *bool               `yaml:"api_enabled" json:"api_enabled"`                                                           //
+	APIPort                   int                 `yaml:"api_port" json:"api_port"`                                                                 //
+	APISocket                 string              `yaml:"api_socket" json:"api_socket"`                                                             // Unix socket path (e.g. /var/run/phpeek-pm.sock)
+	APIAuth                   string              `yaml:"api_auth" json:"api_auth"`                                                                 // Bearer token
+	APITLS                    *TLSConfig          `yaml:"api_tls" json:"api_tls"`                                                                   // TLS configuration for API
+	APIACL                    *ACLConfig          `yaml:"api_acl" json:"api_acl"`                                                                   // IP ACL for API
+	MetricsTLS                *TLSConfig          `yaml:"metrics_tls" json:"metrics_tls"`                                                           // TLS configuration for metrics
+	MetricsACL                *ACLConfig          `yaml:"metrics_acl" json:"metrics_acl"`                                                           // IP ACL for metrics
+	ResourceMetricsEnabled    *bool               `yaml:"resource_metrics_enabled" json:"resource_metrics_enabled"`                                 // Enable CPU/RAM collection
+	ResourceMetricsInterval   int                 `yaml:"resource_metrics_interval" json:"resource_metrics_interval"`                               // seconds (default: 5)
+	ResourceMetricsMaxSamples int                 `yaml:"resource_metrics_max_samples" json:"resource_metrics_max_samples"`                         // Per-instance buffer size (default: 720 = 1h at 5s)
+	AuditEnabled              bool                `yaml:"audit_enabled" json:"audit_enabled"`                                                       // Enable audit logging
+	TracingEnabled            bool                `yaml:"tracing_enabled" json:"tracing_enabled"`                                                   // Enable distributed tracing
+	TracingExporter           string              `yaml:"tracing_exporter" json:"tracing_exporter" enum:"otlp-grpc,otlp-http,stdout,jaeger,zipkin"` // otlp-grpc | otlp-http | stdout | jaeger | zipkin
+	TracingEndpoint           string              `yaml:"tracing_endpoint" json:"tracing_endpoint"`                                                 // Exporter endpoint (e.g., localhost:4317)
+	TracingSampleRate         float64             `yaml:"tracing_sample_rate" json:"tracing_sample_rate"`                                           // 0.0-1.0 (default: 1.0 = 100%)
+	TracingServiceName        string              `yaml:"tracing_service_name" json:"tracing_service_name"`                                         // Service name for traces (default: phpeek-pm)
+	TracingUseTLS             bool                `yaml:"tracing_use_tls" json:"tracing_use_tls"`                                                   // Enable TLS for production (default: false)
+	ScheduleHistorySize       int                 `yaml:"schedule_history_size" json:"schedule_history_size"`                                       // Max execution history entries per job (default: 100)
+	ScheduleLock              *ScheduleLockConfig `yaml:"schedule_lock" json:"schedule_lock"`                                                       // Run scheduled jobs on one replica at a time
+	OneshotHistoryMaxEntries  int                 `yaml:"oneshot_history_max_entries" json:"oneshot_history_max_entries"`                           // Max oneshot history entries per process (default: 5000)
+	OneshotHistoryMaxAge      time.Duration       `yaml:"oneshot_history_max_age" json:"oneshot_history_max_age"`                                   // Max age of oneshot history entries (default: 24h)
+	CrashContextLines         int                 `yaml:"crash_context_lines" json:"crash_context_lines"`                                           // Log lines captured with each crash (default: 50)
+	CrashHistorySize          int                 `yaml:"crash_history_size" json:"crash_history_size"`                                             // Recent crashes kept per process (default: 10)
+	Readiness                 *ReadinessConfig    `yaml:"readiness" json:"readiness"`                                                               // Container readiness file config for K8s
+	Cgroup                    *CgroupConfig       `yaml:"cgroup" json:"cgroup"`                                                                     // Memory events and pressure (PSI) watcher
+	HealthCheckStrict         bool                `yaml:"health_check_strict" json:"health_check_strict"`                                           // Fail process startup if health monitor creation fails (default: false)
+	DependencyTimeout         time.Duration       `yaml:"dependency_timeout" json:"dependency_timeout"`                                             // Max time to wait for dependencies to become ready (default: 5m)
+	ProcessStartTimeout       time.Duration       `yaml:"process_start_timeout" json:"process_start_timeout"`                                       // Timeout for starting a single process (default: 30s)
+	ProcessStopTimeout        time.Duration       `yaml:"process_stop_timeout" json:"process_stop_timeout"`                                         // Timeout for stopping a single process (default: 60s)
+	ProcessParallelism        int                 `yaml:"process_parallelism" json:"process_parallelism"`                                           // Max processes started or stopped at once per dependency level (default: 0 = unlimited)
+	MaxProcessScale           int                 `yaml:"max_process_scale" json:"max_process_scale"`                                               // Maximum instances per process (default: 100)
+	APIMaxRequestBody         int64               `yaml:"api_max_request_body" json:"api_max_request_body"`                                         // Max request body size in bytes (default: 8MB)
+	ZombieReapInterval        time.Duration       `yaml:"zombie_reap_interval" json:"zombie_reap_interval"`                                         // Interval for zombie process reaping (default: 1s)
 }
 
 // HooksConfig contains lifecycle hooks
@@ -79,28 +98,228 @@ type Hook struct {
 
 // Process represents a managed process definition
 type Process struct {
-	Enabled               bool              `yaml:"enabled" json:"enabled"`
-	Type                  string            `yaml:"type" json:"type"`                   // oneshot | longrun (default: longrun)
-	InitialState          string            `yaml:"initial_state" json:"initial_state"` // running | stopped (default: running)
-	Command               []string          `yaml:"command" json:"command"`
-	WorkingDir            string            `yaml:"working_dir" json:"working_dir"` // Working directory override
-	User                  string            `yaml:"user" json:"user"`               // Run as user (name or uid)
-	Group                 string            `yaml:"group" json:"group"`             // Run as group (name or gid)
-	Stdout                *bool             `yaml:"stdout" json:"stdout"`           // Legacy shorthand for logging.stdout
-	Stderr                *bool             `yaml:"stderr" json:"stderr"`           // Legacy shorthand for logging.stderr
-	Restart               string            `yaml:"restart" json:"restart"`         // always | on-failure | never
-	Scale                 int               `yaml:"scale" json:"scale"`             // Number of instances
-	MaxScale              int               `yaml:"max_scale" json:"max_scale"`     // Maximum instances (0 = no limit)
-	DependsOn             []string          `yaml:"depends_on" json:"depends_on"`   // Process dependencies
-	Env                   map[string]string `yaml:"env" json:"env"`
-	HealthCheck           *HealthCheck      `yaml:"health_check" json:"health_check"`
-	Shutdown              *ShutdownConfig   `yaml:"shutdown" json:"shutdown"`
-	Logging               *LoggingConfig    `yaml:"logging" json:"logging"`
-	Schedule              string            `yaml:"schedule" json:"schedule"`                               // Cron expression: "*/5 * * * *"
-	ScheduleTimezone      string            `yaml:"schedule_timezone" json:"schedule_timezone"`             // Timezone: "UTC" (default) | "Local"
-	ScheduleTimeout       string            `yaml:"schedule_timeout" json:"schedule_timeout"`               // Execution timeout: "30s", "5m", "1h" (default: no timeout)
-	ScheduleMaxConcurrent int               `yaml:"schedule_max_concurrent" json:"schedule_max_concurrent"` // Max concurrent: 1=no overlap, 0=unlimited (default: 0)
-	Heartbeat             *HeartbeatConfig  `yaml:"heartbeat" json:"heartbeat"`                             // Heartbeat monitoring config
+	Enabled               bool                   `yaml:"enabled" json:"enabled"`
+	Type                  string                 `yaml:"type" json:"type" enum:"oneshot,longrun"`                   // oneshot | longrun (default: longrun)
+	InitialState          string                 `yaml:"initial_state" json:"initial_state" enum:"running,stopped"` // running | stopped (default: running)
+	Command               []string               `yaml:"command" json:"command"`
+	WorkingDir            string                 `yaml:"working_dir" json:"working_dir"`                        // Working directory override
+	User                  string                 `yaml:"user" json:"user"`                                      // Run as user (name or uid)
+	Group                 string                 `yaml:"group" json:"group"`                                    // Run as group (name or gid)
+	Stdout                *bool                  `yaml:"stdout" json:"stdout"`                                  // Legacy shorthand for logging.stdout
+	Stderr                *bool                  `yaml:"stderr" json:"stderr"`                                  // Legacy shorthand for logging.stderr
+	Restart               string                 `yaml:"restart" json:"restart" enum:"always,on-failure,never"` // always | on-failure | never
+	Scale                 int                    `yaml:"scale" json:"scale"`                                    // Number of instances
+	MaxScale              int                    `yaml:"max_scale" json:"max_scale"`                            // Maximum instances (0 = no limit)
+	DependsOn             []string               `yaml:"depends_on" json:"depends_on"`                          // Process dependencies
+	DependsOnOptions      map[string]*Dependency `yaml:"-" json:"depends_on_options,omitempty"`                 // Long form depends_on entries by process name
+	Triggers              *ProcessTriggers       `yaml:"triggers" json:"triggers"`                              // Jobs started when this oneshot or scheduled job completes
+	Env                   map[string]string      `yaml:"env" json:"env"`
+	HealthCheck           *HealthCheck           `yaml:"health_check" json:"health_check"`
+	Shutdown              *ShutdownConfig        `yaml:"shutdown" json:"shutdown"`
+	Logging               *LoggingConfig         `yaml:"logging" json:"logging"`
+	Schedule              string                 `yaml:"schedule" json:"schedule"`                                                 // Cron expression: "*/5 * * * *"
+	ScheduleTimezone      string                 `yaml:"schedule_timezone" json:"schedule_timezone"`                               // Timezone: "UTC" (default) | "Local"
+	ScheduleTimeout       string                 `yaml:"schedule_timeout" json:"schedule_timeout"`                                 // Execution timeout: "30s", "5m", "1h" (default: no timeout)
+	ScheduleJitter        time.Duration          `yaml:"schedule_jitter" json:"schedule_jitter"`                                   // Random delay of scheduled starts up to this duration: "30s" (default: none)
+	ScheduleBlackout      []string               `yaml:"schedule_blackout" json:"schedule_blackout"`                               // Daily windows without scheduled runs: ["22:00-06:00"]
+	ScheduleWeekdaysOnly  bool                   `yaml:"schedule_weekdays_only" json:"schedule_weekdays_only"`                     // Skip scheduled runs on Saturdays and Sundays
+	ScheduleSkipDates     []string               `yaml:"schedule_skip_dates" json:"schedule_skip_dates"`                           // Days without scheduled runs: ["2025-12-25"]
+	ScheduleOverlap       string                 `yaml:"schedule_overlap" json:"schedule_overlap" enum:"skip,queue,replace,allow"` // skip | queue | replace | allow (default: skip, allow when schedule_max_concurrent > 1)
+	ScheduleMaxConcurrent int                    `yaml:"schedule_max_concurrent" json:"schedule_max_concurrent"`                   // Max parallel runs with schedule_overlap: allow (0 = unlimited)
+	ScheduleQueueSize     int                    `yaml:"schedule_queue_size" json:"schedule_queue_size"`                           // Max waiting runs with schedule_overlap: queue (default: 1)
+	ScheduleRetry         *ScheduleRetry         `yaml:"schedule_retry" json:"schedule_retry"`                                     // Retry failed runs with exponential backoff
+	ScheduleDisableAfter  int                    `yaml:"schedule_disable_after" json:"schedule_disable_after"`                     // Disable the schedule after N consecutive failed runs (0 = never)
+	ScheduleLock          *bool                  `yaml:"schedule_lock" json:"schedule_lock"`                                       // Take global.schedule_lock before runs (default: true when it is set)
+	ScheduleOutput        *ScheduleOutput        `yaml:"schedule_output" json:"schedule_output"`                                   // Output kept with each execution in the schedule history
+	Heartbeat             *HeartbeatConfig       `yaml:"heartbeat" json:"heartbeat"`                                               // Heartbeat monitoring config
+	Autotune              *ProcessAutotune       `yaml:"autotune" json:"autotune"`                                                 // Size from the shared container memory budget
+}
+
+// ProcessTriggers chains oneshot and scheduled processes into workflows: when
+// a run completes, the processes listed for its outcome are started. Every
+// job of a chain gets the chain's run ID in PHPEEK_PM_CHAIN_ID.
+type ProcessTriggers struct {
+	OnSuccess []string `yaml:"on_success" json:"on_success"` // Started after a successful run
+	OnFailure []string `yaml:"on_failure" json:"on_failure"` // Started after a failed run
+}
+
+// Targets returns every process started by the triggers, without duplicates
+func (t *ProcessTriggers) Targets() []string {
+	if t == nil {
+		return nil
+	}
+	targets := make([]string, 0, len(t.OnSuccess)+len(t.OnFailure))
+	seen := make(map[string]bool, cap(targets))
+	for _, target := range append(append([]string{}, t.OnSuccess...), t.OnFailure...) {
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// IsJob returns true for processes that run to completion (oneshot or
+// scheduled), the only ones that can trigger or be triggered by others
+func (p *Process) IsJob() bool {
+	return p.Type == "oneshot" || p.Schedule != ""
+}
+
+// Conditions a process waits for before it starts after a dependency
+const (
+	DependencyStarted               = "started"                // The dependency has been started
+	DependencyHealthy               = "healthy"                // Its health check passed
+	DependencyCompletedSuccessfully = "completed_successfully" // The oneshot dependency exited with 0
+)
+
+// Dependency is the long form of a depends_on entry, as in docker-compose:
+//
+//	depends_on:
+//	  migrate:
+//	    condition: completed_successfully
+//	    timeout: 10m
+//	  redis:
+//	    condition: healthy
+//
+// Without a condition the process waits until the dependency is ready, which
+// is when its health check passes if it has one and right after it started
+// otherwise.
+type Dependency struct {
+	Condition string        `yaml:"condition" json:"condition" enum:"started,healthy,completed_successfully"` // What to wait for (default: ready)
+	Timeout   time.Duration `yaml:"timeout" json:"timeout"`                                                   // Max wait (default: global.dependency_timeout)
+}
+
+// Dependency returns the options of the dependency on name (zero if it was
+// listed in the short form)
+func (p *Process) Dependency(name string) Dependency {
+	if dep := p.DependsOnOptions[name]; dep != nil {
+		return *dep
+	}
+	return Dependency{}
+}
+
+// Reasons a dependency can never satisfy a condition (see CheckCondition)
+var (
+	ErrInvalidCondition  = errors.New("invalid condition")
+	ErrNoHealthCheck     = errors.New("no health check")
+	ErrNotStartupOneshot = errors.New("not a oneshot process run at startup")
+)
+
+// CheckCondition returns an error if the dependency process proc can never
+// satisfy the condition. With a nil proc only the condition is checked.
+func (d Dependency) CheckCondition(proc *Process) error {
+	switch d.Condition {
+	case "", DependencyStarted:
+	case DependencyHealthy:
+		if proc != nil && proc.HealthCheck == nil {
+			return fmt.Errorf("cannot become healthy: %w", ErrNoHealthCheck)
+		}
+	case DependencyCompletedSuccessfully:
+		if proc != nil && (proc.Type != "oneshot" || proc.Schedule != "") {
+			return fmt.Errorf("cannot complete successfully: %w", ErrNotStartupOneshot)
+		}
+	default:
+		return fmt.Errorf("%w %q (must be started, healthy or completed_successfully)", ErrInvalidCondition, d.Condition)
+	}
+	return nil
+}
+
+// UnmarshalYAML accepts depends_on as a list of process names or as a map
+// of process names to Dependency options
+func (p *Process) UnmarshalYAML(value *yaml.Node) error {
+	type plain Process
+
+	// Decode the long form separately, the rest decodes as usual
+	node := *value
+	var long *yaml.Node
+	if node.Kind == yaml.MappingNode {
+		content := make([]*yaml.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "depends_on" && node.Content[i+1].Kind == yaml.MappingNode {
+				long = node.Content[i+1]
+				continue
+			}
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+		node.Content = content
+	}
+	if err := node.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	if long == nil {
+		return nil
+	}
+
+	p.DependsOn = make([]string, 0, len(long.Content)/2)
+	p.DependsOnOptions = make(map[string]*Dependency, len(long.Content)/2)
+	for i := 0; i+1 < len(long.Content); i += 2 {
+		name := long.Content[i].Value
+		dep := &Dependency{}
+		if err := long.Content[i+1].Decode(dep); err != nil {
+			return fmt.Errorf("depends_on.%s: %w", name, err)
+		}
+		p.DependsOn = append(p.DependsOn, name)
+		p.DependsOnOptions[name] = dep
+	}
+	return nil
+}
+
+// MarshalYAML writes depends_on in the long form if any entry has options
+func (p Process) MarshalYAML() (interface{}, error) {
+	type plain Process
+	if len(p.DependsOnOptions) == 0 {
+		return plain(p), nil
+	}
+
+	var node yaml.Node
+	if err := node.Encode(plain(p)); err != nil {
+		return nil, err
+	}
+	long := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, name := range p.DependsOn {
+		var value yaml.Node
+		if err := value.Encode(p.Dependency(name)); err != nil {
+			return nil, err
+		}
+		long.Content = append(long.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, &value)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "depends_on" {
+			node.Content[i+1] = long
+		}
+	}
+	return &node, nil
+}
+
+// ProcessAutotune sizes a process from the container memory budget shared
+// with php-fpm and other auto-tuned processes
+type ProcessAutotune struct {
+	Enabled           bool    `yaml:"enabled" json:"enabled"`                         // Set scale (or workers) from the memory budget
+	MemoryPerInstance int     `yaml:"memory_per_instance" json:"memory_per_instance"` // MB per instance (per worker with workers_flag)
+	Weight            float64 `yaml:"weight" json:"weight"`                           // Share of the budget relative to other consumers (default: 1)
+	MinScale          int     `yaml:"min_scale" json:"min_scale"`                     // Lower bound (default: 1)
+	MaxScale          int     `yaml:"max_scale" json:"max_scale"`                     // Upper bound (0 = process max_scale or unlimited)
+	WorkersFlag       string  `yaml:"workers_flag" json:"workers_flag"`               // Set this command flag instead of scale (e.g., --workers for Octane)
+}
+
+// ScheduleRetry retries failed runs of a scheduled process. Every attempt is
+// recorded in the schedule history, linked to the first attempt of its run.
+type ScheduleRetry struct {
+	MaxAttempts int           `yaml:"max_attempts" json:"max_attempts"` // Attempts per run including the first (default: 3)
+	Backoff     time.Duration `yaml:"backoff" json:"backoff"`           // Delay before the first retry, doubled for each further retry (default: 10s)
+	MaxBackoff  time.Duration `yaml:"max_backoff" json:"max_backoff"`   // Upper bound for the delay (default: 5m)
+	Jitter      float64       `yaml:"jitter" json:"jitter"`             // Random fraction of the delay added or removed, 0-1 (default: 0.2, -1 = none)
+	ExitCodes   []int         `yaml:"exit_codes" json:"exit_codes"`     // Only retry these exit codes (default: any failure)
+}
+
+// ScheduleOutput bounds the output kept with each execution of a scheduled
+// process. The first and last lines are kept, the lines in between are
+// replaced by a truncation marker.
+type ScheduleOutput struct {
+	HeadLines     int  `yaml:"head_lines" json:"head_lines"`           // First lines kept per execution (default: 100)
+	TailLines     int  `yaml:"tail_lines" json:"tail_lines"`           // Last lines kept per execution (default: 200)
+	MaxLineLength int  `yaml:"max_line_length" json:"max_line_length"` // Longer lines are cut, in bytes (default: 4096)
+	OnFailureOnly bool `yaml:"on_failure_only" json:"on_failure_only"` // Drop the output of successful executions
 }
 
 // HeartbeatConfig configures heartbeat monitoring for scheduled jobs
@@ -112,17 +331,17 @@ type HeartbeatConfig struct {
 
 // HealthCheck configuration
 type HealthCheck struct {
-	Type             string   `yaml:"type" json:"type"`                   // tcp | http | exec
-	Address          string   `yaml:"address" json:"address"`             // For TCP
-	URL              string   `yaml:"url" json:"url"`                     // For HTTP
-	Command          []string `yaml:"command" json:"command"`             // For exec
-	InitialDelay     int      `yaml:"initial_delay" json:"initial_delay"` // seconds
-	Period           int      `yaml:"period" json:"period"`               // seconds
-	Timeout          int      `yaml:"timeout" json:"timeout"`             // seconds
+	Type             string   `yaml:"type" json:"type" enum:"tcp,http,exec"` // tcp | http | exec
+	Address          string   `yaml:"address" json:"address"`                // For TCP
+	URL              string   `yaml:"url" json:"url"`                        // For HTTP
+	Command          []string `yaml:"command" json:"command"`                // For exec
+	InitialDelay     int      `yaml:"initial_delay" json:"initial_delay"`    // seconds
+	Period           int      `yaml:"period" json:"period"`                  // seconds
+	Timeout          int      `yaml:"timeout" json:"timeout"`                // seconds
 	FailureThreshold int      `yaml:"failure_threshold" json:"failure_threshold"`
 	SuccessThreshold int      `yaml:"success_threshold" json:"success_threshold"`
-	ExpectedStatus   int      `yaml:"expected_status" json:"expected_status"` // For HTTP
-	Mode             string   `yaml:"mode" json:"mode"`                       // liveness | readiness | both (default: both)
+	ExpectedStatus   int      `yaml:"expected_status" json:"expected_status"`          // For HTTP
+	Mode             string   `yaml:"mode" json:"mode" enum:"liveness,readiness,both"` // liveness | readiness | both (default: both)
 }
 
 // ShutdownConfig configures graceful shutdown behavior
@@ -145,12 +364,35 @@ type LoggingConfig struct {
 	JSON           *JSONConfig           `yaml:"json" json:"json"`                       // JSON log parsing
 	LevelDetection *LevelDetectionConfig `yaml:"level_detection" json:"level_detection"` // Log level detection from content
 	Filters        *FilterConfig         `yaml:"filters" json:"filters"`                 // Include/exclude filtering
+
+	stdoutSet, stderrSet bool // Stdout/Stderr were set in YAML, so false disables the stream
+}
+
+// UnmarshalYAML records which streams were set explicitly. Unset streams
+// default to enabled.
+func (l *LoggingConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain LoggingConfig
+	if err := value.Decode((*plain)(l)); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		switch value.Content[i].Value {
+		case "stdout":
+			l.stdoutSet = true
+		case "stderr":
+			l.stderrSet = true
+		}
+	}
+	return nil
 }
 
 // RedactionConfig configures sensitive data redaction for compliance
 type RedactionConfig struct {
-	Enabled  bool               `yaml:"enabled" json:"enabled"`
-	Patterns []RedactionPattern `yaml:"patterns" json:"patterns"`
+	Enabled     bool               `yaml:"enabled" json:"enabled"`
+	Presets     []string           `yaml:"presets" json:"presets"`         // Built-in detectors (e.g., credit_card, jwt, email)
+	Keys        []string           `yaml:"keys" json:"keys"`               // JSON keys whose values are masked (case-insensitive)
+	Replacement string             `yaml:"replacement" json:"replacement"` // Replacement for presets and keys (default: "***")
+	Patterns    []RedactionPattern `yaml:"patterns" json:"patterns"`
 }
 
 // RedactionPattern defines a regex pattern for redacting sensitive data
@@ -162,10 +404,13 @@ type RedactionPattern struct {
 
 // MultilineConfig configures multiline log handling (e.g., stack traces)
 type MultilineConfig struct {
-	Enabled  bool   `yaml:"enabled" json:"enabled"`
-	Pattern  string `yaml:"pattern" json:"pattern"`     // Regex pattern matching start of new log entry
-	MaxLines int    `yaml:"max_lines" json:"max_lines"` // Max lines to buffer (default: 100)
-	Timeout  int    `yaml:"timeout" json:"timeout"`     // Flush timeout in seconds (default: 1)
+	Enabled    bool   `yaml:"enabled" json:"enabled"`
+	Preset     string `yaml:"preset" json:"preset"`           // Built-in rules: php, laravel, symfony, java, python, indented
+	Pattern    string `yaml:"pattern" json:"pattern"`         // Regex pattern matching start of new log entry
+	Negate     bool   `yaml:"negate" json:"negate"`           // Pattern matches continuation lines instead of entry starts
+	EndPattern string `yaml:"end_pattern" json:"end_pattern"` // Regex pattern matching the last line of an entry
+	MaxLines   int    `yaml:"max_lines" json:"max_lines"`     // Max lines to buffer (default: 100)
+	Timeout    int    `yaml:"timeout" json:"timeout"`         // Flush timeout in seconds (default: 1)
 }
 
 // JSONConfig configures JSON log parsing
@@ -192,33 +437,151 @@ type FilterConfig struct {
 
 // TLSConfig configures TLS/HTTPS for API and metrics endpoints
 type TLSConfig struct {
-	Enabled            bool     `yaml:"enabled" json:"enabled"`                           // Enable TLS
-	CertFile           string   `yaml:"cert_file" json:"cert_file"`                       // Path to certificate file
-	KeyFile            string   `yaml:"key_file" json:"key_file"`                         // Path to private key file
-	CAFile             string   `yaml:"ca_file" json:"ca_file"`                           // Path to CA certificate (for mTLS)
-	ClientAuth         string   `yaml:"client_auth" json:"client_auth"`                   // none | request | require | verify (default: none)
-	MinVersion         string   `yaml:"min_version" json:"min_version"`                   // TLS 1.2 | TLS 1.3 (default: TLS 1.2)
-	CipherSuites       []string `yaml:"cipher_suites" json:"cipher_suites"`               // Allowed cipher suites (empty = defaults)
-	AutoReload         bool     `yaml:"auto_reload" json:"auto_reload"`                   // Auto-reload certs on file change (default: false)
-	AutoReloadInterval int      `yaml:"auto_reload_interval" json:"auto_reload_interval"` // Check interval in seconds (default: 300)
+	Enabled            bool     `yaml:"enabled" json:"enabled"`                                                // Enable TLS
+	CertFile           string   `yaml:"cert_file" json:"cert_file"`                                            // Path to certificate file
+	KeyFile            string   `yaml:"key_file" json:"key_file"`                                              // Path to private key file
+	CAFile             string   `yaml:"ca_file" json:"ca_file"`                                                // Path to CA certificate (for mTLS)
+	ClientAuth         string   `yaml:"client_auth" json:"client_auth" enum:"none,request,require,verify"`     // none | request | require | verify (default: none)
+	MinVersion         string   `yaml:"min_version" json:"min_version" enum:"TLS 1.0,TLS 1.1,TLS 1.2,TLS 1.3"` // TLS 1.2 | TLS 1.3 (default: TLS 1.2)
+	CipherSuites       []string `yaml:"cipher_suites" json:"cipher_suites"`                                    // Allowed cipher suites (empty = defaults)
+	AutoReload         bool     `yaml:"auto_reload" json:"auto_reload"`                                        // Auto-reload certs on file change (default: false)
+	AutoReloadInterval int      `yaml:"auto_reload_interval" json:"auto_reload_interval"`                      // Check interval in seconds (default: 300)
 }
 
 // ACLConfig configures IP-based Access Control Lists
 type ACLConfig struct {
-	Enabled    bool     `yaml:"enabled" json:"enabled"`         // Enable IP ACL
-	Mode       string   `yaml:"mode" json:"mode"`               // allow | deny (default: allow)
-	AllowList  []string `yaml:"allow_list" json:"allow_list"`   // Allowed IP addresses/CIDRs
-	DenyList   []string `yaml:"deny_list" json:"deny_list"`     // Denied IP addresses/CIDRs
-	TrustProxy bool     `yaml:"trust_proxy" json:"trust_proxy"` // Trust X-Forwarded-For header (default: false)
+	Enabled    bool     `yaml:"enabled" json:"enabled"`             // Enable IP ACL
+	Mode       string   `yaml:"mode" json:"mode" enum:"allow,deny"` // allow | deny (default: allow)
+	AllowList  []string `yaml:"allow_list" json:"allow_list"`       // Allowed IP addresses/CIDRs
+	DenyList   []string `yaml:"deny_list" json:"deny_list"`         // Denied IP addresses/CIDRs
+	TrustProxy bool     `yaml:"trust_proxy" json:"trust_proxy"`     // Trust X-Forwarded-For header (default: false)
 }
 
 // ReadinessConfig configures container readiness file for Kubernetes integration
 type ReadinessConfig struct {
-	Enabled   bool     `yaml:"enabled" json:"enabled"`     // Enable readiness file creation
-	Path      string   `yaml:"path" json:"path"`           // Path to readiness file (default: /tmp/phpeek-ready)
-	Mode      string   `yaml:"mode" json:"mode"`           // Readiness mode: "all_healthy" | "all_running" (default: all_healthy)
-	Content   string   `yaml:"content" json:"content"`     // Optional content to write to the file
-	Processes []string `yaml:"processes" json:"processes"` // Specific processes to check (empty = all enabled longrun)
+	Enabled   bool     `yaml:"enabled" json:"enabled"`                          // Enable readiness file creation
+	Path      string   `yaml:"path" json:"path"`                                // Path to readiness file (default: /tmp/phpeek-ready)
+	Mode      string   `yaml:"mode" json:"mode" enum:"all_healthy,all_running"` // Readiness mode: "all_healthy" | "all_running" (default: all_healthy)
+	Content   string   `yaml:"content" json:"content"`                          // Optional content to write to the file
+	Processes []string `yaml:"processes" json:"processes"`                      // Specific processes to check (empty = all enabled longrun)
+}
+
+// FPMPoolConfig configures rendering of a php-fpm pool config file from
+// auto-tuning results, replacing envsubst'd www.conf templates
+type FPMPoolConfig struct {
+	Enabled                 bool   `yaml:"enabled" json:"enabled"`                                     // Write the pool file before processes start
+	Path                    string `yaml:"path" json:"path"`                                           // Output file (default: /usr/local/etc/php-fpm.d/zz-phpeek-pm.conf)
+	Name                    string `yaml:"name" json:"name"`                                           // Pool section name (default: www)
+	Listen                  string `yaml:"listen" json:"listen"`                                       // Address or socket path (default: 9000)
+	ListenOwner             string `yaml:"listen_owner" json:"listen_owner"`                           // Socket owner (unix sockets only)
+	ListenGroup             string `yaml:"listen_group" json:"listen_group"`                           // Socket group (unix sockets only)
+	ListenMode              string `yaml:"listen_mode" json:"listen_mode"`                             // Socket mode (default: 0660 for unix sockets)
+	StatusPath              string `yaml:"status_path" json:"status_path"`                             // pm.status_path (default: /status)
+	PingPath                string `yaml:"ping_path" json:"ping_path"`                                 // ping.path (default: /ping)
+	Slowlog                 string `yaml:"slowlog" json:"slowlog"`                                     // Slow request log file (empty = disabled)
+	RequestSlowlogTimeout   string `yaml:"request_slowlog_timeout" json:"request_slowlog_timeout"`     // e.g., "5s" (default: 5s when slowlog is set)
+	RequestTerminateTimeout string `yaml:"request_terminate_timeout" json:"request_terminate_timeout"` // e.g., "60s" (empty = php-fpm default)
+	Include                 string `yaml:"include" json:"include"`                                     // File with extra pool directives to preserve
+}
+
+// AutotuneConfig holds auto-tuning settings
+type AutotuneConfig struct {
+	Profiles         map[string]*AutotuneProfile `yaml:"profiles" json:"profiles"`                     // Custom profiles selectable like built-in ones
+	PHPFPMWeight     float64                     `yaml:"php_fpm_weight" json:"php_fpm_weight"`         // php-fpm share of the budget when processes are tuned (default: 1)
+	ReservedMemoryMB int                         `yaml:"reserved_memory_mb" json:"reserved_memory_mb"` // Reserved memory without a php-fpm profile (default: 128)
+}
+
+// AutotuneProfile defines a custom auto-tuning profile. It starts from the
+// profile named in Extends (or the built-in profile of the same name) and
+// overrides the fields that are set; nil fields keep the inherited value.
+type AutotuneProfile struct {
+	Extends             string   `yaml:"extends" json:"extends"`                                                // Built-in or custom profile to inherit from
+	Description         string   `yaml:"description" json:"description"`                                        // Shown in tuning output
+	ProcessManager      string   `yaml:"process_manager" json:"process_manager" enum:"static,dynamic,ondemand"` // static | dynamic | ondemand
+	AvgMemoryPerWorker  *int     `yaml:"avg_memory_per_worker" json:"avg_memory_per_worker"`                    // MB per worker (excluding OPcache)
+	OPcacheMemoryMB     *int     `yaml:"opcache_memory_mb" json:"opcache_memory_mb"`                            // MB shared OPcache
+	ReservedMemoryMB    *int     `yaml:"reserved_memory_mb" json:"reserved_memory_mb"`                          // MB reserved for system/Nginx
+	MinWorkers          *int     `yaml:"min_workers" json:"min_workers"`                                        // Minimum workers
+	MaxWorkers          *int     `yaml:"max_workers" json:"max_workers"`                                        // Maximum workers (0 = auto-calculate)
+	SpareMinRatio       *float64 `yaml:"spare_min_ratio" json:"spare_min_ratio"`                                // Min spare servers / max_children
+	SpareMaxRatio       *float64 `yaml:"spare_max_ratio" json:"spare_max_ratio"`                                // Max spare servers / max_children
+	StartServersRatio   *float64 `yaml:"start_servers_ratio" json:"start_servers_ratio"`                        // Start servers / max_children
+	MaxRequestsPerChild *int     `yaml:"max_requests_per_child" json:"max_requests_per_child"`                  // pm.max_requests
+	MaxMemoryUsage      *float64 `yaml:"max_memory_usage" json:"max_memory_usage"`                              // Fraction of container memory to use (0-1)
+}
+
+// LearningConfig enables adaptive auto-tuning: php-fpm worker RSS is sampled
+// over time and the observed percentile replaces the profile's static
+// per-worker memory estimate
+type LearningConfig struct {
+	Enabled    bool    `yaml:"enabled" json:"enabled"`         // Record php-fpm worker memory
+	Process    string  `yaml:"process" json:"process"`         // Process whose children are the workers (default: php-fpm)
+	StatePath  string  `yaml:"state_path" json:"state_path"`   // Persisted samples (default: /var/lib/phpeek-pm/autotune-learning.json)
+	Percentile float64 `yaml:"percentile" json:"percentile"`   // RSS percentile used as per-worker memory (default: 95)
+	MinSamples int     `yaml:"min_samples" json:"min_samples"` // Samples required before recommending (default: 500)
+	Window     int     `yaml:"window" json:"window"`           // Most recent samples kept (default: 20000)
+	Apply      bool    `yaml:"apply" json:"apply"`             // Use the learned value on next start or reload
+}
+
+// CgroupConfig configures the cgroup watcher, which exports OOM kills and
+// pressure stall information (PSI) and can hold back load under pressure
+type CgroupConfig struct {
+	Enabled                 *bool   `yaml:"enabled" json:"enabled"`                                     // Watch the container cgroup (default: true)
+	Path                    string  `yaml:"path" json:"path"`                                           // cgroup mount (default: /sys/fs/cgroup)
+	Interval                int     `yaml:"interval" json:"interval"`                                   // seconds (default: 5)
+	MemoryPressureThreshold float64 `yaml:"memory_pressure_threshold" json:"memory_pressure_threshold"` // Memory "some" avg10 % considered high (default: 20, -1 = ignore)
+	CPUPressureThreshold    float64 `yaml:"cpu_pressure_threshold" json:"cpu_pressure_threshold"`       // CPU "some" avg10 % considered high (default: 80, -1 = ignore)
+	PauseScaleUp            bool    `yaml:"pause_scale_up" json:"pause_scale_up"`                       // Reject scale-ups while pressure is high
+	ShedScheduled           bool    `yaml:"shed_scheduled" json:"shed_scheduled"`                       // Skip scheduled runs while pressure is high
+}
+
+// EnabledValue returns true if the cgroup watcher is enabled (default true
+// once defaults are applied)
+func (c *CgroupConfig) EnabledValue() bool {
+	return c != nil && (c.Enabled == nil || *c.Enabled)
+}
+
+// ScheduleLockConfig configures the lock that scheduled jobs take before a
+// run, so that replicas sharing a config run each trigger once
+type ScheduleLockConfig struct {
+	Backend    string                `yaml:"backend" json:"backend" enum:"file,redis,kubernetes"` // file | redis | kubernetes
+	TTL        time.Duration         `yaml:"ttl" json:"ttl"`                                      // Lock lifetime, renewed during runs (default: 30s)
+	Identity   string                `yaml:"identity" json:"identity"`                            // This replica's name in the lock (default: hostname)
+	File       *FileLockConfig       `yaml:"file" json:"file"`                                    // file backend settings
+	Redis      *RedisLockConfig      `yaml:"redis" json:"redis"`                                  // redis backend settings
+	Kubernetes *KubernetesLockConfig `yaml:"kubernetes" json:"kubernetes"`                        // kubernetes backend settings
+}
+
+// FileLockConfig configures lock files on a filesystem shared by the replicas
+type FileLockConfig struct {
+	Dir string `yaml:"dir" json:"dir"` // Directory of the lock files
+}
+
+// RedisLockConfig configures locks held as Redis keys
+type RedisLockConfig struct {
+	Address   string `yaml:"address" json:"address"`       // host:port
+	Password  string `yaml:"password" json:"password"`     // AUTH password
+	DB        int    `yaml:"db" json:"db"`                 // Database number (default: 0)
+	KeyPrefix string `yaml:"key_prefix" json:"key_prefix"` // Key prefix (default: phpeek-pm:lock:)
+}
+
+// KubernetesLockConfig configures locks held as coordination.k8s.io Leases.
+// Empty settings default to the in-cluster service account.
+type KubernetesLockConfig struct {
+	URL         string `yaml:"url" json:"url"`                   // API server URL
+	Namespace   string `yaml:"namespace" json:"namespace"`       // Namespace of the Leases
+	TokenFile   string `yaml:"token_file" json:"token_file"`     // Bearer token file
+	CAFile      string `yaml:"ca_file" json:"ca_file"`           // CA bundle of the API server
+	LeasePrefix string `yaml:"lease_prefix" json:"lease_prefix"` // Lease name prefix (default: phpeek-pm-)
+}
+
+// ScheduleLockValue returns true if the process takes the global schedule
+// lock before its runs
+func (p *Process) ScheduleLockValue(global *ScheduleLockConfig) bool {
+	if global == nil {
+		return false
+	}
+	return p.ScheduleLock == nil || *p.ScheduleLock
 }
 
 // setGlobalDefaults sets default values for global configuration
@@ -229,6 +592,21 @@ func (c *Config) setGlobalDefaults() {
 	c.setGlobalACLDefaults()
 	c.setGlobalTracingDefaults()
 	c.setGlobalHistoryDefaults()
+	c.setGlobalPHPFPMPoolDefaults()
+	c.setGlobalAutotuneLearningDefaults()
+	c.setGlobalAutotuneDefaults()
+	c.setGlobalCgroupDefaults()
+	c.setGlobalScheduleLockDefaults()
+}
+
+// setGlobalScheduleLockDefaults sets schedule lock defaults when a lock is configured
+func (c *Config) setGlobalScheduleLockDefaults() {
+	if c.Global.ScheduleLock == nil {
+		return
+	}
+	if c.Global.ScheduleLock.TTL == 0 {
+		c.Global.ScheduleLock.TTL = 30 * time.Second
+	}
 }
 
 // setGlobalBasicDefaults sets basic global defaults
@@ -322,6 +700,95 @@ func (c *Config) setGlobalTLSDefaults() {
 	}
 }
 
+// setGlobalPHPFPMPoolDefaults sets php-fpm pool rendering defaults
+func (c *Config) setGlobalPHPFPMPoolDefaults() {
+	pool := c.Global.PHPFPMPool
+	if pool == nil {
+		return
+	}
+	if pool.Path == "" {
+		pool.Path = "/usr/local/etc/php-fpm.d/zz-phpeek-pm.conf"
+	}
+	if pool.Name == "" {
+		pool.Name = "www"
+	}
+	if pool.Listen == "" {
+		pool.Listen = "9000"
+	}
+	if pool.ListenMode == "" && strings.HasPrefix(pool.Listen, "/") {
+		pool.ListenMode = "0660"
+	}
+	if pool.StatusPath == "" {
+		pool.StatusPath = "/status"
+	}
+	if pool.PingPath == "" {
+		pool.PingPath = "/ping"
+	}
+	if pool.RequestSlowlogTimeout == "" && pool.Slowlog != "" {
+		pool.RequestSlowlogTimeout = "5s"
+	}
+}
+
+// setGlobalAutotuneDefaults sets shared memory budget defaults
+func (c *Config) setGlobalAutotuneDefaults() {
+	autotune := c.Global.Autotune
+	if autotune == nil {
+		return
+	}
+	if autotune.PHPFPMWeight == 0 {
+		autotune.PHPFPMWeight = 1
+	}
+	if autotune.ReservedMemoryMB == 0 {
+		autotune.ReservedMemoryMB = 128
+	}
+}
+
+// setGlobalAutotuneLearningDefaults sets adaptive auto-tuning defaults
+func (c *Config) setGlobalAutotuneLearningDefaults() {
+	learning := c.Global.AutotuneLearning
+	if learning == nil {
+		return
+	}
+	if learning.Process == "" {
+		learning.Process = "php-fpm"
+	}
+	if learning.StatePath == "" {
+		learning.StatePath = "/var/lib/phpeek-pm/autotune-learning.json"
+	}
+	if learning.Percentile == 0 {
+		learning.Percentile = 95
+	}
+	if learning.MinSamples == 0 {
+		learning.MinSamples = 500
+	}
+	if learning.Window == 0 {
+		learning.Window = 20000
+	}
+}
+
+// setGlobalCgroupDefaults sets cgroup watcher defaults (enabled unless disabled)
+func (c *Config) setGlobalCgroupDefaults() {
+	if c.Global.Cgroup == nil {
+		c.Global.Cgroup = &CgroupConfig{}
+	}
+	cg := c.Global.Cgroup
+	if cg.Enabled == nil {
+		cg.Enabled = boolPtr(true)
+	}
+	if cg.Path == "" {
+		cg.Path = "/sys/fs/cgroup"
+	}
+	if cg.Interval == 0 {
+		cg.Interval = 5
+	}
+	if cg.MemoryPressureThreshold == 0 {
+		cg.MemoryPressureThreshold = 20
+	}
+	if cg.CPUPressureThreshold == 0 {
+		cg.CPUPressureThreshold = 80
+	}
+}
+
 // setTLSConfigDefaults sets defaults for a TLS configuration
 func setTLSConfigDefaults(tls *TLSConfig) {
 	if tls.MinVersion == "" {
@@ -381,6 +848,12 @@ func (c *Config) setGlobalHistoryDefaults() {
 	if c.Global.OneshotHistoryMaxAge == 0 {
 		c.Global.OneshotHistoryMaxAge = 24 * time.Hour
 	}
+	if c.Global.CrashContextLines == 0 {
+		c.Global.CrashContextLines = 50
+	}
+	if c.Global.CrashHistorySize == 0 {
+		c.Global.CrashHistorySize = 10
+	}
 }
 
 // setProcessDefaults sets defaults for a single process
@@ -400,10 +873,81 @@ func (c *Config) setProcessDefaults(name string, proc *Process) {
 	if proc.Schedule != "" && proc.ScheduleTimezone == "" {
 		proc.ScheduleTimezone = "UTC"
 	}
+	c.setProcessScheduleOverlapDefaults(proc)
 
 	c.setProcessHealthCheckDefaults(proc)
 	c.setProcessShutdownDefaults(proc)
 	c.setProcessLoggingDefaults(name, proc)
+	c.setProcessAutotuneDefaults(proc)
+	c.setProcessScheduleRetryDefaults(proc)
+	c.setProcessScheduleOutputDefaults(proc)
+}
+
+// setProcessScheduleOverlapDefaults sets the overlap policy of a scheduled
+// process. schedule_max_concurrent > 1 implies parallel runs.
+func (c *Config) setProcessScheduleOverlapDefaults(proc *Process) {
+	if proc.Schedule == "" {
+		return
+	}
+	if proc.ScheduleOverlap == "" {
+		proc.ScheduleOverlap = "skip"
+		if proc.ScheduleMaxConcurrent > 1 {
+			proc.ScheduleOverlap = "allow"
+		}
+	}
+	if proc.ScheduleOverlap == "queue" && proc.ScheduleQueueSize == 0 {
+		proc.ScheduleQueueSize = 1
+	}
+}
+
+// setProcessScheduleRetryDefaults sets retry defaults for a scheduled process
+func (c *Config) setProcessScheduleRetryDefaults(proc *Process) {
+	retry := proc.ScheduleRetry
+	if retry == nil {
+		return
+	}
+	if retry.MaxAttempts == 0 {
+		retry.MaxAttempts = 3
+	}
+	if retry.Backoff == 0 {
+		retry.Backoff = 10 * time.Second
+	}
+	if retry.MaxBackoff == 0 {
+		retry.MaxBackoff = 5 * time.Minute
+	}
+	if retry.Jitter == 0 {
+		retry.Jitter = 0.2
+	}
+}
+
+// setProcessScheduleOutputDefaults sets output limits for a scheduled process
+func (c *Config) setProcessScheduleOutputDefaults(proc *Process) {
+	output := proc.ScheduleOutput
+	if output == nil {
+		return
+	}
+	if output.HeadLines == 0 {
+		output.HeadLines = 100
+	}
+	if output.TailLines == 0 {
+		output.TailLines = 200
+	}
+	if output.MaxLineLength == 0 {
+		output.MaxLineLength = 4096
+	}
+}
+
+// setProcessAutotuneDefaults sets memory budget defaults for a process
+func (c *Config) setProcessAutotuneDefaults(proc *Process) {
+	if proc.Autotune == nil {
+		return
+	}
+	if proc.Autotune.Weight == 0 {
+		proc.Autotune.Weight = 1
+	}
+	if proc.Autotune.MinScale == 0 {
+		proc.Autotune.MinScale = 1
+	}
 }
 
 // setProcessHealthCheckDefaults sets health check defaults for a process
@@ -476,10 +1020,10 @@ func (c *Config) setProcessLoggingDefaults(name string, proc *Process) {
 		if proc.Stderr != nil {
 			proc.Logging.Stderr = *proc.Stderr
 		}
-		if proc.Stdout == nil && !proc.Logging.Stdout {
+		if proc.Stdout == nil && !proc.Logging.Stdout && !proc.Logging.stdoutSet {
 			proc.Logging.Stdout = true
 		}
-		if proc.Stderr == nil && !proc.Logging.Stderr {
+		if proc.Stderr == nil && !proc.Logging.Stderr && !proc.Logging.stderrSet {
 			proc.Logging.Stderr = true
 		}
 	}
@@ -536,6 +1080,14 @@ func (g *GlobalConfig) SetMetricsEnabled(v bool) {
 	g.MetricsEnabled = boolPtr(v)
 }
 
+// AutotuneProfiles returns custom auto-tuning profile definitions (nil if none)
+func (g *GlobalConfig) AutotuneProfiles() map[string]*AutotuneProfile {
+	if g == nil || g.Autotune == nil {
+		return nil
+	}
+	return g.Autotune.Profiles
+}
+
 // ResourceMetricsEnabledValue returns true if resource metrics enabled (default true)
 func (g *GlobalConfig) ResourceMetricsEnabledValue() bool {
 	if g == nil || g.ResourceMetricsEnabled == nil {
@@ -594,6 +1146,16 @@ func (p *Process) Equal(other *Process) bool {
 	if !stringSliceEqual(p.DependsOn, other.DependsOn) {
 		return false
 	}
+	for _, name := range p.DependsOn {
+		if p.Dependency(name) != other.Dependency(name) {
+			return false
+		}
+	}
+
+	// Compare triggers
+	if !triggersEqual(p.Triggers, other.Triggers) {
+		return false
+	}
 
 	// Compare environment variables
 	if !stringMapEqual(p.Env, other.Env) {
@@ -639,6 +1201,14 @@ func stringMapEqual(a, b map[string]string) bool {
 	return true
 }
 
+// triggersEqual compares two ProcessTriggers configs
+func triggersEqual(a, b *ProcessTriggers) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return stringSliceEqual(a.OnSuccess, b.OnSuccess) && stringSliceEqual(a.OnFailure, b.OnFailure)
+}
+
 // healthCheckEqual compares two HealthCheck configs
 func healthCheckEqual(a, b *HealthCheck) bool {
 	if a == nil && b == nil {
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestSetDefaults(t *testing.T) {
@@ -413,6 +416,107 @@ func TestSetDefaults(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "php-fpm pool defaults",
+			config: &Config{
+				Global: GlobalConfig{
+					PHPFPMPool: &FPMPoolConfig{Enabled: true},
+				},
+				Processes: map[string]*Process{
+					"test": {Command: []string{"sleep", "1"}},
+				},
+			},
+			validate: func(t *testing.T, c *Config) {
+				pool := c.Global.PHPFPMPool
+				if pool.Path != "/usr/local/etc/php-fpm.d/zz-phpeek-pm.conf" || pool.Name != "www" || pool.Listen != "9000" {
+					t.Errorf("unexpected pool defaults: %+v", pool)
+				}
+				if pool.StatusPath != "/status" || pool.PingPath != "/ping" {
+					t.Errorf("unexpected status/ping defaults: %+v", pool)
+				}
+				if pool.ListenMode != "" || pool.RequestSlowlogTimeout != "" {
+					t.Errorf("socket mode and slowlog timeout should stay empty for TCP without slowlog: %+v", pool)
+				}
+			},
+		},
+		{
+			name: "php-fpm pool socket and slowlog defaults",
+			config: &Config{
+				Global: GlobalConfig{
+					PHPFPMPool: &FPMPoolConfig{Enabled: true, Listen: "/run/php-fpm.sock", Slowlog: "/proc/self/fd/2"},
+				},
+				Processes: map[string]*Process{
+					"test": {Command: []string{"sleep", "1"}},
+				},
+			},
+			validate: func(t *testing.T, c *Config) {
+				if c.Global.PHPFPMPool.ListenMode != "0660" {
+					t.Errorf("ListenMode = %v, want 0660", c.Global.PHPFPMPool.ListenMode)
+				}
+				if c.Global.PHPFPMPool.RequestSlowlogTimeout != "5s" {
+					t.Errorf("RequestSlowlogTimeout = %v, want 5s", c.Global.PHPFPMPool.RequestSlowlogTimeout)
+				}
+			},
+		},
+		{
+			name: "autotune learning defaults",
+			config: &Config{
+				Global: GlobalConfig{
+					AutotuneLearning: &LearningConfig{Enabled: true},
+				},
+				Processes: map[string]*Process{
+					"test": {Command: []string{"sleep", "1"}},
+				},
+			},
+			validate: func(t *testing.T, c *Config) {
+				learning := c.Global.AutotuneLearning
+				if learning.Process != "php-fpm" || learning.StatePath != "/var/lib/phpeek-pm/autotune-learning.json" {
+					t.Errorf("unexpected learning defaults: %+v", learning)
+				}
+				if learning.Percentile != 95 || learning.MinSamples != 500 || learning.Window != 20000 {
+					t.Errorf("unexpected learning sample defaults: %+v", learning)
+				}
+				if learning.Apply {
+					t.Error("Apply should default to false")
+				}
+			},
+		},
+		{
+			name: "autotune memory budget defaults",
+			config: &Config{
+				Global: GlobalConfig{
+					Autotune: &AutotuneConfig{},
+				},
+				Processes: map[string]*Process{
+					"queue": {Command: []string{"php", "artisan", "queue:work"}, Autotune: &ProcessAutotune{Enabled: true, MemoryPerInstance: 128}},
+				},
+			},
+			validate: func(t *testing.T, c *Config) {
+				if c.Global.Autotune.PHPFPMWeight != 1 || c.Global.Autotune.ReservedMemoryMB != 128 {
+					t.Errorf("unexpected autotune defaults: %+v", c.Global.Autotune)
+				}
+				at := c.Processes["queue"].Autotune
+				if at.Weight != 1 || at.MinScale != 1 || at.MaxScale != 0 {
+					t.Errorf("unexpected process autotune defaults: %+v", at)
+				}
+			},
+		},
+		{
+			name:   "cgroup watcher defaults",
+			config: &Config{},
+			validate: func(t *testing.T, c *Config) {
+				cg := c.Global.Cgroup
+				if !cg.EnabledValue() || cg.Path != "/sys/fs/cgroup" || cg.Interval != 5 {
+					t.Errorf("unexpected cgroup defaults: %+v", cg)
+				}
+				if cg.MemoryPressureThreshold != 20 || cg.CPUPressureThreshold != 80 {
+					t.Errorf("unexpected pressure thresholds: %+v", cg)
+				}
+				if cg.PauseScaleUp || cg.ShedScheduled {
+					t.Error("load shedding should default to off")
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -666,6 +770,36 @@ func TestProcess_Equal(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "different depends_on condition",
+			p1: &Process{
+				Enabled:   true,
+				Command:   []string{"sleep", "1"},
+				DependsOn: []string{"dep1"},
+			},
+			p2: &Process{
+				Enabled:          true,
+				Command:          []string{"sleep", "1"},
+				DependsOn:        []string{"dep1"},
+				DependsOnOptions: map[string]*Dependency{"dep1": {Condition: DependencyHealthy}},
+			},
+			want: false,
+		},
+		{
+			name: "empty depends_on options",
+			p1: &Process{
+				Enabled:   true,
+				Command:   []string{"sleep", "1"},
+				DependsOn: []string{"dep1"},
+			},
+			p2: &Process{
+				Enabled:          true,
+				Command:          []string{"sleep", "1"},
+				DependsOn:        []string{"dep1"},
+				DependsOnOptions: map[string]*Dependency{"dep1": {}},
+			},
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -977,3 +1111,297 @@ func TestHookEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggingConfig_ExplicitStreams(t *testing.T) {
+	var cfg Config
+	content := `
+processes:
+  quiet:
+    command: ["sleep", "60"]
+    logging:
+      stdout: false
+  defaults:
+    command: ["sleep", "60"]
+    logging:
+      min_level: warn
+  legacy:
+    command: ["sleep", "60"]
+    stderr: false
+    logging:
+      stderr: true
+`
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	cfg.SetDefaults()
+
+	tests := map[string][2]bool{
+		"quiet":    {false, true}, // Explicit false is kept
+		"defaults": {true, true},  // Unset streams are enabled
+		"legacy":   {true, false}, // The legacy shorthand still wins
+	}
+	for name, want := range tests {
+		logging := cfg.Processes[name].Logging
+		if logging.Stdout != want[0] || logging.Stderr != want[1] {
+			t.Errorf("%s: stdout = %v, stderr = %v, want %v", name, logging.Stdout, logging.Stderr, want)
+		}
+	}
+}
+
+func TestSetDefaults_ScheduleRetry(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]*Process{
+			"defaults": {Command: []string{"true"}, Schedule: "* * * * *", ScheduleRetry: &ScheduleRetry{}},
+			"custom": {Command: []string{"true"}, Schedule: "* * * * *", ScheduleRetry: &ScheduleRetry{
+				MaxAttempts: 5, Backoff: time.Second, MaxBackoff: time.Minute, Jitter: -1,
+			}},
+			"none": {Command: []string{"true"}, Schedule: "* * * * *"},
+		},
+	}
+	cfg.SetDefaults()
+
+	retry := cfg.Processes["defaults"].ScheduleRetry
+	if retry.MaxAttempts != 3 || retry.Backoff != 10*time.Second || retry.MaxBackoff != 5*time.Minute || retry.Jitter != 0.2 {
+		t.Errorf("defaults = %+v", retry)
+	}
+	retry = cfg.Processes["custom"].ScheduleRetry
+	if retry.MaxAttempts != 5 || retry.Backoff != time.Second || retry.MaxBackoff != time.Minute || retry.Jitter != -1 {
+		t.Errorf("custom values were overridden: %+v", retry)
+	}
+	if cfg.Processes["none"].ScheduleRetry != nil {
+		t.Error("schedule_retry should stay unset")
+	}
+}
+
+func TestSetDefaults_ScheduleOverlap(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]*Process{
+			"default":  {Command: []string{"true"}, Schedule: "* * * * *"},
+			"parallel": {Command: []string{"true"}, Schedule: "* * * * *", ScheduleMaxConcurrent: 3},
+			"queue":    {Command: []string{"true"}, Schedule: "* * * * *", ScheduleOverlap: "queue"},
+			"replace":  {Command: []string{"true"}, Schedule: "* * * * *", ScheduleOverlap: "replace", ScheduleMaxConcurrent: 3},
+			"longrun":  {Command: []string{"true"}},
+		},
+	}
+	cfg.SetDefaults()
+
+	tests := map[string]string{
+		"default":  "skip",
+		"parallel": "allow",
+		"queue":    "queue",
+		"replace":  "replace",
+		"longrun":  "",
+	}
+	for name, want := range tests {
+		if got := cfg.Processes[name].ScheduleOverlap; got != want {
+			t.Errorf("%s: schedule_overlap = %q, want %q", name, got, want)
+		}
+	}
+	if got := cfg.Processes["queue"].ScheduleQueueSize; got != 1 {
+		t.Errorf("schedule_queue_size = %d, want 1", got)
+	}
+}
+
+func TestSetDefaults_ScheduleLock(t *testing.T) {
+	cfg := &Config{
+		Global: GlobalConfig{ScheduleLock: &ScheduleLockConfig{Backend: "redis"}},
+		Processes: map[string]*Process{
+			"locked":   {Command: []string{"true"}, Schedule: "* * * * *"},
+			"unlocked": {Command: []string{"true"}, Schedule: "* * * * *", ScheduleLock: boolPtr(false)},
+		},
+	}
+	cfg.SetDefaults()
+
+	if got := cfg.Global.ScheduleLock.TTL; got != 30*time.Second {
+		t.Errorf("schedule_lock.ttl = %s, want 30s", got)
+	}
+	if !cfg.Processes["locked"].ScheduleLockValue(cfg.Global.ScheduleLock) {
+		t.Error("scheduled processes should take the lock by default")
+	}
+	if cfg.Processes["unlocked"].ScheduleLockValue(cfg.Global.ScheduleLock) {
+		t.Error("schedule_lock: false should opt out of the lock")
+	}
+	if cfg.Processes["locked"].ScheduleLockValue(nil) {
+		t.Error("no process takes a lock without global.schedule_lock")
+	}
+
+	empty := &Config{Processes: map[string]*Process{"job": {Command: []string{"true"}}}}
+	empty.SetDefaults()
+	if empty.Global.ScheduleLock != nil {
+		t.Error("schedule_lock should stay unset by default")
+	}
+}
+
+func TestSetDefaults_ScheduleOutput(t *testing.T) {
+	cfg := &Config{
+		Processes: map[string]*Process{
+			"defaults": {Command: []string{"true"}, Schedule: "* * * * *", ScheduleOutput: &ScheduleOutput{OnFailureOnly: true}},
+			"custom":   {Command: []string{"true"}, Schedule: "* * * * *", ScheduleOutput: &ScheduleOutput{HeadLines: 10, TailLines: 20, MaxLineLength: 512}},
+			"unset":    {Command: []string{"true"}, Schedule: "* * * * *"},
+		},
+	}
+	cfg.SetDefaults()
+
+	if got := *cfg.Processes["defaults"].ScheduleOutput; got != (ScheduleOutput{HeadLines: 100, TailLines: 200, MaxLineLength: 4096, OnFailureOnly: true}) {
+		t.Errorf("schedule_output defaults = %+v", got)
+	}
+	if got := *cfg.Processes["custom"].ScheduleOutput; got != (ScheduleOutput{HeadLines: 10, TailLines: 20, MaxLineLength: 512}) {
+		t.Errorf("schedule_output = %+v, explicit limits should be kept", got)
+	}
+	if cfg.Processes["unset"].ScheduleOutput != nil {
+		t.Error("schedule_output should stay unset by default")
+	}
+}
+
+func TestProcessTriggers_Targets(t *testing.T) {
+	var none *ProcessTriggers
+	if targets := none.Targets(); targets != nil {
+		t.Errorf("nil Targets() = %v, want nil", targets)
+	}
+
+	triggers := &ProcessTriggers{OnSuccess: []string{"load", "notify"}, OnFailure: []string{"alert", "notify"}}
+	got := triggers.Targets()
+	want := []string{"load", "notify", "alert"}
+	if len(got) != len(want) {
+		t.Fatalf("Targets() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Targets() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestProcess_IsJob(t *testing.T) {
+	tests := []struct {
+		name string
+		proc Process
+		want bool
+	}{
+		{"longrun", Process{Type: "longrun"}, false},
+		{"default type", Process{}, false},
+		{"oneshot", Process{Type: "oneshot"}, true},
+		{"scheduled", Process{Schedule: "* * * * *"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.proc.IsJob(); got != tt.want {
+				t.Errorf("IsJob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependency_CheckCondition(t *testing.T) {
+	healthChecked := &Process{Type: "longrun", HealthCheck: &HealthCheck{Type: "tcp"}}
+	longrun := &Process{Type: "longrun"}
+	oneshot := &Process{Type: "oneshot"}
+	scheduled := &Process{Type: "oneshot", Schedule: "0 * * * *"}
+
+	tests := []struct {
+		name      string
+		condition string
+		proc      *Process
+		want      error
+	}{
+		{"default", "", longrun, nil},
+		{"started", DependencyStarted, oneshot, nil},
+		{"healthy", DependencyHealthy, healthChecked, nil},
+		{"healthy without health check", DependencyHealthy, longrun, ErrNoHealthCheck},
+		{"completed oneshot", DependencyCompletedSuccessfully, oneshot, nil},
+		{"completed longrun", DependencyCompletedSuccessfully, longrun, ErrNotStartupOneshot},
+		{"completed scheduled", DependencyCompletedSuccessfully, scheduled, ErrNotStartupOneshot},
+		{"unknown process", DependencyHealthy, nil, nil},
+		{"invalid", "ready", oneshot, ErrInvalidCondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Dependency{Condition: tt.condition}.CheckCondition(tt.proc)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("CheckCondition() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriggersEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *ProcessTriggers
+		want bool
+	}{
+		{"both nil", nil, nil, true},
+		{"a nil", nil, &ProcessTriggers{}, false},
+		{"equal", &ProcessTriggers{OnSuccess: []string{"a"}, OnFailure: []string{"b"}}, &ProcessTriggers{OnSuccess: []string{"a"}, OnFailure: []string{"b"}}, true},
+		{"different on_success", &ProcessTriggers{OnSuccess: []string{"a"}}, &ProcessTriggers{OnSuccess: []string{"b"}}, false},
+		{"different on_failure", &ProcessTriggers{OnFailure: []string{"a"}}, &ProcessTriggers{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := triggersEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("triggersEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcess_DependsOnLongForm(t *testing.T) {
+	var cfg Config
+	content := `
+processes:
+  app:
+    command: ["php-fpm"]
+    depends_on:
+      migrate:
+        condition: completed_successfully
+        timeout: 10m
+      redis:
+        condition: healthy
+      cache:
+  worker:
+    command: ["php", "artisan", "queue:work"]
+    depends_on: [app]
+`
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	app := cfg.Processes["app"]
+	if want := []string{"migrate", "redis", "cache"}; !stringSliceEqual(app.DependsOn, want) {
+		t.Errorf("DependsOn = %v, want %v (file order)", app.DependsOn, want)
+	}
+	if len(app.Command) != 1 || app.Command[0] != "php-fpm" {
+		t.Errorf("Command = %v, other keys should still decode", app.Command)
+	}
+	if got := app.Dependency("migrate"); got != (Dependency{Condition: DependencyCompletedSuccessfully, Timeout: 10 * time.Minute}) {
+		t.Errorf("Dependency(migrate) = %+v", got)
+	}
+	if got := app.Dependency("redis"); got.Condition != DependencyHealthy || got.Timeout != 0 {
+		t.Errorf("Dependency(redis) = %+v", got)
+	}
+	if got := app.Dependency("cache"); got != (Dependency{}) {
+		t.Errorf("Dependency(cache) = %+v, want zero", got)
+	}
+
+	worker := cfg.Processes["worker"]
+	if len(worker.DependsOn) != 1 || worker.DependsOn[0] != "app" || worker.DependsOnOptions != nil {
+		t.Errorf("short form = %v / %v", worker.DependsOn, worker.DependsOnOptions)
+	}
+
+	// Long form survives a round trip, the short form stays a list
+	data, err := yaml.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var reloaded Config
+	if err := yaml.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("Unmarshal() of marshaled config error = %v\n%s", err, data)
+	}
+	if !reloaded.Processes["app"].Equal(app) || !reloaded.Processes["worker"].Equal(worker) {
+		t.Errorf("round trip changed depends_on:\n%s", data)
+	}
+
+	if err := yaml.Unmarshal([]byte("depends_on:\n  redis:\n    timeout: [1]\n"), &Process{}); err == nil {
+		t.Error("expected an error for an invalid long form entry")
+	}
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// extensionPrefix marks top-level keys that are ignored on purpose, e.g. an
+// "x-defaults: &defaults" block holding YAML anchors
+const extensionPrefix = "x-"
+
+var (
+	configType           = reflect.TypeOf(Config{})
+	processType          = reflect.TypeOf(Process{})
+	dependsOnOptionsType = reflect.TypeOf(map[string]*Dependency{})
+)
+
+// UnknownKey is a key in a config file that matches no setting. Decoding
+// ignores such keys, so they are usually typos like "helth_check".
+type UnknownKey struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Key        string `json:"key"`
+	Path       string `json:"path"`                 // Dotted key path, e.g. processes.app.helth_check
+	Suggestion string `json:"suggestion,omitempty"` // Closest known key ("" if none is close)
+}
+
+// Position returns the location of the key as file:line:column
+func (k UnknownKey) Position() string {
+	return fmt.Sprintf("%s:%d:%d", k.File, k.Line, k.Column)
+}
+
+// FindUnknownKeys returns the keys of a config file that match no setting,
+// in file order. ${VAR} references are not expanded, so positions match the
+// file on disk.
+func FindUnknownKeys(path string) ([]UnknownKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return FindUnknownKeysIn(path, content)
+}
+
+// FindUnknownKeysIn returns the unknown keys of config file content, reporting
+// them under the given file name
+func FindUnknownKeysIn(file string, content []byte) ([]UnknownKey, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", file, err)
+	}
+	c := &keyChecker{file: file}
+	c.check(&doc, configType, "")
+	return c.keys, nil
+}
+
+// keyChecker walks a YAML node tree alongside the Go type it decodes into
+type keyChecker struct {
+	file string
+	keys []UnknownKey
+}
+
+func (c *keyChecker) check(node *yaml.Node, t reflect.Type, path string) {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == profileType {
+		t = configType
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			c.check(child, t, path)
+		}
+	case yaml.SequenceNode:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, item := range node.Content {
+				c.check(item, t.Elem(), path)
+			}
+		}
+	case yaml.MappingNode:
+		switch t.Kind() {
+		case reflect.Struct:
+			c.checkStruct(node, t, path)
+		case reflect.Map:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				c.check(node.Content[i+1], t.Elem(), joinKeyPath(path, node.Content[i].Value))
+			}
+		}
+	}
+}
+
+func (c *keyChecker) checkStruct(node *yaml.Node, t reflect.Type, path string) {
+	fields := make(map[string]reflect.StructField)
+	names := make([]string, 0, t.NumField())
+	for _, field := range yamlFields(t) {
+		fields[yamlName(field)] = field
+		names = append(names, yamlName(field))
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		// Merge keys (<<: *defaults) merge a mapping or a list of mappings
+		if key.Tag == "!!merge" || key.Value == "<<" {
+			if value.Kind == yaml.SequenceNode {
+				c.check(value, reflect.SliceOf(t), path)
+			} else {
+				c.check(value, t, path)
+			}
+			continue
+		}
+
+		keyPath := joinKeyPath(path, key.Value)
+		if field, ok := fields[key.Value]; ok {
+			fieldType := field.Type
+			if t == processType && key.Value == "depends_on" && value.Kind == yaml.MappingNode {
+				fieldType = dependsOnOptionsType // Long form, see Process.UnmarshalYAML
+			}
+			c.check(value, fieldType, keyPath)
+			continue
+		}
+		if t == configType && path == "" && (key.Value == includeKey || strings.HasPrefix(key.Value, extensionPrefix)) {
+			continue
+		}
+
+		c.keys = append(c.keys, UnknownKey{
+			File:       c.file,
+			Line:       key.Line,
+			Column:     key.Column,
+			Key:        key.Value,
+			Path:       keyPath,
+			Suggestion: closestKey(key.Value, names),
+		})
+	}
+}
+
+// closestKey returns the name closest to key by edit distance, or "" if none
+// is close enough to be a likely typo
+func closestKey(key string, names []string) string {
+	best, bestDist := "", -1
+	for _, name := range names {
+		d := editDistance(key, name)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	if bestDist < 0 || bestDist > 2 && bestDist*3 > len(key) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
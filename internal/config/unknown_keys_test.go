@@ -0,0 +1,155 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const unknownKeysTestConfig = `include: base.yaml
+x-defaults: &defaults
+  restart: always
+  scael: 2
+global:
+  log_levl: info
+  readiness:
+    enabled: true
+    mdoe: all_healthy
+hooks:
+  pre-start:
+    - name: migrate
+      command: ["php", "artisan", "migrate"]
+      retries: 3
+processes:
+  app:
+    <<: *defaults
+    command: ["php-fpm"]
+    env:
+      ANY_NAME: allowed
+    helth_check:
+      type: tcp
+  worker:
+    command: ["php", "artisan", "queue:work"]
+    health_check:
+      type: exec
+      perod: 10
+    logging:
+      redaction:
+        patterns:
+          - name: token
+            patern: "tok_[a-z]+"
+    depends_on:
+      app:
+        conditon: healthy
+profiles:
+  production:
+    global:
+      log_level: warn
+    processes:
+      app:
+        sclae: 3
+`
+
+func TestFindUnknownKeys(t *testing.T) {
+	keys, err := FindUnknownKeysIn("phpeek-pm.yaml", []byte(unknownKeysTestConfig))
+	if err != nil {
+		t.Fatalf("FindUnknownKeysIn() error = %v", err)
+	}
+
+	want := []UnknownKey{
+		{Line: 6, Column: 3, Key: "log_levl", Path: "global.log_levl", Suggestion: "log_level"},
+		{Line: 9, Column: 5, Key: "mdoe", Path: "global.readiness.mdoe", Suggestion: "mode"},
+		{Line: 14, Column: 7, Key: "retries", Path: "hooks.pre-start.retries"},
+		{Line: 4, Column: 3, Key: "scael", Path: "processes.app.scael", Suggestion: "scale"},
+		{Line: 21, Column: 5, Key: "helth_check", Path: "processes.app.helth_check", Suggestion: "health_check"},
+		{Line: 27, Column: 7, Key: "perod", Path: "processes.worker.health_check.perod", Suggestion: "period"},
+		{Line: 32, Column: 13, Key: "patern", Path: "processes.worker.logging.redaction.patterns.patern", Suggestion: "pattern"},
+		{Line: 35, Column: 9, Key: "conditon", Path: "processes.worker.depends_on.app.conditon", Suggestion: "condition"},
+		{Line: 42, Column: 9, Key: "sclae", Path: "profiles.production.processes.app.sclae", Suggestion: "scale"},
+	}
+	for i := range want {
+		want[i].File = "phpeek-pm.yaml"
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("FindUnknownKeysIn() =\n%+v\nwant\n%+v", keys, want)
+	}
+
+	if got := want[4].Position(); got != "phpeek-pm.yaml:21:5" {
+		t.Errorf("Position() = %q", got)
+	}
+}
+
+func TestFindUnknownKeys_Valid(t *testing.T) {
+	keys, err := FindUnknownKeysIn("phpeek-pm.yaml", []byte(profileTestConfig))
+	if err != nil {
+		t.Fatalf("FindUnknownKeysIn() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("FindUnknownKeysIn() = %+v, want none", keys)
+	}
+
+	if _, err := FindUnknownKeysIn("bad.yaml", []byte("global: [")); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestClosestKey(t *testing.T) {
+	names := []string{"health_check", "heartbeat", "restart", "scale"}
+	tests := map[string]string{
+		"helth_check":  "health_check",
+		"healthcheck":  "health_check",
+		"restrat":      "restart",
+		"scael":        "scale",
+		"completely":   "",
+		"my_own_field": "",
+	}
+	for key, want := range tests {
+		if got := closestKey(key, names); got != want {
+			t.Errorf("closestKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+	if got := closestKey("scale", nil); got != "" {
+		t.Errorf("closestKey with no names = %q", got)
+	}
+}
+
+func TestValidateComprehensive_UnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"phpeek-pm.yaml": `
+processes:
+  app:
+    command: ["php-fpm"]
+    helth_check:
+      type: tcp
+      address: 127.0.0.1:9000
+`,
+		"conf.d/extra.yaml": "global:\n  shutdown_timout: 30\n",
+	})
+
+	cfg, err := LoadWithOptions(filepath.Join(dir, "phpeek-pm.yaml"), LoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+	result, _ := cfg.ValidateComprehensive()
+
+	found := make(map[string]ValidationIssue)
+	for _, issue := range result.Warnings {
+		found[issue.Field] = issue
+	}
+
+	app, ok := found["processes.app.helth_check"]
+	if !ok {
+		t.Fatalf("no warning for helth_check in %+v", result.Warnings)
+	}
+	wantPos := filepath.Join(dir, "phpeek-pm.yaml") + ":5:5"
+	if !strings.Contains(app.Message, wantPos) || app.Suggestion != `Did you mean "health_check"?` {
+		t.Errorf("warning = %+v, want position %s and suggestion", app, wantPos)
+	}
+
+	global, ok := found["global.shutdown_timout"]
+	if !ok || !strings.Contains(global.Message, filepath.Join(dir, "conf.d", "extra.yaml")+":2:3") {
+		t.Errorf("warning for conf.d file = %+v", global)
+	}
+}
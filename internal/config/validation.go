@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"reflect"
+	"regexp"
 	"runtime"
 	"strings"
 	"time"
@@ -154,6 +158,8 @@ func (c *Config) ValidateComprehensive() (*ValidationResult, error) {
 	c.lintConfiguration(result)
 	c.validateSystem(result)
 	c.validateSecurity(result)
+	c.validateEnums(result)
+	c.validateUnknownKeys(result)
 
 	// Return error if there are blocking issues
 	if result.HasErrors() {
@@ -171,6 +177,9 @@ const (
 	MaxResourceMetricsSamples  = 100000  // Memory constraint
 	MaxScheduleHistorySize     = 10000   // Per-job history limit
 	MaxOneshotHistoryEntries   = 100000  // Oneshot history limit
+	MaxCrashContextLines       = 1000    // Log lines captured per crash
+	MaxCrashHistorySize        = 1000    // Per-process crash history limit
+	MaxScheduleOutputLines     = 10000   // Head plus tail lines kept per execution
 	MaxProcessScaleLimit        = 1000                   // Per-process instance limit
 	MaxAPIRequestBodySize       = 100 * 1024 * 1024      // 100MB max
 	MinZombieReapInterval       = 100 * time.Millisecond // 100ms minimum (CPU efficiency)
@@ -185,6 +194,10 @@ func (c *Config) validateGlobalSettings(result *ValidationResult) {
 	c.validateGlobalAPISettings(result)
 	c.validateGlobalMetricsSettings(result)
 	c.validateGlobalReadinessSettings(result)
+	c.validateGlobalPHPFPMPoolSettings(result)
+	c.validateGlobalAutotuneLearningSettings(result)
+	c.validateGlobalCgroupSettings(result)
+	c.validateGlobalScheduleLockSettings(result)
 }
 
 // validateGlobalBasicSettings validates shutdown timeout, logging, and restart settings
@@ -201,7 +214,7 @@ func (c *Config) validateGlobalBasicSettings(result *ValidationResult) {
 	}
 
 	// Log level
-	validLogLevels := []string{"debug", "info", "warn", "error"}
+	validLogLevels := fieldEnum(GlobalConfig{}, "LogLevel")
 	if !contains(validLogLevels, c.Global.LogLevel) {
 		result.AddError("global.log_level", fmt.Sprintf("Invalid log level: %s", c.Global.LogLevel), fmt.Sprintf("Must be one of: %s", strings.Join(validLogLevels, ", ")))
 	} else if c.Global.LogLevel == "debug" {
@@ -209,7 +222,7 @@ func (c *Config) validateGlobalBasicSettings(result *ValidationResult) {
 	}
 
 	// Log format
-	validLogFormats := []string{"json", "text"}
+	validLogFormats := fieldEnum(GlobalConfig{}, "LogFormat")
 	if !contains(validLogFormats, c.Global.LogFormat) {
 		result.AddError("global.log_format", fmt.Sprintf("Invalid log format: %s", c.Global.LogFormat), fmt.Sprintf("Must be one of: %s", strings.Join(validLogFormats, ", ")))
 	} else if c.Global.LogFormat == "text" {
@@ -264,11 +277,24 @@ func (c *Config) validateGlobalLimits(result *ValidationResult) {
 		result.AddError("global.oneshot_history_max_entries", fmt.Sprintf("Exceeds maximum (%d > %d)", c.Global.OneshotHistoryMaxEntries, MaxOneshotHistoryEntries), fmt.Sprintf("Set to %d or less", MaxOneshotHistoryEntries))
 	}
 
+	// Crash context limits
+	if c.Global.CrashContextLines > MaxCrashContextLines {
+		result.AddError("global.crash_context_lines", fmt.Sprintf("Exceeds maximum (%d > %d)", c.Global.CrashContextLines, MaxCrashContextLines), fmt.Sprintf("Set to %d or less", MaxCrashContextLines))
+	}
+	if c.Global.CrashHistorySize > MaxCrashHistorySize {
+		result.AddError("global.crash_history_size", fmt.Sprintf("Exceeds maximum (%d > %d)", c.Global.CrashHistorySize, MaxCrashHistorySize), fmt.Sprintf("Set to %d or less", MaxCrashHistorySize))
+	}
+
 	// Max process scale
 	if c.Global.MaxProcessScale > MaxProcessScaleLimit {
 		result.AddError("global.max_process_scale", fmt.Sprintf("Exceeds maximum (%d > %d)", c.Global.MaxProcessScale, MaxProcessScaleLimit), fmt.Sprintf("Set to %d or less", MaxProcessScaleLimit))
 	}
 
+	// Startup/shutdown parallelism
+	if c.Global.ProcessParallelism < 0 {
+		result.AddError("global.process_parallelism", fmt.Sprintf("Must not be negative (got %d)", c.Global.ProcessParallelism), "Use 0 for no limit or 1 to start processes one at a time")
+	}
+
 	// API max request body
 	if c.Global.APIMaxRequestBody > MaxAPIRequestBodySize {
 		result.AddError("global.api_max_request_body", fmt.Sprintf("Exceeds maximum (%d > %d bytes)", c.Global.APIMaxRequestBody, MaxAPIRequestBodySize), fmt.Sprintf("Set to %d bytes (100MB) or less", MaxAPIRequestBodySize))
@@ -323,7 +349,7 @@ func (c *Config) validateGlobalReadinessSettings(result *ValidationResult) {
 	}
 
 	// Validate mode
-	validReadinessModes := []string{"all_healthy", "all_running"}
+	validReadinessModes := fieldEnum(ReadinessConfig{}, "Mode")
 	if !contains(validReadinessModes, c.Global.Readiness.Mode) {
 		result.AddError("global.readiness.mode", fmt.Sprintf("Invalid readiness mode: %s", c.Global.Readiness.Mode), fmt.Sprintf("Must be one of: %s", strings.Join(validReadinessModes, ", ")))
 	}
@@ -348,6 +374,131 @@ func (c *Config) validateGlobalReadinessSettings(result *ValidationResult) {
 	}
 }
 
+var (
+	// fpmDurationPattern matches php-fpm time values (e.g., 30, 30s, 5m, 1h, 1d)
+	fpmDurationPattern = regexp.MustCompile(`^\d+[smhd]?$`)
+	// fpmListenModePattern matches octal socket modes (e.g., 0660)
+	fpmListenModePattern = regexp.MustCompile(`^0?[0-7]{3}$`)
+)
+
+// validateGlobalPHPFPMPoolSettings validates php-fpm pool config rendering
+func (c *Config) validateGlobalPHPFPMPoolSettings(result *ValidationResult) {
+	pool := c.Global.PHPFPMPool
+	if pool == nil || !pool.Enabled {
+		return
+	}
+
+	if pool.Path == "" {
+		result.AddError("global.php_fpm_pool.path", "Pool config path is required when enabled", "Set path like /usr/local/etc/php-fpm.d/zz-phpeek-pm.conf")
+	}
+	if strings.ContainsAny(pool.Name, "[]\n") {
+		result.AddError("global.php_fpm_pool.name", fmt.Sprintf("Invalid pool name: %s", pool.Name), "Use a plain name like 'www'")
+	}
+
+	if pool.ListenMode != "" && !fpmListenModePattern.MatchString(pool.ListenMode) {
+		result.AddError("global.php_fpm_pool.listen_mode", fmt.Sprintf("Invalid listen mode: %s", pool.ListenMode), "Use an octal mode like 0660")
+	}
+
+	for field, path := range map[string]string{"status_path": pool.StatusPath, "ping_path": pool.PingPath} {
+		if path != "" && !strings.HasPrefix(path, "/") {
+			result.AddError("global.php_fpm_pool."+field, fmt.Sprintf("Path must start with '/': %s", path), "Use a path like /status")
+		}
+	}
+
+	for field, value := range map[string]string{"request_slowlog_timeout": pool.RequestSlowlogTimeout, "request_terminate_timeout": pool.RequestTerminateTimeout} {
+		if value != "" && !fpmDurationPattern.MatchString(value) {
+			result.AddError("global.php_fpm_pool."+field, fmt.Sprintf("Invalid php-fpm duration: %s", value), "Use seconds or a suffixed value like 30s, 5m, 1h")
+		}
+	}
+
+	if pool.Include != "" {
+		if _, err := os.Stat(pool.Include); err != nil {
+			result.AddWarning("global.php_fpm_pool.include", fmt.Sprintf("Include file not accessible: %s", pool.Include), "Ensure the file exists in the container before phpeek-pm starts")
+		}
+	}
+}
+
+// validateGlobalAutotuneLearningSettings validates adaptive auto-tuning settings
+func (c *Config) validateGlobalAutotuneLearningSettings(result *ValidationResult) {
+	learning := c.Global.AutotuneLearning
+	if learning == nil || !learning.Enabled {
+		return
+	}
+
+	if learning.StatePath == "" {
+		result.AddError("global.autotune_learning.state_path", "State path is required when enabled", "Set state_path to a file on a persistent volume")
+	}
+	if learning.Percentile <= 0 || learning.Percentile > 100 {
+		result.AddError("global.autotune_learning.percentile", fmt.Sprintf("Must be between 0 and 100 (got %.1f)", learning.Percentile), "Use 95 to cover almost all workers")
+	} else if learning.Percentile < 50 {
+		result.AddWarning("global.autotune_learning.percentile", fmt.Sprintf("Percentile %.0f underestimates worker memory", learning.Percentile), "Use 90 or higher to avoid OOM kills")
+	}
+	if learning.MinSamples < 1 {
+		result.AddError("global.autotune_learning.min_samples", "Must be at least 1", "Use 500 or more for a stable recommendation")
+	}
+	if learning.Window < learning.MinSamples {
+		result.AddError("global.autotune_learning.window", fmt.Sprintf("Window (%d) is smaller than min_samples (%d)", learning.Window, learning.MinSamples), "Increase window or lower min_samples")
+	}
+
+	if !c.Global.ResourceMetricsEnabledValue() {
+		result.AddWarning("global.autotune_learning", "Worker memory is sampled by resource metrics, which are disabled", "Set resource_metrics_enabled: true")
+	}
+	if _, ok := c.Processes[learning.Process]; !ok && len(c.Processes) > 0 {
+		result.AddWarning("global.autotune_learning.process", fmt.Sprintf("Process '%s' not found", learning.Process), "Set process to the name of your php-fpm process")
+	}
+}
+
+// validateGlobalCgroupSettings validates cgroup watcher settings
+func (c *Config) validateGlobalCgroupSettings(result *ValidationResult) {
+	cg := c.Global.Cgroup
+	if !cg.EnabledValue() {
+		return
+	}
+
+	if cg.Interval < 1 {
+		result.AddError("global.cgroup.interval", fmt.Sprintf("Invalid interval: %d", cg.Interval), "Must be at least 1 second (recommended: 5)")
+	}
+	if cg.MemoryPressureThreshold > 100 {
+		result.AddError("global.cgroup.memory_pressure_threshold", fmt.Sprintf("Must be at most 100 (got %.1f)", cg.MemoryPressureThreshold), "Use a percentage such as 20, or -1 to ignore memory pressure")
+	}
+	if cg.CPUPressureThreshold > 100 {
+		result.AddError("global.cgroup.cpu_pressure_threshold", fmt.Sprintf("Must be at most 100 (got %.1f)", cg.CPUPressureThreshold), "Use a percentage such as 80, or -1 to ignore CPU pressure")
+	}
+	if (cg.PauseScaleUp || cg.ShedScheduled) && cg.MemoryPressureThreshold < 0 && cg.CPUPressureThreshold < 0 {
+		result.AddWarning("global.cgroup", "pause_scale_up/shed_scheduled have no effect when both pressure thresholds are disabled", "Set memory_pressure_threshold or cpu_pressure_threshold")
+	}
+}
+
+// validateGlobalScheduleLockSettings validates the leader lock of scheduled jobs
+func (c *Config) validateGlobalScheduleLockSettings(result *ValidationResult) {
+	lock := c.Global.ScheduleLock
+	if lock == nil {
+		return
+	}
+
+	switch lock.Backend {
+	case "file":
+		if lock.File == nil || lock.File.Dir == "" {
+			result.AddError("global.schedule_lock.file.dir", "Lock directory is required for the file backend", "Use a directory on a filesystem shared by all replicas")
+		}
+	case "redis":
+		if lock.Redis == nil || lock.Redis.Address == "" {
+			result.AddError("global.schedule_lock.redis.address", "Redis address is required for the redis backend", "Use host:port (e.g., redis:6379)")
+		} else if _, _, err := net.SplitHostPort(lock.Redis.Address); err != nil {
+			result.AddError("global.schedule_lock.redis.address", fmt.Sprintf("Invalid address: %s", lock.Redis.Address), "Use host:port (e.g., redis:6379)")
+		}
+	}
+
+	if lock.TTL < time.Second {
+		result.AddError("global.schedule_lock.ttl", fmt.Sprintf("Invalid ttl: %s", lock.TTL), "Must be at least 1s (recommended: 30s)")
+	} else if lock.TTL < 5*time.Second {
+		result.AddWarning("global.schedule_lock.ttl", fmt.Sprintf("Short ttl: %s", lock.TTL), "The lock is renewed every ttl/3, a short ttl may be lost during network hiccups")
+	}
+	if lock.Identity == "" && lock.Backend != "file" {
+		result.AddSuggestion("global.schedule_lock.identity", "Identity defaults to the hostname", "Make sure every replica has a unique hostname, or set identity")
+	}
+}
+
 // validateProcesses validates all process configurations
 func (c *Config) validateProcesses(result *ValidationResult) {
 	if len(c.Processes) == 0 {
@@ -377,6 +528,17 @@ func (c *Config) validateSingleProcess(name string, proc *Process, result *Valid
 	// Oneshot-specific validation
 	c.validateOneshotConstraints(name, proc, result)
 
+	// Auto-tuning validation
+	c.validateProcessAutotune(name, proc, result)
+
+	// Schedule retry and circuit breaker validation
+	c.validateProcessScheduleOverlap(name, proc, result)
+	c.validateProcessScheduleCalendar(name, proc, result)
+	c.validateProcessScheduleFailures(name, proc, result)
+	c.validateProcessScheduleLock(name, proc, result)
+	c.validateProcessScheduleOutput(name, proc, result)
+	c.validateProcessTriggersComprehensive(name, proc, result)
+
 	// Health check validation
 	if proc.HealthCheck != nil {
 		c.validateHealthCheck(name, proc.HealthCheck, result)
@@ -391,17 +553,17 @@ func (c *Config) validateSingleProcess(name string, proc *Process, result *Valid
 
 // validateProcessCoreFields validates type, initial_state, and restart policy
 func (c *Config) validateProcessCoreFields(name string, proc *Process, result *ValidationResult) {
-	validTypes := []string{"oneshot", "longrun"}
+	validTypes := fieldEnum(Process{}, "Type")
 	if !contains(validTypes, proc.Type) {
 		result.AddProcessError(name, "type", fmt.Sprintf("Invalid type: %s", proc.Type), fmt.Sprintf("Must be one of: %s", strings.Join(validTypes, ", ")))
 	}
 
-	validStates := []string{"running", "stopped"}
+	validStates := fieldEnum(Process{}, "InitialState")
 	if !contains(validStates, proc.InitialState) {
 		result.AddProcessError(name, "initial_state", fmt.Sprintf("Invalid initial state: %s", proc.InitialState), fmt.Sprintf("Must be one of: %s", strings.Join(validStates, ", ")))
 	}
 
-	validRestartPolicies := []string{"always", "on-failure", "never"}
+	validRestartPolicies := fieldEnum(Process{}, "Restart")
 	if !contains(validRestartPolicies, proc.Restart) {
 		result.AddProcessError(name, "restart", fmt.Sprintf("Invalid restart policy: %s", proc.Restart), fmt.Sprintf("Must be one of: %s", strings.Join(validRestartPolicies, ", ")))
 	}
@@ -437,6 +599,208 @@ func (c *Config) validateOneshotConstraints(name string, proc *Process, result *
 	}
 }
 
+// validateProcessAutotune validates memory budget settings
+func (c *Config) validateProcessAutotune(name string, proc *Process, result *ValidationResult) {
+	at := proc.Autotune
+	if at == nil || !at.Enabled {
+		return
+	}
+
+	if at.MemoryPerInstance <= 0 {
+		result.AddProcessError(name, "autotune.memory_per_instance", "Must be greater than 0 when enabled", "Set to the typical RSS of one instance in MB (e.g., 128)")
+	}
+	if at.Weight < 0 {
+		result.AddProcessError(name, "autotune.weight", fmt.Sprintf("Invalid weight: %.2f", at.Weight), "Must be positive (default: 1)")
+	}
+	if at.MinScale < 1 {
+		result.AddProcessError(name, "autotune.min_scale", fmt.Sprintf("Invalid min_scale: %d", at.MinScale), "Must be at least 1")
+	}
+	if at.MaxScale > 0 && at.MaxScale < at.MinScale {
+		result.AddProcessError(name, "autotune.max_scale", fmt.Sprintf("max_scale (%d) is lower than min_scale (%d)", at.MaxScale, at.MinScale), "Set max_scale >= min_scale or 0 for no limit")
+	}
+	if at.WorkersFlag != "" && !strings.HasPrefix(at.WorkersFlag, "-") {
+		result.AddProcessError(name, "autotune.workers_flag", fmt.Sprintf("Invalid flag: %s", at.WorkersFlag), "Use the full flag name (e.g., --workers)")
+	}
+	if proc.Schedule != "" || proc.Type == "oneshot" {
+		result.AddProcessWarning(name, "autotune", "Auto-tuning only applies to long-running processes", "Remove autotune or use type: longrun")
+	}
+}
+
+// validateProcessScheduleOverlap checks that schedule_max_concurrent and
+// schedule_queue_size match the overlap policy they apply to
+func (c *Config) validateProcessScheduleOverlap(name string, proc *Process, result *ValidationResult) {
+	if proc.Schedule == "" {
+		return
+	}
+
+	if proc.ScheduleQueueSize < 0 {
+		result.AddProcessError(name, "schedule_queue_size", fmt.Sprintf("Invalid value: %d", proc.ScheduleQueueSize), "Must be 1 or more")
+	}
+	if proc.ScheduleMaxConcurrent > 1 && proc.ScheduleOverlap != "allow" {
+		result.AddProcessWarning(name, "schedule_max_concurrent", fmt.Sprintf("schedule_max_concurrent is ignored with schedule_overlap: %s", proc.ScheduleOverlap), "Set schedule_overlap: allow to run executions in parallel")
+	}
+	if proc.ScheduleQueueSize > 1 && proc.ScheduleOverlap != "queue" {
+		result.AddProcessWarning(name, "schedule_queue_size", fmt.Sprintf("schedule_queue_size is ignored with schedule_overlap: %s", proc.ScheduleOverlap), "Set schedule_overlap: queue or remove schedule_queue_size")
+	}
+	if proc.ScheduleOverlap == "allow" && proc.ScheduleMaxConcurrent == 0 {
+		result.AddProcessSuggestion(name, "schedule_max_concurrent", "Parallel runs are unlimited", "Set schedule_max_concurrent to bound runs that pile up when the job is slow")
+	}
+}
+
+// validateProcessScheduleCalendar validates schedule_jitter and the calendar
+// constraints of a scheduled process
+func (c *Config) validateProcessScheduleCalendar(name string, proc *Process, result *ValidationResult) {
+	hasCalendar := len(proc.ScheduleBlackout) > 0 || proc.ScheduleWeekdaysOnly || len(proc.ScheduleSkipDates) > 0
+	if proc.Schedule == "" {
+		if hasCalendar || proc.ScheduleJitter != 0 {
+			result.AddProcessWarning(name, "schedule_jitter", "schedule_jitter, schedule_blackout, schedule_weekdays_only and schedule_skip_dates only apply to scheduled processes", "Set schedule or remove them")
+		}
+		return
+	}
+
+	for _, window := range proc.ScheduleBlackout {
+		if err := parseScheduleWindow(window); err != nil {
+			result.AddProcessError(name, "schedule_blackout", fmt.Sprintf("Invalid window %v", err), "Use HH:MM-HH:MM, e.g. 22:00-06:00 (wraps past midnight)")
+		}
+	}
+	for _, date := range proc.ScheduleSkipDates {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			result.AddProcessError(name, "schedule_skip_dates", fmt.Sprintf("Invalid date %q", date), "Use YYYY-MM-DD, e.g. 2025-12-25")
+		}
+	}
+
+	if proc.ScheduleJitter < 0 {
+		result.AddProcessError(name, "schedule_jitter", fmt.Sprintf("Negative jitter: %s", proc.ScheduleJitter), "Use a duration like 30s, or remove it")
+		return
+	}
+	if proc.ScheduleJitter == 0 {
+		return
+	}
+	sched, err := scheduleParser.Parse(proc.Schedule)
+	if err != nil {
+		return // Reported by Validate
+	}
+	first := sched.Next(time.Now())
+	if interval := sched.Next(first).Sub(first); interval > 0 && proc.ScheduleJitter >= interval {
+		result.AddProcessWarning(name, "schedule_jitter", fmt.Sprintf("Jitter (%s) is not shorter than the schedule interval (%s)", proc.ScheduleJitter, interval), "Runs may start after the next trigger and be skipped by schedule_overlap, lower schedule_jitter")
+	}
+}
+
+// validateProcessScheduleLock validates the per-process schedule_lock opt-in
+func (c *Config) validateProcessScheduleLock(name string, proc *Process, result *ValidationResult) {
+	if proc.ScheduleLock == nil || !*proc.ScheduleLock {
+		return
+	}
+	if proc.Schedule == "" {
+		result.AddProcessWarning(name, "schedule_lock", "schedule_lock only applies to scheduled processes", "Set schedule or remove schedule_lock")
+	} else if c.Global.ScheduleLock == nil {
+		result.AddProcessWarning(name, "schedule_lock", "schedule_lock has no effect without global.schedule_lock", "Configure global.schedule_lock with a lock backend")
+	}
+}
+
+// scheduleOutputMemoryWarning is the worst-case memory of the output kept in
+// a job's history above which a warning is reported
+const scheduleOutputMemoryWarning = 256 * 1024 * 1024
+
+// validateProcessScheduleOutput validates the per-execution output limits
+func (c *Config) validateProcessScheduleOutput(name string, proc *Process, result *ValidationResult) {
+	output := proc.ScheduleOutput
+	if output == nil {
+		return
+	}
+	if proc.Schedule == "" {
+		result.AddProcessWarning(name, "schedule_output", "schedule_output only applies to scheduled processes", "Set schedule or remove schedule_output")
+		return
+	}
+
+	if output.HeadLines < 0 || output.TailLines < 0 {
+		result.AddProcessError(name, "schedule_output", "Line limits cannot be negative", "Set head_lines and tail_lines to 1 or more")
+		return
+	}
+	if output.MaxLineLength < 0 {
+		result.AddProcessError(name, "schedule_output.max_line_length", fmt.Sprintf("Invalid value: %d", output.MaxLineLength), "Must be 1 or more bytes")
+		return
+	}
+	if lines := output.HeadLines + output.TailLines; lines > MaxScheduleOutputLines {
+		result.AddProcessError(name, "schedule_output", fmt.Sprintf("Keeps too many lines per execution (%d > %d)", lines, MaxScheduleOutputLines), fmt.Sprintf("Lower head_lines + tail_lines to %d or less", MaxScheduleOutputLines))
+		return
+	}
+
+	worstCase := int64(output.HeadLines+output.TailLines) * int64(output.MaxLineLength) * int64(c.Global.ScheduleHistorySize)
+	if worstCase > scheduleOutputMemoryWarning && !output.OnFailureOnly {
+		result.AddProcessWarning(name, "schedule_output", fmt.Sprintf("Output of %d executions may use up to %d MB", c.Global.ScheduleHistorySize, worstCase/(1024*1024)), "Lower the limits or global.schedule_history_size, or set on_failure_only: true")
+	}
+}
+
+// validateProcessTriggersComprehensive validates the processes a job starts
+// when it completes
+func (c *Config) validateProcessTriggersComprehensive(name string, proc *Process, result *ValidationResult) {
+	if proc.Triggers == nil {
+		return
+	}
+	targets := proc.Triggers.Targets()
+	if len(targets) == 0 {
+		result.AddProcessWarning(name, "triggers", "No processes listed", "Add processes to on_success or on_failure, or remove triggers")
+		return
+	}
+	if !proc.IsJob() {
+		result.AddProcessError(name, "triggers", "Only oneshot and scheduled processes can trigger others", "Use type: oneshot or set schedule")
+		return
+	}
+
+	for _, target := range targets {
+		targetProc, exists := c.Processes[target]
+		switch {
+		case target == name:
+			result.AddProcessError(name, "triggers", "Process triggers itself", "Remove the process from its own on_success and on_failure")
+		case !exists:
+			result.AddProcessError(name, "triggers", fmt.Sprintf("Triggered process '%s' not defined", target), fmt.Sprintf("Add process '%s' or remove it from triggers", target))
+		case !targetProc.IsJob():
+			result.AddProcessError(name, "triggers", fmt.Sprintf("Triggered process '%s' is long-running", target), fmt.Sprintf("Only oneshot and scheduled processes can be triggered, set type: oneshot on '%s'", target))
+		case !targetProc.Enabled:
+			result.AddProcessWarning(name, "triggers", fmt.Sprintf("Triggered process '%s' is disabled", target), fmt.Sprintf("Enable '%s' or remove it from triggers", target))
+		case targetProc.Schedule == "" && targetProc.InitialState == "running":
+			result.AddProcessSuggestion(name, "triggers", fmt.Sprintf("Triggered process '%s' also runs at startup", target), fmt.Sprintf("Set initial_state: stopped on '%s' to run it only when triggered", target))
+		}
+	}
+}
+
+// validateProcessScheduleFailures validates schedule_retry and
+// schedule_disable_after
+func (c *Config) validateProcessScheduleFailures(name string, proc *Process, result *ValidationResult) {
+	if proc.Schedule == "" {
+		if proc.ScheduleRetry != nil || proc.ScheduleDisableAfter != 0 {
+			result.AddProcessWarning(name, "schedule_retry", "schedule_retry and schedule_disable_after only apply to scheduled processes", "Set schedule or remove them (use restart for long-running processes)")
+		}
+		return
+	}
+
+	if proc.ScheduleDisableAfter < 0 {
+		result.AddProcessError(name, "schedule_disable_after", fmt.Sprintf("Invalid value: %d", proc.ScheduleDisableAfter), "Must be 0 (never disable) or more")
+	}
+
+	retry := proc.ScheduleRetry
+	if retry == nil {
+		return
+	}
+	if retry.MaxAttempts < 1 {
+		result.AddProcessError(name, "schedule_retry.max_attempts", fmt.Sprintf("Invalid max_attempts: %d", retry.MaxAttempts), "Must be at least 1 (1 = no retry)")
+	}
+	if retry.Backoff < 0 || retry.MaxBackoff < 0 {
+		result.AddProcessError(name, "schedule_retry.backoff", "Backoff cannot be negative", "Use a duration like 10s")
+	} else if retry.MaxBackoff < retry.Backoff {
+		result.AddProcessWarning(name, "schedule_retry.max_backoff", fmt.Sprintf("max_backoff (%s) is lower than backoff (%s)", retry.MaxBackoff, retry.Backoff), "Every retry waits max_backoff, raise it to allow exponential backoff")
+	}
+	if retry.Jitter > 1 || (retry.Jitter < 0 && retry.Jitter != -1) {
+		result.AddProcessError(name, "schedule_retry.jitter", fmt.Sprintf("Invalid jitter: %.2f", retry.Jitter), "Must be between 0 and 1, or -1 to disable")
+	}
+	for _, code := range retry.ExitCodes {
+		if code == 0 {
+			result.AddProcessWarning(name, "schedule_retry.exit_codes", "Exit code 0 is a success and is never retried", "Remove 0 from exit_codes")
+		}
+	}
+}
+
 // validateProcessLoggingConfig validates logging configuration
 func (c *Config) validateProcessLoggingConfig(name string, proc *Process, result *ValidationResult) {
 	if proc.Logging == nil {
@@ -449,7 +813,7 @@ func (c *Config) validateProcessLoggingConfig(name string, proc *Process, result
 
 // validateHealthCheck validates health check configuration
 func (c *Config) validateHealthCheck(processName string, hc *HealthCheck, result *ValidationResult) {
-	validTypes := []string{"tcp", "http", "exec"}
+	validTypes := fieldEnum(HealthCheck{}, "Type")
 	if !contains(validTypes, hc.Type) {
 		result.AddProcessError(processName, "health_check.type", fmt.Sprintf("Invalid type: %s", hc.Type), fmt.Sprintf("Must be one of: %s", strings.Join(validTypes, ", ")))
 	}
@@ -502,11 +866,51 @@ func (c *Config) validateDependencies(result *ValidationResult) {
 		for _, dep := range proc.DependsOn {
 			if _, exists := c.Processes[dep]; !exists {
 				result.AddProcessError(name, "depends_on", fmt.Sprintf("Dependency '%s' not defined", dep), fmt.Sprintf("Add process '%s' or remove from depends_on", dep))
+				continue
+			}
+			c.validateDependencyCondition(name, dep, result)
+		}
+		for dep := range proc.DependsOnOptions {
+			if !contains(proc.DependsOn, dep) {
+				result.AddProcessError(name, "depends_on", fmt.Sprintf("Options for '%s', which is not a dependency", dep), fmt.Sprintf("Add '%s' to depends_on or remove its options", dep))
 			}
 		}
 	}
 }
 
+// validateDependencyCondition checks that a dependency can reach the
+// condition the process waits for
+func (c *Config) validateDependencyCondition(name, dep string, result *ValidationResult) {
+	proc, depProc := c.Processes[name], c.Processes[dep]
+	opts := proc.Dependency(dep)
+
+	if opts.Timeout < 0 {
+		result.AddProcessError(name, "depends_on", fmt.Sprintf("Negative timeout for '%s'", dep), "Use a positive duration like 2m, or omit it to use global.dependency_timeout")
+	}
+
+	if err := opts.CheckCondition(depProc); err != nil {
+		switch {
+		case errors.Is(err, ErrNoHealthCheck):
+			result.AddProcessError(name, "depends_on", fmt.Sprintf("Dependency '%s' has no health check", dep), fmt.Sprintf("Add a health_check to '%s' or use condition: started", dep))
+		case errors.Is(err, ErrNotStartupOneshot):
+			result.AddProcessError(name, "depends_on", fmt.Sprintf("Dependency '%s' is not a oneshot process run at startup", dep), "Use condition: started or healthy for long-running processes")
+		default:
+			result.AddProcessError(name, "depends_on", fmt.Sprintf("Invalid condition '%s' for '%s'", opts.Condition, dep), "Use started, healthy or completed_successfully")
+		}
+		return
+	}
+	if opts.Condition == "" {
+		if depProc.Type == "oneshot" && depProc.Schedule == "" {
+			result.AddProcessSuggestion(name, "depends_on", fmt.Sprintf("Dependency '%s' is a oneshot process", dep), fmt.Sprintf("Use condition: completed_successfully to wait until '%s' has finished", dep))
+		}
+		return
+	}
+
+	if depProc.InitialState == "stopped" {
+		result.AddProcessWarning(name, "depends_on", fmt.Sprintf("Dependency '%s' starts stopped", dep), fmt.Sprintf("'%s' waits for condition %s until it times out", name, opts.Condition))
+	}
+}
+
 // detectCycle detects circular dependencies (DFS-based)
 func (c *Config) detectCycle(name string, visited, recStack map[string]bool, path []string) bool {
 	visited[name] = true
@@ -558,11 +962,18 @@ func (c *Config) lintProcessSecurity(name string, proc *Process, result *Validat
 	}
 
 	// Check for hardcoded secrets in environment
+	secretPaths := make(map[string]bool)
+	for _, ref := range c.Secrets().Refs() {
+		secretPaths[ref.Path] = true
+	}
 	for key, val := range proc.Env {
 		lowerKey := strings.ToLower(key)
 		if strings.Contains(lowerKey, "password") || strings.Contains(lowerKey, "secret") || strings.Contains(lowerKey, "token") {
+			if secretPaths["processes."+name+".env."+key] {
+				continue
+			}
 			if !strings.Contains(val, "$") && !strings.Contains(val, "{") {
-				result.AddProcessWarning(name, fmt.Sprintf("env.%s", key), "Possible hardcoded secret in environment variable", "Use environment variable interpolation (e.g., ${SECRET_FROM_RUNTIME})")
+				result.AddProcessWarning(name, fmt.Sprintf("env.%s", key), "Possible hardcoded secret in environment variable", "Use a secret reference (e.g., ${file:/run/secrets/db_password}) or interpolation (e.g., ${SECRET_FROM_RUNTIME})")
 			}
 		}
 	}
@@ -616,6 +1027,43 @@ func (c *Config) validateSecurity(result *ValidationResult) {
 	}
 }
 
+// validateEnums checks every field with an enum tag that the steps above do
+// not check with a more specific message
+func (c *Config) validateEnums(result *ValidationResult) {
+	reported := make(map[string]bool, len(result.Errors))
+	for _, issue := range result.Errors {
+		reported[issue.Field] = true
+	}
+
+	walkEnums(reflect.ValueOf(c).Elem(), "", func(path, value string, allowed []string) {
+		if value == "" || contains(allowed, value) || reported[path] {
+			return
+		}
+		result.AddError(path, fmt.Sprintf("Invalid value: %s", value), fmt.Sprintf("Must be one of: %s", strings.Join(allowed, ", ")))
+	})
+}
+
+// validateUnknownKeys warns about keys in the config files that match no
+// setting, which decoding silently ignores
+func (c *Config) validateUnknownKeys(result *ValidationResult) {
+	if c.Sources == nil {
+		return
+	}
+	for _, file := range c.Sources.Files {
+		keys, err := FindUnknownKeys(file)
+		if err != nil {
+			continue // The loader already parsed the file, so it changed since
+		}
+		for _, key := range keys {
+			hint := "Remove it or check the spelling (see 'phpeek-pm schema' for all settings)"
+			if key.Suggestion != "" {
+				hint = fmt.Sprintf("Did you mean %q?", key.Suggestion)
+			}
+			result.AddWarning(key.Path, fmt.Sprintf("Unknown key %q at %s", key.Key, key.Position()), hint)
+		}
+	}
+}
+
 // contains checks if a string slice contains a value
 func contains(slice []string, val string) bool {
 	for _, item := range slice {
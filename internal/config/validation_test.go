@@ -935,6 +935,56 @@ func TestValidateComprehensive_UpperBounds(t *testing.T) {
 			expectError: true,
 			errorField:  "global.oneshot_history_max_entries",
 		},
+		{
+			name: "crash_context_lines exceeds max",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					CrashContextLines:  MaxCrashContextLines + 1, // Exceeds max
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.crash_context_lines",
+		},
+		{
+			name: "crash_history_size exceeds max",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					CrashHistorySize:   MaxCrashHistorySize + 1, // Exceeds max
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.crash_history_size",
+		},
 		{
 			name: "max_process_scale exceeds max",
 			config: &Config{
@@ -960,6 +1010,31 @@ func TestValidateComprehensive_UpperBounds(t *testing.T) {
 			expectError: true,
 			errorField:  "global.max_process_scale",
 		},
+		{
+			name: "negative process_parallelism",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					ProcessParallelism: -1,
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.process_parallelism",
+		},
 		{
 			name: "api_max_request_body exceeds max",
 			config: &Config{
@@ -985,6 +1060,256 @@ func TestValidateComprehensive_UpperBounds(t *testing.T) {
 			expectError: true,
 			errorField:  "global.api_max_request_body",
 		},
+		{
+			name: "autotune_learning invalid percentile",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					AutotuneLearning:   &LearningConfig{Enabled: true, StatePath: "/tmp/learning.json", Percentile: 120, MinSamples: 10, Window: 100},
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.autotune_learning.percentile",
+		},
+		{
+			name: "autotune_learning window smaller than min_samples",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					AutotuneLearning:   &LearningConfig{Enabled: true, StatePath: "/tmp/learning.json", Percentile: 95, MinSamples: 500, Window: 100},
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.autotune_learning.window",
+		},
+		{
+			name: "cgroup interval below 1",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					Cgroup:             &CgroupConfig{Interval: 0, MemoryPressureThreshold: 20, CPUPressureThreshold: 80},
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.cgroup.interval",
+		},
+		{
+			name: "cgroup memory pressure threshold above 100",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					Cgroup:             &CgroupConfig{Interval: 5, MemoryPressureThreshold: 150, CPUPressureThreshold: 80},
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.cgroup.memory_pressure_threshold",
+		},
+		{
+			name: "process autotune missing memory_per_instance",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+						Autotune:     &ProcessAutotune{Enabled: true, Weight: 1, MinScale: 1},
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "processes.test.autotune.memory_per_instance",
+		},
+		{
+			name: "process autotune max_scale below min_scale",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+						Autotune:     &ProcessAutotune{Enabled: true, MemoryPerInstance: 128, Weight: 1, MinScale: 4, MaxScale: 2},
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "processes.test.autotune.max_scale",
+		},
+		{
+			name: "process autotune invalid workers_flag",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+						Autotune:     &ProcessAutotune{Enabled: true, MemoryPerInstance: 128, Weight: 1, MinScale: 1, WorkersFlag: "workers"},
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "processes.test.autotune.workers_flag",
+		},
+		{
+			name: "php_fpm_pool invalid listen mode",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					PHPFPMPool:         &FPMPoolConfig{Enabled: true, Path: "/tmp/pool.conf", ListenMode: "rw-rw----"},
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.php_fpm_pool.listen_mode",
+		},
+		{
+			name: "php_fpm_pool invalid terminate timeout",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					PHPFPMPool:         &FPMPoolConfig{Enabled: true, Path: "/tmp/pool.conf", RequestTerminateTimeout: "1 minute"},
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.php_fpm_pool.request_terminate_timeout",
+		},
+		{
+			name: "php_fpm_pool relative status path",
+			config: &Config{
+				Global: GlobalConfig{
+					ShutdownTimeout:    30,
+					LogLevel:           "info",
+					LogFormat:          "json",
+					MaxRestartAttempts: 3,
+					RestartBackoff:     5,
+					PHPFPMPool:         &FPMPoolConfig{Enabled: true, Path: "/tmp/pool.conf", StatusPath: "status"},
+				},
+				Processes: map[string]*Process{
+					"test": {
+						Enabled:      true,
+						Type:         "longrun",
+						InitialState: "running",
+						Command:      []string{"sleep", "60"},
+						Restart:      "always",
+						Scale:        1,
+					},
+				},
+			},
+			expectError: true,
+			errorField:  "global.php_fpm_pool.status_path",
+		},
 		{
 			name: "all values within limits",
 			config: &Config{
@@ -1243,3 +1568,509 @@ func TestValidateComprehensive_ZombieReapIntervalBounds(t *testing.T) {
 		})
 	}
 }
+
+// newScheduleTestConfig returns a valid config with a single oneshot "job"
+// process using the given schedule
+func newScheduleTestConfig(schedule string) *Config {
+	return &Config{
+		Global: GlobalConfig{
+			ShutdownTimeout:    30,
+			LogLevel:           "info",
+			LogFormat:          "json",
+			MaxRestartAttempts: 3,
+			RestartBackoff:     5,
+		},
+		Processes: map[string]*Process{
+			"job": {
+				Enabled:      true,
+				Type:         "oneshot",
+				InitialState: "running",
+				Command:      []string{"php", "artisan", "report"},
+				Restart:      "never",
+				Scale:        1,
+				Schedule:     schedule,
+			},
+		},
+	}
+}
+
+// hasIssue reports whether issues contains one for field
+func hasIssue(issues []ValidationIssue, field string) bool {
+	for _, issue := range issues {
+		if issue.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// checkScheduleIssues validates cfg and checks that it reports an error for
+// errorField and a warning for warningField. When either is empty, no issue
+// of that kind may mention scope.
+func checkScheduleIssues(t *testing.T, cfg *Config, scope, errorField, warningField string) {
+	t.Helper()
+
+	result, _ := cfg.ValidateComprehensive()
+	if errorField != "" && !hasIssue(result.Errors, errorField) {
+		t.Errorf("expected error for %s, got %+v", errorField, result.Errors)
+	}
+	if errorField == "" {
+		for _, issue := range result.Errors {
+			if strings.Contains(issue.Field, scope) {
+				t.Errorf("unexpected error: %+v", issue)
+			}
+		}
+	}
+	if warningField != "" && !hasIssue(result.Warnings, warningField) {
+		t.Errorf("expected warning for %s, got %+v", warningField, result.Warnings)
+	}
+	if warningField == "" {
+		for _, issue := range result.Warnings {
+			if strings.Contains(issue.Field, scope) {
+				t.Errorf("unexpected warning: %+v", issue)
+			}
+		}
+	}
+}
+
+func TestValidateComprehensive_ScheduleFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		schedule     string
+		retry        *ScheduleRetry
+		disableAfter int
+		errorField   string
+		warningField string
+	}{
+		{
+			name:     "valid retry",
+			schedule: "*/5 * * * *",
+			retry:    &ScheduleRetry{MaxAttempts: 3, Backoff: 10 * time.Second, MaxBackoff: time.Minute, Jitter: 0.2},
+		},
+		{
+			name:       "zero max_attempts",
+			schedule:   "*/5 * * * *",
+			retry:      &ScheduleRetry{MaxAttempts: 0, Backoff: 10 * time.Second, MaxBackoff: time.Minute},
+			errorField: "processes.job.schedule_retry.max_attempts",
+		},
+		{
+			name:       "jitter above 1",
+			schedule:   "*/5 * * * *",
+			retry:      &ScheduleRetry{MaxAttempts: 3, Backoff: 10 * time.Second, MaxBackoff: time.Minute, Jitter: 1.5},
+			errorField: "processes.job.schedule_retry.jitter",
+		},
+		{
+			name:         "max_backoff below backoff",
+			schedule:     "*/5 * * * *",
+			retry:        &ScheduleRetry{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Second, Jitter: -1},
+			warningField: "processes.job.schedule_retry.max_backoff",
+		},
+		{
+			name:         "exit code 0",
+			schedule:     "*/5 * * * *",
+			retry:        &ScheduleRetry{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Minute, ExitCodes: []int{0, 75}},
+			warningField: "processes.job.schedule_retry.exit_codes",
+		},
+		{
+			name:         "negative disable_after",
+			schedule:     "*/5 * * * *",
+			disableAfter: -1,
+			errorField:   "processes.job.schedule_disable_after",
+		},
+		{
+			name:         "retry without schedule",
+			retry:        &ScheduleRetry{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Minute},
+			warningField: "processes.job.schedule_retry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newScheduleTestConfig(tt.schedule)
+			job := cfg.Processes["job"]
+			job.ScheduleRetry = tt.retry
+			job.ScheduleDisableAfter = tt.disableAfter
+
+			checkScheduleIssues(t, cfg, "processes.job.schedule", tt.errorField, tt.warningField)
+		})
+	}
+}
+
+func TestValidateComprehensive_ScheduleOverlap(t *testing.T) {
+	tests := []struct {
+		name          string
+		overlap       string
+		maxConcurrent int
+		queueSize     int
+		errorField    string
+		warningField  string
+	}{
+		{
+			name:      "valid queue",
+			overlap:   "queue",
+			queueSize: 5,
+		},
+		{
+			name:          "valid allow",
+			overlap:       "allow",
+			maxConcurrent: 3,
+		},
+		{
+			name:       "invalid policy",
+			overlap:    "parallel",
+			errorField: "processes.job.schedule_overlap",
+		},
+		{
+			name:          "max_concurrent without allow",
+			overlap:       "skip",
+			maxConcurrent: 3,
+			warningField:  "processes.job.schedule_max_concurrent",
+		},
+		{
+			name:         "queue_size without queue",
+			overlap:      "replace",
+			queueSize:    5,
+			warningField: "processes.job.schedule_queue_size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newScheduleTestConfig("*/5 * * * *")
+			job := cfg.Processes["job"]
+			job.ScheduleOverlap = tt.overlap
+			job.ScheduleMaxConcurrent = tt.maxConcurrent
+			job.ScheduleQueueSize = tt.queueSize
+
+			checkScheduleIssues(t, cfg, "processes.job.schedule", tt.errorField, tt.warningField)
+		})
+	}
+}
+
+func TestValidateComprehensive_ScheduleCalendar(t *testing.T) {
+	tests := []struct {
+		name         string
+		schedule     string
+		jitter       time.Duration
+		blackout     []string
+		skipDates    []string
+		errorField   string
+		warningField string
+	}{
+		{
+			name:      "valid calendar",
+			schedule:  "*/5 * * * *",
+			jitter:    time.Minute,
+			blackout:  []string{"22:00-06:00"},
+			skipDates: []string{"2025-12-25"},
+		},
+		{
+			name:       "invalid blackout",
+			schedule:   "*/5 * * * *",
+			blackout:   []string{"10pm-6am"},
+			errorField: "processes.job.schedule_blackout",
+		},
+		{
+			name:       "invalid skip date",
+			schedule:   "*/5 * * * *",
+			skipDates:  []string{"2025-13-01"},
+			errorField: "processes.job.schedule_skip_dates",
+		},
+		{
+			name:         "jitter above interval",
+			schedule:     "@every 30s",
+			jitter:       time.Minute,
+			warningField: "processes.job.schedule_jitter",
+		},
+		{
+			name:         "calendar without schedule",
+			blackout:     []string{"22:00-06:00"},
+			warningField: "processes.job.schedule_jitter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newScheduleTestConfig(tt.schedule)
+			job := cfg.Processes["job"]
+			job.ScheduleJitter = tt.jitter
+			job.ScheduleBlackout = tt.blackout
+			job.ScheduleSkipDates = tt.skipDates
+
+			checkScheduleIssues(t, cfg, "processes.job.schedule", tt.errorField, tt.warningField)
+		})
+	}
+}
+
+func TestValidateComprehensive_ScheduleLock(t *testing.T) {
+	tests := []struct {
+		name         string
+		lock         *ScheduleLockConfig
+		processLock  *bool
+		schedule     string
+		errorField   string
+		warningField string
+	}{
+		{
+			name:     "valid redis lock",
+			lock:     &ScheduleLockConfig{Backend: "redis", TTL: 30 * time.Second, Identity: "web-1", Redis: &RedisLockConfig{Address: "redis:6379"}},
+			schedule: "*/5 * * * *",
+		},
+		{
+			name:       "redis address without port",
+			lock:       &ScheduleLockConfig{Backend: "redis", TTL: 30 * time.Second, Redis: &RedisLockConfig{Address: "redis"}},
+			schedule:   "*/5 * * * *",
+			errorField: "global.schedule_lock.redis.address",
+		},
+		{
+			name:       "file without dir",
+			lock:       &ScheduleLockConfig{Backend: "file", TTL: 30 * time.Second},
+			schedule:   "*/5 * * * *",
+			errorField: "global.schedule_lock.file.dir",
+		},
+		{
+			name:       "ttl below 1s",
+			lock:       &ScheduleLockConfig{Backend: "kubernetes", TTL: 100 * time.Millisecond},
+			schedule:   "*/5 * * * *",
+			errorField: "global.schedule_lock.ttl",
+		},
+		{
+			name:         "short ttl",
+			lock:         &ScheduleLockConfig{Backend: "kubernetes", TTL: 2 * time.Second},
+			schedule:     "*/5 * * * *",
+			warningField: "global.schedule_lock.ttl",
+		},
+		{
+			name:         "process lock without global lock",
+			processLock:  boolPtr(true),
+			schedule:     "*/5 * * * *",
+			warningField: "processes.job.schedule_lock",
+		},
+		{
+			name:         "process lock without schedule",
+			lock:         &ScheduleLockConfig{Backend: "kubernetes", TTL: 30 * time.Second},
+			processLock:  boolPtr(true),
+			warningField: "processes.job.schedule_lock",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newScheduleTestConfig(tt.schedule)
+			cfg.Global.ScheduleLock = tt.lock
+			cfg.Processes["job"].ScheduleLock = tt.processLock
+
+			checkScheduleIssues(t, cfg, "schedule_lock", tt.errorField, tt.warningField)
+		})
+	}
+}
+
+func TestValidateComprehensive_ScheduleOutput(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       *ScheduleOutput
+		historySize  int
+		schedule     string
+		errorField   string
+		warningField string
+	}{
+		{
+			name:        "defaults",
+			output:      &ScheduleOutput{HeadLines: 100, TailLines: 200, MaxLineLength: 4096},
+			historySize: 100,
+			schedule:    "*/5 * * * *",
+		},
+		{
+			name:        "negative tail lines",
+			output:      &ScheduleOutput{HeadLines: 100, TailLines: -1, MaxLineLength: 4096},
+			historySize: 100,
+			schedule:    "*/5 * * * *",
+			errorField:  "processes.job.schedule_output",
+		},
+		{
+			name:        "negative max line length",
+			output:      &ScheduleOutput{HeadLines: 100, TailLines: 200, MaxLineLength: -1},
+			historySize: 100,
+			schedule:    "*/5 * * * *",
+			errorField:  "processes.job.schedule_output.max_line_length",
+		},
+		{
+			name:        "too many lines",
+			output:      &ScheduleOutput{HeadLines: 6000, TailLines: 6000, MaxLineLength: 4096},
+			historySize: 100,
+			schedule:    "*/5 * * * *",
+			errorField:  "processes.job.schedule_output",
+		},
+		{
+			name:         "large worst case memory",
+			output:       &ScheduleOutput{HeadLines: 1000, TailLines: 1000, MaxLineLength: 4096},
+			historySize:  100,
+			schedule:     "*/5 * * * *",
+			warningField: "processes.job.schedule_output",
+		},
+		{
+			name:        "large limits kept on failure only",
+			output:      &ScheduleOutput{HeadLines: 1000, TailLines: 1000, MaxLineLength: 4096, OnFailureOnly: true},
+			historySize: 100,
+			schedule:    "*/5 * * * *",
+		},
+		{
+			name:         "output without schedule",
+			output:       &ScheduleOutput{HeadLines: 100, TailLines: 200, MaxLineLength: 4096},
+			historySize:  100,
+			warningField: "processes.job.schedule_output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newScheduleTestConfig(tt.schedule)
+			cfg.Global.ScheduleHistorySize = tt.historySize
+			cfg.Processes["job"].ScheduleOutput = tt.output
+
+			checkScheduleIssues(t, cfg, "schedule_output", tt.errorField, tt.warningField)
+		})
+	}
+}
+
+func TestValidateComprehensive_DependencyConditions(t *testing.T) {
+	tests := []struct {
+		name           string
+		dependsOn      []string
+		options        map[string]*Dependency
+		wantError      string
+		wantWarning    string
+		wantSuggestion string
+	}{
+		{name: "valid", dependsOn: []string{"migrate", "redis"}, options: map[string]*Dependency{
+			"migrate": {Condition: DependencyCompletedSuccessfully},
+			"redis":   {Condition: DependencyHealthy, Timeout: time.Minute},
+		}},
+		{name: "unknown condition", dependsOn: []string{"redis"}, options: map[string]*Dependency{"redis": {Condition: "ready"}}, wantError: "Invalid condition"},
+		{name: "negative timeout", dependsOn: []string{"redis"}, options: map[string]*Dependency{"redis": {Timeout: -time.Second}}, wantError: "Negative timeout"},
+		{name: "options without dependency", dependsOn: []string{"redis"}, options: map[string]*Dependency{"web": {}}, wantError: "not a dependency"},
+		{name: "healthy without health check", dependsOn: []string{"web"}, options: map[string]*Dependency{"web": {Condition: DependencyHealthy}}, wantError: "has no health check"},
+		{name: "completed on longrun", dependsOn: []string{"redis"}, options: map[string]*Dependency{"redis": {Condition: DependencyCompletedSuccessfully}}, wantError: "not a oneshot process"},
+		{name: "stopped dependency", dependsOn: []string{"worker"}, options: map[string]*Dependency{"worker": {Condition: DependencyStarted}}, wantWarning: "starts stopped"},
+		{name: "short form oneshot", dependsOn: []string{"migrate"}, wantSuggestion: "completed_successfully"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Global: GlobalConfig{ShutdownTimeout: 30, LogLevel: "info", LogFormat: "json", MaxRestartAttempts: 3, RestartBackoff: 5},
+				Processes: map[string]*Process{
+					"app": {
+						Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1,
+						DependsOn:        tt.dependsOn,
+						DependsOnOptions: tt.options,
+					},
+					"migrate": {Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"true"}, Restart: "never", Scale: 1},
+					"redis": {
+						Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1,
+						HealthCheck: &HealthCheck{Type: "tcp", Address: "127.0.0.1:6379", Period: 10, Timeout: 5, FailureThreshold: 3},
+					},
+					"web":    {Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1},
+					"worker": {Enabled: true, Type: "longrun", InitialState: "stopped", Command: []string{"true"}, Restart: "always", Scale: 1},
+				},
+			}
+
+			result, _ := cfg.ValidateComprehensive()
+			find := func(issues []ValidationIssue, msg string) bool {
+				for _, issue := range issues {
+					if issue.Field == "processes.app.depends_on" && (msg == "" || strings.Contains(issue.Message+issue.Suggestion, msg)) {
+						return true
+					}
+				}
+				return false
+			}
+
+			if tt.wantError != "" && !find(result.Errors, tt.wantError) {
+				t.Errorf("expected error %q, got %+v", tt.wantError, result.Errors)
+			}
+			if tt.wantError == "" && find(result.Errors, "") {
+				t.Errorf("unexpected depends_on error: %+v", result.Errors)
+			}
+			if tt.wantWarning != "" && !find(result.Warnings, tt.wantWarning) {
+				t.Errorf("expected warning %q, got %+v", tt.wantWarning, result.Warnings)
+			}
+			if tt.wantWarning == "" && find(result.Warnings, "") {
+				t.Errorf("unexpected depends_on warning: %+v", result.Warnings)
+			}
+			if tt.wantSuggestion != "" && !find(result.Suggestions, tt.wantSuggestion) {
+				t.Errorf("expected suggestion %q, got %+v", tt.wantSuggestion, result.Suggestions)
+			}
+		})
+	}
+}
+
+func TestValidateComprehensive_Triggers(t *testing.T) {
+	tests := []struct {
+		name           string
+		triggers       *ProcessTriggers
+		sourceType     string
+		wantError      string
+		wantWarning    string
+		wantSuggestion string
+	}{
+		{name: "valid", triggers: &ProcessTriggers{OnSuccess: []string{"load"}, OnFailure: []string{"report"}}, sourceType: "oneshot"},
+		{name: "empty", triggers: &ProcessTriggers{}, sourceType: "oneshot", wantWarning: "No processes listed"},
+		{name: "longrun source", triggers: &ProcessTriggers{OnSuccess: []string{"load"}}, sourceType: "longrun", wantError: "Only oneshot and scheduled processes"},
+		{name: "missing target", triggers: &ProcessTriggers{OnSuccess: []string{"ghost"}}, sourceType: "oneshot", wantError: "not defined"},
+		{name: "longrun target", triggers: &ProcessTriggers{OnSuccess: []string{"web"}}, sourceType: "oneshot", wantError: "is long-running"},
+		{name: "disabled target", triggers: &ProcessTriggers{OnFailure: []string{"disabled"}}, sourceType: "oneshot", wantWarning: "is disabled"},
+		{name: "target runs at startup", triggers: &ProcessTriggers{OnSuccess: []string{"migrate"}}, sourceType: "oneshot", wantSuggestion: "also runs at startup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oneshot := func(initialState string) *Process {
+				return &Process{Enabled: true, Type: "oneshot", InitialState: initialState, Command: []string{"true"}, Restart: "never", Scale: 1}
+			}
+			source := oneshot("running")
+			if tt.sourceType == "longrun" {
+				source = &Process{Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1}
+			}
+			source.Triggers = tt.triggers
+			disabled := oneshot("stopped")
+			disabled.Enabled = false
+			cfg := &Config{
+				Global: GlobalConfig{ShutdownTimeout: 30, LogLevel: "info", LogFormat: "json", MaxRestartAttempts: 3, RestartBackoff: 5},
+				Processes: map[string]*Process{
+					"extract":  source,
+					"load":     oneshot("stopped"),
+					"migrate":  oneshot("running"),
+					"disabled": disabled,
+					"report":   {Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"true"}, Restart: "never", Scale: 1, Schedule: "0 0 * * *"},
+					"web":      {Enabled: true, Type: "longrun", InitialState: "running", Command: []string{"true"}, Restart: "always", Scale: 1},
+				},
+			}
+
+			result, _ := cfg.ValidateComprehensive()
+			find := func(issues []ValidationIssue, msg string) bool {
+				for _, issue := range issues {
+					if issue.Field == "processes.extract.triggers" && (msg == "" || strings.Contains(issue.Message, msg)) {
+						return true
+					}
+				}
+				return false
+			}
+
+			if tt.wantError != "" && !find(result.Errors, tt.wantError) {
+				t.Errorf("expected error %q, got %+v", tt.wantError, result.Errors)
+			}
+			if tt.wantError == "" && find(result.Errors, "") {
+				t.Errorf("unexpected trigger error: %+v", result.Errors)
+			}
+			if tt.wantWarning != "" && !find(result.Warnings, tt.wantWarning) {
+				t.Errorf("expected warning %q, got %+v", tt.wantWarning, result.Warnings)
+			}
+			if tt.wantWarning == "" && find(result.Warnings, "") {
+				t.Errorf("unexpected trigger warning: %+v", result.Warnings)
+			}
+			if tt.wantSuggestion != "" && !find(result.Suggestions, tt.wantSuggestion) {
+				t.Errorf("expected suggestion %q, got %+v", tt.wantSuggestion, result.Suggestions)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package deps
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gophpeek/phpeek-pm/internal/config"
 )
@@ -10,12 +11,16 @@ import (
 type Graph struct {
 	// nodes maps process name to its dependencies
 	nodes map[string][]string
+	// conditions maps process name to the conditions of its long form
+	// dependencies (see config.Dependency)
+	conditions map[string]map[string]string
 }
 
 // NewGraph creates a new empty dependency graph
 func NewGraph() *Graph {
 	return &Graph{
-		nodes: make(map[string][]string),
+		nodes:      make(map[string][]string),
+		conditions: make(map[string]map[string]string),
 	}
 }
 
@@ -30,6 +35,11 @@ func NewGraphFromConfig(processes map[string]*config.Process) (*Graph, error) {
 			continue
 		}
 		g.AddNode(name, proc.DependsOn)
+		for _, dep := range proc.DependsOn {
+			if condition := proc.Dependency(dep).Condition; condition != "" {
+				g.SetCondition(name, dep, condition)
+			}
+		}
 	}
 
 	// Validate the graph
@@ -37,6 +47,47 @@ func NewGraphFromConfig(processes map[string]*config.Process) (*Graph, error) {
 		return nil, err
 	}
 
+	// Conditions must be reachable by the dependency
+	for name, conditions := range g.conditions {
+		for dep := range conditions {
+			if err := processes[name].Dependency(dep).CheckCondition(processes[dep]); err != nil {
+				return nil, fmt.Errorf("process %q waits for %q: %w", name, dep, err)
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// NewTriggerGraphFromConfig creates a graph of job chains from process configs.
+// Edges point from a job to the processes its triggers start, so a cycle means
+// a chain that would restart itself forever. Triggers to disabled processes are
+// ignored since they never run.
+func NewTriggerGraphFromConfig(processes map[string]*config.Process) (*Graph, error) {
+	g := NewGraph()
+
+	for name, proc := range processes {
+		if !proc.Enabled {
+			continue
+		}
+		var targets []string
+		for _, target := range proc.Triggers.Targets() {
+			if targetProc, exists := processes[target]; exists && !targetProc.Enabled {
+				continue
+			}
+			targets = append(targets, target)
+		}
+		g.AddNode(name, targets)
+	}
+
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	if hasCycle, cycle := g.HasCycle(); hasCycle {
+		return nil, fmt.Errorf("trigger cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
 	return g, nil
 }
 
@@ -45,6 +96,20 @@ func (g *Graph) AddNode(name string, deps []string) {
 	g.nodes[name] = deps
 }
 
+// SetCondition sets what name waits for before it starts after dep
+func (g *Graph) SetCondition(name, dep, condition string) {
+	if g.conditions[name] == nil {
+		g.conditions[name] = make(map[string]string)
+	}
+	g.conditions[name][dep] = condition
+}
+
+// Condition returns what name waits for before it starts after dep, "" if it
+// waits until dep is ready
+func (g *Graph) Condition(name, dep string) string {
+	return g.conditions[name][dep]
+}
+
 // Nodes returns all process names in the graph
 func (g *Graph) Nodes() []string {
 	nodes := make([]string, 0, len(g.nodes))
@@ -190,6 +255,40 @@ func (g *Graph) TopologicalSort() ([]string, error) {
 	return result, nil
 }
 
+// Levels groups processes by dependency depth: level 0 holds the processes
+// without dependencies, and every other process is one level after its
+// deepest dependency. Processes of a level do not depend on each other, so a
+// level can start in parallel once the levels before it have started.
+// Each level is sorted alphabetically for determinism.
+func (g *Graph) Levels() ([][]string, error) {
+	order, err := g.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+
+	depth := make(map[string]int, len(order))
+	var levels [][]string
+	for _, node := range order {
+		level := 0
+		for _, dep := range g.nodes[node] {
+			if depth[dep]+1 > level {
+				level = depth[dep] + 1
+			}
+		}
+		depth[node] = level
+
+		for len(levels) <= level {
+			levels = append(levels, nil)
+		}
+		levels[level] = append(levels[level], node)
+	}
+
+	for _, level := range levels {
+		sortAlphabetically(level)
+	}
+	return levels, nil
+}
+
 // sortAlphabetically sorts a slice of process names alphabetically for deterministic ordering
 func sortAlphabetically(nodes []string) {
 	// Simple insertion sort (queue is typically small)
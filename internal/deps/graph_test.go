@@ -878,3 +878,241 @@ func TestNewGraphFromConfig_WithTopologicalSort(t *testing.T) {
 		t.Errorf("Expected 3 nodes in order, got %d: %v", len(order), order)
 	}
 }
+
+// TestNewTriggerGraphFromConfig tests job chain graph construction and cycle detection
+func TestNewTriggerGraphFromConfig(t *testing.T) {
+	job := func(enabled bool, onSuccess, onFailure []string) *config.Process {
+		return &config.Process{
+			Enabled:  enabled,
+			Type:     "oneshot",
+			Triggers: &config.ProcessTriggers{OnSuccess: onSuccess, OnFailure: onFailure},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		processes map[string]*config.Process
+		wantError bool
+		errMsg    string
+	}{
+		{
+			name: "linear chain",
+			processes: map[string]*config.Process{
+				"extract":   job(true, []string{"transform"}, nil),
+				"transform": job(true, []string{"load"}, []string{"alert"}),
+				"load":      job(true, nil, nil),
+				"alert":     job(true, nil, nil),
+			},
+		},
+		{
+			name: "triggers do not mix with depends_on",
+			processes: map[string]*config.Process{
+				"php-fpm": {Enabled: true},
+				"nginx":   {Enabled: true, DependsOn: []string{"php-fpm"}},
+				"backup":  job(true, []string{"upload"}, nil),
+				"upload":  {Enabled: true, Type: "oneshot", DependsOn: []string{"backup"}},
+			},
+		},
+		{
+			name: "cycle through on_failure",
+			processes: map[string]*config.Process{
+				"a": job(true, []string{"b"}, nil),
+				"b": job(true, nil, []string{"a"}),
+			},
+			wantError: true,
+			errMsg:    "trigger cycle detected",
+		},
+		{
+			name: "self trigger",
+			processes: map[string]*config.Process{
+				"retry": job(true, nil, []string{"retry"}),
+			},
+			wantError: true,
+			errMsg:    "self-dependency",
+		},
+		{
+			name: "missing target",
+			processes: map[string]*config.Process{
+				"a": job(true, []string{"ghost"}, nil),
+			},
+			wantError: true,
+			errMsg:    "non-existent",
+		},
+		{
+			name: "cycle broken by disabled process",
+			processes: map[string]*config.Process{
+				"a": job(true, []string{"b"}, nil),
+				"b": job(false, []string{"a"}, nil),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph, err := NewTriggerGraphFromConfig(tt.processes)
+
+			if tt.wantError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				} else if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if graph == nil {
+				t.Fatal("Expected non-nil graph")
+			}
+		})
+	}
+}
+
+// TestNewTriggerGraphFromConfig_CyclePath tests the cycle path in the error message
+func TestNewTriggerGraphFromConfig_CyclePath(t *testing.T) {
+	processes := map[string]*config.Process{
+		"a":  {Enabled: true, Type: "oneshot", Triggers: &config.ProcessTriggers{OnSuccess: []string{"a2"}}},
+		"a2": {Enabled: true, Type: "oneshot", Triggers: &config.ProcessTriggers{OnSuccess: []string{"a"}}},
+	}
+
+	_, err := NewTriggerGraphFromConfig(processes)
+	if err == nil {
+		t.Fatal("Expected trigger cycle error")
+	}
+	if !strings.Contains(err.Error(), " -> ") {
+		t.Errorf("Expected cycle path joined with arrows, got: %v", err)
+	}
+}
+
+// TestNewGraphFromConfig_Conditions tests long form depends_on conditions
+func TestNewGraphFromConfig_Conditions(t *testing.T) {
+	health := &config.HealthCheck{Type: "tcp", Address: "127.0.0.1:6379"}
+	base := func() map[string]*config.Process {
+		return map[string]*config.Process{
+			"migrate": {Enabled: true, Type: "oneshot"},
+			"redis":   {Enabled: true, Type: "longrun", HealthCheck: health},
+			"app": {
+				Enabled:   true,
+				Type:      "longrun",
+				DependsOn: []string{"migrate", "redis"},
+				DependsOnOptions: map[string]*config.Dependency{
+					"migrate": {Condition: config.DependencyCompletedSuccessfully},
+					"redis":   {Condition: config.DependencyHealthy},
+				},
+			},
+		}
+	}
+
+	graph, err := NewGraphFromConfig(base())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := graph.Condition("app", "migrate"); got != config.DependencyCompletedSuccessfully {
+		t.Errorf("Condition(app, migrate) = %q", got)
+	}
+	if got := graph.Condition("app", "redis"); got != config.DependencyHealthy {
+		t.Errorf("Condition(app, redis) = %q", got)
+	}
+	if got := graph.Condition("redis", "app"); got != "" {
+		t.Errorf("Condition(redis, app) = %q, want none", got)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(map[string]*config.Process)
+		errMsg string
+	}{
+		{
+			name:   "completed_successfully on longrun",
+			modify: func(p map[string]*config.Process) { p["migrate"].Type = "longrun" },
+			errMsg: "not a oneshot process",
+		},
+		{
+			name:   "completed_successfully on scheduled job",
+			modify: func(p map[string]*config.Process) { p["migrate"].Schedule = "0 * * * *" },
+			errMsg: "not a oneshot process",
+		},
+		{
+			name:   "healthy without health check",
+			modify: func(p map[string]*config.Process) { p["redis"].HealthCheck = nil },
+			errMsg: "no health check",
+		},
+		{
+			name: "unknown condition",
+			modify: func(p map[string]*config.Process) {
+				p["app"].DependsOnOptions["redis"].Condition = "ready"
+			},
+			errMsg: "invalid condition",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processes := base()
+			tt.modify(processes)
+			_, err := NewGraphFromConfig(processes)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+// TestGraph_Levels tests grouping processes by dependency depth
+func TestGraph_Levels(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("postgres", []string{})
+	g.AddNode("redis", []string{})
+	g.AddNode("migrate", []string{"postgres"})
+	g.AddNode("php-fpm", []string{"migrate", "redis"})
+	g.AddNode("nginx", []string{"php-fpm"})
+	g.AddNode("horizon", []string{"redis", "migrate"})
+	g.AddNode("worker-b", []string{})
+	g.AddNode("worker-a", []string{})
+
+	levels, err := g.Levels()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := [][]string{
+		{"postgres", "redis", "worker-a", "worker-b"},
+		{"migrate"},
+		{"horizon", "php-fpm"},
+		{"nginx"},
+	}
+	if len(levels) != len(want) {
+		t.Fatalf("Expected %d levels, got %v", len(want), levels)
+	}
+	for i := range want {
+		if strings.Join(levels[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("Level %d: expected %v, got %v", i, want[i], levels[i])
+		}
+	}
+}
+
+// TestGraph_Levels_Errors tests that invalid graphs have no levels
+func TestGraph_Levels_Errors(t *testing.T) {
+	cycle := NewGraph()
+	cycle.AddNode("A", []string{"B"})
+	cycle.AddNode("B", []string{"A"})
+	if _, err := cycle.Levels(); err == nil || !strings.Contains(err.Error(), "circular dependency") {
+		t.Errorf("Expected circular dependency error, got: %v", err)
+	}
+
+	missing := NewGraph()
+	missing.AddNode("A", []string{"ghost"})
+	if _, err := missing.Levels(); err == nil {
+		t.Error("Expected missing dependency error")
+	}
+
+	levels, err := NewGraph().Levels()
+	if err != nil || len(levels) != 0 {
+		t.Errorf("Expected no levels for an empty graph, got %v, %v", levels, err)
+	}
+}
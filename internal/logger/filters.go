@@ -110,3 +110,20 @@ func (lf *LogFilters) GetMinLevel() slog.Level {
 func (lf *LogFilters) HasFilters() bool {
 	return lf.hasFilters
 }
+
+// Config returns the include/exclude patterns as a FilterConfig, or nil
+// when no pattern filters are configured.
+func (lf *LogFilters) Config() *config.FilterConfig {
+	if !lf.hasFilters {
+		return nil
+	}
+
+	cfg := &config.FilterConfig{}
+	for _, pattern := range lf.excludePatterns {
+		cfg.Exclude = append(cfg.Exclude, pattern.String())
+	}
+	for _, pattern := range lf.includePatterns {
+		cfg.Include = append(cfg.Include, pattern.String())
+	}
+	return cfg
+}
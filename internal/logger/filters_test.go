@@ -427,3 +427,32 @@ func TestLogFilters_HasFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestLogFilters_Config(t *testing.T) {
+	filters, err := NewLogFilters(nil, "info")
+	if err != nil {
+		t.Fatalf("NewLogFilters() error = %v", err)
+	}
+	if filters.Config() != nil {
+		t.Error("Config() should be nil without pattern filters")
+	}
+
+	filters, err = NewLogFilters(&config.FilterConfig{
+		Exclude: []string{"health", "^ping"},
+		Include: []string{"order"},
+	}, "info")
+	if err != nil {
+		t.Fatalf("NewLogFilters() error = %v", err)
+	}
+
+	cfg := filters.Config()
+	if cfg == nil {
+		t.Fatal("Config() returned nil")
+	}
+	if len(cfg.Exclude) != 2 || cfg.Exclude[1] != "^ping" {
+		t.Errorf("Exclude = %v, want [health ^ping]", cfg.Exclude)
+	}
+	if len(cfg.Include) != 1 || cfg.Include[0] != "order" {
+		t.Errorf("Include = %v, want [order]", cfg.Include)
+	}
+}
@@ -1,10 +1,16 @@
 package logger
 
 import (
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// logSeq hands out process-wide sequence numbers so entries from different
+// buffers can be ordered and paginated with a single cursor.
+var logSeq atomic.Uint64
+
 // LogEntry represents a single log entry with metadata
 type LogEntry struct {
 	Timestamp   time.Time
@@ -13,6 +19,7 @@ type LogEntry struct {
 	Stream      string // stdout or stderr
 	Message     string
 	Level       string // debug, info, warn, error
+	Seq         uint64 // Monotonic sequence number assigned by LogBuffer.Add
 }
 
 // LogBuffer is a thread-safe ring buffer for storing recent log entries
@@ -42,6 +49,7 @@ func (lb *LogBuffer) Add(entry LogEntry) {
 	lb.mu.Lock()
 	defer lb.mu.Unlock()
 
+	entry.Seq = logSeq.Add(1)
 	lb.entries[lb.index] = entry
 	lb.index++
 
@@ -116,6 +124,54 @@ func (lb *LogBuffer) GetRecent(n int) []LogEntry {
 	return result
 }
 
+// Query returns entries matching q, newest first, stopping once q.Limit
+// matches have been collected. Entries are scanned in place under the read
+// lock so only matching entries are copied.
+func (lb *LogBuffer) Query(q LogQuery) []LogEntry {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	count := lb.index
+	if lb.full {
+		count = lb.size
+	}
+
+	result := make([]LogEntry, 0)
+	for i := 0; i < count; i++ {
+		// Walk backwards from the most recently written slot
+		pos := lb.index - 1 - i
+		if pos < 0 {
+			pos += lb.size
+		}
+		entry := lb.entries[pos]
+
+		if q.Before > 0 && entry.Seq >= q.Before {
+			continue
+		}
+		if !q.Matches(entry) {
+			continue
+		}
+
+		result = append(result, entry)
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+	}
+
+	return result
+}
+
+// SortNewestFirst orders entries by sequence number, newest first.
+// Entries without a sequence number fall back to timestamp ordering.
+func SortNewestFirst(entries []LogEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Seq != entries[j].Seq && entries[i].Seq != 0 && entries[j].Seq != 0 {
+			return entries[i].Seq > entries[j].Seq
+		}
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+}
+
 // Clear clears all entries from the buffer
 func (lb *LogBuffer) Clear() {
 	lb.mu.Lock()
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -520,3 +521,63 @@ func TestLogBuffer_GetRecent_Full_NoWrapNeeded(t *testing.T) {
 		t.Errorf("GetRecent(5) should return 5 entries, got %d", len(result2))
 	}
 }
+
+func TestLogBuffer_Query(t *testing.T) {
+	buffer := NewLogBuffer(5)
+	base := time.Unix(1700000000, 0)
+
+	// Seven entries into a five-slot buffer forces a wrap
+	for i := 0; i < 7; i++ {
+		level := "info"
+		if i%2 == 0 {
+			level = "error"
+		}
+		buffer.Add(LogEntry{
+			Timestamp:  base.Add(time.Duration(i) * time.Second),
+			InstanceID: "app-0",
+			Stream:     "stdout",
+			Message:    fmt.Sprintf("message %d", i),
+			Level:      level,
+		})
+	}
+
+	all := buffer.Query(LogQuery{})
+	if len(all) != 5 {
+		t.Fatalf("Query() returned %d entries, want 5", len(all))
+	}
+	if all[0].Message != "message 6" || all[4].Message != "message 2" {
+		t.Errorf("Query() order = %q..%q, want newest first", all[0].Message, all[4].Message)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Seq >= all[i-1].Seq {
+			t.Errorf("entry %d seq %d not below previous %d", i, all[i].Seq, all[i-1].Seq)
+		}
+	}
+
+	errors := buffer.Query(LogQuery{Levels: []string{"error"}, Limit: 2})
+	if len(errors) != 2 || errors[0].Message != "message 6" || errors[1].Message != "message 4" {
+		t.Errorf("level query = %+v, want messages 6 and 4", errors)
+	}
+
+	page := buffer.Query(LogQuery{Levels: []string{"error"}, Before: errors[1].Seq})
+	if len(page) != 1 || page[0].Message != "message 2" {
+		t.Errorf("cursor query = %+v, want message 2", page)
+	}
+
+	since := buffer.Query(LogQuery{Since: base.Add(5 * time.Second)})
+	if len(since) != 2 {
+		t.Errorf("since query returned %d entries, want 2", len(since))
+	}
+}
+
+func TestSortNewestFirst(t *testing.T) {
+	entries := []LogEntry{
+		{Message: "a", Seq: 1},
+		{Message: "c", Seq: 3},
+		{Message: "b", Seq: 2},
+	}
+	SortNewestFirst(entries)
+	if entries[0].Message != "c" || entries[1].Message != "b" || entries[2].Message != "a" {
+		t.Errorf("SortNewestFirst() = %v", entries)
+	}
+}
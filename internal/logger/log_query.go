@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogQuery describes a server-side filter over buffered log entries.
+// Zero-value fields are ignored, so an empty query matches every entry.
+type LogQuery struct {
+	Pattern  *regexp.Regexp // Regex matched against the message (nil = any)
+	Contains string         // Case-insensitive substring match on the message
+	Levels   []string       // Allowed levels (empty = all)
+	Stream   string         // stdout, stderr, combined or event (empty = all)
+	Instance string         // Exact instance ID (empty = all)
+	Since    time.Time      // Only entries at or after this time
+	Until    time.Time      // Only entries at or before this time
+	Before   uint64         // Cursor: only entries with Seq < Before (0 = newest)
+	Limit    int            // Maximum entries returned (0 = unlimited)
+}
+
+// Matches reports whether the entry satisfies every filter of the query.
+// Limit and Before are pagination concerns and are not evaluated here.
+func (q LogQuery) Matches(entry LogEntry) bool {
+	if q.Instance != "" && entry.InstanceID != q.Instance {
+		return false
+	}
+	if q.Stream != "" && entry.Stream != q.Stream {
+		return false
+	}
+	if len(q.Levels) > 0 && !containsFold(q.Levels, entry.Level) {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && entry.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.Contains != "" && !strings.Contains(strings.ToLower(entry.Message), strings.ToLower(q.Contains)) {
+		return false
+	}
+	if q.Pattern != nil && !q.Pattern.MatchString(entry.Message) {
+		return false
+	}
+	return true
+}
+
+// NextCursor returns the cursor for the page following results, or 0 when
+// results did not fill the limit and there is nothing more to fetch.
+// results must be ordered newest first, as returned by Query.
+func (q LogQuery) NextCursor(results []LogEntry) uint64 {
+	if q.Limit <= 0 || len(results) < q.Limit {
+		return 0
+	}
+	return results[len(results)-1].Seq
+}
+
+// MergeQueryResults combines per-buffer query results into a single
+// newest-first slice capped at the query limit.
+func MergeQueryResults(q LogQuery, results ...[]LogEntry) []LogEntry {
+	total := 0
+	for _, r := range results {
+		total += len(r)
+	}
+
+	merged := make([]LogEntry, 0, total)
+	for _, r := range results {
+		merged = append(merged, r...)
+	}
+
+	SortNewestFirst(merged)
+
+	if q.Limit > 0 && len(merged) > q.Limit {
+		merged = merged[:q.Limit]
+	}
+	return merged
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
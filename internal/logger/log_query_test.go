@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLogQuery_Matches(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	entry := LogEntry{
+		Timestamp:  now,
+		InstanceID: "web-1",
+		Stream:     "stderr",
+		Message:    "PDOException: SQLSTATE[HY000] Connection refused",
+		Level:      "error",
+	}
+
+	tests := []struct {
+		name  string
+		query LogQuery
+		want  bool
+	}{
+		{"empty query", LogQuery{}, true},
+		{"substring case insensitive", LogQuery{Contains: "pdoexception"}, true},
+		{"substring miss", LogQuery{Contains: "timeout"}, false},
+		{"regex match", LogQuery{Pattern: regexp.MustCompile(`SQLSTATE\[\w+\]`)}, true},
+		{"regex miss", LogQuery{Pattern: regexp.MustCompile(`^INFO`)}, false},
+		{"level match", LogQuery{Levels: []string{"warn", "ERROR"}}, true},
+		{"level miss", LogQuery{Levels: []string{"info"}}, false},
+		{"stream match", LogQuery{Stream: "stderr"}, true},
+		{"stream miss", LogQuery{Stream: "stdout"}, false},
+		{"instance match", LogQuery{Instance: "web-1"}, true},
+		{"instance miss", LogQuery{Instance: "web-0"}, false},
+		{"since inclusive", LogQuery{Since: now}, true},
+		{"since after", LogQuery{Since: now.Add(time.Second)}, false},
+		{"until inclusive", LogQuery{Until: now}, true},
+		{"until before", LogQuery{Until: now.Add(-time.Second)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(entry); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogQuery_NextCursor(t *testing.T) {
+	results := []LogEntry{{Seq: 9}, {Seq: 7}}
+
+	if got := (LogQuery{Limit: 2}).NextCursor(results); got != 7 {
+		t.Errorf("NextCursor() full page = %d, want 7", got)
+	}
+	if got := (LogQuery{Limit: 3}).NextCursor(results); got != 0 {
+		t.Errorf("NextCursor() partial page = %d, want 0", got)
+	}
+	if got := (LogQuery{}).NextCursor(results); got != 0 {
+		t.Errorf("NextCursor() unlimited = %d, want 0", got)
+	}
+}
+
+func TestMergeQueryResults(t *testing.T) {
+	stdout := []LogEntry{{Message: "out-5", Seq: 5}, {Message: "out-2", Seq: 2}}
+	stderr := []LogEntry{{Message: "err-4", Seq: 4}, {Message: "err-1", Seq: 1}}
+
+	merged := MergeQueryResults(LogQuery{Limit: 3}, stdout, stderr)
+	if len(merged) != 3 {
+		t.Fatalf("MergeQueryResults() returned %d entries, want 3", len(merged))
+	}
+	want := []string{"out-5", "err-4", "out-2"}
+	for i, msg := range want {
+		if merged[i].Message != msg {
+			t.Errorf("merged[%d] = %s, want %s", i, merged[i].Message, msg)
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package logger
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 )
 
+// globalLevel backs every handler created by New so the daemon log level
+// can be changed at runtime via SetLevel without rebuilding loggers.
+var globalLevel = new(slog.LevelVar)
+
 // New creates a new structured logger with the specified level and format
 func New(level, format string) *slog.Logger {
 	var logLevel slog.Level
@@ -20,9 +25,10 @@ func New(level, format string) *slog.Logger {
 	default:
 		logLevel = slog.LevelInfo
 	}
+	globalLevel.Set(logLevel)
 
 	opts := &slog.HandlerOptions{
-		Level: logLevel,
+		Level: globalLevel,
 	}
 
 	var handler slog.Handler
@@ -34,3 +40,33 @@ func New(level, format string) *slog.Logger {
 
 	return slog.New(handler)
 }
+
+// SetLevel changes the level of every logger created by New.
+// Accepts debug, info, warn or error.
+func SetLevel(level string) error {
+	parsed, err := parseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %w", err)
+	}
+	globalLevel.Set(parsed)
+	return nil
+}
+
+// GetLevel returns the current level of loggers created by New.
+func GetLevel() string {
+	return LevelName(globalLevel.Level())
+}
+
+// LevelName returns the lowercase name used in configuration for level.
+func LevelName(level slog.Level) string {
+	switch {
+	case level <= slog.LevelDebug:
+		return "debug"
+	case level <= slog.LevelInfo:
+		return "info"
+	case level <= slog.LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"testing"
 )
@@ -230,3 +231,46 @@ func TestNew_LoggerCanLog(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLevel(t *testing.T) {
+	defer func() { _ = SetLevel("info") }()
+
+	log := New("info", "text")
+	if log.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("debug should be disabled at info level")
+	}
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if !log.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("existing logger should pick up the new level")
+	}
+	if got := GetLevel(); got != "debug" {
+		t.Errorf("GetLevel() = %q, want debug", got)
+	}
+
+	if err := SetLevel("verbose"); err == nil {
+		t.Error("SetLevel(invalid) should fail")
+	}
+	if got := GetLevel(); got != "debug" {
+		t.Errorf("invalid SetLevel changed level to %q", got)
+	}
+}
+
+func TestLevelName(t *testing.T) {
+	tests := map[slog.Level]string{
+		slog.LevelDebug - 4: "debug",
+		slog.LevelDebug:     "debug",
+		slog.LevelInfo:      "info",
+		slog.LevelWarn:      "warn",
+		slog.LevelError:     "error",
+		slog.LevelError + 4: "error",
+	}
+
+	for level, want := range tests {
+		if got := LevelName(level); got != want {
+			t.Errorf("LevelName(%v) = %q, want %q", level, got, want)
+		}
+	}
+}
@@ -13,6 +13,8 @@ import (
 type MultilineBuffer struct {
 	enabled      bool
 	startPattern *regexp.Regexp
+	negate       bool           // startPattern matches continuation lines instead
+	endPattern   *regexp.Regexp // Completes the current entry immediately
 	maxLines     int
 	timeout      time.Duration
 
@@ -26,16 +28,41 @@ func NewMultilineBuffer(cfg *config.MultilineConfig) (*MultilineBuffer, error) {
 		return &MultilineBuffer{enabled: false}, nil
 	}
 
+	pattern, negate, end := cfg.Pattern, cfg.Negate, cfg.EndPattern
+	if cfg.Preset != "" {
+		preset, ok := multilinePresets[cfg.Preset]
+		if !ok {
+			return nil, fmt.Errorf("unknown multiline preset '%s' (available: %s)",
+				cfg.Preset, strings.Join(MultilinePresets(), ", "))
+		}
+		// Explicit pattern settings override the preset
+		if pattern == "" {
+			pattern, negate = preset.pattern, preset.negate
+		}
+		if end == "" {
+			end = preset.endPattern
+		}
+	}
+
 	// Compile start pattern
 	var startPattern *regexp.Regexp
 	var err error
-	if cfg.Pattern != "" {
-		startPattern, err = regexp.Compile(cfg.Pattern)
+	if pattern != "" {
+		startPattern, err = regexp.Compile(pattern)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile multiline pattern: %w", err)
 		}
 	}
 
+	// Compile end pattern
+	var endPattern *regexp.Regexp
+	if end != "" {
+		endPattern, err = regexp.Compile(end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile multiline end pattern: %w", err)
+		}
+	}
+
 	// Set defaults
 	maxLines := cfg.MaxLines
 	if maxLines == 0 {
@@ -50,6 +77,8 @@ func NewMultilineBuffer(cfg *config.MultilineConfig) (*MultilineBuffer, error) {
 	return &MultilineBuffer{
 		enabled:      true,
 		startPattern: startPattern,
+		negate:       negate,
+		endPattern:   endPattern,
 		maxLines:     maxLines,
 		timeout:      timeout,
 		lines:        make([]string, 0, 10),
@@ -64,6 +93,8 @@ func NewMultilineBuffer(cfg *config.MultilineConfig) (*MultilineBuffer, error) {
 // - If line matches startPattern and buffer is not empty: flush buffer, start new entry
 // - If line matches startPattern and buffer is empty: start new entry
 // - If line doesn't match startPattern: add to current buffer
+// - With negate, the match is inverted (startPattern identifies continuation lines)
+// - If line matches endPattern: flush buffer including this line
 // - If buffer exceeds maxLines: force flush
 // - If timeout exceeded: force flush (checked externally via ShouldFlush)
 func (mb *MultilineBuffer) Add(line string) (complete bool, entry string) {
@@ -72,13 +103,13 @@ func (mb *MultilineBuffer) Add(line string) (complete bool, entry string) {
 		return true, line
 	}
 
-	// Fast-path: no start pattern configured
-	if mb.startPattern == nil {
+	// Fast-path: no patterns configured
+	if mb.startPattern == nil && mb.endPattern == nil {
 		return true, line
 	}
 
 	// Check if this line starts a new log entry
-	isStart := mb.startPattern.MatchString(line)
+	isStart := mb.startPattern != nil && mb.startPattern.MatchString(line) != mb.negate
 
 	// If this is a new entry and we have buffered lines, flush the buffer
 	if isStart && len(mb.lines) > 0 {
@@ -97,8 +128,8 @@ func (mb *MultilineBuffer) Add(line string) (complete bool, entry string) {
 		mb.startTime = time.Now()
 	}
 
-	// Check if buffer exceeded max lines (force flush)
-	if len(mb.lines) >= mb.maxLines {
+	// End of entry reached, or buffer exceeded max lines (force flush)
+	if (mb.endPattern != nil && mb.endPattern.MatchString(line)) || len(mb.lines) >= mb.maxLines {
 		return true, mb.flush()
 	}
 
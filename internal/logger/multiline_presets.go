@@ -0,0 +1,60 @@
+package logger
+
+import "sort"
+
+// multilinePreset holds built-in multiline rules for a common log format.
+// With negate set, pattern matches continuation lines and any other line
+// starts a new entry.
+type multilinePreset struct {
+	pattern    string
+	negate     bool
+	endPattern string
+}
+
+// multilinePresets maps preset names to their multiline rules
+var multilinePresets = map[string]multilinePreset{
+	// PHP error_log / CLI output: "PHP Fatal error: Uncaught ..." followed by
+	// "Stack trace:", "#N ..." frames and a closing "thrown in ... on line N"
+	"php": {
+		pattern:    `^(?:Stack trace:|#\d+ |Next [\w\\]+|[ \t])`,
+		negate:     true,
+		endPattern: `^\s*thrown in .+ on line \d+\s*$`,
+	},
+	// Monolog via Laravel: "[2024-01-01 12:00:00] production.ERROR: ..."
+	// exceptions end with the closing "} of the JSON context
+	"laravel": {
+		pattern:    `^\[\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}`,
+		endPattern: `^"\}\s*$`,
+	},
+	// Monolog via Symfony: "[2024-01-01T12:00:00.000000+00:00] app.ERROR: ..."
+	// or console output "12:00:00 ERROR     [app] ..."
+	"symfony": {
+		pattern: `^(?:\[\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}[^\]]*\]|\d{2}:\d{2}:\d{2} [A-Z]+ +\[)`,
+	},
+	// Java-style traces: indented "at ..." frames, "... N more" and "Caused by:"
+	"java": {
+		pattern: `^(?:[ \t]|Caused by:|Suppressed:|\.\.\. \d+ )`,
+		negate:  true,
+	},
+	// Python tracebacks: indented frames, chained exception banners and the
+	// final "SomeError: message" line belong to the preceding entry
+	"python": {
+		pattern: `^(?:[ \t]|Traceback \(most recent call last\):|During handling of the above exception|The above exception was the direct cause|[A-Za-z_][\w.]*(?:Error|Exception|Exit|Interrupt|Warning)(?::|$))`,
+		negate:  true,
+	},
+	// Generic: indented lines continue the previous entry
+	"indented": {
+		pattern: `^[ \t]`,
+		negate:  true,
+	},
+}
+
+// MultilinePresets returns the names of the built-in multiline presets
+func MultilinePresets() []string {
+	names := make([]string, 0, len(multilinePresets))
+	for name := range multilinePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -469,3 +469,202 @@ func TestMultilineBuffer_IsEnabled(t *testing.T) {
 		})
 	}
 }
+
+// collectEntries feeds lines through the buffer and returns the emitted
+// entries, plus whatever is left after a final Flush
+func collectEntries(mb *MultilineBuffer, lines []string) (entries []string, remaining string) {
+	for _, line := range lines {
+		if complete, entry := mb.Add(line); complete && entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, mb.Flush()
+}
+
+func TestMultilineBuffer_Presets(t *testing.T) {
+	tests := []struct {
+		preset    string
+		lines     []string
+		entries   []string
+		remaining string
+	}{
+		{
+			preset: "php",
+			lines: []string{
+				"PHP Fatal error:  Uncaught RuntimeException: boom in /app/src/Job.php:12",
+				"Stack trace:",
+				"#0 /app/src/Worker.php(40): Job->run()",
+				"#1 {main}",
+				"  thrown in /app/src/Job.php on line 12",
+				"Worker started",
+			},
+			entries: []string{
+				"PHP Fatal error:  Uncaught RuntimeException: boom in /app/src/Job.php:12\nStack trace:\n#0 /app/src/Worker.php(40): Job->run()\n#1 {main}\n  thrown in /app/src/Job.php on line 12",
+			},
+			remaining: "Worker started",
+		},
+		{
+			preset: "laravel",
+			lines: []string{
+				"[2024-01-01 12:00:00] production.ERROR: Query failed {\"exception\":\"[object] (PDOException(code: 0): Query failed at /app/Db.php:5)",
+				"[stacktrace]",
+				"#0 /app/User.php(45): query()",
+				"#1 {main}",
+				"\"} ",
+				"[2024-01-01 12:00:01] production.INFO: Next request",
+			},
+			entries: []string{
+				"[2024-01-01 12:00:00] production.ERROR: Query failed {\"exception\":\"[object] (PDOException(code: 0): Query failed at /app/Db.php:5)\n[stacktrace]\n#0 /app/User.php(45): query()\n#1 {main}\n\"} ",
+			},
+			remaining: "[2024-01-01 12:00:01] production.INFO: Next request",
+		},
+		{
+			preset: "symfony",
+			lines: []string{
+				"[2024-01-01T12:00:00.123456+00:00] app.ERROR: Uncaught exception",
+				"#0 /app/src/Kernel.php(10): handle()",
+				"12:00:01 INFO      [app] Handled",
+			},
+			entries: []string{
+				"[2024-01-01T12:00:00.123456+00:00] app.ERROR: Uncaught exception\n#0 /app/src/Kernel.php(10): handle()",
+			},
+			remaining: "12:00:01 INFO      [app] Handled",
+		},
+		{
+			preset: "java",
+			lines: []string{
+				"Exception in thread \"main\" java.lang.IllegalStateException: bad",
+				"\tat com.example.App.run(App.java:10)",
+				"Caused by: java.io.IOException: disk",
+				"\t... 3 more",
+				"Started",
+			},
+			entries: []string{
+				"Exception in thread \"main\" java.lang.IllegalStateException: bad\n\tat com.example.App.run(App.java:10)\nCaused by: java.io.IOException: disk\n\t... 3 more",
+			},
+			remaining: "Started",
+		},
+		{
+			preset: "python",
+			lines: []string{
+				"ERROR Unhandled exception",
+				"Traceback (most recent call last):",
+				"  File \"app.py\", line 3, in <module>",
+				"    main()",
+				"ValueError: invalid literal",
+				"INFO Shutting down",
+			},
+			entries: []string{
+				"ERROR Unhandled exception\nTraceback (most recent call last):\n  File \"app.py\", line 3, in <module>\n    main()\nValueError: invalid literal",
+			},
+			remaining: "INFO Shutting down",
+		},
+		{
+			preset: "indented",
+			lines: []string{
+				"first entry",
+				"    continued",
+				"second entry",
+			},
+			entries:   []string{"first entry\n    continued"},
+			remaining: "second entry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			mb, err := NewMultilineBuffer(&config.MultilineConfig{Enabled: true, Preset: tt.preset})
+			if err != nil {
+				t.Fatalf("NewMultilineBuffer() error = %v", err)
+			}
+
+			entries, remaining := collectEntries(mb, tt.lines)
+			if len(entries) != len(tt.entries) {
+				t.Fatalf("got %d entries, want %d: %q", len(entries), len(tt.entries), entries)
+			}
+			for i := range entries {
+				if entries[i] != tt.entries[i] {
+					t.Errorf("entry %d = %q, want %q", i, entries[i], tt.entries[i])
+				}
+			}
+			if remaining != tt.remaining {
+				t.Errorf("remaining = %q, want %q", remaining, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestMultilineBuffer_EndPattern(t *testing.T) {
+	mb, err := NewMultilineBuffer(&config.MultilineConfig{
+		Enabled:    true,
+		Pattern:    `^BEGIN`,
+		EndPattern: `^END$`,
+	})
+	if err != nil {
+		t.Fatalf("NewMultilineBuffer() error = %v", err)
+	}
+
+	mb.Add("BEGIN")
+	mb.Add("body")
+	complete, entry := mb.Add("END")
+	if !complete || entry != "BEGIN\nbody\nEND" {
+		t.Errorf("Add(END) = (%v, %q), want complete entry", complete, entry)
+	}
+	if mb.BufferSize() != 0 {
+		t.Errorf("expected empty buffer after end pattern, got %d lines", mb.BufferSize())
+	}
+}
+
+func TestMultilineBuffer_Negate(t *testing.T) {
+	mb, err := NewMultilineBuffer(&config.MultilineConfig{
+		Enabled: true,
+		Pattern: `^\s`,
+		Negate:  true,
+	})
+	if err != nil {
+		t.Fatalf("NewMultilineBuffer() error = %v", err)
+	}
+
+	entries, remaining := collectEntries(mb, []string{"one", " a", " b", "two"})
+	if len(entries) != 1 || entries[0] != "one\n a\n b" || remaining != "two" {
+		t.Errorf("entries = %q, remaining = %q", entries, remaining)
+	}
+}
+
+func TestNewMultilineBuffer_PresetOverrides(t *testing.T) {
+	// Explicit pattern replaces the preset pattern but keeps its end pattern
+	mb, err := NewMultilineBuffer(&config.MultilineConfig{
+		Enabled: true,
+		Preset:  "laravel",
+		Pattern: `^\{`,
+	})
+	if err != nil {
+		t.Fatalf("NewMultilineBuffer() error = %v", err)
+	}
+	if mb.startPattern.String() != `^\{` || mb.negate {
+		t.Errorf("expected explicit start pattern, got %q (negate=%v)", mb.startPattern, mb.negate)
+	}
+	if mb.endPattern == nil || mb.endPattern.String() != multilinePresets["laravel"].endPattern {
+		t.Errorf("expected preset end pattern, got %v", mb.endPattern)
+	}
+}
+
+func TestNewMultilineBuffer_InvalidPresetAndEndPattern(t *testing.T) {
+	_, err := NewMultilineBuffer(&config.MultilineConfig{Enabled: true, Preset: "cobol"})
+	if err == nil || !strings.Contains(err.Error(), "unknown multiline preset") {
+		t.Errorf("expected unknown preset error, got %v", err)
+	}
+
+	_, err = NewMultilineBuffer(&config.MultilineConfig{Enabled: true, EndPattern: "[invalid"})
+	if err == nil || !strings.Contains(err.Error(), "end pattern") {
+		t.Errorf("expected end pattern compile error, got %v", err)
+	}
+}
+
+func TestMultilinePresets(t *testing.T) {
+	want := []string{"indented", "java", "laravel", "php", "python", "symfony"}
+	got := MultilinePresets()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("MultilinePresets() = %v, want %v", got, want)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gophpeek/phpeek-pm/internal/config"
@@ -27,11 +29,14 @@ type ProcessWriter struct {
 	multiline     *MultilineBuffer
 	jsonParser    *JSONParser
 	levelDetector *LevelDetector
-	filters       *LogFilters
+	filters       atomic.Pointer[LogFilters] // Swappable at runtime via SetFilters
 
 	// Log buffer for TUI/API access
 	logBuffer *LogBuffer
 
+	// Write and Flush may be called concurrently, e.g. when stdout and
+	// stderr share the writer
+	mu     sync.Mutex
 	buffer bytes.Buffer
 }
 
@@ -73,10 +78,11 @@ func NewProcessWriter(logger *slog.Logger, processName, instanceID, stream strin
 	}
 
 	// Initialize LogFilters
-	pw.filters, err = NewLogFilters(cfg.Filters, cfg.MinLevel)
+	filters, err := NewLogFilters(cfg.Filters, cfg.MinLevel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log filters: %w", err)
 	}
+	pw.filters.Store(filters)
 
 	return pw, nil
 }
@@ -84,6 +90,9 @@ func NewProcessWriter(logger *slog.Logger, processName, instanceID, stream strin
 // Write implements io.Writer
 // Processes incoming bytes through the full logging pipeline
 func (pw *ProcessWriter) Write(p []byte) (n int, err error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
 	pw.buffer.Write(p)
 
 	// Process complete lines
@@ -180,8 +189,8 @@ func (pw *ProcessWriter) processEntry(entry string) {
 	}
 
 	// Step 5: Filter check
-	if pw.filters != nil {
-		if !pw.filters.ShouldLog(entry, level) {
+	if filters := pw.filters.Load(); filters != nil {
+		if !filters.ShouldLog(entry, level) {
 			return // Drop log
 		}
 	}
@@ -234,6 +243,9 @@ func (pw *ProcessWriter) processEntry(entry string) {
 // Flush flushes any remaining buffered output
 // CRITICAL: Must be called when process exits to avoid losing buffered output
 func (pw *ProcessWriter) Flush() {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
 	// Flush incomplete line buffer
 	if pw.buffer.Len() > 0 {
 		line := pw.buffer.String()
@@ -267,6 +279,25 @@ func (pw *ProcessWriter) GetRecentLogs(n int) []LogEntry {
 	return pw.logBuffer.GetRecent(n)
 }
 
+// SetFilters atomically replaces the level and pattern filters applied to
+// subsequent entries. A nil value disables filtering entirely.
+func (pw *ProcessWriter) SetFilters(filters *LogFilters) {
+	pw.filters.Store(filters)
+}
+
+// Filters returns the filters currently applied by the writer (may be nil).
+func (pw *ProcessWriter) Filters() *LogFilters {
+	return pw.filters.Load()
+}
+
+// QueryLogs returns buffered entries matching q, newest first
+func (pw *ProcessWriter) QueryLogs(q LogQuery) []LogEntry {
+	if pw.logBuffer == nil {
+		return []LogEntry{}
+	}
+	return pw.logBuffer.Query(q)
+}
+
 // AddEvent adds a lifecycle event to the log buffer
 // Events use Level "event" to distinguish them from regular log output
 // These are rendered as dividers in the TUI log view
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"log/slog"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -87,7 +88,7 @@ func TestNewProcessWriter_WithConfig(t *testing.T) {
 	if pw.levelDetector == nil {
 		t.Error("levelDetector should be initialized")
 	}
-	if pw.filters == nil {
+	if pw.filters.Load() == nil {
 		t.Error("filters should be initialized")
 	}
 }
@@ -163,6 +164,40 @@ func TestProcessWriter_Write_MultipleLines(t *testing.T) {
 	}
 }
 
+func TestProcessWriter_Write_Concurrent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	pw, err := NewProcessWriter(logger, "test-process", "test-0", "stdout", nil)
+	if err != nil {
+		t.Fatalf("NewProcessWriter() error = %v", err)
+	}
+
+	// stdout and stderr of a scheduled run share one writer
+	var wg sync.WaitGroup
+	for _, stream := range []string{"out", "err"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, _ = pw.Write([]byte(stream + " line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	pw.Flush()
+
+	logs := pw.GetLogs()
+	if len(logs) != 200 {
+		t.Fatalf("expected 200 log entries, got %d", len(logs))
+	}
+	for _, entry := range logs {
+		if entry.Message != "out line" && entry.Message != "err line" {
+			t.Errorf("interleaved entry %q", entry.Message)
+		}
+	}
+}
+
 func TestProcessWriter_Write_PartialLine(t *testing.T) {
 	var buf bytes.Buffer
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
@@ -316,6 +351,39 @@ func TestProcessWriter_WithJSONParsing(t *testing.T) {
 	}
 }
 
+func TestProcessWriter_RedactsJSONKeysBeforeMerge(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	cfg := &config.LoggingConfig{
+		Redaction: &config.RedactionConfig{
+			Enabled: true,
+			Keys:    []string{"password"},
+		},
+		JSON: &config.JSONConfig{
+			Enabled:        true,
+			ExtractLevel:   true,
+			ExtractMessage: true,
+			MergeFields:    true,
+		},
+	}
+
+	pw, err := NewProcessWriter(logger, "test-process", "test-0", "stdout", cfg)
+	if err != nil {
+		t.Fatalf("NewProcessWriter() error = %v", err)
+	}
+
+	_, _ = pw.Write([]byte(`{"level":"error","message":"Login failed","password":"hunter2"}` + "\n"))
+
+	output := buf.String()
+	if strings.Contains(output, "hunter2") {
+		t.Errorf("password leaked into merged fields: %s", output)
+	}
+	if !strings.Contains(output, "password=***") {
+		t.Errorf("expected masked password attribute, got: %s", output)
+	}
+}
+
 func TestProcessWriter_WithLevelDetection(t *testing.T) {
 	var buf bytes.Buffer
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
@@ -396,6 +464,49 @@ func TestProcessWriter_WithFilters(t *testing.T) {
 	}
 }
 
+func TestProcessWriter_SetFilters(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	cfg := &config.LoggingConfig{
+		MinLevel:       "error",
+		LevelDetection: &config.LevelDetectionConfig{Enabled: true, DefaultLevel: "info"},
+	}
+
+	pw, err := NewProcessWriter(logger, "test-process", "test-0", "stdout", cfg)
+	if err != nil {
+		t.Fatalf("NewProcessWriter() error = %v", err)
+	}
+
+	_, _ = pw.Write([]byte("WARN slow query\n"))
+	if len(pw.GetLogs()) != 0 {
+		t.Fatal("warn entry should be dropped at min level error")
+	}
+
+	lowered, err := NewLogFilters(&config.FilterConfig{Exclude: []string{"noise"}}, "debug")
+	if err != nil {
+		t.Fatalf("NewLogFilters() error = %v", err)
+	}
+	pw.SetFilters(lowered)
+
+	if pw.Filters() != lowered {
+		t.Error("Filters() should return the swapped filters")
+	}
+
+	_, _ = pw.Write([]byte("WARN slow query\n"))
+	_, _ = pw.Write([]byte("WARN noise\n"))
+	if got := len(pw.GetLogs()); got != 1 {
+		t.Errorf("expected 1 entry after lowering level, got %d", got)
+	}
+
+	// nil disables filtering entirely
+	pw.SetFilters(nil)
+	_, _ = pw.Write([]byte("WARN noise\n"))
+	if got := len(pw.GetLogs()); got != 2 {
+		t.Errorf("expected 2 entries with filters disabled, got %d", got)
+	}
+}
+
 func TestProcessWriter_WithMultiline(t *testing.T) {
 	var buf bytes.Buffer
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
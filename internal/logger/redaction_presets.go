@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"net"
+	"sort"
+	"strings"
+)
+
+// redactionDetector is a built-in pattern used by a redaction preset.
+// prefix is a replacement template (e.g. "${1}") for capture groups kept in
+// front of the masked value; validate rejects regex matches that are not real
+// secrets (e.g. digit runs failing the Luhn check).
+type redactionDetector struct {
+	pattern  string
+	prefix   string
+	validate func(match string) bool
+}
+
+// redactionPresets maps preset names to their detectors
+var redactionPresets = map[string][]redactionDetector{
+	"credit_card": {
+		{pattern: `\b(?:\d[ -]?){12,18}\d\b`, validate: luhnValid},
+	},
+	"iban": {
+		{pattern: `\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`, validate: ibanValid},
+	},
+	"jwt": {
+		{pattern: `\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`},
+	},
+	"auth_header": {
+		{pattern: `(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`, prefix: "${1} "},
+	},
+	"aws_key": {
+		{pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+		{pattern: `(?i)(aws_secret_access_key["']?\s*[=:]\s*["']?)[A-Za-z0-9/+=]{40}`, prefix: "${1}"},
+	},
+	"stripe_key": {
+		{pattern: `\b(?:sk|pk|rk)_(?:live|test)_[0-9A-Za-z]{16,}\b`},
+		{pattern: `\bwhsec_[0-9A-Za-z]{32,}\b`},
+	},
+	"github_token": {
+		{pattern: `\bgh[pousr]_[0-9A-Za-z]{36,}\b`},
+		{pattern: `\bgithub_pat_[0-9A-Za-z_]{22,}\b`},
+	},
+	"email": {
+		{pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`},
+	},
+	"ipv4": {
+		{pattern: `\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`},
+	},
+	"ipv6": {
+		{pattern: `(?i)(?:\b[0-9a-f]{1,4}(?::[0-9a-f]{0,4}){2,7}|::[0-9a-f]{1,4}(?::[0-9a-f]{1,4}){0,6})\b`, validate: ipv6Valid},
+	},
+	"laravel_app_key": {
+		{pattern: `\b(APP_KEY\s*[=:]\s*["']?)?base64:[A-Za-z0-9+/]{43}=`, prefix: "${1}"},
+	},
+}
+
+// RedactionPresets returns the names of the built-in redaction presets
+func RedactionPresets() []string {
+	names := make([]string, 0, len(redactionPresets))
+	for name := range redactionPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+// used by payment card numbers
+func luhnValid(s string) bool {
+	sum := 0
+	digits := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+		double = !double
+	}
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// ibanValid reports whether s is an IBAN with a valid ISO 13616 mod-97 checksum
+func ibanValid(s string) bool {
+	iban := strings.ReplaceAll(s, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Move country code and check digits to the end, map letters to 10..35
+	// and compute the remainder digit by digit to avoid big integers
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// ipv6Valid reports whether s is an IPv6 address. Matches without any digit
+// are rejected to avoid masking hex-only identifiers such as "Face::add".
+func ipv6Valid(s string) bool {
+	if !strings.ContainsAny(s, "0123456789") {
+		return false
+	}
+	ip := net.ParseIP(s)
+	return ip != nil && strings.Contains(s, ":")
+}
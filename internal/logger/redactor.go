@@ -1,16 +1,21 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/gophpeek/phpeek-pm/internal/config"
 )
 
 // Redactor handles sensitive data redaction for compliance
 type Redactor struct {
-	enabled  bool
-	patterns []*compiledPattern
+	enabled        bool
+	patterns       []*compiledPattern
+	keys           map[string]struct{} // Lower-cased JSON keys to mask
+	keyReplacement string
 }
 
 // compiledPattern represents a pre-compiled redaction pattern
@@ -18,6 +23,7 @@ type compiledPattern struct {
 	name        string
 	regex       *regexp.Regexp
 	replacement string
+	validate    func(match string) bool // Optional check applied to each match
 }
 
 // NewRedactor creates a new Redactor from configuration
@@ -27,7 +33,28 @@ func NewRedactor(cfg *config.RedactionConfig) (*Redactor, error) {
 		return &Redactor{enabled: false}, nil
 	}
 
-	patterns := make([]*compiledPattern, 0, len(cfg.Patterns))
+	defaultReplacement := cfg.Replacement
+	if defaultReplacement == "" {
+		defaultReplacement = "***"
+	}
+
+	patterns := make([]*compiledPattern, 0, len(cfg.Presets)+len(cfg.Patterns))
+	for _, name := range cfg.Presets {
+		detectors, ok := redactionPresets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction preset '%s' (available: %s)",
+				name, strings.Join(RedactionPresets(), ", "))
+		}
+		for _, d := range detectors {
+			patterns = append(patterns, &compiledPattern{
+				name:        name,
+				regex:       regexp.MustCompile(d.pattern),
+				replacement: d.prefix + defaultReplacement,
+				validate:    d.validate,
+			})
+		}
+	}
+
 	for _, p := range cfg.Patterns {
 		// Validate pattern fields
 		if p.Pattern == "" {
@@ -43,7 +70,7 @@ func NewRedactor(cfg *config.RedactionConfig) (*Redactor, error) {
 		// Default replacement if not specified
 		replacement := p.Replacement
 		if replacement == "" {
-			replacement = "***"
+			replacement = defaultReplacement
 		}
 
 		patterns = append(patterns, &compiledPattern{
@@ -53,9 +80,22 @@ func NewRedactor(cfg *config.RedactionConfig) (*Redactor, error) {
 		})
 	}
 
+	var keys map[string]struct{}
+	if len(cfg.Keys) > 0 {
+		keys = make(map[string]struct{}, len(cfg.Keys))
+		for _, key := range cfg.Keys {
+			if key == "" {
+				return nil, fmt.Errorf("redaction key must not be empty")
+			}
+			keys[strings.ToLower(key)] = struct{}{}
+		}
+	}
+
 	return &Redactor{
-		enabled:  true,
-		patterns: patterns,
+		enabled:        true,
+		patterns:       patterns,
+		keys:           keys,
+		keyReplacement: defaultReplacement,
 	}, nil
 }
 
@@ -68,20 +108,90 @@ func (r *Redactor) Redact(input string) string {
 		return input
 	}
 
-	// Fast-path: no patterns configured
-	if len(r.patterns) == 0 {
+	// Fast-path: nothing configured
+	if len(r.patterns) == 0 && len(r.keys) == 0 {
 		return input
 	}
 
+	// Mask sensitive JSON fields first, before JSONParser merges them as attributes
+	result := r.redactJSONKeys(input)
+
 	// Apply each pattern sequentially
-	result := input
 	for _, p := range r.patterns {
-		result = p.regex.ReplaceAllString(result, p.replacement)
+		if p.validate == nil {
+			result = p.regex.ReplaceAllString(result, p.replacement)
+			continue
+		}
+		result = p.regex.ReplaceAllStringFunc(result, func(match string) string {
+			if p.validate(match) {
+				return p.replacement
+			}
+			return match
+		})
 	}
 
 	return result
 }
 
+// redactJSONKeys masks the values of configured keys at any depth of a JSON
+// object log line. Non-JSON input and lines without matching keys are
+// returned unchanged, preserving their original formatting.
+func (r *Redactor) redactJSONKeys(input string) string {
+	if len(r.keys) == 0 {
+		return input
+	}
+
+	trimmed := strings.TrimSpace(input)
+	if !strings.HasPrefix(trimmed, "{") {
+		return input
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber() // Keep numbers exactly as logged
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return input
+	}
+
+	if !r.redactValue(data) {
+		return input
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return input
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// redactValue walks decoded JSON, masking values of configured keys.
+// Returns true if anything was masked.
+func (r *Redactor) redactValue(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if _, ok := r.keys[strings.ToLower(key)]; ok {
+				v[key] = r.keyReplacement
+				changed = true
+				continue
+			}
+			if r.redactValue(child) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if r.redactValue(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
 // IsEnabled returns whether redaction is enabled
 func (r *Redactor) IsEnabled() bool {
 	return r.enabled
@@ -197,6 +197,38 @@ var (
 		[]string{"process", "instance"},
 	)
 
+	// Cgroup metrics (see internal/cgroup)
+	CgroupMemoryEvents = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "phpeek_pm_cgroup_memory_events",
+			Help: "Cgroup memory event counters reported by the kernel",
+		},
+		[]string{"event"}, // event: low, high, max, oom, oom_kill
+	)
+
+	CgroupPressure = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "phpeek_pm_cgroup_pressure_percent",
+			Help: "Pressure stall information: percentage of time tasks were stalled",
+		},
+		[]string{"resource", "kind", "window"}, // resource: memory, cpu; kind: some, full; window: avg10, avg60, avg300
+	)
+
+	CgroupUnderPressure = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "phpeek_pm_cgroup_under_pressure",
+			Help: "Pressure is above the configured thresholds (1=yes, 0=no)",
+		},
+	)
+
+	ProcessOOMKills = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "phpeek_pm_process_oom_kills_total",
+			Help: "Total number of process instances killed by the kernel OOM killer",
+		},
+		[]string{"name"},
+	)
+
 	// Build info
 	BuildInfo = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -256,6 +288,30 @@ func RecordHookExecution(hookName, hookType string, duration float64, success bo
 	HookDuration.WithLabelValues(hookName, hookType).Observe(duration)
 }
 
+// RecordProcessOOMKill records an instance killed by the kernel OOM killer
+func RecordProcessOOMKill(processName string) {
+	ProcessOOMKills.WithLabelValues(processName).Inc()
+}
+
+// SetCgroupMemoryEvent sets a cgroup memory event counter
+func SetCgroupMemoryEvent(event string, count uint64) {
+	CgroupMemoryEvents.WithLabelValues(event).Set(float64(count))
+}
+
+// SetCgroupPressure sets a pressure stall percentage
+func SetCgroupPressure(resource, kind, window string, percent float64) {
+	CgroupPressure.WithLabelValues(resource, kind, window).Set(percent)
+}
+
+// SetCgroupUnderPressure sets whether pressure is above the configured thresholds
+func SetCgroupUnderPressure(high bool) {
+	value := 0.0
+	if high {
+		value = 1.0
+	}
+	CgroupUnderPressure.Set(value)
+}
+
 // SetDesiredScale sets the desired process scale
 func SetDesiredScale(processName string, scale int) {
 	ProcessDesiredScale.WithLabelValues(processName).Set(float64(scale))
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -54,6 +55,34 @@ func CollectProcessMetrics(pid int, processName, instanceID string) (*ResourceSa
 	return sample, nil
 }
 
+// CollectChildrenRSS returns the resident memory of each direct child of pid
+// (e.g., php-fpm workers forked by the master process)
+func CollectChildrenRSS(pid int) ([]uint64, error) {
+	if pid <= 0 || pid > 0x7FFFFFFF {
+		return nil, fmt.Errorf("invalid PID: %d", pid)
+	}
+	proc, err := process.NewProcess(int32(pid)) // #nosec G115 -- bounds checked above
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := proc.Children()
+	if err != nil {
+		if errors.Is(err, process.ErrorNoChildren) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rss := make([]uint64, 0, len(children))
+	for _, child := range children {
+		if memInfo, err := child.MemoryInfo(); err == nil && memInfo.RSS > 0 {
+			rss = append(rss, memInfo.RSS)
+		}
+	}
+	return rss, nil
+}
+
 // UpdatePrometheusMetrics updates Prometheus gauges with resource sample
 func UpdatePrometheusMetrics(processName, instanceID string, sample *ResourceSample) {
 	ProcessCPUPercent.WithLabelValues(processName, instanceID).Set(sample.CPUPercent)
@@ -70,6 +99,11 @@ func UpdatePrometheusMetrics(processName, instanceID string, sample *ResourceSam
 	}
 }
 
+// ChildMemoryRecorder receives RSS samples of an instance's child processes
+type ChildMemoryRecorder interface {
+	RecordWorkerRSS(rss []uint64)
+}
+
 // ResourceCollector manages resource metric collection
 type ResourceCollector struct {
 	interval   time.Duration
@@ -77,6 +111,9 @@ type ResourceCollector struct {
 	buffers    map[string]*TimeSeriesBuffer // key: "process-instance"
 	mu         sync.RWMutex
 	logger     *slog.Logger
+
+	// Per-process recorders for child process memory (e.g., php-fpm workers)
+	childRecorders map[string]ChildMemoryRecorder
 }
 
 // NewResourceCollector creates a new resource collector
@@ -86,9 +123,27 @@ func NewResourceCollector(interval time.Duration, maxSamples int, logger *slog.L
 		maxSamples: maxSamples,
 		buffers:    make(map[string]*TimeSeriesBuffer),
 		logger:     logger.With("component", "resource_collector"),
+
+		childRecorders: make(map[string]ChildMemoryRecorder),
 	}
 }
 
+// WatchChildren enables child process memory sampling for a process;
+// every collection passes the children's RSS to recorder
+func (rc *ResourceCollector) WatchChildren(processName string, recorder ChildMemoryRecorder) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.childRecorders[processName] = recorder
+}
+
+// ChildRecorder returns the child memory recorder for a process, if any
+func (rc *ResourceCollector) ChildRecorder(processName string) (ChildMemoryRecorder, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	recorder, ok := rc.childRecorders[processName]
+	return recorder, ok
+}
+
 // GetHistory returns time series for a process instance
 func (rc *ResourceCollector) GetHistory(processName, instanceID string, since time.Time, limit int) []ResourceSample {
 	rc.mu.RLock()
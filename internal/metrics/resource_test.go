@@ -3,6 +3,7 @@ package metrics
 import (
 	"log/slog"
 	"os"
+	"os/exec"
 	"testing"
 	"time"
 
@@ -622,3 +623,62 @@ func TestCollectProcessMetrics_WithRealProcess(t *testing.T) {
 		t.Error("Expected non-zero thread count")
 	}
 }
+
+// childRecorderFunc adapts a function to ChildMemoryRecorder
+type childRecorderFunc func(rss []uint64)
+
+func (f childRecorderFunc) RecordWorkerRSS(rss []uint64) { f(rss) }
+
+// TestResourceCollector_WatchChildren tests registering child memory recorders
+func TestResourceCollector_WatchChildren(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	rc := NewResourceCollector(5*time.Second, 100, logger)
+
+	if _, ok := rc.ChildRecorder("php-fpm"); ok {
+		t.Fatal("expected no recorder before WatchChildren")
+	}
+
+	var recorded []uint64
+	rc.WatchChildren("php-fpm", childRecorderFunc(func(rss []uint64) { recorded = rss }))
+
+	recorder, ok := rc.ChildRecorder("php-fpm")
+	if !ok {
+		t.Fatal("expected recorder after WatchChildren")
+	}
+	recorder.RecordWorkerRSS([]uint64{42})
+	if len(recorded) != 1 || recorded[0] != 42 {
+		t.Errorf("recorder not called, got %v", recorded)
+	}
+	if _, ok := rc.ChildRecorder("nginx"); ok {
+		t.Error("recorder should only be registered for php-fpm")
+	}
+}
+
+// TestCollectChildrenRSS tests collecting memory of child processes
+func TestCollectChildrenRSS(t *testing.T) {
+	if _, err := CollectChildrenRSS(-1); err == nil {
+		t.Error("expected error for invalid pid")
+	}
+
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start child process: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	rss, err := CollectChildrenRSS(os.Getpid())
+	if err != nil {
+		t.Fatalf("CollectChildrenRSS() error = %v", err)
+	}
+	if len(rss) == 0 {
+		t.Fatal("expected at least one child")
+	}
+	for _, v := range rss {
+		if v == 0 {
+			t.Error("expected non-zero RSS")
+		}
+	}
+}
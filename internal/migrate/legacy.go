@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// legacyBackoffMaxFactor matches the restart_backoff_max derived from the
+// legacy restart_backoff (see Config.SetDefaults)
+const legacyBackoffMaxFactor = 12
+
+// migrateLegacy rewrites the legacy settings of a phpeek-pm config in place,
+// keeping comments and key order:
+//
+//	global.restart_backoff: 5   -> restart_backoff_initial: 5s, restart_backoff_max: 1m0s
+//	processes.*.stdout: false   -> processes.*.logging.stdout: false
+//	processes.*.stderr: false   -> processes.*.logging.stderr: false
+//
+// Profiles are rewritten the same way. Keys that match no setting are
+// reported as unmapped.
+func migrateLegacy(name string, content []byte) (*Result, error) {
+	result := &Result{Format: FormatPHPeek}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", name, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+	root := doc.Content[0]
+
+	migrateLegacyTree(root, "", result)
+	if profiles, _ := mappingValue(root, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(profiles.Content); i += 2 {
+			migrateLegacyTree(profiles.Content[i+1], "profiles."+profiles.Content[i].Value+".", result)
+		}
+	}
+
+	out, err := encodeNode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	result.YAML = out
+
+	unknown, err := config.FindUnknownKeysIn(name, out)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range unknown {
+		if key.Suggestion != "" {
+			result.unmapped(key.Path, "unknown key, did you mean %q?", key.Suggestion)
+		} else {
+			result.unmapped(key.Path, "unknown key, ignored by phpeek-pm")
+		}
+	}
+	return result, nil
+}
+
+// migrateLegacyTree rewrites the legacy settings of a config or profile
+func migrateLegacyTree(root *yaml.Node, prefix string, result *Result) {
+	if global, _ := mappingValue(root, "global"); global != nil {
+		migrateRestartBackoff(global, prefix+"global.restart_backoff", result)
+	}
+
+	processes, _ := mappingValue(root, "processes")
+	if processes == nil || processes.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(processes.Content); i += 2 {
+		proc := processes.Content[i+1]
+		if proc.Kind != yaml.MappingNode {
+			continue
+		}
+		path := prefix + "processes." + processes.Content[i].Value
+		for _, stream := range []string{"stdout", "stderr"} {
+			migrateStream(proc, stream, path, result)
+		}
+	}
+}
+
+// migrateRestartBackoff replaces restart_backoff seconds with the durations
+// it implies
+func migrateRestartBackoff(global *yaml.Node, path string, result *Result) {
+	value, index := mappingValue(global, "restart_backoff")
+	if value == nil {
+		return
+	}
+	seconds, err := strconv.Atoi(value.Value)
+	if err != nil || seconds <= 0 {
+		result.unmapped(path, "%q is not a number of seconds, set restart_backoff_initial and restart_backoff_max instead", value.Value)
+		return
+	}
+
+	// The replacements take the place and comments of the legacy key
+	key := global.Content[index]
+	global.Content = append(global.Content[:index], global.Content[index+2:]...)
+	comment := key.HeadComment
+
+	backoff := time.Duration(seconds) * time.Second
+	for _, setting := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"restart_backoff_initial", backoff},
+		{"restart_backoff_max", backoff * legacyBackoffMaxFactor},
+	} {
+		if existing, _ := mappingValue(global, setting.key); existing != nil {
+			result.note(path, "removed, %s is already set", setting.key)
+			continue
+		}
+		newKey := stringNode(setting.key)
+		newKey.HeadComment, comment = comment, ""
+		global.Content = append(global.Content[:index], append([]*yaml.Node{newKey, stringNode(setting.value.String())}, global.Content[index:]...)...)
+		index += 2
+		result.note(path, "replaced by %s: %s", setting.key, setting.value)
+	}
+}
+
+// migrateStream moves a process stdout/stderr shorthand under logging
+func migrateStream(proc *yaml.Node, stream, path string, result *Result) {
+	value, _ := mappingValue(proc, stream)
+	if value == nil {
+		return
+	}
+	removeKey(proc, stream)
+
+	logging, _ := mappingValue(proc, "logging")
+	if logging == nil || logging.Kind != yaml.MappingNode {
+		logging = &yaml.Node{Kind: yaml.MappingNode}
+		setKey(proc, "logging", logging)
+	}
+	setKey(logging, stream, value)
+	result.note(path+"."+stream, "moved to logging.%s", stream)
+}
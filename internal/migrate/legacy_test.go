@@ -0,0 +1,164 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+const legacyConfig = `version: "1.0"
+global:
+  # Back off 10s between restarts
+  restart_backoff: 10
+  log_level: info
+processes:
+  app:
+    command: ["php-fpm", "-F"]
+    stdout: false # too noisy
+    logging:
+      min_level: warn
+  worker:
+    command: ["php", "artisan", "queue:work"]
+    stderr: false
+    helth_check:
+      type: tcp
+profiles:
+  production:
+    processes:
+      app:
+        stdout: true
+`
+
+// loadConfig writes content to a temporary config file and loads it
+func loadConfig(t *testing.T, content []byte, profile string) *config.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "phpeek-pm.yaml")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadWithOptions(path, config.LoadOptions{Profile: profile})
+	if err != nil {
+		t.Fatalf("migrated config does not load: %v\n%s", err, content)
+	}
+	return cfg
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	result, err := Migrate("phpeek-pm.yaml", []byte(legacyConfig), "")
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.Format != FormatPHPeek {
+		t.Errorf("Format = %s, want %s", result.Format, FormatPHPeek)
+	}
+	out := string(result.YAML)
+
+	for _, want := range []string{
+		"# Back off 10s between restarts\n  restart_backoff_initial: 10s\n  restart_backoff_max: 2m0s\n",
+		"min_level: warn\n      stdout: false # too noisy\n",
+		"logging:\n      stderr: false\n",
+		"app:\n        logging:\n          stdout: true\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("migrated config missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "restart_backoff:") || strings.Contains(out, "\n    stderr:") {
+		t.Errorf("legacy settings left in:\n%s", out)
+	}
+
+	// The typo is reported, not silently kept
+	unmapped := result.Unmapped()
+	if len(unmapped) != 1 || unmapped[0].Source != "processes.worker.helth_check" || !strings.Contains(unmapped[0].Message, `"health_check"`) {
+		t.Errorf("Unmapped() = %v", unmapped)
+	}
+	if len(result.Notes) != 6 {
+		t.Errorf("Notes = %v, want 5 changes and 1 unmapped key", result.Notes)
+	}
+}
+
+func TestMigrateLegacy_SameEffectiveConfig(t *testing.T) {
+	result, err := Migrate("phpeek-pm.yaml", []byte(legacyConfig), FormatPHPeek)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	for _, profile := range []string{"", "production"} {
+		before := loadConfig(t, []byte(legacyConfig), profile)
+		after := loadConfig(t, result.YAML, profile)
+
+		if before.Global.RestartBackoffInitial != after.Global.RestartBackoffInitial ||
+			before.Global.RestartBackoffMax != after.Global.RestartBackoffMax {
+			t.Errorf("profile %q: backoff %s/%s, want %s/%s", profile,
+				after.Global.RestartBackoffInitial, after.Global.RestartBackoffMax,
+				before.Global.RestartBackoffInitial, before.Global.RestartBackoffMax)
+		}
+		for name, proc := range before.Processes {
+			got := after.Processes[name].Logging
+			if got.Stdout != proc.Logging.Stdout || got.Stderr != proc.Logging.Stderr || got.MinLevel != proc.Logging.MinLevel {
+				t.Errorf("profile %q: %s logging = %+v, want %+v", profile, name, got, proc.Logging)
+			}
+		}
+	}
+}
+
+func TestMigrateLegacy_Nothing(t *testing.T) {
+	content := "processes:\n  app:\n    command: [\"php-fpm\"]\n"
+	result, err := Migrate("phpeek-pm.yaml", []byte(content), FormatPHPeek)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(result.Notes) != 0 || string(result.YAML) != content {
+		t.Errorf("current config changed: notes = %v\n%s", result.Notes, result.YAML)
+	}
+}
+
+func TestMigrateLegacy_RestartBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		global   string
+		want     []string
+		unmapped bool
+	}{
+		{
+			name:   "new settings win",
+			global: "  restart_backoff: 5\n  restart_backoff_max: 30s\n",
+			want:   []string{"restart_backoff_initial: 5s", "restart_backoff_max: 30s"},
+		},
+		{
+			name:     "env reference kept",
+			global:   "  restart_backoff: ${BACKOFF}\n",
+			want:     []string{"restart_backoff: ${BACKOFF}"},
+			unmapped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "global:\n" + tt.global + "processes:\n  app:\n    command: [\"php-fpm\"]\n"
+			result, err := Migrate("phpeek-pm.yaml", []byte(content), FormatPHPeek)
+			if err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(result.YAML), want) {
+					t.Errorf("missing %q:\n%s", want, result.YAML)
+				}
+			}
+			if got := len(result.Unmapped()) > 0; got != tt.unmapped {
+				t.Errorf("unmapped = %v, want %v (%v)", got, tt.unmapped, result.Notes)
+			}
+		})
+	}
+}
+
+func TestMigrateLegacy_Errors(t *testing.T) {
+	if _, err := Migrate("phpeek-pm.yaml", []byte("global: ["), FormatPHPeek); err == nil {
+		t.Error("expected parse error")
+	}
+	if _, err := Migrate("phpeek-pm.yaml", nil, FormatPHPeek); err == nil {
+		t.Error("expected error for empty file")
+	}
+}
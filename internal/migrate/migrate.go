@@ -0,0 +1,259 @@
+// Package migrate converts legacy phpeek-pm configs, supervisord configs and
+// Procfiles to the current configuration format.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the format of a file to migrate
+type Format string
+
+const (
+	FormatPHPeek      Format = "phpeek-pm"
+	FormatSupervisord Format = "supervisord"
+	FormatProcfile    Format = "procfile"
+)
+
+// Formats returns the supported input formats
+func Formats() []string {
+	return []string{string(FormatPHPeek), string(FormatSupervisord), string(FormatProcfile)}
+}
+
+// configVersion is the version written to converted configs
+const configVersion = "1.0"
+
+// Note reports a setting that was rewritten or could not be mapped
+type Note struct {
+	Source   string `json:"source"`   // Where the setting was found, e.g. "[program:queue] startsecs"
+	Message  string `json:"message"`  // What happened to it
+	Unmapped bool   `json:"unmapped"` // The setting was dropped
+}
+
+func (n Note) String() string {
+	return n.Source + ": " + n.Message
+}
+
+// Result is a migrated configuration
+type Result struct {
+	Format Format `json:"format"` // Input format
+	YAML   []byte `json:"-"`      // Migrated phpeek-pm config
+	Notes  []Note `json:"notes"`  // Changes made, in input order
+}
+
+// Unmapped returns the notes for settings that were dropped
+func (r *Result) Unmapped() []Note {
+	var notes []Note
+	for _, note := range r.Notes {
+		if note.Unmapped {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+func (r *Result) note(source, format string, args ...interface{}) {
+	r.Notes = append(r.Notes, Note{Source: source, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *Result) unmapped(source, format string, args ...interface{}) {
+	r.Notes = append(r.Notes, Note{Source: source, Message: fmt.Sprintf(format, args...), Unmapped: true})
+}
+
+var (
+	supervisordSection = regexp.MustCompile(`(?m)^\s*\[(program|supervisord|group|include|eventlistener|fcgi-program)(:[^\]]+)?\]`)
+	procfileLine       = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+)
+
+// DetectFormat guesses the format of a file from its name and content
+func DetectFormat(name string, content []byte) Format {
+	base := strings.ToLower(filepath.Base(name))
+	switch {
+	case strings.HasPrefix(base, "procfile"):
+		return FormatProcfile
+	case strings.HasSuffix(base, ".yaml"), strings.HasSuffix(base, ".yml"):
+		return FormatPHPeek
+	case supervisordSection.Match(content):
+		return FormatSupervisord
+	}
+
+	// A Procfile is only "name: command" lines, which is also valid YAML
+	procfile := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !procfileLine.MatchString(line) || strings.HasPrefix(line, "processes:") || strings.HasPrefix(line, "global:") {
+			return FormatPHPeek
+		}
+		procfile = true
+	}
+	if procfile {
+		return FormatProcfile
+	}
+	return FormatPHPeek
+}
+
+// Migrate converts content to the current config format. The format is
+// detected from name and content when empty.
+func Migrate(name string, content []byte, format Format) (*Result, error) {
+	if format == "" {
+		format = DetectFormat(name, content)
+	}
+	switch format {
+	case FormatPHPeek:
+		return migrateLegacy(name, content)
+	case FormatSupervisord:
+		return convertSupervisord(name, content)
+	case FormatProcfile:
+		return convertProcfile(content)
+	default:
+		return nil, fmt.Errorf("unknown format %q (must be one of: %s)", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// program is a process converted from another process manager
+type program struct {
+	name    string
+	process *config.Process
+}
+
+// encodeConfig writes converted processes as a config file, in input order
+// and without zero-valued settings
+func encodeConfig(global map[string]interface{}, programs []program) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	appendPair(root, "version", &yaml.Node{Kind: yaml.ScalarNode, Value: configVersion, Style: yaml.DoubleQuotedStyle})
+
+	if len(global) > 0 {
+		node := &yaml.Node{}
+		if err := node.Encode(global); err != nil {
+			return nil, err
+		}
+		appendPair(root, "global", node)
+	}
+
+	processes := &yaml.Node{Kind: yaml.MappingNode}
+	for _, p := range programs {
+		node := &yaml.Node{}
+		if err := node.Encode(p.process); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", p.name, err)
+		}
+		compactNode(node)
+		if command, _ := mappingValue(node, "command"); command != nil {
+			command.Style = yaml.FlowStyle
+		}
+		// Converters only set the streams. Unset streams default to enabled,
+		// so a disabled stream must be written as false.
+		removeKey(node, "logging")
+		if logging := p.process.Logging; logging != nil && (!logging.Stdout || !logging.Stderr) {
+			streams := &yaml.Node{Kind: yaml.MappingNode}
+			appendPair(streams, "stdout", boolNode(logging.Stdout))
+			appendPair(streams, "stderr", boolNode(logging.Stderr))
+			appendPair(node, "logging", streams)
+		}
+		appendPair(processes, p.name, node)
+	}
+	appendPair(root, "processes", processes)
+
+	return encodeNode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}})
+}
+
+func encodeNode(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compactNode removes null, zero and empty values from a mapping, recursively
+func compactNode(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		value := node.Content[i+1]
+		compactNode(value)
+		if isZeroNode(value) {
+			continue
+		}
+		content = append(content, node.Content[i], value)
+	}
+	node.Content = content
+}
+
+func isZeroNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		return len(node.Content) == 0
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return true
+		case "!!bool":
+			return node.Value == "false"
+		case "!!int", "!!float":
+			return node.Value == "0"
+		case "!!str":
+			return node.Value == "" || node.Value == "0s" // Zero time.Duration
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value of key in a mapping node and its index in
+// Content, or nil and -1
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, int) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1], i
+		}
+	}
+	return nil, -1
+}
+
+// removeKey deletes key from a mapping node
+func removeKey(node *yaml.Node, key string) {
+	if _, i := mappingValue(node, key); i >= 0 {
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+	}
+}
+
+// setKey sets key in a mapping node, replacing any existing value
+func setKey(node *yaml.Node, key string, value *yaml.Node) {
+	if _, i := mappingValue(node, key); i >= 0 {
+		node.Content[i+1] = value
+		return
+	}
+	appendPair(node, key, value)
+}
+
+func appendPair(node *yaml.Node, key string, value *yaml.Node) {
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func boolNode(v bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+}
+
+func stringNode(v string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+}
@@ -0,0 +1,60 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    Format
+	}{
+		{name: "procfile by name", file: "/app/Procfile.dev", content: "web: php -S 0.0.0.0:80", want: FormatProcfile},
+		{name: "yaml by extension", file: "phpeek-pm.yml", content: "web: php", want: FormatPHPeek},
+		{name: "supervisord sections", file: "app.conf", content: "; comment\n[program:queue]\ncommand=php artisan queue:work\n", want: FormatSupervisord},
+		{name: "procfile content", file: "processes", content: "# dev\nweb: php -S 0.0.0.0:80\nworker: php artisan queue:work\n", want: FormatProcfile},
+		{name: "config content", file: "config", content: "version: \"1.0\"\nprocesses:\n  app:\n    command: [php-fpm]\n", want: FormatPHPeek},
+		{name: "empty", file: "config", content: "", want: FormatPHPeek},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.file, []byte(tt.content)); got != tt.want {
+				t.Errorf("DetectFormat() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrate_UnknownFormat(t *testing.T) {
+	_, err := Migrate("app.conf", []byte("x"), "circus")
+	if err == nil || !strings.Contains(err.Error(), "supervisord") {
+		t.Errorf("Migrate() error = %v, want unknown format listing the formats", err)
+	}
+}
+
+func TestResult_Unmapped(t *testing.T) {
+	result := &Result{}
+	result.note("a", "moved")
+	result.unmapped("b", "dropped %d", 1)
+
+	unmapped := result.Unmapped()
+	if len(unmapped) != 1 || unmapped[0].String() != "b: dropped 1" {
+		t.Errorf("Unmapped() = %v", unmapped)
+	}
+}
+
+func TestEncodeConfig_Compact(t *testing.T) {
+	out, err := encodeConfig(nil, []program{{name: "app", process: &config.Process{Enabled: true, Command: []string{"sleep", "60"}}}})
+	if err != nil {
+		t.Fatalf("encodeConfig() error = %v", err)
+	}
+	want := "version: \"1.0\"\nprocesses:\n  app:\n    enabled: true\n    command: [sleep, \"60\"]\n"
+	if string(out) != want {
+		t.Errorf("encodeConfig() =\n%s\nwant\n%s", out, want)
+	}
+}
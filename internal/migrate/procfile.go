@@ -0,0 +1,128 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// procfileRelease is the Heroku process type run once per deploy
+const procfileRelease = "release"
+
+// shellMeta are characters that need a shell to mean what they do in a
+// Procfile, where commands run through sh -c
+const shellMeta = "$`|&;<>*?(){}~!"
+
+// convertProcfile converts "name: command" lines to processes. Commands that
+// use shell syntax (variables, pipes, &&) run through sh -c like foreman does.
+func convertProcfile(content []byte) (*Result, error) {
+	result := &Result{Format: FormatProcfile}
+	var programs []program
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		source := fmt.Sprintf("line %d", lineNo)
+
+		m := procfileLine.FindStringSubmatch(line)
+		if m == nil {
+			result.unmapped(source, "expected \"name: command\", got %q", line)
+			continue
+		}
+		name, command := m[1], strings.TrimSpace(m[2])
+		if seen[name] {
+			result.unmapped(source, "duplicate process %s, the first definition is kept", name)
+			continue
+		}
+		seen[name] = true
+
+		proc := &config.Process{Enabled: true}
+		if strings.ContainsAny(command, shellMeta) {
+			proc.Command = []string{"sh", "-c", command}
+		} else {
+			args, err := splitCommand(command)
+			if err != nil {
+				result.unmapped(source, "%v", err)
+				continue
+			}
+			proc.Command = args
+		}
+		if name == procfileRelease {
+			proc.Type = "oneshot"
+			proc.Restart = "never"
+			result.note(source, "release converted to a oneshot process, make other processes depend_on it to run it first")
+		}
+		programs = append(programs, program{name: name, process: proc})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(programs) == 0 {
+		return nil, fmt.Errorf("no processes found")
+	}
+
+	var err error
+	result.YAML, err = encodeConfig(nil, programs)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// splitCommand splits a command line into arguments, honouring single and
+// double quotes and backslash escapes
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command %q", command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
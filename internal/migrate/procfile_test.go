@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const procfile = `# Heroku processes
+web: vendor/bin/heroku-php-apache2 public/
+worker: php artisan queue:work --tries=3 && echo done
+release: php artisan migrate --force
+clock: php artisan schedule:work "quoted arg"
+web: duplicate
+not a process line
+`
+
+func TestConvertProcfile(t *testing.T) {
+	result, err := Migrate("Procfile", []byte(procfile), "")
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.Format != FormatProcfile {
+		t.Fatalf("Format = %s, want %s", result.Format, FormatProcfile)
+	}
+
+	cfg := loadConfig(t, result.YAML, "")
+	commands := map[string][]string{
+		"web":     {"vendor/bin/heroku-php-apache2", "public/"},
+		"worker":  {"sh", "-c", "php artisan queue:work --tries=3 && echo done"},
+		"release": {"php", "artisan", "migrate", "--force"},
+		"clock":   {"php", "artisan", "schedule:work", "quoted arg"},
+	}
+	for name, want := range commands {
+		proc := cfg.Processes[name]
+		if proc == nil || !proc.Enabled || !reflect.DeepEqual(proc.Command, want) {
+			t.Errorf("%s = %+v, want command %q", name, proc, want)
+		}
+	}
+	if release := cfg.Processes["release"]; release.Type != "oneshot" || release.Restart != "never" {
+		t.Errorf("release type = %s, restart = %s, want a oneshot", release.Type, release.Restart)
+	}
+
+	// Processes keep the Procfile order
+	if order := strings.Index(string(result.YAML), "web:") < strings.Index(string(result.YAML), "clock:"); !order {
+		t.Errorf("processes out of order:\n%s", result.YAML)
+	}
+
+	unmapped := result.Unmapped()
+	if len(unmapped) != 2 || unmapped[0].Source != "line 6" || unmapped[1].Source != "line 7" {
+		t.Errorf("Unmapped() = %v, want the duplicate and invalid lines", unmapped)
+	}
+}
+
+func TestConvertProcfile_Empty(t *testing.T) {
+	if _, err := Migrate("Procfile", []byte("# nothing\n"), FormatProcfile); err == nil {
+		t.Error("expected an error for a Procfile without processes")
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "php artisan serve", want: []string{"php", "artisan", "serve"}},
+		{in: `  sh -c 'echo "hi"'  `, want: []string{"sh", "-c", `echo "hi"`}},
+		{in: `echo "a \"b\"" c\ d`, want: []string{"echo", `a "b"`, "c d"}},
+		{in: `echo ""`, want: []string{"echo", ""}},
+		{in: `echo 'unterminated`, wantErr: true},
+		{in: "   ", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitCommand(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
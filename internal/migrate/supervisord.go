@@ -0,0 +1,380 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// iniSection is a [section] of a supervisord config with its keys in order
+type iniSection struct {
+	name string
+	keys []string
+	vals map[string]string
+}
+
+func (s *iniSection) get(key string) (string, bool) {
+	v, ok := s.vals[key]
+	return v, ok
+}
+
+// parseINI parses the supervisord flavour of INI: "key = value" or
+// "key: value", ";" and "#" comments (inline after whitespace) and indented
+// continuation lines
+func parseINI(content []byte) ([]*iniSection, error) {
+	var sections []*iniSection
+	var current *iniSection
+	var lastKey string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(stripINIComment(raw))
+		if line == "" {
+			continue
+		}
+
+		if raw[0] == ' ' || raw[0] == '\t' {
+			if current == nil || lastKey == "" {
+				return nil, fmt.Errorf("line %d: continuation line without a key", lineNo)
+			}
+			current.vals[lastKey] += "\n" + line
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = &iniSection{name: strings.TrimSpace(line[1 : len(line)-1]), vals: make(map[string]string)}
+			sections = append(sections, current)
+			lastKey = ""
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", lineNo, line)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: key outside of a section", lineNo)
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:idx]))
+		if _, ok := current.vals[key]; !ok {
+			current.keys = append(current.keys, key)
+		}
+		current.vals[key] = strings.TrimSpace(line[idx+1:])
+		lastKey = key
+	}
+	return sections, scanner.Err()
+}
+
+func stripINIComment(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") {
+		return ""
+	}
+	for i := 1; i < len(line); i++ {
+		if line[i] == ';' && (line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// supervisordIgnored are program settings with no effect under phpeek-pm,
+// either because their supervisord default is what phpeek-pm does or because
+// they configure supervisord's own log files
+var supervisordIgnored = map[string]bool{
+	"process_name":            true,
+	"redirect_stderr":         true,
+	"stdout_logfile_maxbytes": true,
+	"stdout_logfile_backups":  true,
+	"stderr_logfile_maxbytes": true,
+	"stderr_logfile_backups":  true,
+	"stopasgroup":             true,
+	"killasgroup":             true,
+}
+
+// supervisordExpansion matches %(name)s style expansions
+var supervisordExpansion = regexp.MustCompile(`%\(([A-Za-z0-9_]+)\)[-#0 +]*[0-9]*[sd]`)
+
+// supervisordLogLevels maps supervisord log levels to phpeek-pm levels
+var supervisordLogLevels = map[string]string{
+	"critical": "error",
+	"error":    "error",
+	"warn":     "warn",
+	"info":     "info",
+	"debug":    "debug",
+	"trace":    "debug",
+	"blather":  "debug",
+}
+
+// convertSupervisord converts the [program:x] sections of a supervisord
+// config to processes
+func convertSupervisord(name string, content []byte) (*Result, error) {
+	sections, err := parseINI(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	result := &Result{Format: FormatSupervisord}
+	here := filepath.Dir(name)
+
+	var global map[string]interface{}
+	var globalEnv map[string]string
+	var programs []program
+
+	for _, section := range sections {
+		kind, sectionName, _ := strings.Cut(section.name, ":")
+		source := "[" + section.name + "]"
+
+		switch kind {
+		case "program":
+			proc := convertProgram(section, sectionName, here, result)
+			programs = append(programs, program{name: sectionName, process: proc})
+		case "supervisord":
+			if level, ok := section.get("loglevel"); ok {
+				if mapped, ok := supervisordLogLevels[strings.ToLower(level)]; ok {
+					global = map[string]interface{}{"log_level": mapped}
+				} else {
+					result.unmapped(source+" loglevel", "unknown level %q", level)
+				}
+			}
+			if env, ok := section.get("environment"); ok {
+				globalEnv, err = parseSupervisordEnv(expandSupervisord(env, "", here, source, result))
+				if err != nil {
+					return nil, fmt.Errorf("%s environment: %w", source, err)
+				}
+				result.note(source+" environment", "added to the env of every process")
+			}
+		case "group":
+			result.note(source, "groups are not supported, its programs are converted individually")
+		case "include":
+			files, _ := section.get("files")
+			result.unmapped(source, "included files %s are not converted, migrate each of them", files)
+		case "eventlistener", "fcgi-program":
+			result.unmapped(source, "%s sections are not supported", kind)
+		case "unix_http_server", "inet_http_server", "supervisorctl":
+			result.note(source, "not needed, use the phpeek-pm API and TUI to control processes")
+		default:
+			if !strings.HasPrefix(kind, "rpcinterface") {
+				result.unmapped(source, "unknown section")
+			}
+		}
+	}
+
+	if len(programs) == 0 {
+		return nil, fmt.Errorf("%s has no [program:x] sections", name)
+	}
+	for _, p := range programs {
+		for key, value := range globalEnv {
+			if _, ok := p.process.Env[key]; ok {
+				continue
+			}
+			if p.process.Env == nil {
+				p.process.Env = make(map[string]string)
+			}
+			p.process.Env[key] = value
+		}
+	}
+
+	result.YAML, err = encodeConfig(global, programs)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// convertProgram converts a [program:x] section
+func convertProgram(section *iniSection, name, here string, result *Result) *config.Process {
+	source := "[" + section.name + "]"
+	proc := &config.Process{
+		Enabled: true,
+		Restart: "on-failure", // supervisord default: autorestart=unexpected
+	}
+
+	var priority string
+	for _, key := range section.keys {
+		if supervisordIgnored[key] {
+			continue
+		}
+		keySource := source + " " + key
+		value := expandSupervisord(section.vals[key], name, here, keySource, result)
+
+		switch key {
+		case "command":
+			command, err := splitCommand(value)
+			if err != nil {
+				result.unmapped(keySource, "%v", err)
+				continue
+			}
+			proc.Command = command
+		case "autostart":
+			if !parseSupervisordBool(value) {
+				proc.InitialState = "stopped"
+			}
+		case "autorestart":
+			switch strings.ToLower(value) {
+			case "true":
+				proc.Restart = "always"
+			case "false":
+				proc.Restart = "never"
+			case "unexpected":
+				proc.Restart = "on-failure"
+			default:
+				result.unmapped(keySource, "unknown value %q", value)
+			}
+		case "numprocs":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				result.unmapped(keySource, "invalid number %q", value)
+				continue
+			}
+			if n > 1 {
+				proc.Scale = n
+			}
+		case "user":
+			proc.User = value
+		case "directory":
+			proc.WorkingDir = value
+		case "environment":
+			env, err := parseSupervisordEnv(value)
+			if err != nil {
+				result.unmapped(keySource, "%v", err)
+				continue
+			}
+			proc.Env = env
+		case "stopsignal":
+			shutdown(proc).Signal = supervisordSignal(value)
+		case "stopwaitsecs":
+			secs, err := strconv.Atoi(value)
+			if err != nil {
+				result.unmapped(keySource, "invalid number %q", value)
+				continue
+			}
+			shutdown(proc).Timeout = secs
+		case "stdout_logfile", "stderr_logfile":
+			convertLogfile(proc, key, value, keySource, result)
+		case "priority":
+			priority = value
+		case "startsecs", "startretries":
+			result.unmapped(keySource, "not supported, phpeek-pm restarts with exponential backoff (see global.max_restart_attempts and global.restart_backoff_initial)")
+		case "exitcodes":
+			result.unmapped(keySource, "not supported, any non-zero exit is a failure")
+		default:
+			result.unmapped(keySource, "not supported")
+		}
+	}
+
+	if len(proc.Command) == 0 {
+		result.unmapped(source, "no command, set processes.%s.command", name)
+	}
+	if priority != "" {
+		result.unmapped(source+" priority", "start order is not converted, use depends_on to order processes")
+	}
+	return proc
+}
+
+func shutdown(proc *config.Process) *config.ShutdownConfig {
+	if proc.Shutdown == nil {
+		proc.Shutdown = &config.ShutdownConfig{}
+	}
+	return proc.Shutdown
+}
+
+// convertLogfile maps stdout_logfile and stderr_logfile. Output sent to the
+// container log or discarded maps to logging, files do not.
+func convertLogfile(proc *config.Process, key, value, source string, result *Result) {
+	enabled := true
+	switch value {
+	case "/dev/stdout", "/dev/stderr", "/dev/fd/1", "/dev/fd/2", "/proc/self/fd/1", "/proc/self/fd/2":
+	case "NONE":
+		enabled = false
+	default:
+		result.unmapped(source, "log file %s is not supported, phpeek-pm writes process output to its own log", value)
+		return
+	}
+
+	if proc.Logging == nil {
+		proc.Logging = &config.LoggingConfig{Stdout: true, Stderr: true}
+	}
+	if key == "stdout_logfile" {
+		proc.Logging.Stdout = enabled
+	} else {
+		proc.Logging.Stderr = enabled
+	}
+}
+
+// expandSupervisord replaces %(name)s expansions: ENV_X becomes ${X},
+// program_name and here are substituted, others are reported
+func expandSupervisord(value, program, here, source string, result *Result) string {
+	return supervisordExpansion.ReplaceAllStringFunc(value, func(match string) string {
+		name := supervisordExpansion.FindStringSubmatch(match)[1]
+		switch {
+		case strings.HasPrefix(name, "ENV_"):
+			return "${" + strings.TrimPrefix(name, "ENV_") + "}"
+		case name == "program_name" && program != "":
+			return program
+		case name == "here":
+			return here
+		default:
+			result.unmapped(source, "%s has no equivalent and was kept as is", match)
+			return match
+		}
+	})
+}
+
+// parseSupervisordEnv parses KEY="value",KEY2=value2
+func parseSupervisordEnv(value string) (map[string]string, error) {
+	env := make(map[string]string)
+	rest := strings.TrimSpace(strings.ReplaceAll(value, "\n", ","))
+	for rest != "" {
+		key, after, ok := strings.Cut(rest, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid environment %q", value)
+		}
+
+		var val string
+		after = strings.TrimLeft(after, " \t")
+		if after != "" && (after[0] == '"' || after[0] == '\'') {
+			end := strings.IndexByte(after[1:], after[0])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in environment %q", value)
+			}
+			val = after[1 : end+1]
+			after = after[end+2:]
+			if i := strings.IndexByte(after, ','); i >= 0 {
+				after = after[i:]
+			}
+		} else if i := strings.IndexByte(after, ','); i >= 0 {
+			val, after = strings.TrimSpace(after[:i]), after[i:]
+		} else {
+			val, after = strings.TrimSpace(after), ""
+		}
+
+		env[key] = val
+		rest = strings.TrimLeft(after, " \t,")
+	}
+	return env, nil
+}
+
+func parseSupervisordBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// supervisordSignal converts TERM to SIGTERM
+func supervisordSignal(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if strings.HasPrefix(value, "SIG") {
+		return value
+	}
+	return "SIG" + value
+}
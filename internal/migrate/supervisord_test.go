@@ -0,0 +1,182 @@
+package migrate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const supervisordConfig = `[supervisord]
+nodaemon=true
+loglevel=warn
+environment=APP_ENV="production",TZ=UTC
+
+[unix_http_server]
+file=/var/run/supervisor.sock
+
+[rpcinterface:supervisor]
+supervisor.rpcinterface_factory = supervisor.rpcinterface:make_main_rpcinterface
+
+[program:php-fpm]
+command=php-fpm -F
+autorestart=true
+stdout_logfile=/dev/stdout
+stdout_logfile_maxbytes=0
+redirect_stderr=true
+priority=5
+
+[program:queue]
+process_name=%(program_name)s_%(process_num)02d
+command=php %(here)s/artisan queue:work --sleep=3 ; inline comment
+numprocs=4
+user=www-data
+directory=/var/www
+environment=QUEUE="default,high",
+    REDIS_HOST=%(ENV_REDIS_HOST)s
+stopsignal=QUIT
+stopwaitsecs=60
+startsecs=1
+stdout_logfile=/var/log/queue.log
+stderr_logfile=NONE
+autostart=false
+
+[eventlistener:memmon]
+command=memmon -a 200MB
+`
+
+func TestConvertSupervisord(t *testing.T) {
+	result, err := Migrate("/etc/supervisor/supervisord.conf", []byte(supervisordConfig), "")
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.Format != FormatSupervisord {
+		t.Fatalf("Format = %s, want %s", result.Format, FormatSupervisord)
+	}
+
+	cfg := loadConfig(t, result.YAML, "")
+	if cfg.Global.LogLevel != "warn" {
+		t.Errorf("log_level = %s, want warn", cfg.Global.LogLevel)
+	}
+
+	fpm := cfg.Processes["php-fpm"]
+	if fpm == nil || !fpm.Enabled || fpm.Restart != "always" || !reflect.DeepEqual(fpm.Command, []string{"php-fpm", "-F"}) {
+		t.Fatalf("php-fpm = %+v", fpm)
+	}
+	if fpm.Env["APP_ENV"] != "production" || fpm.Env["TZ"] != "UTC" {
+		t.Errorf("php-fpm env = %v, want [supervisord] environment", fpm.Env)
+	}
+
+	t.Setenv("REDIS_HOST", "redis")
+	cfg = loadConfig(t, result.YAML, "")
+	queue := cfg.Processes["queue"]
+	want := []string{"php", "/etc/supervisor/artisan", "queue:work", "--sleep=3"}
+	if !reflect.DeepEqual(queue.Command, want) {
+		t.Errorf("queue command = %q, want %q", queue.Command, want)
+	}
+	if queue.Scale != 4 || queue.User != "www-data" || queue.WorkingDir != "/var/www" || queue.InitialState != "stopped" || queue.Restart != "on-failure" {
+		t.Errorf("queue = %+v", queue)
+	}
+	wantEnv := map[string]string{"APP_ENV": "production", "TZ": "UTC", "QUEUE": "default,high", "REDIS_HOST": "redis"}
+	if !reflect.DeepEqual(queue.Env, wantEnv) {
+		t.Errorf("queue env = %v, want %v", queue.Env, wantEnv)
+	}
+	if queue.Shutdown.Signal != "SIGQUIT" || queue.Shutdown.Timeout != 60 {
+		t.Errorf("queue shutdown = %+v", queue.Shutdown)
+	}
+	if !queue.Logging.Stdout || queue.Logging.Stderr {
+		t.Errorf("queue logging stdout = %v, stderr = %v, want stderr discarded", queue.Logging.Stdout, queue.Logging.Stderr)
+	}
+
+	var unmapped []string
+	for _, note := range result.Unmapped() {
+		unmapped = append(unmapped, note.Source)
+	}
+	wantUnmapped := []string{
+		"[program:php-fpm] priority",
+		"[program:queue] startsecs",
+		"[program:queue] stdout_logfile",
+		"[eventlistener:memmon]",
+	}
+	if !reflect.DeepEqual(unmapped, wantUnmapped) {
+		t.Errorf("unmapped = %q, want %q", unmapped, wantUnmapped)
+	}
+}
+
+func TestConvertSupervisord_Errors(t *testing.T) {
+	tests := map[string]string{
+		"no programs":       "[supervisord]\nnodaemon=true\n",
+		"key before header": "command=sleep 60\n[program:a]\n",
+		"no separator":      "[program:a]\ncommand\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Migrate("app.conf", []byte(content), FormatSupervisord); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestConvertProgram_Unmapped(t *testing.T) {
+	content := `[program:app]
+command=sleep "60
+autorestart=sometimes
+numprocs=many
+exitcodes=0,2
+umask=022
+`
+	result, err := Migrate("app.conf", []byte(content), FormatSupervisord)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	var got []string
+	for _, note := range result.Unmapped() {
+		got = append(got, note.String())
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{
+		"[program:app] command: unterminated quote",
+		`[program:app] autorestart: unknown value "sometimes"`,
+		`[program:app] numprocs: invalid number "many"`,
+		"[program:app] exitcodes: not supported",
+		"[program:app] umask: not supported",
+		"[program:app]: no command",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("unmapped notes missing %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestParseSupervisordEnv(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		{in: `A="1",B=2`, want: map[string]string{"A": "1", "B": "2"}},
+		{in: `A='x,y', B = "z" `, want: map[string]string{"A": "x,y", "B": "z"}},
+		{in: "A=1,\nB=2", want: map[string]string{"A": "1", "B": "2"}},
+		{in: `A=`, want: map[string]string{"A": ""}},
+		{in: `A="1`, wantErr: true},
+		{in: `=1`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSupervisordEnv(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSupervisordEnv(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSupervisordEnv(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSupervisordSignal(t *testing.T) {
+	for in, want := range map[string]string{"TERM": "SIGTERM", "quit": "SIGQUIT", "SIGINT": "SIGINT"} {
+		if got := supervisordSignal(in); got != want {
+			t.Errorf("supervisordSignal(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
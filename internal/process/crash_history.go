@@ -0,0 +1,110 @@
+package process
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/logger"
+)
+
+const (
+	// DefaultCrashContextLines is the number of log lines captured with each crash.
+	DefaultCrashContextLines = 50
+
+	// DefaultCrashHistorySize is the number of recent crashes kept per process.
+	DefaultCrashHistorySize = 10
+)
+
+// CrashRecord captures an unexpected exit of a process instance together with
+// the last lines it wrote to stdout/stderr, which usually contain the stack
+// trace or error message explaining the crash.
+type CrashRecord struct {
+	ProcessName  string            `json:"process_name"`
+	InstanceID   string            `json:"instance_id"`
+	PID          int               `json:"pid"`
+	ExitCode     int               `json:"exit_code"`
+	Signal       string            `json:"signal,omitempty"`
+	OOMKilled    bool              `json:"oom_killed,omitempty"` // Killed by the kernel OOM killer
+	Error        string            `json:"error,omitempty"`
+	RestartCount int               `json:"restart_count"`
+	CrashedAt    time.Time         `json:"crashed_at"`
+	LastLines    []logger.LogEntry `json:"last_lines"` // Oldest first
+}
+
+// CrashHistory keeps the most recent crashes of a single process.
+// It is a bounded, thread-safe store; the oldest record is evicted once
+// maxEntries is reached.
+type CrashHistory struct {
+	maxEntries int
+	entries    []CrashRecord
+	mu         sync.RWMutex
+}
+
+// NewCrashHistory creates a crash history retaining up to maxEntries records
+// (default: DefaultCrashHistorySize if <= 0).
+func NewCrashHistory(maxEntries int) *CrashHistory {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCrashHistorySize
+	}
+	return &CrashHistory{
+		maxEntries: maxEntries,
+		entries:    make([]CrashRecord, 0, maxEntries),
+	}
+}
+
+// Record adds a crash record, evicting the oldest one when full
+func (h *CrashHistory) Record(record CrashRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, record)
+	if len(h.entries) > h.maxEntries {
+		h.entries = h.entries[len(h.entries)-h.maxEntries:]
+	}
+}
+
+// GetRecent returns up to limit crashes, newest first (limit <= 0 = all)
+func (h *CrashHistory) GetRecent(limit int) []CrashRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := len(h.entries)
+	if limit > 0 && count > limit {
+		count = limit
+	}
+
+	result := make([]CrashRecord, count)
+	for i, j := len(h.entries)-1, 0; j < count; i, j = i-1, j+1 {
+		result[j] = h.entries[i]
+	}
+	return result
+}
+
+// Len returns the number of stored crash records
+func (h *CrashHistory) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.entries)
+}
+
+// snapshotLastLines returns the last n entries written by the given writers,
+// merged into chronological order (oldest first).
+func snapshotLastLines(n int, writers ...*logger.ProcessWriter) []logger.LogEntry {
+	if n <= 0 {
+		return nil
+	}
+
+	var results [][]logger.LogEntry
+	for _, w := range writers {
+		if w != nil {
+			results = append(results, w.GetRecentLogs(n))
+		}
+	}
+
+	// MergeQueryResults yields newest first capped at n; reverse for reading order
+	lines := logger.MergeQueryResults(logger.LogQuery{Limit: n}, results...)
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
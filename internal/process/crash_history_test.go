@@ -0,0 +1,80 @@
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/gophpeek/phpeek-pm/internal/logger"
+)
+
+func TestCrashHistory_RecordAndEvict(t *testing.T) {
+	h := NewCrashHistory(3)
+
+	for i := 0; i < 5; i++ {
+		h.Record(CrashRecord{ProcessName: "worker", ExitCode: i})
+	}
+
+	if h.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", h.Len())
+	}
+
+	recent := h.GetRecent(0)
+	if len(recent) != 3 {
+		t.Fatalf("GetRecent(0) returned %d records, want 3", len(recent))
+	}
+	for i, want := range []int{4, 3, 2} {
+		if recent[i].ExitCode != want {
+			t.Errorf("recent[%d].ExitCode = %d, want %d", i, recent[i].ExitCode, want)
+		}
+	}
+
+	if got := h.GetRecent(1); len(got) != 1 || got[0].ExitCode != 4 {
+		t.Errorf("GetRecent(1) = %+v, want newest crash only", got)
+	}
+}
+
+func TestNewCrashHistory_Defaults(t *testing.T) {
+	h := NewCrashHistory(0)
+	if h.maxEntries != DefaultCrashHistorySize {
+		t.Errorf("maxEntries = %d, want %d", h.maxEntries, DefaultCrashHistorySize)
+	}
+	if got := h.GetRecent(5); len(got) != 0 {
+		t.Errorf("GetRecent() on empty history returned %d records", len(got))
+	}
+}
+
+func TestSnapshotLastLines(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	stdout, err := logger.NewProcessWriter(log, "worker", "worker-0", "stdout", nil)
+	if err != nil {
+		t.Fatalf("NewProcessWriter() error = %v", err)
+	}
+	stderr, err := logger.NewProcessWriter(log, "worker", "worker-0", "stderr", nil)
+	if err != nil {
+		t.Fatalf("NewProcessWriter() error = %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, _ = stdout.Write([]byte(fmt.Sprintf("line %d\n", i)))
+	}
+	_, _ = stderr.Write([]byte("fatal error\n"))
+
+	lines := snapshotLastLines(3, stdout, nil, stderr)
+	if len(lines) != 3 {
+		t.Fatalf("snapshotLastLines() returned %d lines, want 3", len(lines))
+	}
+
+	want := []string{"line 3", "line 4", "fatal error"}
+	for i, entry := range lines {
+		if entry.Message != want[i] {
+			t.Errorf("lines[%d] = %q, want %q", i, entry.Message, want[i])
+		}
+	}
+
+	if got := snapshotLastLines(0, stdout); got != nil {
+		t.Errorf("snapshotLastLines(0) = %v, want nil", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gophpeek/phpeek-pm/internal/audit"
+	"github.com/gophpeek/phpeek-pm/internal/cgroup"
 	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/metrics"
 	"github.com/gophpeek/phpeek-pm/internal/readiness"
@@ -45,7 +46,8 @@ const (
 //	defer manager.Shutdown(ctx)
 type Manager struct {
 	config            *config.Config
-	configPath        string // Path to config file for saving
+	configPath        string          // Path to config file for saving
+	configTransform   ConfigTransform // Applied to configs loaded by ReloadConfig
 	logger            *slog.Logger
 	auditLogger       *audit.Logger
 	processes         map[string]*Supervisor
@@ -62,10 +64,27 @@ type Manager struct {
 	processDeathCh    chan string
 	startTime         time.Time
 
+	// Runtime log overrides (see manager_logging.go)
+	logOverrides     map[string]*logOverride // Process name -> per-process override
+	logLevelOverride *logOverride            // Daemon log level override (nil = configured)
+	logOverridesMu   sync.Mutex
+
+	// Adaptive auto-tuning (see manager_autotune.go)
+	autotune *autotuneLearning
+
+	// Cgroup pressure and OOM watcher (see manager_cgroup.go, nil = disabled)
+	cgroupWatcher *cgroup.Watcher
+
+	// Leader lock of scheduled jobs (see manager_schedule.go, nil = disabled)
+	scheduleLock    schedule.Locker
+	scheduleLockErr error // Why the lock could not be created
+
 	// Configurable timeouts and limits (initialized from global config or defaults)
-	dependencyTimeout  time.Duration
-	processStopTimeout time.Duration
-	maxProcessScale    int
+	dependencyTimeout   time.Duration
+	processStartTimeout time.Duration
+	processStopTimeout  time.Duration
+	processParallelism  int // Max processes started/stopped at once per level (0 = unlimited)
+	maxProcessScale     int
 }
 
 // NewManager creates a new Manager with the provided configuration.
@@ -120,6 +139,11 @@ func NewManager(cfg *config.Config, logger *slog.Logger, auditLogger *audit.Logg
 		dependencyTimeout = cfg.Global.DependencyTimeout
 	}
 
+	processStartTimeout := DefaultProcessStartTimeout
+	if cfg.Global.ProcessStartTimeout > 0 {
+		processStartTimeout = cfg.Global.ProcessStartTimeout
+	}
+
 	processStopTimeout := DefaultProcessStopTimeout
 	if cfg.Global.ProcessStopTimeout > 0 {
 		processStopTimeout = cfg.Global.ProcessStopTimeout
@@ -130,24 +154,32 @@ func NewManager(cfg *config.Config, logger *slog.Logger, auditLogger *audit.Logg
 		maxProcessScale = cfg.Global.MaxProcessScale
 	}
 
-	return &Manager{
-		config:             cfg,
-		logger:             logger,
-		auditLogger:        auditLogger,
-		processes:          make(map[string]*Supervisor),
-		scheduler:          scheduler,
-		scheduleExecutor:   scheduleExecutor,
-		resourceCollector:  resourceCollector,
-		oneshotHistory:     oneshotHistory,
-		readinessManager:   readinessMgr,
-		shutdownCh:         make(chan struct{}),
-		allDeadCh:          make(chan struct{}),
-		processDeathCh:     make(chan string, 10),
-		startTime:          startTime,
-		dependencyTimeout:  dependencyTimeout,
-		processStopTimeout: processStopTimeout,
-		maxProcessScale:    maxProcessScale,
+	m := &Manager{
+		config:              cfg,
+		logger:              logger,
+		auditLogger:         auditLogger,
+		processes:           make(map[string]*Supervisor),
+		scheduler:           scheduler,
+		scheduleExecutor:    scheduleExecutor,
+		resourceCollector:   resourceCollector,
+		oneshotHistory:      oneshotHistory,
+		readinessManager:    readinessMgr,
+		shutdownCh:          make(chan struct{}),
+		allDeadCh:           make(chan struct{}),
+		processDeathCh:      make(chan string, 10),
+		startTime:           startTime,
+		dependencyTimeout:   dependencyTimeout,
+		processStartTimeout: processStartTimeout,
+		processStopTimeout:  processStopTimeout,
+		processParallelism:  cfg.Global.ProcessParallelism,
+		maxProcessScale:     maxProcessScale,
+		logOverrides:        make(map[string]*logOverride),
 	}
+	m.initAutotuneLearning()
+	m.initCgroupWatch()
+	m.initScheduleLock()
+	m.maskAuditSecrets()
+	return m
 }
 
 // ProcessInfo represents process status information returned by ListProcesses.
@@ -164,13 +196,16 @@ type ProcessInfo struct {
 	Instances      []ProcessInstanceInfo `json:"instances"`
 	// Schedule fields (only for scheduled processes)
 	Schedule      string `json:"schedule,omitempty"`       // Cron expression
-	ScheduleState string `json:"schedule_state,omitempty"` // idle | executing | paused
+	ScheduleState string `json:"schedule_state,omitempty"` // idle | executing | paused | disabled
 	NextRun       int64  `json:"next_run,omitempty"`       // Unix timestamp of next scheduled run
 	LastRun       int64  `json:"last_run,omitempty"`       // Unix timestamp of last run
 	// Execution history fields (only for scheduled processes)
 	RunCount     int     `json:"run_count,omitempty"`      // Total execution count
 	SuccessRate  float64 `json:"success_rate,omitempty"`   // Success rate (0-100)
 	LastExitCode *int    `json:"last_exit_code,omitempty"` // Exit code of last execution (nil if never run)
+	// Failure handling fields (only for scheduled processes)
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"` // Failed runs in a row
+	DisableAfter        int `json:"disable_after,omitempty"`        // Failed runs in a row that disable the schedule
 }
 
 // ProcessInstanceInfo represents instance status within a process.
@@ -270,13 +305,15 @@ func (m *Manager) ListProcesses() []ProcessInfo {
 				state = "running"
 			case "paused":
 				state = "paused"
+			case "disabled":
+				state = "disabled"
 			default:
 				state = status.State
 			}
 
 			// Get last exit code from history
 			var lastExitCode *int
-			if lastEntry, ok := job.History.GetLast(); ok && !lastEntry.EndTime.IsZero() {
+			if lastEntry, ok := job.History.GetLast(); ok && !lastEntry.EndTime.IsZero() && !lastEntry.Skipped {
 				exitCode := lastEntry.ExitCode
 				lastExitCode = &exitCode
 			}
@@ -296,6 +333,9 @@ func (m *Manager) ListProcesses() []ProcessInfo {
 				RunCount:      status.Stats.TotalExecutions,
 				SuccessRate:   status.Stats.SuccessRate,
 				LastExitCode:  lastExitCode,
+
+				ConsecutiveFailures: status.ConsecutiveFailures,
+				DisableAfter:        status.DisableAfter,
 			}
 			processes = append(processes, info)
 		}
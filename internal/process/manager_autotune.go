@@ -0,0 +1,111 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/autotune"
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// autotuneLearningSaveInterval is how often observed worker memory is persisted
+const autotuneLearningSaveInterval = time.Minute
+
+// autotuneLearning tracks observed php-fpm worker memory and the tuning
+// result it is compared against.
+type autotuneLearning struct {
+	cfg      *config.LearningConfig
+	observer *autotune.WorkerMemoryObserver
+
+	mu      sync.RWMutex
+	calc    *autotune.Calculator
+	current *autotune.PHPFPMConfig
+}
+
+// initAutotuneLearning restores persisted worker memory samples and registers
+// the observer with the resource collector.
+func (m *Manager) initAutotuneLearning() {
+	cfg := m.config.Global.AutotuneLearning
+	if cfg == nil || !cfg.Enabled {
+		return
+	}
+	if m.resourceCollector == nil {
+		m.logger.Warn("Autotune learning requires resource metrics, learning disabled")
+		return
+	}
+
+	observer, err := autotune.LoadWorkerMemoryObserver(cfg.StatePath, cfg.Window)
+	if err != nil {
+		m.logger.Warn("Failed to restore worker memory samples, starting fresh",
+			"path", cfg.StatePath,
+			"error", err,
+		)
+	}
+
+	m.autotune = &autotuneLearning{cfg: cfg, observer: observer}
+	m.resourceCollector.WatchChildren(cfg.Process, observer)
+
+	m.logger.Info("Autotune learning enabled",
+		"process", cfg.Process,
+		"state_path", cfg.StatePath,
+		"samples", observer.Stats().Samples,
+	)
+
+	go m.persistWorkerMemory()
+}
+
+// persistWorkerMemory periodically saves observed worker memory until shutdown.
+func (m *Manager) persistWorkerMemory() {
+	ticker := time.NewTicker(autotuneLearningSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.shutdownCh:
+			return
+		case <-ticker.C:
+			if err := m.SaveWorkerMemory(); err != nil {
+				m.logger.Warn("Failed to persist worker memory samples", "error", err)
+			}
+		}
+	}
+}
+
+// SaveWorkerMemory persists observed worker memory samples. It is a no-op
+// when autotune learning is disabled.
+func (m *Manager) SaveWorkerMemory() error {
+	if m.autotune == nil {
+		return nil
+	}
+	return m.autotune.observer.Save(m.autotune.cfg.StatePath)
+}
+
+// SetAutotuneResult records the calculator and configuration used for the
+// current php-fpm tuning so reports can compare them with observed memory.
+func (m *Manager) SetAutotuneResult(calc *autotune.Calculator, current *autotune.PHPFPMConfig) {
+	if m.autotune == nil {
+		return
+	}
+	m.autotune.mu.Lock()
+	defer m.autotune.mu.Unlock()
+	m.autotune.calc = calc
+	m.autotune.current = current
+}
+
+// GetAutotuneReport compares observed worker memory with the profile
+// estimate and recommends max_children.
+func (m *Manager) GetAutotuneReport() (*autotune.LearningReport, error) {
+	if m.autotune == nil {
+		return nil, fmt.Errorf("autotune learning is not enabled")
+	}
+
+	m.autotune.mu.RLock()
+	calc, current := m.autotune.calc, m.autotune.current
+	m.autotune.mu.RUnlock()
+
+	cfg := m.autotune.cfg
+	report := autotune.BuildLearningReport(m.autotune.observer, cfg.Process, cfg.Percentile, cfg.MinSamples, calc, current)
+	report.Apply = cfg.Apply
+	return report, nil
+}
@@ -0,0 +1,85 @@
+package process
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gophpeek/phpeek-pm/internal/audit"
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+func createAutotuneTestManager(t *testing.T, statePath string) *Manager {
+	t.Helper()
+
+	cfg := &config.Config{
+		Global: config.GlobalConfig{
+			ShutdownTimeout:           5,
+			ResourceMetricsInterval:   5,
+			ResourceMetricsMaxSamples: 10,
+			AutotuneLearning: &config.LearningConfig{
+				Enabled:    true,
+				Process:    "php-fpm",
+				StatePath:  statePath,
+				Percentile: 95,
+				MinSamples: 10,
+				Window:     100,
+			},
+		},
+		Processes: map[string]*config.Process{},
+	}
+	cfg.Global.SetResourceMetricsEnabled(true)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	m := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	t.Cleanup(func() { _ = m.Shutdown(context.Background()) })
+	return m
+}
+
+func TestManager_AutotuneLearning(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "learning.json")
+	m := createAutotuneTestManager(t, statePath)
+
+	recorder, ok := m.GetResourceCollector().ChildRecorder("php-fpm")
+	if !ok {
+		t.Fatal("expected worker memory recorder for php-fpm")
+	}
+	for i := 0; i < 20; i++ {
+		recorder.RecordWorkerRSS([]uint64{64 << 20, 32 << 20})
+	}
+
+	report, err := m.GetAutotuneReport()
+	if err != nil {
+		t.Fatalf("GetAutotuneReport() error = %v", err)
+	}
+	if !report.Ready || report.ObservedMB != 64 || report.Stats.Samples != 40 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if err := m.SaveWorkerMemory(); err != nil {
+		t.Fatalf("SaveWorkerMemory() error = %v", err)
+	}
+
+	// A new manager restores the persisted samples
+	restored := createAutotuneTestManager(t, statePath)
+	report, err = restored.GetAutotuneReport()
+	if err != nil {
+		t.Fatalf("GetAutotuneReport() error = %v", err)
+	}
+	if report.Stats.Samples != 40 {
+		t.Errorf("expected 40 restored samples, got %d", report.Stats.Samples)
+	}
+}
+
+func TestManager_AutotuneLearningDisabled(t *testing.T) {
+	m := createLoggingTestManager(t)
+
+	if _, err := m.GetAutotuneReport(); err == nil {
+		t.Error("expected error when learning is disabled")
+	}
+	if err := m.SaveWorkerMemory(); err != nil {
+		t.Errorf("SaveWorkerMemory() should be a no-op, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package process
+
+import (
+	"fmt"
+
+	"github.com/gophpeek/phpeek-pm/internal/cgroup"
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// initCgroupWatch starts the cgroup watcher when enabled and a cgroup is
+// found. It exports memory events and pressure, tags OOM-killed instances,
+// and optionally holds back scale-ups and scheduled runs under pressure.
+func (m *Manager) initCgroupWatch() {
+	cfg := m.config.Global.Cgroup
+	if !cfg.EnabledValue() {
+		return
+	}
+
+	reader := cgroup.NewReader(cfg.Path, "")
+	if reader.Version() == 0 {
+		m.logger.Debug("No cgroup found, cgroup watcher disabled", "path", cfg.Path)
+		return
+	}
+
+	m.cgroupWatcher = cgroup.NewWatcher(cfg, reader, m.logger)
+	m.logger.Info("Cgroup watcher enabled",
+		"path", cfg.Path,
+		"version", reader.Version(),
+		"pause_scale_up", cfg.PauseScaleUp,
+		"shed_scheduled", cfg.ShedScheduled,
+	)
+
+	go m.cgroupWatcher.Run(m.shutdownCh)
+}
+
+// GetCgroupStats returns the last cgroup snapshot
+func (m *Manager) GetCgroupStats() (cgroup.Stats, error) {
+	if m.cgroupWatcher == nil {
+		return cgroup.Stats{}, fmt.Errorf("cgroup watcher is not enabled")
+	}
+	return m.cgroupWatcher.Stats(), nil
+}
+
+// newSupervisor creates a supervisor wired to the manager's shared oneshot
+// history, OOM detection, death notifications and completion triggers
+func (m *Manager) newSupervisor(name string, procCfg *config.Process, globalCfg *config.GlobalConfig) *Supervisor {
+	sup := NewSupervisor(name, procCfg, globalCfg, m.logger, m.auditLogger, m.resourceCollector)
+	sup.SetOneshotHistory(m.oneshotHistory)
+	sup.SetDeathNotifier(m.NotifyProcessDeath)
+	if len(procCfg.Triggers.Targets()) > 0 {
+		sup.SetCompletionNotifier(m.handleJobCompletion)
+	}
+	if m.cgroupWatcher != nil {
+		sup.SetOOMDetector(m.cgroupWatcher)
+	}
+	return sup
+}
+
+// pressureError returns an error describing the cgroup pressure when it is
+// above the configured thresholds
+func (m *Manager) pressureError() error {
+	if m.cgroupWatcher == nil {
+		return nil
+	}
+	if high, reason := m.cgroupWatcher.UnderPressure(); high {
+		return fmt.Errorf("container under pressure: %s", reason)
+	}
+	return nil
+}
+
+// checkScaleUpPressure rejects scale-ups under pressure when pause_scale_up is set
+func (m *Manager) checkScaleUpPressure() error {
+	m.mu.RLock()
+	cfg := m.config.Global.Cgroup
+	m.mu.RUnlock()
+
+	if !cfg.EnabledValue() || !cfg.PauseScaleUp {
+		return nil
+	}
+	if err := m.pressureError(); err != nil {
+		return fmt.Errorf("scale up paused: %w", err)
+	}
+	return nil
+}
+
+// scheduleGate skips scheduled runs under pressure when shed_scheduled is set
+func (m *Manager) scheduleGate() func(jobName string) error {
+	if cfg := m.config.Global.Cgroup; !cfg.EnabledValue() || !cfg.ShedScheduled {
+		return nil
+	}
+	return func(string) error {
+		return m.pressureError()
+	}
+}
@@ -0,0 +1,124 @@
+package process
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/audit"
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+const highMemoryPressure = "some avg10=45.00 avg60=20.00 avg300=5.00 total=1000\nfull avg10=10.00 avg60=5.00 avg300=1.00 total=100\n"
+
+// writeFakeCgroup creates a cgroup v2 tree with the given memory pressure
+func writeFakeCgroup(t *testing.T, root, memoryPressure string) {
+	t.Helper()
+	files := map[string]string{
+		"cgroup.controllers": "cpu memory\n",
+		"memory.events":      "low 0\nhigh 0\nmax 0\noom 0\noom_kill 0\n",
+		"memory.pressure":    memoryPressure,
+		"cpu.pressure":       "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func createCgroupTestManager(t *testing.T, root string) *Manager {
+	t.Helper()
+
+	cfg := &config.Config{
+		Global: config.GlobalConfig{
+			ShutdownTimeout: 5,
+			Cgroup: &config.CgroupConfig{
+				Path:                    root,
+				Interval:                60,
+				MemoryPressureThreshold: 20,
+				CPUPressureThreshold:    80,
+				PauseScaleUp:            true,
+				ShedScheduled:           true,
+			},
+		},
+		Processes: map[string]*config.Process{
+			"worker": {
+				Enabled:      true,
+				InitialState: "running",
+				Command:      []string{"sleep", "60"},
+				Restart:      "never",
+				Scale:        1,
+			},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	m := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = m.Shutdown(ctx)
+	})
+	return m
+}
+
+func TestManager_CgroupPressure(t *testing.T) {
+	root := t.TempDir()
+	writeFakeCgroup(t, root, highMemoryPressure)
+
+	m := createCgroupTestManager(t, root)
+	if m.cgroupWatcher == nil {
+		t.Fatal("expected cgroup watcher for fake cgroup tree")
+	}
+	m.cgroupWatcher.Poll()
+
+	stats, err := m.GetCgroupStats()
+	if err != nil || stats.Version != 2 {
+		t.Fatalf("GetCgroupStats() = %+v, %v", stats, err)
+	}
+
+	ctx := context.Background()
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// Scale-up is paused under pressure, scale-down is not
+	err = m.ScaleProcess(ctx, "worker", 2)
+	if err == nil || !strings.Contains(err.Error(), "scale up paused") {
+		t.Fatalf("ScaleProcess() error = %v, want scale up paused", err)
+	}
+
+	gate := m.scheduleGate()
+	if gate == nil || gate("backup") == nil {
+		t.Error("scheduled runs should be shed under pressure")
+	}
+
+	// Once pressure recovers, scale-up and scheduled runs proceed
+	writeFakeCgroup(t, root, "some avg10=1.00 avg60=1.00 avg300=1.00 total=10\n")
+	m.cgroupWatcher.Poll()
+
+	if err := m.ScaleProcess(ctx, "worker", 2); err != nil {
+		t.Errorf("ScaleProcess() after recovery error = %v", err)
+	}
+	if err := gate("backup"); err != nil {
+		t.Errorf("gate after recovery = %v", err)
+	}
+}
+
+func TestManager_CgroupDisabled(t *testing.T) {
+	m := createCgroupTestManager(t, t.TempDir()) // No cgroup files
+	if m.cgroupWatcher != nil {
+		t.Fatal("watcher should be disabled without a cgroup")
+	}
+	if _, err := m.GetCgroupStats(); err == nil {
+		t.Error("expected error when the watcher is disabled")
+	}
+	if err := m.checkScaleUpPressure(); err != nil {
+		t.Errorf("checkScaleUpPressure() = %v, want nil without a watcher", err)
+	}
+}
@@ -0,0 +1,144 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
+)
+
+// ChainStep is one run of a job within a job chain, taken from the oneshot or
+// the schedule history
+type ChainStep struct {
+	Process     string    `json:"process"`
+	Kind        string    `json:"kind"` // "oneshot" | "scheduled"
+	ExecutionID int64     `json:"execution_id"`
+	InstanceID  string    `json:"instance_id,omitempty"`
+	TriggeredBy string    `json:"triggered_by,omitempty"` // Empty for the job that started the chain
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+	Running     bool      `json:"running"`
+	ExitCode    int       `json:"exit_code"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// handleJobCompletion starts the processes that follow a finished oneshot or
+// scheduled run, passing its job chain on. Targets that cannot run, e.g.
+// because they are still busy, are skipped.
+func (m *Manager) handleJobCompletion(c schedule.Completion) {
+	select {
+	case <-m.shutdownCh:
+		m.logger.Debug("Skipping job triggers during shutdown", "process", c.Job)
+		return
+	default:
+	}
+
+	m.mu.RLock()
+	procCfg, ok := m.config.Processes[c.Job]
+	m.mu.RUnlock()
+	if !ok || procCfg.Triggers == nil {
+		return
+	}
+
+	outcome, targets := "success", procCfg.Triggers.OnSuccess
+	if !c.Success {
+		outcome, targets = "failure", procCfg.Triggers.OnFailure
+	}
+
+	chain := c.Chain.Next(c.Job)
+	for _, target := range targets {
+		if err := m.triggerChainedJob(target, chain); err != nil {
+			m.logger.Warn("Failed to trigger chained job",
+				"process", c.Job,
+				"target", target,
+				"outcome", outcome,
+				"chain_id", chain.ID,
+				"error", err,
+			)
+			continue
+		}
+		m.logger.Info("Triggered chained job",
+			"process", c.Job,
+			"target", target,
+			"outcome", outcome,
+			"chain_id", chain.ID,
+		)
+	}
+}
+
+// triggerChainedJob starts a oneshot or scheduled process as part of a chain
+func (m *Manager) triggerChainedJob(name string, chain schedule.Chain) error {
+	m.mu.RLock()
+	procCfg, cfgOk := m.config.Processes[name]
+	sup, supOk := m.processes[name]
+	m.mu.RUnlock()
+
+	if !cfgOk {
+		return fmt.Errorf("process %s not found in configuration", name)
+	}
+	if !procCfg.Enabled {
+		return fmt.Errorf("process %s is disabled in configuration", name)
+	}
+
+	// Like StartProcess, runs live independently of the run that triggered them
+	if procCfg.Schedule != "" {
+		return m.scheduler.TriggerJobChained(context.Background(), name, chain)
+	}
+	if !supOk {
+		return fmt.Errorf("process %s not found in running processes", name)
+	}
+	return sup.RunOneshot(context.Background(), chain)
+}
+
+// GetChainHistory returns the runs of a job chain across the oneshot and
+// schedule histories (oldest first). Runs evicted from either history are
+// missing.
+func (m *Manager) GetChainHistory(chainID string) []ChainStep {
+	var steps []ChainStep
+
+	if m.oneshotHistory != nil {
+		for _, exec := range m.oneshotHistory.GetChain(chainID) {
+			steps = append(steps, ChainStep{
+				Process:     exec.ProcessName,
+				Kind:        "oneshot",
+				ExecutionID: exec.ID,
+				InstanceID:  exec.InstanceID,
+				TriggeredBy: exec.TriggeredBy,
+				StartedAt:   exec.StartedAt,
+				FinishedAt:  exec.FinishedAt,
+				Running:     exec.FinishedAt.IsZero(),
+				ExitCode:    exec.ExitCode,
+				Success:     exec.Success,
+				Error:       exec.Error,
+			})
+		}
+	}
+
+	for name, job := range m.scheduler.GetAllJobs() {
+		for _, entry := range job.History.GetAll() {
+			if entry.ChainID != chainID {
+				continue
+			}
+			steps = append(steps, ChainStep{
+				Process:     name,
+				Kind:        "scheduled",
+				ExecutionID: entry.ID,
+				TriggeredBy: entry.TriggeredBy,
+				StartedAt:   entry.StartTime,
+				FinishedAt:  entry.EndTime,
+				Running:     entry.IsRunning(),
+				ExitCode:    entry.ExitCode,
+				Success:     entry.Success,
+				Error:       entry.Error,
+			})
+		}
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].StartedAt.Before(steps[j].StartedAt)
+	})
+	return steps
+}
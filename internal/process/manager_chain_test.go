@@ -0,0 +1,255 @@
+package process
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/audit"
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// createChainTestManager creates and starts a manager for job chain tests
+func createChainTestManager(t *testing.T, processes map[string]*config.Process) *Manager {
+	t.Helper()
+
+	cfg := &config.Config{
+		Global: config.GlobalConfig{
+			ShutdownTimeout:    5,
+			LogLevel:           "error",
+			MaxRestartAttempts: 3,
+			RestartBackoff:     1,
+		},
+		Processes: processes,
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	t.Cleanup(func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = manager.Shutdown(shutdownCtx)
+	})
+	return manager
+}
+
+// waitForChain waits until a chain rooted at root has n finished steps
+func waitForChain(t *testing.T, manager *Manager, root string, n int) []ChainStep {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, exec := range manager.GetOneshotExecutions(root, 0) {
+			if exec.ChainID == "" {
+				continue
+			}
+			steps := manager.GetChainHistory(exec.ChainID)
+			finished := 0
+			for _, step := range steps {
+				if !step.Running {
+					finished++
+				}
+			}
+			if finished >= n {
+				return steps
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("chain rooted at %s did not reach %d finished steps", root, n)
+	return nil
+}
+
+func TestManager_JobChain(t *testing.T) {
+	manager := createChainTestManager(t, map[string]*config.Process{
+		"extract": {
+			Enabled:      true,
+			InitialState: "running",
+			Type:         "oneshot",
+			Command:      []string{"sh", "-c", "exit 0"},
+			Restart:      "never",
+			Scale:        1,
+			Triggers:     &config.ProcessTriggers{OnSuccess: []string{"load"}, OnFailure: []string{"alert"}},
+		},
+		"load": {
+			Enabled:      true,
+			InitialState: "stopped",
+			Type:         "oneshot",
+			Command:      []string{"sh", "-c", `test "$PHPEEK_PM_CHAIN_ROOT" = extract && exit 3`},
+			Restart:      "never",
+			Scale:        1,
+			Triggers:     &config.ProcessTriggers{OnFailure: []string{"report"}},
+		},
+		"alert": {
+			Enabled:      true,
+			InitialState: "stopped",
+			Type:         "oneshot",
+			Command:      []string{"true"},
+			Restart:      "never",
+			Scale:        1,
+		},
+		"report": {
+			Enabled:  true,
+			Schedule: "0 0 1 1 *",
+			Command:  []string{"sh", "-c", `test "$PHPEEK_PM_TRIGGERED_BY" = load`},
+			Restart:  "never",
+			Scale:    1,
+		},
+	})
+
+	steps := waitForChain(t, manager, "extract", 3)
+	if len(steps) != 3 {
+		t.Fatalf("chain has %d steps, want extract, load and report: %+v", len(steps), steps)
+	}
+
+	want := []struct {
+		process, kind, triggeredBy string
+		success                    bool
+	}{
+		{"extract", "oneshot", "", true},
+		{"load", "oneshot", "extract", false},
+		{"report", "scheduled", "load", true},
+	}
+	for i, w := range want {
+		step := steps[i]
+		if step.Process != w.process || step.Kind != w.kind || step.TriggeredBy != w.triggeredBy || step.Success != w.success {
+			t.Errorf("step %d = %+v, want %s (%s) triggered by %q, success %v", i, step, w.process, w.kind, w.triggeredBy, w.success)
+		}
+	}
+
+	if execs := manager.GetOneshotExecutions("alert", 0); len(execs) != 0 {
+		t.Errorf("alert ran %d times, want 0 after a successful extract", len(execs))
+	}
+}
+
+func TestManager_JobChain_TargetBusy(t *testing.T) {
+	manager := createChainTestManager(t, map[string]*config.Process{
+		"trigger": {
+			Enabled:      true,
+			InitialState: "running",
+			Type:         "oneshot",
+			Command:      []string{"true"},
+			Restart:      "never",
+			Scale:        1,
+			Triggers:     &config.ProcessTriggers{OnSuccess: []string{"slow"}},
+		},
+		"slow": {
+			Enabled:      true,
+			InitialState: "running",
+			Type:         "oneshot",
+			Command:      []string{"sleep", "2"},
+			Restart:      "never",
+			Scale:        1,
+		},
+	})
+
+	// The target is still running its startup run, so the trigger is skipped
+	waitForChain(t, manager, "trigger", 1)
+	time.Sleep(100 * time.Millisecond)
+	for _, exec := range manager.GetOneshotExecutions("slow", 0) {
+		if exec.ChainID != "" {
+			t.Errorf("busy target ran in chain %s", exec.ChainID)
+		}
+	}
+}
+
+func TestManager_JobChain_AfterReload(t *testing.T) {
+	processes := func(command string) map[string]*config.Process {
+		return map[string]*config.Process{
+			"extract": {
+				Enabled:      true,
+				InitialState: "running",
+				Type:         "oneshot",
+				Command:      []string{"sh", "-c", command},
+				Restart:      "never",
+				Scale:        1,
+				Triggers:     &config.ProcessTriggers{OnSuccess: []string{"load"}},
+			},
+			"load": {
+				Enabled:      true,
+				InitialState: "stopped",
+				Type:         "oneshot",
+				Command:      []string{"true"},
+				Restart:      "never",
+				Scale:        1,
+			},
+		}
+	}
+	manager := createChainTestManager(t, processes("exit 0"))
+	waitForChain(t, manager, "extract", 2)
+
+	// The reload recreates the supervisor of the changed oneshot and runs it
+	cfg := &config.Config{
+		Global:    manager.config.Global,
+		Processes: processes("true"),
+	}
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	data, _ := yaml.Marshal(cfg)
+	if err := os.WriteFile(cfgPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	manager.SetConfigPath(cfgPath)
+	if err := manager.ReloadConfig(context.Background()); err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		chained := 0
+		for _, exec := range manager.GetOneshotExecutions("load", 0) {
+			if exec.ChainID != "" {
+				chained++
+			}
+		}
+		if chained == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("load ran %d times in a chain, want 2 after the reload", chained)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestManager_Start_TriggerCycle(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{ShutdownTimeout: 5, LogLevel: "error"},
+		Processes: map[string]*config.Process{
+			"a": {
+				Enabled:      true,
+				InitialState: "stopped",
+				Type:         "oneshot",
+				Command:      []string{"true"},
+				Scale:        1,
+				Triggers:     &config.ProcessTriggers{OnSuccess: []string{"b"}},
+			},
+			"b": {
+				Enabled:      true,
+				InitialState: "stopped",
+				Type:         "oneshot",
+				Command:      []string{"true"},
+				Scale:        1,
+				Triggers:     &config.ProcessTriggers{OnFailure: []string{"a"}},
+			},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	err := manager.Start(context.Background())
+	if err == nil {
+		_ = manager.Shutdown(context.Background())
+		t.Fatal("Start() should fail on a trigger cycle")
+	}
+	if !strings.Contains(err.Error(), "trigger cycle") {
+		t.Errorf("Start() error = %v, want a trigger cycle error", err)
+	}
+}
@@ -15,6 +15,17 @@ func (m *Manager) SetConfigPath(path string) {
 	m.configPath = path
 }
 
+// ConfigTransform adjusts a freshly loaded configuration before it is applied,
+// e.g. to re-apply auto-tuned scales on reload.
+type ConfigTransform func(cfg *config.Config) error
+
+// SetConfigTransform sets the transform applied to configurations loaded by ReloadConfig.
+func (m *Manager) SetConfigTransform(fn ConfigTransform) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configTransform = fn
+}
+
 // AddProcess adds a new process to the configuration and optionally starts it.
 func (m *Manager) AddProcess(ctx context.Context, name string, procCfg *config.Process) error {
 	m.mu.Lock()
@@ -45,8 +56,7 @@ func (m *Manager) AddProcess(ctx context.Context, name string, procCfg *config.P
 	if procCfg.Enabled {
 		m.logger.Info("Starting new process", "name", name, "command", procCfg.Command, "scale", procCfg.Scale)
 
-		supervisor := NewSupervisor(name, procCfg, &m.config.Global, m.logger, m.auditLogger, m.resourceCollector)
-		supervisor.SetOneshotHistory(m.oneshotHistory)
+		supervisor := m.newSupervisor(name, procCfg, &m.config.Global)
 		// Use background context for supervisor lifetime (independent of API request)
 		if err := supervisor.Start(context.Background()); err != nil {
 			// Remove from config on failure
@@ -146,8 +156,7 @@ func (m *Manager) updateProcessLocked(ctx context.Context, name string, procCfg
 
 		// If new config is enabled, start with new config
 		if procCfg.Enabled {
-			newSupervisor := NewSupervisor(name, procCfg, &m.config.Global, m.logger, m.auditLogger, m.resourceCollector)
-			newSupervisor.SetOneshotHistory(m.oneshotHistory)
+			newSupervisor := m.newSupervisor(name, procCfg, &m.config.Global)
 			// Use background context for supervisor lifetime (independent of API request)
 			if err := newSupervisor.Start(context.Background()); err != nil {
 				// Rollback config change on error
@@ -166,8 +175,7 @@ func (m *Manager) updateProcessLocked(ctx context.Context, name string, procCfg
 		// Process wasn't running but new config enables it
 		m.logger.Info("Starting previously disabled process", "name", name)
 
-		supervisor := NewSupervisor(name, procCfg, &m.config.Global, m.logger, m.auditLogger, m.resourceCollector)
-		supervisor.SetOneshotHistory(m.oneshotHistory)
+		supervisor := m.newSupervisor(name, procCfg, &m.config.Global)
 		// Use background context for supervisor lifetime (independent of API request)
 		if err := supervisor.Start(context.Background()); err != nil {
 			// Rollback config change on error
@@ -218,6 +226,9 @@ func (m *Manager) SaveConfig() error {
 	if configPath == "" {
 		return fmt.Errorf("config file path not set")
 	}
+	if err := cfg.Sources.CheckSave(); err != nil {
+		return fmt.Errorf("cannot save config: %w", err)
+	}
 
 	m.logger.Info("Saving configuration", "path", configPath)
 
@@ -244,11 +255,20 @@ func (m *Manager) ReloadConfig(ctx context.Context) error {
 
 	m.logger.Info("Reloading configuration", "path", m.configPath)
 
-	// Load new config
-	newCfg, err := config.LoadWithEnvExpansion(m.configPath)
+	// Load new config with the profile the running config was loaded with
+	var opts config.LoadOptions
+	if m.config.Sources != nil {
+		opts.Profile = m.config.Sources.Profile
+	}
+	newCfg, err := config.LoadWithOptions(m.configPath, opts)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if m.configTransform != nil {
+		if err := m.configTransform(newCfg); err != nil {
+			return fmt.Errorf("failed to apply config transform: %w", err)
+		}
+	}
 
 	// Determine what changed
 	toStop := []string{}
@@ -286,6 +306,7 @@ func (m *Manager) ReloadConfig(ctx context.Context) error {
 
 	// Update config
 	m.config = newCfg
+	m.maskAuditSecrets()
 
 	// Start new processes
 	m.startNewProcesses(newCfg, toStart)
@@ -320,8 +341,7 @@ func (m *Manager) startNewProcesses(cfg *config.Config, names []string) {
 		procCfg := cfg.Processes[name]
 		if procCfg.Enabled {
 			m.logger.Info("Starting new process", "name", name)
-			supervisor := NewSupervisor(name, procCfg, &cfg.Global, m.logger, m.auditLogger, m.resourceCollector)
-			supervisor.SetOneshotHistory(m.oneshotHistory)
+			supervisor := m.newSupervisor(name, procCfg, &cfg.Global)
 			// Use background context for supervisor lifetime (independent of reload request)
 			if err := supervisor.Start(context.Background()); err != nil {
 				m.logger.Error("Failed to start new process during reload", "name", name, "error", err)
@@ -346,8 +366,7 @@ func (m *Manager) updateChangedProcesses(ctx context.Context, cfg *config.Config
 			}
 
 			if procCfg.Enabled {
-				newSupervisor := NewSupervisor(name, procCfg, &cfg.Global, m.logger, m.auditLogger, m.resourceCollector)
-				newSupervisor.SetOneshotHistory(m.oneshotHistory)
+				newSupervisor := m.newSupervisor(name, procCfg, &cfg.Global)
 				// Use background context for supervisor lifetime (independent of reload request)
 				if err := newSupervisor.Start(context.Background()); err != nil {
 					m.logger.Error("Failed to start updated process", "name", name, "error", err)
@@ -359,8 +378,7 @@ func (m *Manager) updateChangedProcesses(ctx context.Context, cfg *config.Config
 			}
 		} else if procCfg.Enabled {
 			m.logger.Info("Starting previously disabled process", "name", name)
-			supervisor := NewSupervisor(name, procCfg, &cfg.Global, m.logger, m.auditLogger, m.resourceCollector)
-			supervisor.SetOneshotHistory(m.oneshotHistory)
+			supervisor := m.newSupervisor(name, procCfg, &cfg.Global)
 			// Use background context for supervisor lifetime (independent of reload request)
 			if err := supervisor.Start(context.Background()); err != nil {
 				m.logger.Error("Failed to start process during reload", "name", name, "error", err)
@@ -376,15 +394,25 @@ func (m *Manager) GetConfig() *config.Config {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Return a copy to prevent external modifications
-	cfgCopy := *m.config
-	cfgCopy.Processes = make(map[string]*config.Process, len(m.config.Processes))
-	for k, v := range m.config.Processes {
-		procCopy := *v
-		cfgCopy.Processes[k] = &procCopy
-	}
+	// Return a deep copy to prevent external modifications, with resolved
+	// secrets masked
+	return m.config.Secrets().MaskConfig(m.config)
+}
 
-	return &cfgCopy
+// Secrets returns the secret values resolved for the current config, used to
+// mask them in output
+func (m *Manager) Secrets() *config.Secrets {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.Secrets()
+}
+
+// maskAuditSecrets makes the audit logger mask the current config's secrets.
+// Caller must hold m.mu or be constructing the manager.
+func (m *Manager) maskAuditSecrets() {
+	if m.auditLogger != nil {
+		m.auditLogger.SetMasker(m.config.Secrets())
+	}
 }
 
 // GetProcessConfig returns a copy of a single process configuration.
@@ -410,5 +438,14 @@ func (m *Manager) GetProcessConfig(name string) (*config.Process, error) {
 	if proc.DependsOn != nil {
 		procCopy.DependsOn = append([]string{}, proc.DependsOn...)
 	}
+	if proc.DependsOnOptions != nil {
+		procCopy.DependsOnOptions = make(map[string]*config.Dependency, len(proc.DependsOnOptions))
+		for k, dep := range proc.DependsOnOptions {
+			if dep != nil {
+				depCopy := *dep
+				procCopy.DependsOnOptions[k] = &depCopy
+			}
+		}
+	}
 	return &procCopy, nil
 }
@@ -2,6 +2,7 @@ package process
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -519,6 +520,112 @@ func TestManager_ReloadConfig_NoConfigPath(t *testing.T) {
 	}
 }
 
+// TestManager_ReloadConfig_Transform tests that the config transform runs before diffing
+func TestManager_ReloadConfig_Transform(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	newConfig := func(scale int) *config.Config {
+		return &config.Config{
+			Global: config.GlobalConfig{
+				ShutdownTimeout:    10,
+				LogLevel:           "error",
+				MaxRestartAttempts: 3,
+				RestartBackoff:     1,
+			},
+			Processes: map[string]*config.Process{
+				"worker": {
+					Enabled:      true,
+					InitialState: "stopped",
+					Command:      []string{"sleep", "300"},
+					Restart:      "never",
+					Scale:        scale,
+				},
+			},
+		}
+	}
+
+	// The file has scale 1, the running config was tuned to 3
+	data, _ := yaml.Marshal(newConfig(1))
+	if err := os.WriteFile(cfgPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(newConfig(3), logger, audit.NewLogger(logger, false))
+	manager.SetConfigPath(cfgPath)
+	manager.SetConfigTransform(func(cfg *config.Config) error {
+		cfg.Processes["worker"].Scale = 3
+		return nil
+	})
+
+	if err := manager.ReloadConfig(context.Background()); err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+	if got := manager.config.Processes["worker"].Scale; got != 3 {
+		t.Errorf("scale after reload = %d, want transformed 3", got)
+	}
+
+	// A failing transform aborts the reload
+	manager.SetConfigTransform(func(cfg *config.Config) error {
+		cfg.Processes["worker"].Scale = 5
+		return fmt.Errorf("tuning failed")
+	})
+	err := manager.ReloadConfig(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "tuning failed") {
+		t.Errorf("expected transform error, got %v", err)
+	}
+	if got := manager.config.Processes["worker"].Scale; got != 3 {
+		t.Errorf("failed reload should keep the previous config, scale = %d", got)
+	}
+}
+
+// TestManager_ReloadConfig_KeepsProfile tests that reload applies the profile
+// the running config was loaded with, regardless of PHPEEK_PM_PROFILE
+func TestManager_ReloadConfig_KeepsProfile(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+global:
+  log_level: error
+  shutdown_timeout: 10
+processes:
+  worker:
+    initial_state: stopped
+    command: ["sleep", "300"]
+    restart: never
+profiles:
+  production:
+    processes:
+      worker:
+        scale: %d
+`
+	if err := os.WriteFile(cfgPath, []byte(fmt.Sprintf(content, 2)), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := config.LoadWithOptions(cfgPath, config.LoadOptions{Profile: "production"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	manager.SetConfigPath(cfgPath)
+
+	t.Setenv(config.ProfileEnvVar, "")
+	if err := os.WriteFile(cfgPath, []byte(fmt.Sprintf(content, 4)), 0644); err != nil {
+		t.Fatalf("Failed to write updated config: %v", err)
+	}
+	if err := manager.ReloadConfig(context.Background()); err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+	if got := manager.config.Processes["worker"].Scale; got != 4 {
+		t.Errorf("scale after reload = %d, want 4 from the production profile", got)
+	}
+	if got := manager.config.Sources.Profile; got != "production" {
+		t.Errorf("profile after reload = %q, want production", got)
+	}
+}
+
 // TestManager_SaveConfig_NoConfigPath tests save when config path is not set
 func TestManager_SaveConfig_NoConfigPath(t *testing.T) {
 	cfg := &config.Config{
@@ -585,7 +692,10 @@ func TestManager_GetProcessConfig_CopiesData(t *testing.T) {
 				Command:   []string{"sleep", "30"},
 				Scale:     1,
 				DependsOn: []string{"dep1", "dep2"},
-				Env:       map[string]string{"KEY": "value"},
+				DependsOnOptions: map[string]*config.Dependency{
+					"dep1": {Condition: config.DependencyStarted},
+				},
+				Env: map[string]string{"KEY": "value"},
 			},
 		},
 	}
@@ -604,6 +714,7 @@ func TestManager_GetProcessConfig_CopiesData(t *testing.T) {
 	procCfg.Scale = 10
 	procCfg.Command = append(procCfg.Command, "extra")
 	procCfg.DependsOn = append(procCfg.DependsOn, "extra")
+	procCfg.DependsOnOptions["dep1"].Condition = config.DependencyHealthy
 	procCfg.Env["NEW"] = "value"
 
 	// Get another copy and verify original unchanged
@@ -617,6 +728,9 @@ func TestManager_GetProcessConfig_CopiesData(t *testing.T) {
 	if len(procCfg2.DependsOn) != 2 {
 		t.Error("Original depends_on was modified")
 	}
+	if procCfg2.Dependency("dep1").Condition != config.DependencyStarted {
+		t.Error("Original depends_on options were modified")
+	}
 	if _, exists := procCfg2.Env["NEW"]; exists {
 		t.Error("Original env was modified")
 	}
@@ -665,6 +779,129 @@ func TestManager_GetConfig_CopiesData(t *testing.T) {
 	}
 }
 
+// TestManager_GetConfig_MasksSecrets tests that resolved secrets are masked in
+// GetConfig and the audit log, and saved as references
+func TestManager_GetConfig_MasksSecrets(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "db_password")
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(secretPath, []byte("s3cr3t-pw\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	content := fmt.Sprintf(`
+global:
+  log_level: error
+processes:
+  worker:
+    initial_state: stopped
+    command: ["php", "worker.php", "--db-password=${file:%s}"]
+    restart: never
+`, secretPath)
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadWithEnvExpansion(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+
+	var auditBuf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&auditBuf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, true))
+	manager.SetConfigPath(cfgPath)
+
+	if got := manager.GetConfig().Processes["worker"].Command[2]; got != "--db-password=***" {
+		t.Errorf("GetConfig() command = %q, want masked", got)
+	}
+	if got := manager.Secrets().MaskProcess("worker", cfg.Processes["worker"]).Command[2]; got != "--db-password=***" {
+		t.Errorf("MaskProcess() command = %q, want masked", got)
+	}
+
+	manager.auditLogger.LogProcessAdded("worker", cfg.Processes["worker"].Command, 1)
+	if strings.Contains(auditBuf.String(), "s3cr3t-pw") {
+		t.Errorf("secret leaked into audit log: %s", auditBuf.String())
+	}
+
+	if err := manager.SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	saved, _ := os.ReadFile(cfgPath)
+	if strings.Contains(string(saved), "s3cr3t-pw") || !strings.Contains(string(saved), "${file:"+secretPath+"}") {
+		t.Errorf("saved config should reference the secret, got:\n%s", saved)
+	}
+}
+
+// TestManager_SaveConfig_MergedSources tests that a config merged from
+// several files is not written back into the main file
+func TestManager_SaveConfig_MergedSources(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	files := map[string]string{
+		cfgPath: "include: workers.yaml\nglobal:\n  log_level: error\n",
+		filepath.Join(dir, "workers.yaml"): "processes:\n  worker:\n    initial_state: stopped\n    command: [\"php\", \"worker.php\"]\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.LoadWithEnvExpansion(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadWithEnvExpansion() error = %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	manager.SetConfigPath(cfgPath)
+
+	if err := manager.SaveConfig(); err == nil || !strings.Contains(err.Error(), "merged from 2 files") {
+		t.Errorf("SaveConfig() error = %v, want merged config refused", err)
+	}
+	if saved, _ := os.ReadFile(cfgPath); string(saved) != files[cfgPath] {
+		t.Errorf("main config file was modified:\n%s", saved)
+	}
+}
+
+// TestManager_SaveConfig_Profile tests that a config with a profile applied
+// is not written back into the main file
+func TestManager_SaveConfig_Profile(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+global:
+  log_level: error
+processes:
+  worker:
+    initial_state: stopped
+    command: ["php", "worker.php"]
+profiles:
+  production:
+    processes:
+      worker:
+        scale: 4
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadWithOptions(cfgPath, config.LoadOptions{Profile: "production"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() error = %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	manager.SetConfigPath(cfgPath)
+
+	if err := manager.SaveConfig(); err == nil || !strings.Contains(err.Error(), `profile "production"`) {
+		t.Errorf("SaveConfig() error = %v, want profile config refused", err)
+	}
+	if saved, _ := os.ReadFile(cfgPath); string(saved) != content {
+		t.Errorf("main config file was modified:\n%s", saved)
+	}
+}
+
 // TestManager_ReloadConfig_MultipleChanges tests multiple simultaneous changes
 func TestManager_ReloadConfig_MultipleChanges(t *testing.T) {
 	tmpDir := t.TempDir()
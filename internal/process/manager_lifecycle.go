@@ -2,8 +2,11 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gophpeek/phpeek-pm/internal/config"
@@ -18,6 +21,8 @@ import (
 // Start starts all enabled processes in dependency order.
 // It executes pre-start hooks, starts processes respecting dependencies,
 // registers scheduled processes, and executes post-start hooks.
+// Processes of a dependency level start in parallel (see
+// global.process_parallelism) once the levels before them have started.
 func (m *Manager) Start(ctx context.Context) error {
 	ctx, span := tracing.StartProcessManagerSpan(ctx, "start",
 		attribute.Int("process_count", len(m.config.Processes)))
@@ -38,49 +43,71 @@ func (m *Manager) Start(ctx context.Context) error {
 		m.logger.Info("Pre-start hooks completed successfully")
 	}
 
-	// Get startup order (topological sort by priority and dependencies)
-	startupOrder, err := m.getStartupOrder()
+	// Get startup levels (processes only depend on processes of earlier levels)
+	startupLevels, err := m.getStartupLevels()
 	if err != nil {
 		return fmt.Errorf("failed to determine startup order: %w", err)
 	}
+	var startupOrder []string
+	for _, level := range startupLevels {
+		startupOrder = append(startupOrder, level...)
+	}
+
+	// Reject job chains that would restart themselves forever
+	if _, err := deps.NewTriggerGraphFromConfig(m.config.Processes); err != nil {
+		return fmt.Errorf("invalid job triggers: %w", err)
+	}
 
 	m.logger.Info("Starting processes",
 		"count", len(startupOrder),
+		"levels", len(startupLevels),
 		"order", startupOrder,
+		"parallelism", m.processParallelism,
 	)
 
 	// Record manager process count
 	metrics.SetManagerProcessCount(len(startupOrder))
 
-	// Start processes in order
-	for i, name := range startupOrder {
-		m.logger.Debug("Processing startup queue",
-			"index", i,
-			"total", len(startupOrder),
-			"process", name,
+	// Start processes level by level
+	for i, level := range startupLevels {
+		m.logger.Debug("Processing startup level",
+			"level", i,
+			"total", len(startupLevels),
+			"processes", level,
 		)
 
-		procCfg, ok := m.config.Processes[name]
-		if !ok || !procCfg.Enabled {
-			m.logger.Debug("Skipping disabled/missing process", "name", name)
-			continue
+		// Register the supervisors of the level first, so that m.processes is
+		// only read while its processes start
+		var names []string
+		for _, name := range level {
+			procCfg, ok := m.config.Processes[name]
+			if !ok || !procCfg.Enabled {
+				m.logger.Debug("Skipping disabled/missing process", "name", name)
+				continue
+			}
+			if procCfg.Schedule == "" {
+				m.addSupervisor(name, procCfg)
+			}
+			names = append(names, name)
 		}
 
-		// Wait for dependencies to be ready before starting this process
-		if err := m.waitForDependencies(ctx, name, procCfg.DependsOn); err != nil {
-			return err
-		}
+		errs := m.runParallel(names, func(name string) error {
+			procCfg := m.config.Processes[name]
 
-		// Handle scheduled processes separately
-		if procCfg.Schedule != "" {
-			if err := m.registerScheduledProcess(name, procCfg); err != nil {
+			// Wait for dependencies to be ready before starting this process
+			if err := m.waitForDependencies(ctx, name, procCfg); err != nil {
 				return err
 			}
-			continue // Skip normal process startup
-		}
 
-		// Start regular process
-		if err := m.startRegularProcess(ctx, name, procCfg); err != nil {
+			// Handle scheduled processes separately
+			if procCfg.Schedule != "" {
+				return m.registerScheduledProcess(name, procCfg)
+			}
+
+			// Start regular process
+			return m.startRegularProcess(ctx, name, procCfg)
+		})
+		if err := errors.Join(errs...); err != nil {
 			return err
 		}
 	}
@@ -116,8 +143,11 @@ func (m *Manager) Start(ctx context.Context) error {
 	return nil
 }
 
-// waitForDependencies waits for all process dependencies to be ready.
-func (m *Manager) waitForDependencies(ctx context.Context, name string, dependencies []string) error {
+// waitForDependencies waits for all process dependencies to meet their
+// depends_on condition. Dependencies are waited on in parallel, each up to its
+// own timeout (default: global.dependency_timeout).
+func (m *Manager) waitForDependencies(ctx context.Context, name string, procCfg *config.Process) error {
+	dependencies := procCfg.DependsOn
 	if len(dependencies) == 0 {
 		return nil
 	}
@@ -127,26 +157,59 @@ func (m *Manager) waitForDependencies(ctx context.Context, name string, dependen
 		"dependencies", dependencies,
 	)
 
-	for _, depName := range dependencies {
-		m.logger.Debug("Checking dependency", "process", name, "dependency", depName)
-
+	supervisors := make([]*Supervisor, len(dependencies))
+	for i, depName := range dependencies {
 		depSup, ok := m.processes[depName]
 		if !ok {
 			return fmt.Errorf("dependency %s not found for process %s", depName, name)
 		}
+		supervisors[i] = depSup
+	}
+
+	// The first failed dependency stops waiting for the others
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i, depName := range dependencies {
+		dep := procCfg.Dependency(depName)
+		timeout := dep.Timeout
+		if timeout <= 0 {
+			timeout = m.dependencyTimeout
+		}
 
-		// Wait for dependency readiness
-		m.logger.Debug("Waiting for dependency readiness",
+		m.logger.Debug("Waiting for dependency",
 			"process", name,
 			"dependency", depName,
-			"timeout", m.dependencyTimeout,
+			"condition", dep.Condition,
+			"timeout", timeout,
 		)
 
-		if err := depSup.WaitForReadiness(ctx, m.dependencyTimeout); err != nil {
-			return fmt.Errorf("dependency %s not ready for process %s: %w", depName, name, err)
-		}
+		wg.Add(1)
+		go func(depSup *Supervisor, depName string) {
+			defer wg.Done()
+			if err := depSup.WaitForCondition(ctx, dep.Condition, timeout); err != nil {
+				errOnce.Do(func() {
+					if dep.Condition == "" {
+						firstErr = fmt.Errorf("dependency %s not ready for process %s: %w", depName, name, err)
+					} else {
+						firstErr = fmt.Errorf("dependency %s not %s for process %s: %w", depName, dep.Condition, name, err)
+					}
+					cancel()
+				})
+				return
+			}
+			m.logger.Debug("Dependency ready", "process", name, "dependency", depName)
+		}(supervisors[i], depName)
+	}
+	wg.Wait()
 
-		m.logger.Debug("Dependency ready", "process", name, "dependency", depName)
+	if firstErr != nil {
+		return firstErr
 	}
 
 	m.logger.Info("All dependencies ready", "process", name)
@@ -171,21 +234,79 @@ func (m *Manager) registerScheduledProcess(name string, procCfg *config.Process)
 		}
 	}
 
-	// Register with executor (includes log capture)
-	if err := m.scheduleExecutor.RegisterProcess(name, schedule.ProcessConfig{
+	calendar, err := schedule.NewCalendar(procCfg.ScheduleBlackout, procCfg.ScheduleWeekdaysOnly, procCfg.ScheduleSkipDates)
+	if err != nil {
+		return fmt.Errorf("invalid calendar for scheduled process %s: %w", name, err)
+	}
+
+	lock, err := m.scheduleLocker(name, procCfg)
+	if err != nil {
+		return err
+	}
+
+	execCfg := schedule.ProcessConfig{
 		Command:    procCfg.Command,
 		WorkingDir: procCfg.WorkingDir,
 		Env:        procCfg.Env,
 		Timeout:    timeout,
 		Logging:    procCfg.Logging,
-	}); err != nil {
+	}
+
+	// Unlike supervised processes, a scheduled process whose user cannot be
+	// resolved is not run at all rather than run as the manager's user
+	creds, err := ResolveCredentials(procCfg.User, procCfg.Group)
+	if err != nil {
+		return fmt.Errorf("invalid credentials for scheduled process %s: %w", name, err)
+	}
+	if creds != nil {
+		execCfg.Credential = &syscall.Credential{Uid: creds.Uid, Gid: creds.Gid}
+	}
+	if procCfg.Shutdown != nil {
+		if procCfg.Shutdown.Signal != "" {
+			execCfg.StopSignal = parseSignal(procCfg.Shutdown.Signal)
+		}
+		execCfg.StopTimeout = time.Duration(procCfg.Shutdown.Timeout) * time.Second
+	}
+
+	// Register with executor (includes log capture)
+	if err := m.scheduleExecutor.RegisterProcess(name, execCfg); err != nil {
 		return fmt.Errorf("failed to register scheduled process %s: %w", name, err)
 	}
 
 	// Add to scheduler with options
 	jobOpts := schedule.JobOptions{
 		Timeout:       timeout,
+		Overlap:       schedule.OverlapPolicy(procCfg.ScheduleOverlap),
 		MaxConcurrent: procCfg.ScheduleMaxConcurrent,
+		QueueSize:     procCfg.ScheduleQueueSize,
+		Gate:          m.scheduleGate(),
+		DisableAfter:  procCfg.ScheduleDisableAfter,
+		Jitter:        procCfg.ScheduleJitter,
+		Calendar:      calendar,
+		Lock:          lock,
+	}
+	if len(procCfg.Triggers.Targets()) > 0 {
+		jobOpts.OnComplete = m.handleJobCompletion
+	}
+	if lock != nil {
+		jobOpts.LockTTL = m.config.Global.ScheduleLock.TTL
+	}
+	if retry := procCfg.ScheduleRetry; retry != nil {
+		jobOpts.Retry = schedule.RetryPolicy{
+			MaxAttempts: retry.MaxAttempts,
+			Backoff:     retry.Backoff,
+			MaxBackoff:  retry.MaxBackoff,
+			Jitter:      retry.Jitter,
+			ExitCodes:   retry.ExitCodes,
+		}
+	}
+	if output := procCfg.ScheduleOutput; output != nil {
+		jobOpts.Output = schedule.OutputOptions{
+			HeadLines:     output.HeadLines,
+			TailLines:     output.TailLines,
+			MaxLineLength: output.MaxLineLength,
+			OnFailureOnly: output.OnFailureOnly,
+		}
 	}
 	if err := m.scheduler.AddJobWithOptions(name, procCfg.Schedule, procCfg.ScheduleTimezone, jobOpts); err != nil {
 		return fmt.Errorf("failed to schedule process %s: %w", name, err)
@@ -195,12 +316,25 @@ func (m *Manager) registerScheduledProcess(name string, procCfg *config.Process)
 		"name", name,
 		"schedule", procCfg.Schedule,
 		"timeout", timeout,
+		"overlap", procCfg.ScheduleOverlap,
 		"max_concurrent", procCfg.ScheduleMaxConcurrent,
+		"max_attempts", jobOpts.Retry.MaxAttempts,
+		"disable_after", procCfg.ScheduleDisableAfter,
+		"locked", lock != nil,
 	)
 	return nil
 }
 
-// startRegularProcess starts a non-scheduled process.
+// addSupervisor creates the supervisor of a non-scheduled process and
+// registers it with the manager.
+func (m *Manager) addSupervisor(name string, procCfg *config.Process) *Supervisor {
+	sup := m.newSupervisor(name, procCfg, &m.config.Global)
+	m.processes[name] = sup
+	return sup
+}
+
+// startRegularProcess starts a non-scheduled process registered with
+// addSupervisor.
 func (m *Manager) startRegularProcess(ctx context.Context, name string, procCfg *config.Process) error {
 	m.logger.Info("Starting process",
 		"name", name,
@@ -211,11 +345,10 @@ func (m *Manager) startRegularProcess(ctx context.Context, name string, procCfg
 	// Record desired scale
 	metrics.SetDesiredScale(name, procCfg.Scale)
 
-	// Create supervisor for this process
-	sup := NewSupervisor(name, procCfg, &m.config.Global, m.logger, m.auditLogger, m.resourceCollector)
-	sup.SetDeathNotifier(m.NotifyProcessDeath)
-	sup.SetOneshotHistory(m.oneshotHistory)
-	m.processes[name] = sup
+	sup, ok := m.processes[name]
+	if !ok {
+		return fmt.Errorf("process %s has no supervisor", name)
+	}
 
 	// Start the process only if initial_state is "running"
 	if procCfg.InitialState == "running" {
@@ -224,7 +357,7 @@ func (m *Manager) startRegularProcess(ctx context.Context, name string, procCfg
 			attribute.String("process_name", name),
 			attribute.Int("scale", procCfg.Scale))
 
-		if err := sup.Start(processCtx); err != nil {
+		if err := m.startSupervisor(processCtx, sup); err != nil {
 			tracing.RecordError(processSpan, err, "Failed to start process")
 			processSpan.End()
 			return fmt.Errorf("failed to start process %s: %w", name, err)
@@ -244,6 +377,72 @@ func (m *Manager) startRegularProcess(ctx context.Context, name string, procCfg
 	return nil
 }
 
+// startSupervisor starts sup, failing if that takes longer than
+// global.process_start_timeout. The started process keeps running under ctx,
+// which the timeout does not apply to. A process that starts after the
+// timeout is stopped again.
+func (m *Manager) startSupervisor(ctx context.Context, sup *Supervisor) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- sup.Start(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(m.processStartTimeout):
+		// Stop the process once Start returns, so that it does not keep
+		// running untracked after its start failed
+		go func() {
+			if err := <-errCh; err != nil {
+				return
+			}
+			stopCtx, cancel := context.WithTimeout(context.Background(), m.processStopTimeout)
+			defer cancel()
+			if err := sup.Stop(stopCtx); err != nil {
+				m.logger.Warn("Failed to stop process that started after its timeout",
+					"name", sup.name,
+					"error", err,
+				)
+			}
+		}()
+		return fmt.Errorf("process did not start within %v", m.processStartTimeout)
+	}
+}
+
+// runParallel calls fn for every name, at most global.process_parallelism at
+// a time (0 = all at once), and returns the errors in the order of names.
+func (m *Manager) runParallel(names []string, fn func(name string) error) []error {
+	limit := m.processParallelism
+	if limit <= 0 || limit > len(names) {
+		limit = len(names)
+	}
+
+	results := make([]error, len(names))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = fn(name)
+		}(i, name)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
 // Shutdown gracefully shuts down all processes in reverse dependency order.
 // It stops the scheduler, executes pre-stop hooks, stops all processes,
 // and executes post-stop hooks.
@@ -291,45 +490,18 @@ func (m *Manager) Shutdown(ctx context.Context) error {
 
 	m.logger.Info("Shutting down processes", "count", len(m.processes))
 
-	// Get shutdown order (reverse of startup order)
-	shutdownOrder := m.getShutdownOrder()
-
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(shutdownOrder))
-
-	// Shutdown processes in reverse order
-	for _, name := range shutdownOrder {
-		sup, ok := m.processes[name]
-		if !ok {
-			continue
-		}
-
-		wg.Add(1)
-		go func(name string, sup *Supervisor) {
-			defer wg.Done()
-
-			m.logger.Info("Stopping process", "name", name)
-
-			if err := sup.Stop(ctx); err != nil {
-				m.logger.Error("Failed to stop process",
-					"name", name,
-					"error", err,
-				)
-				errChan <- fmt.Errorf("process %s: %w", name, err)
-				return
-			}
-
-			m.logger.Info("Process stopped successfully", "name", name)
-		}(name, sup)
+	// Stop dependents before their dependencies: levels in reverse startup
+	// order, the processes of a level in parallel
+	var errs []error
+	for _, level := range m.getShutdownLevels() {
+		errs = append(errs, m.runParallel(level, func(name string) error {
+			return m.stopProcessForShutdown(ctx, name)
+		})...)
 	}
 
-	wg.Wait()
-	close(errChan)
-
-	// Collect errors
-	var errs []error
-	for err := range errChan {
-		errs = append(errs, err)
+	// Persist learned worker memory so the next start can use it
+	if err := m.SaveWorkerMemory(); err != nil {
+		m.logger.Warn("Failed to persist worker memory samples", "error", err)
 	}
 
 	// Execute post-stop hooks
@@ -350,28 +522,84 @@ func (m *Manager) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// getStartupOrder returns processes in startup order (topological sort).
-func (m *Manager) getStartupOrder() ([]string, error) {
-	// Use DAG-based topological sort with dependency resolution
+// stopProcessForShutdown stops a process during shutdown, waiting up to
+// global.process_stop_timeout so that one stuck process does not hold up the
+// remaining levels. Instances still running then are force killed.
+func (m *Manager) stopProcessForShutdown(ctx context.Context, name string) error {
+	sup, ok := m.processes[name]
+	if !ok {
+		return nil
+	}
+
+	m.logger.Info("Stopping process", "name", name)
+
+	stopCtx, cancel := context.WithTimeout(ctx, m.processStopTimeout)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- sup.Stop(stopCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-stopCtx.Done():
+		// Kill what is still shutting down gracefully and wait for Stop to
+		// return, so that no process outlives the shutdown
+		sup.Kill()
+		<-errCh
+		err = fmt.Errorf("process did not stop in time: %w", stopCtx.Err())
+	}
+	if err != nil {
+		m.logger.Error("Failed to stop process",
+			"name", name,
+			"error", err,
+		)
+		return fmt.Errorf("process %s: %w", name, err)
+	}
+
+	m.logger.Info("Process stopped successfully", "name", name)
+	return nil
+}
+
+// getStartupLevels returns processes grouped by dependency level, in startup
+// order (see deps.Graph.Levels).
+func (m *Manager) getStartupLevels() ([][]string, error) {
 	graph, err := deps.NewGraphFromConfig(m.config.Processes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
 	}
 
-	return graph.TopologicalSort()
+	return graph.Levels()
 }
 
-// getShutdownOrder returns processes in shutdown order (reverse of startup).
-func (m *Manager) getShutdownOrder() []string {
-	startupOrder, _ := m.getStartupOrder()
+// getShutdownLevels returns processes grouped by dependency level, in
+// shutdown order (reverse of startup). Running processes missing from the
+// dependency graph are stopped first.
+func (m *Manager) getShutdownLevels() [][]string {
+	startupLevels, _ := m.getStartupLevels()
+
+	known := make(map[string]bool)
+	shutdownLevels := make([][]string, 0, len(startupLevels)+1)
+	for i := len(startupLevels) - 1; i >= 0; i-- {
+		for _, name := range startupLevels[i] {
+			known[name] = true
+		}
+		shutdownLevels = append(shutdownLevels, startupLevels[i])
+	}
 
-	// Reverse the order
-	shutdownOrder := make([]string, len(startupOrder))
-	for i, name := range startupOrder {
-		shutdownOrder[len(startupOrder)-1-i] = name
+	var unknown []string
+	for name := range m.processes {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		shutdownLevels = append([][]string{unknown}, shutdownLevels...)
 	}
 
-	return shutdownOrder
+	return shutdownLevels
 }
 
 // StartProcess starts a stopped process by name.
@@ -0,0 +1,295 @@
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/logger"
+)
+
+// LogLevelSettings describes the daemon log level and any runtime override.
+type LogLevelSettings struct {
+	Level      string     `json:"level"`
+	Configured string     `json:"configured"`
+	Override   bool       `json:"override"`
+	RevertAt   *time.Time `json:"revert_at,omitempty"`
+}
+
+// LogSettings describes the effective log filtering of a single process.
+type LogSettings struct {
+	Process  string     `json:"process"`
+	MinLevel string     `json:"min_level"`
+	Exclude  []string   `json:"exclude,omitempty"`
+	Include  []string   `json:"include,omitempty"`
+	Override bool       `json:"override"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+// LogSettingsUpdate is a partial change to a process's log filtering.
+// An empty MinLevel keeps the current level and nil Filters keeps the
+// current include/exclude patterns.
+type LogSettingsUpdate struct {
+	MinLevel string
+	Filters  *config.FilterConfig
+}
+
+// logOverride tracks a runtime logging change and its optional revert timer.
+type logOverride struct {
+	timer    *time.Timer
+	revertAt time.Time
+}
+
+// stop cancels the pending revert, if any.
+func (o *logOverride) stop() {
+	if o != nil && o.timer != nil {
+		o.timer.Stop()
+	}
+}
+
+// revertAtPtr returns the revert deadline or nil when the override is permanent.
+func (o *logOverride) revertAtPtr() *time.Time {
+	if o == nil || o.revertAt.IsZero() {
+		return nil
+	}
+	t := o.revertAt
+	return &t
+}
+
+// GetLogLevel returns the current daemon log level.
+func (m *Manager) GetLogLevel() LogLevelSettings {
+	m.logOverridesMu.Lock()
+	override := m.logLevelOverride
+	m.logOverridesMu.Unlock()
+
+	return LogLevelSettings{
+		Level:      logger.GetLevel(),
+		Configured: m.configuredLogLevel(),
+		Override:   override != nil,
+		RevertAt:   override.revertAtPtr(),
+	}
+}
+
+// SetLogLevel changes the daemon log level without restarting anything.
+// If ttl > 0 the configured level is restored automatically after ttl.
+func (m *Manager) SetLogLevel(level string, ttl time.Duration) (LogLevelSettings, error) {
+	if err := logger.SetLevel(level); err != nil {
+		return LogLevelSettings{}, err
+	}
+
+	override := &logOverride{}
+	if ttl > 0 {
+		override.revertAt = time.Now().Add(ttl)
+		override.timer = time.AfterFunc(ttl, func() {
+			m.expireLogLevel(override)
+		})
+	}
+
+	m.logOverridesMu.Lock()
+	m.logLevelOverride.stop()
+	m.logLevelOverride = override
+	m.logOverridesMu.Unlock()
+
+	m.logger.Info("Log level changed at runtime",
+		"level", level,
+		"ttl", ttl,
+	)
+
+	return m.GetLogLevel(), nil
+}
+
+// ResetLogLevel restores the configured daemon log level.
+func (m *Manager) ResetLogLevel() LogLevelSettings {
+	m.logOverridesMu.Lock()
+	m.logLevelOverride.stop()
+	m.logLevelOverride = nil
+	m.logOverridesMu.Unlock()
+
+	m.restoreLogLevel()
+	return m.GetLogLevel()
+}
+
+// expireLogLevel reverts the level once a TTL elapses, unless the override
+// has been replaced in the meantime.
+func (m *Manager) expireLogLevel(override *logOverride) {
+	m.logOverridesMu.Lock()
+	if m.logLevelOverride != override {
+		m.logOverridesMu.Unlock()
+		return
+	}
+	m.logLevelOverride = nil
+	m.logOverridesMu.Unlock()
+
+	m.restoreLogLevel()
+}
+
+// restoreLogLevel applies the configured daemon log level.
+func (m *Manager) restoreLogLevel() {
+	level := m.configuredLogLevel()
+	if err := logger.SetLevel(level); err != nil {
+		_ = logger.SetLevel("info")
+	}
+	m.logger.Info("Log level restored", "level", logger.GetLevel())
+}
+
+// configuredLogLevel returns the log level from the global configuration.
+func (m *Manager) configuredLogLevel() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.config.Global.LogLevel == "" {
+		return "info"
+	}
+	return m.config.Global.LogLevel
+}
+
+// GetProcessLogSettings returns the log filtering currently applied to a
+// supervised or scheduled process.
+func (m *Manager) GetProcessLogSettings(processName string) (LogSettings, error) {
+	filters, err := m.processLogFilters(processName)
+	if err != nil {
+		return LogSettings{}, err
+	}
+
+	m.logOverridesMu.Lock()
+	override := m.logOverrides[processName]
+	m.logOverridesMu.Unlock()
+
+	settings := LogSettings{
+		Process:  processName,
+		MinLevel: "info",
+		Override: override != nil,
+		RevertAt: override.revertAtPtr(),
+	}
+	if filters != nil {
+		settings.MinLevel = logger.LevelName(filters.GetMinLevel())
+		if cfg := filters.Config(); cfg != nil {
+			settings.Exclude = cfg.Exclude
+			settings.Include = cfg.Include
+		}
+	}
+
+	return settings, nil
+}
+
+// SetProcessLogSettings changes a process's minimum level and filters on its
+// existing log writers, without touching the running process.
+// If ttl > 0 the configured settings are restored automatically after ttl.
+func (m *Manager) SetProcessLogSettings(processName string, update LogSettingsUpdate, ttl time.Duration) (LogSettings, error) {
+	current, err := m.processLogFilters(processName)
+	if err != nil {
+		return LogSettings{}, err
+	}
+
+	minLevel := update.MinLevel
+	filterCfg := update.Filters
+	if current != nil {
+		if minLevel == "" {
+			minLevel = logger.LevelName(current.GetMinLevel())
+		}
+		if filterCfg == nil {
+			filterCfg = current.Config()
+		}
+	}
+
+	filters, err := logger.NewLogFilters(filterCfg, minLevel)
+	if err != nil {
+		return LogSettings{}, fmt.Errorf("invalid log settings: %w", err)
+	}
+
+	if err := m.applyLogFilters(processName, filters); err != nil {
+		return LogSettings{}, err
+	}
+
+	override := &logOverride{}
+	if ttl > 0 {
+		override.revertAt = time.Now().Add(ttl)
+		override.timer = time.AfterFunc(ttl, func() {
+			m.expireProcessLogSettings(processName, override)
+		})
+	}
+
+	m.logOverridesMu.Lock()
+	m.logOverrides[processName].stop()
+	m.logOverrides[processName] = override
+	m.logOverridesMu.Unlock()
+
+	m.logger.Info("Process log settings changed at runtime",
+		"process", processName,
+		"min_level", logger.LevelName(filters.GetMinLevel()),
+		"ttl", ttl,
+	)
+
+	return m.GetProcessLogSettings(processName)
+}
+
+// ResetProcessLogSettings restores the configured log filtering of a process.
+func (m *Manager) ResetProcessLogSettings(processName string) (LogSettings, error) {
+	m.logOverridesMu.Lock()
+	m.logOverrides[processName].stop()
+	delete(m.logOverrides, processName)
+	m.logOverridesMu.Unlock()
+
+	if err := m.applyLogFilters(processName, nil); err != nil {
+		return LogSettings{}, err
+	}
+
+	return m.GetProcessLogSettings(processName)
+}
+
+// expireProcessLogSettings reverts a process override once its TTL elapses,
+// unless the override has been replaced in the meantime.
+func (m *Manager) expireProcessLogSettings(processName string, override *logOverride) {
+	m.logOverridesMu.Lock()
+	if m.logOverrides[processName] != override {
+		m.logOverridesMu.Unlock()
+		return
+	}
+	delete(m.logOverrides, processName)
+	m.logOverridesMu.Unlock()
+
+	if err := m.applyLogFilters(processName, nil); err != nil {
+		m.logger.Debug("Could not restore process log settings",
+			"process", processName,
+			"error", err,
+		)
+		return
+	}
+	m.logger.Info("Process log settings restored", "process", processName)
+}
+
+// processLogFilters returns the filters in effect for a supervised or
+// scheduled process.
+func (m *Manager) processLogFilters(processName string) (*logger.LogFilters, error) {
+	m.mu.RLock()
+	sup, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if exists {
+		return sup.LogFilters(), nil
+	}
+
+	if m.scheduleExecutor != nil && m.scheduleExecutor.HasProcess(processName) {
+		return m.scheduleExecutor.LogFilters(processName), nil
+	}
+
+	return nil, fmt.Errorf("process not found: %s", processName)
+}
+
+// applyLogFilters installs filters on a process's writers; nil restores the
+// configured filters.
+func (m *Manager) applyLogFilters(processName string, filters *logger.LogFilters) error {
+	m.mu.RLock()
+	sup, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if exists {
+		return sup.SetLogFilters(filters)
+	}
+
+	if m.scheduleExecutor != nil && m.scheduleExecutor.HasProcess(processName) {
+		return m.scheduleExecutor.SetLogFilters(processName, filters)
+	}
+
+	return fmt.Errorf("process not found: %s", processName)
+}
@@ -0,0 +1,201 @@
+package process
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/audit"
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/logger"
+)
+
+func createLoggingTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	cfg := &config.Config{
+		Global: config.GlobalConfig{
+			ShutdownTimeout:    5,
+			LogLevel:           "warn",
+			MaxRestartAttempts: 3,
+			RestartBackoff:     1,
+		},
+		Processes: map[string]*config.Process{
+			"worker": {
+				Enabled: true,
+				Command: []string{"sleep", "10"},
+				Restart: "never",
+				Scale:   1,
+				Logging: &config.LoggingConfig{
+					Stdout:   true,
+					Stderr:   true,
+					MinLevel: "info",
+					Filters:  &config.FilterConfig{Exclude: []string{"healthcheck"}},
+				},
+			},
+			"cron-task": {
+				Enabled:  true,
+				Type:     "scheduled",
+				Schedule: "*/5 * * * *",
+				Command:  []string{"echo", "scheduled"},
+				Restart:  "never",
+				Scale:    1,
+			},
+		},
+	}
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, log, audit.NewLogger(log, false))
+
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start manager: %v", err)
+	}
+	t.Cleanup(func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = manager.Shutdown(shutdownCtx)
+	})
+
+	return manager
+}
+
+func TestManager_ProcessLogSettings(t *testing.T) {
+	manager := createLoggingTestManager(t)
+
+	settings, err := manager.GetProcessLogSettings("worker")
+	if err != nil {
+		t.Fatalf("GetProcessLogSettings() error = %v", err)
+	}
+	if settings.MinLevel != "info" || settings.Override {
+		t.Errorf("initial settings = %+v, want configured info level", settings)
+	}
+	if len(settings.Exclude) != 1 || settings.Exclude[0] != "healthcheck" {
+		t.Errorf("Exclude = %v, want [healthcheck]", settings.Exclude)
+	}
+
+	// Partial update keeps existing patterns
+	settings, err = manager.SetProcessLogSettings("worker", LogSettingsUpdate{MinLevel: "debug"}, 0)
+	if err != nil {
+		t.Fatalf("SetProcessLogSettings() error = %v", err)
+	}
+	if settings.MinLevel != "debug" || !settings.Override || settings.RevertAt != nil {
+		t.Errorf("updated settings = %+v, want permanent debug override", settings)
+	}
+	if len(settings.Exclude) != 1 {
+		t.Errorf("partial update dropped exclude patterns: %v", settings.Exclude)
+	}
+
+	// Replacing filters
+	settings, err = manager.SetProcessLogSettings("worker", LogSettingsUpdate{
+		Filters: &config.FilterConfig{Include: []string{"order"}},
+	}, 0)
+	if err != nil {
+		t.Fatalf("SetProcessLogSettings(filters) error = %v", err)
+	}
+	if len(settings.Exclude) != 0 || len(settings.Include) != 1 || settings.MinLevel != "debug" {
+		t.Errorf("filter replacement = %+v", settings)
+	}
+
+	settings, err = manager.ResetProcessLogSettings("worker")
+	if err != nil {
+		t.Fatalf("ResetProcessLogSettings() error = %v", err)
+	}
+	if settings.MinLevel != "info" || settings.Override || len(settings.Exclude) != 1 {
+		t.Errorf("reset settings = %+v, want configured values", settings)
+	}
+}
+
+func TestManager_ProcessLogSettings_TTL(t *testing.T) {
+	manager := createLoggingTestManager(t)
+
+	settings, err := manager.SetProcessLogSettings("worker", LogSettingsUpdate{MinLevel: "error"}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SetProcessLogSettings() error = %v", err)
+	}
+	if settings.RevertAt == nil {
+		t.Fatal("RevertAt should be set when ttl > 0")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		settings, _ = manager.GetProcessLogSettings("worker")
+		if !settings.Override {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if settings.Override || settings.MinLevel != "info" {
+		t.Errorf("settings after ttl = %+v, want configured info level", settings)
+	}
+}
+
+func TestManager_ProcessLogSettings_Scheduled(t *testing.T) {
+	manager := createLoggingTestManager(t)
+
+	settings, err := manager.SetProcessLogSettings("cron-task", LogSettingsUpdate{MinLevel: "warn"}, 0)
+	if err != nil {
+		t.Fatalf("SetProcessLogSettings(scheduled) error = %v", err)
+	}
+	if settings.MinLevel != "warn" {
+		t.Errorf("scheduled MinLevel = %s, want warn", settings.MinLevel)
+	}
+
+	if _, err := manager.ResetProcessLogSettings("cron-task"); err != nil {
+		t.Errorf("ResetProcessLogSettings(scheduled) error = %v", err)
+	}
+}
+
+func TestManager_ProcessLogSettings_Errors(t *testing.T) {
+	manager := createLoggingTestManager(t)
+
+	if _, err := manager.GetProcessLogSettings("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("GetProcessLogSettings(missing) error = %v, want not found", err)
+	}
+	if _, err := manager.SetProcessLogSettings("missing", LogSettingsUpdate{MinLevel: "debug"}, 0); err == nil {
+		t.Error("SetProcessLogSettings(missing) should fail")
+	}
+	if _, err := manager.SetProcessLogSettings("worker", LogSettingsUpdate{MinLevel: "verbose"}, 0); err == nil {
+		t.Error("SetProcessLogSettings(invalid level) should fail")
+	}
+	if _, err := manager.SetProcessLogSettings("worker", LogSettingsUpdate{
+		Filters: &config.FilterConfig{Exclude: []string{"("}},
+	}, 0); err == nil {
+		t.Error("SetProcessLogSettings(invalid pattern) should fail")
+	}
+}
+
+func TestManager_LogLevel(t *testing.T) {
+	manager := createLoggingTestManager(t)
+	defer func() { _ = logger.SetLevel("info") }()
+
+	if _, err := manager.SetLogLevel("verbose", 0); err == nil {
+		t.Error("SetLogLevel(invalid) should fail")
+	}
+
+	settings, err := manager.SetLogLevel("debug", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SetLogLevel() error = %v", err)
+	}
+	if settings.Level != "debug" || settings.Configured != "warn" || !settings.Override || settings.RevertAt == nil {
+		t.Errorf("settings = %+v, want temporary debug override", settings)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && manager.GetLogLevel().Override {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := manager.GetLogLevel(); got.Override || got.Level != "warn" {
+		t.Errorf("level after ttl = %+v, want configured warn", got)
+	}
+
+	if _, err := manager.SetLogLevel("error", 0); err != nil {
+		t.Fatalf("SetLogLevel() error = %v", err)
+	}
+	if got := manager.ResetLogLevel(); got.Override || got.Level != "warn" {
+		t.Errorf("ResetLogLevel() = %+v, want configured warn", got)
+	}
+}
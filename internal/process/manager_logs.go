@@ -52,3 +52,56 @@ func (m *Manager) GetStackLogs(limit int) []logger.LogEntry {
 
 	return allLogs
 }
+
+// QueryLogs returns log entries for a specific process that match the query,
+// newest first. Supports both supervised and scheduled processes.
+// Returns error if process doesn't exist.
+func (m *Manager) QueryLogs(processName string, q logger.LogQuery) ([]logger.LogEntry, error) {
+	m.mu.RLock()
+	sup, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if exists {
+		return sup.QueryLogs(q), nil
+	}
+
+	if m.scheduleExecutor != nil && m.scheduleExecutor.HasProcess(processName) {
+		return m.scheduleExecutor.QueryLogs(processName, q), nil
+	}
+
+	return nil, fmt.Errorf("process not found: %s", processName)
+}
+
+// QueryStackLogs returns log entries across every supervised and scheduled
+// process that match the query, newest first and capped by q.Limit.
+func (m *Manager) QueryStackLogs(q logger.LogQuery) []logger.LogEntry {
+	m.mu.RLock()
+	results := make([][]logger.LogEntry, 0, len(m.processes))
+	for _, sup := range m.processes {
+		results = append(results, sup.QueryLogs(q))
+	}
+	m.mu.RUnlock()
+
+	if m.scheduler != nil && m.scheduleExecutor != nil {
+		for name := range m.scheduler.GetAllJobs() {
+			results = append(results, m.scheduleExecutor.QueryLogs(name, q))
+		}
+	}
+
+	return logger.MergeQueryResults(q, results...)
+}
+
+// GetRecentCrashes returns the recent crashes of a process with the log
+// lines captured at crash time, newest first (limit <= 0 = all).
+// Returns error if process doesn't exist.
+func (m *Manager) GetRecentCrashes(processName string, limit int) ([]CrashRecord, error) {
+	m.mu.RLock()
+	sup, exists := m.processes[processName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("process not found: %s", processName)
+	}
+
+	return sup.GetRecentCrashes(limit), nil
+}
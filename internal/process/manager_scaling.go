@@ -67,6 +67,9 @@ func (m *Manager) ScaleProcess(ctx context.Context, name string, desiredScale in
 
 	// SCALE UP: Start new instances
 	if desiredScale > currentScale {
+		if err := m.checkScaleUpPressure(); err != nil {
+			return err
+		}
 		if err := sup.ScaleUp(ctx, desiredScale); err != nil {
 			return fmt.Errorf("scale up failed: %w", err)
 		}
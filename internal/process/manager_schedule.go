@@ -2,10 +2,42 @@ package process
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
+// initScheduleLock creates the leader lock of scheduled jobs when
+// global.schedule_lock is set. Errors are reported when a job that uses the
+// lock is registered, so that the manager never runs it unlocked.
+func (m *Manager) initScheduleLock() {
+	cfg := m.config.Global.ScheduleLock
+	if cfg == nil {
+		return
+	}
+
+	m.scheduleLock, m.scheduleLockErr = schedule.NewLocker(cfg)
+	if m.scheduleLockErr != nil {
+		m.logger.Error("Failed to create schedule lock", "backend", cfg.Backend, "error", m.scheduleLockErr)
+		return
+	}
+	m.logger.Info("Schedule lock enabled", "backend", cfg.Backend, "ttl", cfg.TTL)
+}
+
+// scheduleLocker returns the lock a scheduled process takes before its runs
+// (nil = none)
+func (m *Manager) scheduleLocker(name string, procCfg *config.Process) (schedule.Locker, error) {
+	if !procCfg.ScheduleLockValue(m.config.Global.ScheduleLock) {
+		return nil, nil
+	}
+	if m.scheduleLockErr != nil {
+		return nil, fmt.Errorf("schedule lock unavailable for scheduled process %s: %w", name, m.scheduleLockErr)
+	}
+	return m.scheduleLock, nil
+}
+
 // GetScheduler returns the scheduler for scheduled processes.
 func (m *Manager) GetScheduler() *schedule.Scheduler {
 	return m.scheduler
@@ -26,6 +58,12 @@ func (m *Manager) GetScheduleHistory(name string, limit int) ([]schedule.Executi
 	return m.scheduler.GetJobHistory(name, limit)
 }
 
+// GetScheduleOutput returns the captured output of an execution of a
+// scheduled job.
+func (m *Manager) GetScheduleOutput(name string, executionID int64) (schedule.ExecutionLogs, error) {
+	return m.scheduler.GetJobOutput(name, executionID)
+}
+
 // PauseSchedule pauses a scheduled job.
 func (m *Manager) PauseSchedule(name string) error {
 	return m.scheduler.PauseJob(name)
@@ -45,3 +83,22 @@ func (m *Manager) TriggerSchedule(ctx context.Context, name string) error {
 func (m *Manager) TriggerScheduleSync(ctx context.Context, name string) (int, error) {
 	return m.scheduler.TriggerJobSync(ctx, name)
 }
+
+// PreviewSchedule computes the next count runs of a scheduled process after
+// from, as configured (count <= 0 uses the default, a zero from means now).
+func (m *Manager) PreviewSchedule(name string, count int, from time.Time) (*schedule.Preview, error) {
+	m.mu.RLock()
+	procCfg, exists := m.config.Processes[name]
+	m.mu.RUnlock()
+	if !exists || procCfg.Schedule == "" {
+		return nil, fmt.Errorf("scheduled process %q not found", name)
+	}
+
+	opts, err := schedule.PreviewOptionsFromConfig(procCfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule for process %s: %w", name, err)
+	}
+	opts.Count = count
+	opts.From = from
+	return schedule.PreviewSchedule(procCfg.Schedule, opts)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gophpeek/phpeek-pm/internal/audit"
 	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // createScheduleTestManager creates a manager with scheduler for testing
@@ -393,3 +394,195 @@ func TestManager_NilOneshotHistory(t *testing.T) {
 		t.Error("GetOneshotStats with nil history should return empty stats with non-nil ByProcess")
 	}
 }
+
+// TestManager_RegisterScheduledProcess_UnknownUser verifies a scheduled process
+// is not run as the manager's user when its user cannot be resolved
+func TestManager_RegisterScheduledProcess_UnknownUser(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{LogLevel: "error"},
+		Processes: map[string]*config.Process{
+			"backup": {
+				Enabled:  true,
+				Schedule: "0 2 * * *",
+				Command:  []string{"echo", "backup"},
+				User:     "phpeek-no-such-user",
+			},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+
+	err := manager.registerScheduledProcess("backup", cfg.Processes["backup"])
+	if err == nil {
+		t.Fatal("registerScheduledProcess() should fail for an unknown user")
+	}
+	if manager.scheduleExecutor.HasProcess("backup") {
+		t.Error("process should not be registered with the executor")
+	}
+}
+
+// TestManager_RegisterScheduledProcess_ScheduleLock verifies scheduled
+// processes take the global schedule lock unless they opt out
+func TestManager_RegisterScheduledProcess_ScheduleLock(t *testing.T) {
+	lockDir := t.TempDir()
+	unlocked := false
+	cfg := &config.Config{
+		Global: config.GlobalConfig{
+			LogLevel: "error",
+			ScheduleLock: &config.ScheduleLockConfig{
+				Backend:  "file",
+				TTL:      30 * time.Second,
+				Identity: "replica-a",
+				File:     &config.FileLockConfig{Dir: lockDir},
+			},
+		},
+		Processes: map[string]*config.Process{
+			"backup": {Enabled: true, Schedule: "0 2 * * *", Command: []string{"true"}},
+			"report": {Enabled: true, Schedule: "0 3 * * *", Command: []string{"true"}, ScheduleLock: &unlocked},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	if manager.scheduleLock == nil {
+		t.Fatalf("schedule lock not created: %v", manager.scheduleLockErr)
+	}
+
+	for name, procCfg := range cfg.Processes {
+		if err := manager.registerScheduledProcess(name, procCfg); err != nil {
+			t.Fatalf("registerScheduledProcess(%s) error = %v", name, err)
+		}
+	}
+
+	// Hold the lock from another replica, backup must skip while report runs
+	other, err := schedule.NewFileLocker(lockDir, "replica-b")
+	if err != nil {
+		t.Fatalf("NewFileLocker() error = %v", err)
+	}
+	if ok, _ := other.Acquire(context.Background(), "backup", time.Minute); !ok {
+		t.Fatal("other replica should get the lock")
+	}
+	defer other.Release(context.Background(), "backup")
+
+	if _, err := manager.TriggerScheduleSync(context.Background(), "backup"); err == nil || err.Error() != "not leader" {
+		t.Errorf("backup: TriggerScheduleSync() error = %v, want not leader", err)
+	}
+	if exitCode, err := manager.TriggerScheduleSync(context.Background(), "report"); err != nil || exitCode != 0 {
+		t.Errorf("report: TriggerScheduleSync() = %d, %v, want 0", exitCode, err)
+	}
+}
+
+// TestManager_RegisterScheduledProcess_ScheduleLockError verifies a process
+// that needs the lock is not scheduled unlocked when the lock is unavailable
+func TestManager_RegisterScheduledProcess_ScheduleLockError(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{
+			LogLevel:     "error",
+			ScheduleLock: &config.ScheduleLockConfig{Backend: "file", TTL: 30 * time.Second, Identity: "a"},
+		},
+		Processes: map[string]*config.Process{
+			"backup": {Enabled: true, Schedule: "0 2 * * *", Command: []string{"true"}},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+
+	if err := manager.registerScheduledProcess("backup", cfg.Processes["backup"]); err == nil {
+		t.Fatal("registerScheduledProcess() should fail when the schedule lock is unavailable")
+	}
+}
+
+// TestManager_PreviewSchedule verifies previews use the process's schedule
+// settings and reject processes without a schedule
+func TestManager_PreviewSchedule(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{LogLevel: "error"},
+		Processes: map[string]*config.Process{
+			"backup": {
+				Enabled:          true,
+				Schedule:         "*/10 * * * *",
+				ScheduleTimezone: "UTC",
+				ScheduleTimeout:  "15m",
+				Command:          []string{"true"},
+			},
+			"web": {Enabled: true, Command: []string{"true"}},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+
+	from := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	preview, err := manager.PreviewSchedule("backup", 3, from)
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	if len(preview.Runs) != 3 {
+		t.Fatalf("got %d runs, want 3", len(preview.Runs))
+	}
+	if got := preview.Runs[0].Time.Format("15:04"); got != "10:10" {
+		t.Errorf("first run = %s, want 10:10", got)
+	}
+	if !preview.Runs[0].Overlap {
+		t.Error("a 15m timeout should overlap a 10m schedule")
+	}
+
+	if _, err := manager.PreviewSchedule("web", 3, from); err == nil {
+		t.Error("PreviewSchedule() should fail for a process without schedule")
+	}
+	if _, err := manager.PreviewSchedule("missing", 3, from); err == nil {
+		t.Error("PreviewSchedule() should fail for an unknown process")
+	}
+}
+
+// TestManager_GetScheduleOutput verifies the output of scheduled executions
+// is captured with the process's schedule_output limits
+func TestManager_GetScheduleOutput(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{LogLevel: "error"},
+		Processes: map[string]*config.Process{
+			"report": {
+				Enabled:        true,
+				Schedule:       "0 3 * * *",
+				Command:        []string{"sh", "-c", "for i in 1 2 3 4 5; do echo line $i; done"},
+				ScheduleOutput: &config.ScheduleOutput{HeadLines: 1, TailLines: 2, MaxLineLength: 100},
+			},
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	if err := manager.registerScheduledProcess("report", cfg.Processes["report"]); err != nil {
+		t.Fatalf("registerScheduledProcess() error = %v", err)
+	}
+
+	if exitCode, err := manager.TriggerScheduleSync(context.Background(), "report"); err != nil || exitCode != 0 {
+		t.Fatalf("TriggerScheduleSync() = %d, %v, want 0", exitCode, err)
+	}
+
+	history, err := manager.GetScheduleHistory("report", 1)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("GetScheduleHistory() = %v, %v", history, err)
+	}
+	logs, err := manager.GetScheduleOutput("report", history[0].ID)
+	if err != nil {
+		t.Fatalf("GetScheduleOutput() error = %v", err)
+	}
+
+	// Head keeps the start event, tail the last output line and exit event
+	if logs.TruncatedLines != 4 || len(logs.Lines) != 4 {
+		t.Fatalf("GetScheduleOutput() = %+v, want 4 truncated lines", logs)
+	}
+	if logs.Lines[2].Message != "line 5" {
+		t.Errorf("last output line = %q, want line 5", logs.Lines[2].Message)
+	}
+
+	if _, err := manager.GetScheduleOutput("report", 99); err == nil {
+		t.Error("expected error for unknown execution")
+	}
+	if _, err := manager.GetScheduleOutput("missing", 1); err == nil {
+		t.Error("expected error for unknown job")
+	}
+}
@@ -2,8 +2,12 @@ package process
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -858,3 +862,323 @@ func TestManager_GetConfig(t *testing.T) {
 		t.Error("Expected nil config for non-existent process")
 	}
 }
+
+// TestManager_Start_DependencyConditions tests long form depends_on entries
+func TestManager_Start_DependencyConditions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(logger, false)
+
+	newConfig := func(migrate []string, dep *config.Dependency, app []string) *config.Config {
+		return &config.Config{
+			Global: config.GlobalConfig{
+				ShutdownTimeout:    5,
+				LogLevel:           "error",
+				MaxRestartAttempts: 1,
+				RestartBackoff:     1,
+				DependencyTimeout:  5 * time.Second,
+			},
+			Processes: map[string]*config.Process{
+				"migrate": {Enabled: true, Type: "oneshot", InitialState: "running", Command: migrate, Restart: "never", Scale: 1},
+				"app": {
+					Enabled:          true,
+					Type:             "longrun",
+					InitialState:     "running",
+					Command:          app,
+					Restart:          "never",
+					Scale:            1,
+					DependsOn:        []string{"migrate"},
+					DependsOnOptions: map[string]*config.Dependency{"migrate": dep},
+				},
+			},
+		}
+	}
+	shutdown := func(manager *Manager) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = manager.Shutdown(ctx)
+	}
+
+	t.Run("waits for the oneshot to complete", func(t *testing.T) {
+		marker := filepath.Join(t.TempDir(), "migrated")
+		manager := NewManager(newConfig(
+			[]string{"sh", "-c", "sleep 0.3 && touch " + marker},
+			&config.Dependency{Condition: config.DependencyCompletedSuccessfully},
+			[]string{"sh", "-c", "test -f " + marker + " && sleep 3600"},
+		), logger, auditLogger)
+		defer shutdown(manager)
+
+		if err := manager.Start(context.Background()); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if _, err := os.Stat(marker); err != nil {
+			t.Fatalf("app started before migrate completed: %v", err)
+		}
+		testutil.Eventually(t, func() bool {
+			for _, info := range manager.ListProcesses() {
+				if info.Name == "app" {
+					return info.State == "running"
+				}
+			}
+			return false
+		}, "app to keep running", 2*time.Second)
+	})
+
+	t.Run("fails when the oneshot fails", func(t *testing.T) {
+		manager := NewManager(newConfig(
+			[]string{"false"},
+			&config.Dependency{Condition: config.DependencyCompletedSuccessfully},
+			[]string{"sleep", "3600"},
+		), logger, auditLogger)
+		defer shutdown(manager)
+
+		err := manager.Start(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "dependency migrate not completed_successfully for process app") {
+			t.Errorf("Start() error = %v, want failed dependency", err)
+		}
+	})
+
+	t.Run("uses the dependency timeout", func(t *testing.T) {
+		manager := NewManager(newConfig(
+			[]string{"sleep", "10"},
+			&config.Dependency{Condition: config.DependencyCompletedSuccessfully, Timeout: 200 * time.Millisecond},
+			[]string{"sleep", "3600"},
+		), logger, auditLogger)
+		defer shutdown(manager)
+
+		started := time.Now()
+		err := manager.Start(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "did not complete within 200ms") {
+			t.Errorf("Start() error = %v, want timeout", err)
+		}
+		if elapsed := time.Since(started); elapsed > 3*time.Second {
+			t.Errorf("Start() took %v, want the 200ms dependency timeout", elapsed)
+		}
+	})
+
+	t.Run("stops waiting after the first failure", func(t *testing.T) {
+		cfg := newConfig([]string{"false"}, &config.Dependency{Condition: config.DependencyCompletedSuccessfully}, []string{"sleep", "3600"})
+		cfg.Processes["warmup"] = &config.Process{Enabled: true, Type: "oneshot", InitialState: "running", Command: []string{"sleep", "10"}, Restart: "never", Scale: 1}
+		app := cfg.Processes["app"]
+		app.DependsOn = []string{"warmup", "migrate"}
+		app.DependsOnOptions["warmup"] = &config.Dependency{Condition: config.DependencyCompletedSuccessfully}
+		manager := NewManager(cfg, logger, auditLogger)
+		defer shutdown(manager)
+
+		started := time.Now()
+		err := manager.Start(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "dependency migrate not completed_successfully") || strings.Contains(err.Error(), "warmup") {
+			t.Errorf("Start() error = %v, want only the failed dependency", err)
+		}
+		if elapsed := time.Since(started); elapsed > 3*time.Second {
+			t.Errorf("Start() took %v, want to stop waiting for warmup", elapsed)
+		}
+	})
+
+	t.Run("checks all dependencies before waiting", func(t *testing.T) {
+		manager := NewManager(newConfig([]string{"sleep", "10"}, &config.Dependency{Condition: config.DependencyCompletedSuccessfully}, []string{"sleep", "3600"}), logger, auditLogger)
+		manager.processes["migrate"] = &Supervisor{}
+
+		started := time.Now()
+		err := manager.waitForDependencies(context.Background(), "app", &config.Process{DependsOn: []string{"migrate", "missing"}})
+		if err == nil || err.Error() != "dependency missing not found for process app" {
+			t.Errorf("waitForDependencies() error = %v, want missing dependency", err)
+		}
+		if elapsed := time.Since(started); elapsed > time.Second {
+			t.Errorf("waitForDependencies() took %v, want to fail before waiting", elapsed)
+		}
+	})
+}
+
+// TestManager_RunParallel tests the parallelism cap of startup and shutdown levels
+func TestManager_RunParallel(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(logger, false)
+
+	for _, tt := range []struct {
+		parallelism int
+		wantMax     int32
+	}{
+		{parallelism: 0, wantMax: 6},
+		{parallelism: 2, wantMax: 2},
+		{parallelism: 1, wantMax: 1},
+	} {
+		cfg := &config.Config{Global: config.GlobalConfig{LogLevel: "error", ProcessParallelism: tt.parallelism}}
+		manager := NewManager(cfg, logger, auditLogger)
+
+		var running, maxRunning atomic.Int32
+		names := []string{"a", "b", "c", "d", "e", "f"}
+		errs := manager.runParallel(names, func(name string) error {
+			n := running.Add(1)
+			for {
+				if m := maxRunning.Load(); n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			running.Add(-1)
+			if name == "b" || name == "e" {
+				return fmt.Errorf("%s failed", name)
+			}
+			return nil
+		})
+
+		if got := maxRunning.Load(); got != tt.wantMax {
+			t.Errorf("parallelism %d: max concurrent = %d, want %d", tt.parallelism, got, tt.wantMax)
+		}
+		if len(errs) != 2 || errs[0].Error() != "b failed" || errs[1].Error() != "e failed" {
+			t.Errorf("parallelism %d: errors = %v, want b and e in order", tt.parallelism, errs)
+		}
+	}
+}
+
+// TestManager_GetShutdownLevels tests that dependents stop before their dependencies
+func TestManager_GetShutdownLevels(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{LogLevel: "error"},
+		Processes: map[string]*config.Process{
+			"db":     {Enabled: true, Command: []string{"sleep", "1"}},
+			"cache":  {Enabled: true, Command: []string{"sleep", "1"}},
+			"app":    {Enabled: true, Command: []string{"sleep", "1"}, DependsOn: []string{"db", "cache"}},
+			"nginx":  {Enabled: true, Command: []string{"sleep", "1"}, DependsOn: []string{"app"}},
+			"worker": {Enabled: true, Command: []string{"sleep", "1"}, DependsOn: []string{"db"}},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+
+	startup, err := manager.getStartupLevels()
+	if err != nil {
+		t.Fatalf("getStartupLevels() error = %v", err)
+	}
+	if got := fmt.Sprint(startup); got != "[[cache db] [app worker] [nginx]]" {
+		t.Errorf("getStartupLevels() = %s", got)
+	}
+
+	// Processes added outside the config are stopped first
+	manager.processes["adhoc"] = nil
+	if got := fmt.Sprint(manager.getShutdownLevels()); got != "[[adhoc] [nginx] [app worker] [cache db]]" {
+		t.Errorf("getShutdownLevels() = %s", got)
+	}
+}
+
+// TestManager_Shutdown_DependencyOrder tests that shutdown stops levels in reverse startup order
+func TestManager_Shutdown_DependencyOrder(t *testing.T) {
+	stopLog := filepath.Join(t.TempDir(), "stopped")
+	process := func(name string, dependsOn ...string) *config.Process {
+		return &config.Process{
+			Enabled:      true,
+			Type:         "longrun",
+			InitialState: "running",
+			Command:      []string{"sleep", "3600"},
+			Restart:      "never",
+			Scale:        1,
+			DependsOn:    dependsOn,
+			Shutdown: &config.ShutdownConfig{
+				Timeout: 1,
+				PreStopHook: &config.Hook{
+					Name:    "log-" + name,
+					Command: []string{"sh", "-c", "echo " + name + " >> " + stopLog},
+					Timeout: 2,
+				},
+			},
+		}
+	}
+	cfg := &config.Config{
+		Global: config.GlobalConfig{
+			ShutdownTimeout:    10,
+			LogLevel:           "error",
+			MaxRestartAttempts: 1,
+			RestartBackoff:     1,
+			ProcessStopTimeout: 5 * time.Second,
+		},
+		Processes: map[string]*config.Process{
+			"db":    process("db"),
+			"app":   process("app", "db"),
+			"nginx": process("nginx", "app"),
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Logf("Shutdown with errors (may be expected): %v", err)
+	}
+
+	data, err := os.ReadFile(stopLog)
+	if err != nil {
+		t.Fatalf("pre-stop hooks did not run: %v", err)
+	}
+	if got := strings.Fields(string(data)); strings.Join(got, ",") != "nginx,app,db" {
+		t.Errorf("stop order = %v, want nginx, app, db", got)
+	}
+}
+
+func TestManager_StartSupervisor_Timeout(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{ShutdownTimeout: 5, LogLevel: "error", ProcessStartTimeout: 100 * time.Millisecond},
+		Processes: map[string]*config.Process{
+			"web": {Enabled: true, InitialState: "running", Type: "longrun", Command: []string{"sleep", "30"}, Restart: "never", Scale: 1},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	sup := manager.newSupervisor("web", cfg.Processes["web"], &cfg.Global)
+
+	// Hold up Start until after the timeout
+	sup.operationMu.Lock()
+	err := manager.startSupervisor(context.Background(), sup)
+	if err == nil || !strings.Contains(err.Error(), "did not start within") {
+		t.Fatalf("startSupervisor() error = %v, want a timeout", err)
+	}
+	sup.operationMu.Unlock()
+
+	// The late start is stopped again
+	testutil.Eventually(t, func() bool {
+		sup.mu.RLock()
+		defer sup.mu.RUnlock()
+		return sup.started && sup.state == StateStopped
+	}, "late start to be stopped")
+}
+
+func TestManager_StopProcessForShutdown_Timeout(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{ShutdownTimeout: 5, LogLevel: "error", ProcessStopTimeout: 200 * time.Millisecond},
+		Processes: map[string]*config.Process{
+			"web": {Enabled: true, InitialState: "running", Type: "longrun", Command: []string{"sleep", "30"}, Restart: "never", Scale: 1},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	manager := NewManager(cfg, logger, audit.NewLogger(logger, false))
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	sup := manager.processes["web"]
+
+	// Hold up Stop until after the timeout
+	sup.operationMu.Lock()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- manager.stopProcessForShutdown(context.Background(), "web")
+	}()
+	time.Sleep(400 * time.Millisecond)
+	select {
+	case err := <-errCh:
+		t.Fatalf("stopProcessForShutdown() = %v before Stop returned", err)
+	default:
+	}
+	sup.operationMu.Unlock()
+
+	err := <-errCh
+	if err == nil || !strings.Contains(err.Error(), "did not stop in time") {
+		t.Errorf("stopProcessForShutdown() error = %v, want a timeout", err)
+	}
+	if state := sup.GetState(); state != StateStopped {
+		t.Errorf("state = %s, want stopped", state)
+	}
+}
@@ -3,6 +3,8 @@ package process
 import (
 	"sync"
 	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // OneshotExecution represents a single oneshot process execution record.
@@ -19,7 +21,9 @@ type OneshotExecution struct {
 	Error       string    `json:"error,omitempty"`
 	Duration    string    `json:"duration,omitempty"`
 	DurationMs  int64     `json:"duration_ms,omitempty"`
-	TriggerType string    `json:"trigger_type"` // "manual" | "startup" | "api"
+	TriggerType string    `json:"trigger_type"`           // "manual" | "startup" | "api" | "chain"
+	ChainID     string    `json:"chain_id,omitempty"`     // Job chain run the execution belongs to
+	TriggeredBy string    `json:"triggered_by,omitempty"` // Job whose completion started the execution
 }
 
 // OneshotHistory stores execution history for oneshot and scheduled processes.
@@ -106,6 +110,36 @@ func (h *OneshotHistory) Complete(id int64, exitCode int, err error) {
 	}
 }
 
+// SetChain records the job chain run an execution belongs to
+func (h *OneshotHistory) SetChain(id int64, chain schedule.Chain) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if h.entries[i].ID == id {
+			h.entries[i].ChainID = chain.ID
+			h.entries[i].TriggeredBy = chain.TriggeredBy
+			return
+		}
+	}
+}
+
+// GetChain returns all entries of a job chain run (oldest first)
+func (h *OneshotHistory) GetChain(chainID string) []OneshotExecution {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	h.evictReadLocked()
+
+	var result []OneshotExecution
+	for _, entry := range h.entries {
+		if entry.ChainID == chainID {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
 // GetAll returns all entries for a process (newest first)
 func (h *OneshotHistory) GetAll(processName string) []OneshotExecution {
 	h.mu.RLock()
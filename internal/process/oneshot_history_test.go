@@ -4,6 +4,8 @@ import (
 	"errors"
 	"testing"
 	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 func TestOneshotHistory_NewOneshotHistory(t *testing.T) {
@@ -344,3 +346,28 @@ func TestOneshotHistory_Concurrent(t *testing.T) {
 	// No panics = success
 	t.Log("Concurrent access completed without panics")
 }
+
+func TestOneshotHistory_Chain(t *testing.T) {
+	h := NewOneshotHistory(100, 1*time.Hour)
+
+	chain := schedule.NewChain("extract")
+	root := h.Record("extract", "extract-0", "startup")
+	h.SetChain(root, chain)
+	h.Record("other", "other-0", "startup")
+	next := h.Record("load", "load-0", schedule.TriggeredChain)
+	h.SetChain(next, chain.Next("extract"))
+
+	entries := h.GetChain(chain.ID)
+	if len(entries) != 2 {
+		t.Fatalf("len(GetChain) = %d, want 2", len(entries))
+	}
+	if entries[0].ProcessName != "extract" || entries[0].TriggeredBy != "" {
+		t.Errorf("first entry = %+v, want the root run of extract", entries[0])
+	}
+	if entries[1].ProcessName != "load" || entries[1].TriggeredBy != "extract" || entries[1].ChainID != chain.ID {
+		t.Errorf("second entry = %+v, want load triggered by extract", entries[1])
+	}
+	if entries := h.GetChain("missing"); len(entries) != 0 {
+		t.Errorf("GetChain(missing) = %v, want none", entries)
+	}
+}
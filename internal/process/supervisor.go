@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/gophpeek/phpeek-pm/internal/hooks"
 	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/metrics"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // ProcessState represents the lifecycle state of a process instance.
@@ -98,17 +100,27 @@ type Supervisor struct {
 	auditLogger        *audit.Logger
 	instances          []*Instance
 	state              ProcessState
+	started            bool // Started at least once, even if it already exited
 	healthMonitor      *HealthMonitor
 	healthStatus       <-chan HealthStatus
 	restartPolicy      RestartPolicy
 	resourceCollector  *metrics.ResourceCollector // Shared resource collector (can be nil)
 	oneshotHistory     *OneshotHistory            // Shared oneshot history (can be nil)
 	deathNotifier      func(string)               // Callback when all instances are dead
+	completionNotifier func(schedule.Completion)  // Callback when a oneshot run ends (nil = no triggers)
+	runChain           schedule.Chain             // Job chain of the current oneshot run (zero = none)
+	runNotified        bool                       // Completion of the current oneshot run was reported
 	credentials        *Credentials               // Resolved user/group credentials (nil = inherit)
 	healthCheckStrict  bool                       // Fail startup if health monitor creation fails
+	logFilters         atomic.Pointer[logger.LogFilters] // Runtime log filter override (nil = config)
+	crashHistory       *CrashHistory                     // Recent crashes with captured output
+	crashContextLines  int                               // Log lines captured per crash
+	oomDetector        OOMDetector                       // Attributes SIGKILL exits to the OOM killer (can be nil)
 	ctx                context.Context
 	cancel             context.CancelFunc
 	readinessCh        chan struct{}  // Closed when service becomes ready
+	healthyCh          chan struct{}  // Closed when the health check first passes
+	healthyOnce        sync.Once      // Ensures healthyCh is closed exactly once
 	readinessOnce      sync.Once      // CRITICAL: Ensures readinessCh closed exactly once
 	isReady            bool           // Track readiness state
 	goroutines         sync.WaitGroup // CRITICAL: Track all goroutines for clean shutdown
@@ -136,6 +148,7 @@ type Instance struct {
 	state         ProcessState
 	pid           int
 	started       time.Time
+	exited        time.Time // When cmd.Wait returned (zero while running)
 	restartCount  int
 	doneCh        chan struct{} // Closed when process exits (monitored by monitorInstance)
 	stdoutWriter  *logger.ProcessWriter
@@ -182,6 +195,11 @@ func NewSupervisor(name string, cfg *config.Process, globalCfg *config.GlobalCon
 	// Get max attempts from global config (default: 3)
 	maxAttempts := globalCfg.MaxRestartAttempts
 
+	crashContextLines := globalCfg.CrashContextLines
+	if crashContextLines <= 0 {
+		crashContextLines = DefaultCrashContextLines
+	}
+
 	// Resolve user/group credentials at initialization
 	var creds *Credentials
 	if cfg.User != "" || cfg.Group != "" {
@@ -215,7 +233,10 @@ func NewSupervisor(name string, cfg *config.Process, globalCfg *config.GlobalCon
 		resourceCollector:  resourceCollector,
 		credentials:        creds,
 		healthCheckStrict:  globalCfg.HealthCheckStrict,
+		crashHistory:       NewCrashHistory(globalCfg.CrashHistorySize),
+		crashContextLines:  crashContextLines,
 		readinessCh:        make(chan struct{}),
+		healthyCh:          make(chan struct{}),
 		isReady:            false,
 	}
 }
@@ -227,6 +248,28 @@ func (s *Supervisor) SetDeathNotifier(notifier func(string)) {
 	s.deathNotifier = notifier
 }
 
+// SetCompletionNotifier sets the callback for when every instance of a
+// oneshot run has exited. Runs get a job chain so that the processes the
+// callback starts share it.
+func (s *Supervisor) SetCompletionNotifier(notifier func(schedule.Completion)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completionNotifier = notifier
+}
+
+// OOMDetector reports whether the kernel OOM killer terminated a process
+// shortly before exitedAt that no earlier call claimed (see cgroup.Watcher)
+type OOMDetector interface {
+	ConsumeOOMKill(exitedAt time.Time) bool
+}
+
+// SetOOMDetector sets the detector used to tag SIGKILLed instances as OOM killed
+func (s *Supervisor) SetOOMDetector(detector OOMDetector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.oomDetector = detector
+}
+
 // SetOneshotHistory sets the shared oneshot history for tracking executions
 func (s *Supervisor) SetOneshotHistory(history *OneshotHistory) {
 	s.mu.Lock()
@@ -307,6 +350,83 @@ func (s *Supervisor) WaitForReadiness(ctx context.Context, timeout time.Duration
 	}
 }
 
+// dependencyPollInterval is how often WaitForCondition checks the state of
+// the process for the started and completed_successfully conditions
+const dependencyPollInterval = 100 * time.Millisecond
+
+// WaitForCondition waits until the process meets a depends_on condition (see
+// config.Dependency). An empty condition waits for readiness.
+func (s *Supervisor) WaitForCondition(ctx context.Context, condition string, timeout time.Duration) error {
+	switch condition {
+	case "":
+		return s.WaitForReadiness(ctx, timeout)
+	case config.DependencyHealthy:
+		if s.config.HealthCheck == nil {
+			return fmt.Errorf("service has no health check")
+		}
+		select {
+		case <-s.healthyCh:
+			return nil
+		case <-time.After(timeout):
+			return fmt.Errorf("service did not become healthy within %v", timeout)
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled while waiting for health check")
+		}
+	case config.DependencyStarted, config.DependencyCompletedSuccessfully:
+	default:
+		return fmt.Errorf("unknown dependency condition %q", condition)
+	}
+
+	timeoutCh := time.After(timeout)
+	ticker := time.NewTicker(dependencyPollInterval)
+	defer ticker.Stop()
+	for {
+		met, err := s.conditionMet(condition)
+		if err != nil || met {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-timeoutCh:
+			if condition == config.DependencyStarted {
+				return fmt.Errorf("service did not start within %v", timeout)
+			}
+			return fmt.Errorf("service did not complete within %v", timeout)
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled while waiting for condition %s", condition)
+		}
+	}
+}
+
+// conditionMet reports whether the process has been started or has completed
+// successfully. A fast oneshot counts as started after it exited. A failed
+// oneshot run never completes successfully.
+func (s *Supervisor) conditionMet(condition string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if condition == config.DependencyStarted {
+		return s.started, nil
+	}
+
+	if len(s.instances) == 0 {
+		return false, nil
+	}
+	for _, instance := range s.instances {
+		instance.mu.RLock()
+		state := instance.state
+		instance.mu.RUnlock()
+		switch state {
+		case StateFailed:
+			return false, fmt.Errorf("instance %s failed", instance.id)
+		case StateCompleted:
+		default:
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // Start starts all instances of the process
 func (s *Supervisor) Start(ctx context.Context) error {
 	s.operationMu.Lock()
@@ -315,6 +435,67 @@ func (s *Supervisor) Start(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Oneshots that trigger others start a new job chain
+	s.runChain = schedule.Chain{}
+	if s.completionNotifier != nil {
+		s.runChain = schedule.NewChain(s.name)
+	}
+	s.runNotified = false
+
+	return s.start(ctx)
+}
+
+// RunOneshot runs a oneshot process again as part of a job chain. Processes
+// that were never started or have been stopped are started.
+func (s *Supervisor) RunOneshot(ctx context.Context, chain schedule.Chain) error {
+	if s.config.Type != "oneshot" {
+		return fmt.Errorf("process %s is not a oneshot process", s.name)
+	}
+
+	s.operationMu.Lock()
+	defer s.operationMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, inst := range s.instances {
+		inst.mu.RLock()
+		running := inst.state == StateRunning || inst.state == StateStopping
+		inst.mu.RUnlock()
+		if running {
+			return fmt.Errorf("process %s is already running", s.name)
+		}
+	}
+
+	s.runChain = chain
+	s.runNotified = false
+
+	if s.ctx == nil || s.ctx.Err() != nil {
+		s.instances = nil
+		return s.start(ctx)
+	}
+
+	// Replace the instances of the previous run
+	instances := make([]*Instance, 0, s.config.Scale)
+	for i := 0; i < s.config.Scale; i++ {
+		instanceID := fmt.Sprintf("%s-%d", s.name, i)
+
+		instance, err := s.startInstance(s.ctx, instanceID)
+		if err != nil {
+			s.instances = instances
+			return fmt.Errorf("failed to start instance %s: %w", instanceID, err)
+		}
+
+		instances = append(instances, instance)
+	}
+	s.instances = instances
+	s.state = StateRunning
+
+	return nil
+}
+
+// start starts all instances of the process. Callers hold s.operationMu and s.mu.
+func (s *Supervisor) start(ctx context.Context) error {
 	// Create context for this supervisor's lifetime
 	s.ctx, s.cancel = context.WithCancel(ctx)
 
@@ -335,6 +516,7 @@ func (s *Supervisor) Start(ctx context.Context) error {
 	}
 
 	s.state = StateRunning
+	s.started = true
 
 	// Start health monitoring if configured
 	if s.config.HealthCheck != nil {
@@ -439,6 +621,15 @@ func (s *Supervisor) startInstance(ctx context.Context, instanceID string) (*Ins
 		}
 	}
 
+	if override := s.logFilters.Load(); override != nil {
+		if stdoutWriter != nil {
+			stdoutWriter.SetFilters(override)
+		}
+		if stderrWriter != nil {
+			stderrWriter.SetFilters(override)
+		}
+	}
+
 	if stdoutWriter != nil {
 		cmd.Stdout = stdoutWriter
 	} else {
@@ -470,7 +661,14 @@ func (s *Supervisor) startInstance(ctx context.Context, instanceID string) (*Ins
 
 	// Record oneshot execution in history
 	if s.config.Type == "oneshot" && s.oneshotHistory != nil {
-		instance.oneshotExecID = s.oneshotHistory.Record(s.name, instanceID, "startup")
+		triggerType := "startup"
+		if s.runChain.TriggeredBy != "" {
+			triggerType = schedule.TriggeredChain
+		}
+		instance.oneshotExecID = s.oneshotHistory.Record(s.name, instanceID, triggerType)
+		if !s.runChain.IsZero() {
+			s.oneshotHistory.SetChain(instance.oneshotExecID, s.runChain)
+		}
 	}
 
 	s.logger.Info("Process instance started",
@@ -516,6 +714,7 @@ func (s *Supervisor) monitorInstance(instance *Instance) {
 
 	instance.mu.Lock()
 	exitCode := instance.cmd.ProcessState.ExitCode()
+	instance.exited = time.Now()
 	instance.state = StateStopped
 	restartCount := instance.restartCount
 	instance.mu.Unlock()
@@ -756,6 +955,33 @@ func (s *Supervisor) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Kill force kills the process groups of the running instances, including
+// children holding their output open, so that a Stop waiting for them returns.
+func (s *Supervisor) Kill() {
+	s.mu.RLock()
+	instances := make([]*Instance, len(s.instances))
+	copy(instances, s.instances)
+	s.mu.RUnlock()
+
+	for _, instance := range instances {
+		select {
+		case <-instance.doneCh:
+			continue // Already exited
+		default:
+		}
+		instance.mu.RLock()
+		pid, cmd := instance.pid, instance.cmd
+		instance.mu.RUnlock()
+		if pid <= 0 {
+			continue
+		}
+		// The group outlives its leader, so this works after it exited
+		if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil && cmd != nil && cmd.Process != nil {
+			_ = cmd.Process.Kill()
+		}
+	}
+}
+
 // stopInstance stops a single process instance
 func (s *Supervisor) stopInstance(ctx context.Context, instance *Instance) error {
 	// NIL safety check
@@ -901,6 +1127,7 @@ func (s *Supervisor) cleanupInstanceResources(instance *Instance, pid int, reaso
 func (s *Supervisor) handleOneshotExit(instance *Instance, exitCode int, err error) {
 	instance.mu.Lock()
 	execID := instance.oneshotExecID
+	stopped := !instance.allowRestart
 	if exitCode == 0 {
 		instance.state = StateCompleted
 		s.logger.Info("Oneshot process completed successfully",
@@ -926,9 +1153,54 @@ func (s *Supervisor) handleOneshotExit(instance *Instance, exitCode int, err err
 		s.markReady("oneshot completed successfully")
 	}
 
+	// Runs stopped on purpose do not trigger other jobs
+	if !stopped {
+		s.notifyCompletion(instance, exitCode, err)
+	}
+
 	s.checkAllInstancesDead()
 }
 
+// notifyCompletion reports the oneshot run to the completion notifier once
+// every instance has exited. The run succeeded if every instance completed.
+func (s *Supervisor) notifyCompletion(instance *Instance, exitCode int, err error) {
+	s.mu.Lock()
+	notifier := s.completionNotifier
+	if notifier == nil || s.runNotified {
+		s.mu.Unlock()
+		return
+	}
+	success := true
+	for _, inst := range s.instances {
+		inst.mu.RLock()
+		state := inst.state
+		inst.mu.RUnlock()
+		if state == StateRunning || state == StateStopping {
+			s.mu.Unlock()
+			return
+		}
+		if state != StateCompleted {
+			success = false
+		}
+	}
+	s.runNotified = true
+	chain := s.runChain
+	s.mu.Unlock()
+
+	instance.mu.RLock()
+	execID := instance.oneshotExecID
+	instance.mu.RUnlock()
+
+	notifier(schedule.Completion{
+		Job:         s.name,
+		ExecutionID: execID,
+		ExitCode:    exitCode,
+		Success:     success,
+		Err:         err,
+		Chain:       chain,
+	})
+}
+
 // logProcessExit logs process exit appropriately based on whether it was intentional
 func (s *Supervisor) logProcessExit(instance *Instance, exitCode int, restartCount int, allowRestart bool, err error) {
 	if err != nil {
@@ -941,10 +1213,12 @@ func (s *Supervisor) logProcessExit(instance *Instance, exitCode int, restartCou
 			)
 		} else {
 			// Unexpected exit - log as error
+			oomKilled := s.wasOOMKilled(instance)
 			s.logger.Error("Process instance exited with error",
 				"instance_id", instance.id,
 				"exit_code", exitCode,
 				"restart_count", restartCount,
+				"oom_killed", oomKilled,
 				"error", err,
 			)
 
@@ -953,7 +1227,22 @@ func (s *Supervisor) logProcessExit(instance *Instance, exitCode int, restartCou
 			if instance.cmd.ProcessState != nil && instance.cmd.ProcessState.Sys() != nil {
 				signal = instance.cmd.ProcessState.String()
 			}
-			s.auditLogger.LogProcessCrash(s.name, instance.pid, exitCode, signal)
+			record := s.crashRecord(instance, exitCode, restartCount, signal, oomKilled, err)
+
+			lastOutput := make([]string, 0, len(record.LastLines))
+			for _, line := range record.LastLines {
+				lastOutput = append(lastOutput, fmt.Sprintf("[%s] %s", line.Stream, line.Message))
+			}
+			if oomKilled {
+				metrics.RecordProcessOOMKill(s.name)
+				s.auditLogger.LogProcessOOMKill(s.name, instance.pid, exitCode, signal, lastOutput)
+			} else {
+				s.auditLogger.LogProcessCrashWithOutput(s.name, instance.pid, exitCode, signal, lastOutput)
+			}
+
+			// Recorded after the audit event, so crashes listed by the API
+			// always have one
+			s.crashHistory.Record(record)
 		}
 	} else {
 		s.logger.Info("Process instance exited",
@@ -964,6 +1253,67 @@ func (s *Supervisor) logProcessExit(instance *Instance, exitCode int, restartCou
 	}
 }
 
+// wasOOMKilled reports whether an instance killed by SIGKILL was terminated
+// by the kernel OOM killer, as recorded by the cgroup
+func (s *Supervisor) wasOOMKilled(instance *Instance) bool {
+	s.mu.RLock()
+	detector := s.oomDetector
+	s.mu.RUnlock()
+
+	if detector == nil || instance.cmd == nil || instance.cmd.ProcessState == nil {
+		return false
+	}
+	status, ok := instance.cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() || status.Signal() != syscall.SIGKILL {
+		return false
+	}
+
+	instance.mu.RLock()
+	exitedAt := instance.exited
+	instance.mu.RUnlock()
+	return detector.ConsumeOOMKill(exitedAt)
+}
+
+// crashRecord describes a crashed instance with a snapshot of its last
+// output lines
+func (s *Supervisor) crashRecord(instance *Instance, exitCode int, restartCount int, signal string, oomKilled bool, err error) CrashRecord {
+	instance.mu.RLock()
+	stdoutWriter := instance.stdoutWriter
+	stderrWriter := instance.stderrWriter
+	pid := instance.pid
+	instance.mu.RUnlock()
+
+	// The process has exited, so flush partial lines (e.g. a final fatal
+	// error without trailing newline) before taking the snapshot
+	if stdoutWriter != nil {
+		stdoutWriter.Flush()
+	}
+	if stderrWriter != nil {
+		stderrWriter.Flush()
+	}
+
+	record := CrashRecord{
+		ProcessName:  s.name,
+		InstanceID:   instance.id,
+		PID:          pid,
+		ExitCode:     exitCode,
+		Signal:       signal,
+		OOMKilled:    oomKilled,
+		RestartCount: restartCount,
+		CrashedAt:    time.Now(),
+		LastLines:    snapshotLastLines(s.crashContextLines, stdoutWriter, stderrWriter),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// GetRecentCrashes returns up to limit recent crashes, newest first (limit <= 0 = all)
+func (s *Supervisor) GetRecentCrashes(limit int) []CrashRecord {
+	return s.crashHistory.GetRecent(limit)
+}
+
 // attemptRestart attempts to restart a failed process instance
 func (s *Supervisor) attemptRestart(instance *Instance, exitCode int, restartCount int) {
 	backoff := s.restartPolicy.BackoffDuration(restartCount)
@@ -1051,6 +1401,9 @@ func (s *Supervisor) envVars(instanceID string) []string {
 		fmt.Sprintf("PHPEEK_PM_INSTANCE_ID=%s", instanceID),
 	)
 
+	// Add job chain variables
+	envs = append(envs, s.runChain.EnvVars()...)
+
 	return envs
 }
 
@@ -1076,6 +1429,7 @@ func (s *Supervisor) handleHealthStatus(ctx context.Context) {
 			if status.Healthy {
 				// Signal readiness on first successful health check
 				s.markReady("health check passed")
+				s.healthyOnce.Do(func() { close(s.healthyCh) })
 			} else if !status.Healthy {
 				s.logger.Error("Process unhealthy, triggering restart",
 					"error", status.Error,
@@ -1184,9 +1538,10 @@ func (s *Supervisor) checkAllInstancesDead() {
 		}
 	}
 	notifier := s.deathNotifier
+	hasInstances := len(s.instances) > 0
 	s.mu.RUnlock()
 
-	if allDead && len(s.instances) > 0 && notifier != nil {
+	if allDead && hasInstances && notifier != nil {
 		s.logger.Debug("All instances dead, notifying manager")
 		notifier(s.name)
 	}
@@ -1262,6 +1617,20 @@ func (s *Supervisor) collectInstanceMetrics() {
 
 		// Update Prometheus gauges
 		metrics.UpdatePrometheusMetrics(s.name, instanceID, sample)
+
+		// Feed worker memory learning (php-fpm children)
+		if recorder, ok := s.resourceCollector.ChildRecorder(s.name); ok {
+			rss, err := metrics.CollectChildrenRSS(pid)
+			if err != nil {
+				s.logger.Debug("Failed to collect child memory",
+					"instance", instanceID,
+					"pid", pid,
+					"error", err,
+				)
+				continue
+			}
+			recorder.RecordWorkerRSS(rss)
+		}
 	}
 
 	// Record collection duration
@@ -1315,3 +1684,91 @@ func (s *Supervisor) GetLogs(limit int) []logger.LogEntry {
 
 	return allLogs
 }
+
+// QueryLogs returns log entries from all instances matching q, newest first.
+// Each writer is queried with the same limit and the results are merged,
+// so at most q.Limit entries are returned overall.
+func (s *Supervisor) QueryLogs(q logger.LogQuery) []logger.LogEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results [][]logger.LogEntry
+	for _, inst := range s.instances {
+		if q.Instance != "" && inst.id != q.Instance {
+			continue
+		}
+
+		inst.mu.RLock()
+		stdoutWriter := inst.stdoutWriter
+		stderrWriter := inst.stderrWriter
+		inst.mu.RUnlock()
+
+		if stdoutWriter != nil {
+			results = append(results, stdoutWriter.QueryLogs(q))
+		}
+		if stderrWriter != nil {
+			results = append(results, stderrWriter.QueryLogs(q))
+		}
+	}
+
+	return logger.MergeQueryResults(q, results...)
+}
+
+// SetLogFilters swaps the log filters of every instance writer without
+// touching the running processes. The override also applies to instances
+// started later (restarts, scale-ups). Passing nil restores the filters
+// from the process logging configuration.
+func (s *Supervisor) SetLogFilters(filters *logger.LogFilters) error {
+	effective := filters
+	if filters == nil {
+		configured, err := s.configuredLogFilters()
+		if err != nil {
+			return err
+		}
+		effective = configured
+	}
+
+	s.logFilters.Store(filters)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, inst := range s.instances {
+		inst.mu.RLock()
+		stdoutWriter := inst.stdoutWriter
+		stderrWriter := inst.stderrWriter
+		inst.mu.RUnlock()
+
+		if stdoutWriter != nil {
+			stdoutWriter.SetFilters(effective)
+		}
+		if stderrWriter != nil {
+			stderrWriter.SetFilters(effective)
+		}
+	}
+
+	return nil
+}
+
+// LogFilters returns the log filters currently in effect, preferring a
+// runtime override over the configured filters.
+func (s *Supervisor) LogFilters() *logger.LogFilters {
+	if override := s.logFilters.Load(); override != nil {
+		return override
+	}
+
+	configured, err := s.configuredLogFilters()
+	if err != nil {
+		return nil
+	}
+	return configured
+}
+
+// configuredLogFilters builds the filters defined by the process logging
+// configuration, or nil when logging is not configured.
+func (s *Supervisor) configuredLogFilters() (*logger.LogFilters, error) {
+	if s.config.Logging == nil {
+		return nil, nil
+	}
+	return logger.NewLogFilters(s.config.Logging.Filters, s.config.Logging.MinLevel)
+}
@@ -1,15 +1,23 @@
 package process
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
 
 	"github.com/gophpeek/phpeek-pm/internal/audit"
 	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/logger"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
+	"github.com/gophpeek/phpeek-pm/internal/testutil"
 )
 
 func TestSupervisor_WaitForReadiness(t *testing.T) {
@@ -586,3 +594,540 @@ func TestSupervisor_MonitorInstance_Lifecycle(t *testing.T) {
 		})
 	}
 }
+
+func TestSupervisor_QueryLogs(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(log, false)
+
+	cfg := &config.Process{
+		Enabled:      true,
+		InitialState: "running",
+		Command:      []string{"sh", "-c", "echo 'starting worker'; echo 'PDOException: connection refused' >&2; sleep 1"},
+		Restart:      "never",
+		Scale:        1,
+	}
+
+	globalCfg := &config.GlobalConfig{
+		LogLevel:           "info",
+		MaxRestartAttempts: 3,
+		RestartBackoff:     5,
+	}
+
+	sup := NewSupervisor("test-query", cfg, globalCfg, log, auditLogger, nil)
+
+	ctx := context.Background()
+	if err := sup.Start(ctx); err != nil {
+		t.Fatalf("Failed to start supervisor: %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = sup.Stop(stopCtx)
+	}()
+
+	var logs []logger.LogEntry
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		logs = sup.QueryLogs(logger.LogQuery{Contains: "pdoexception"})
+		if len(logs) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("QueryLogs(search) returned %d entries, want 1", len(logs))
+	}
+	if logs[0].Stream != "stderr" {
+		t.Errorf("matched entry stream = %s, want stderr", logs[0].Stream)
+	}
+
+	if got := sup.QueryLogs(logger.LogQuery{Stream: "stdout"}); len(got) != 1 {
+		t.Errorf("QueryLogs(stream=stdout) returned %d entries, want 1", len(got))
+	}
+	if got := sup.QueryLogs(logger.LogQuery{Instance: "other-0"}); len(got) != 0 {
+		t.Errorf("QueryLogs(instance=other-0) returned %d entries, want 0", len(got))
+	}
+}
+
+func TestSupervisor_SetLogFilters(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(log, false)
+
+	cfg := &config.Process{
+		Enabled:      true,
+		InitialState: "running",
+		Command:      []string{"sleep", "5"},
+		Restart:      "never",
+		Scale:        1,
+		Logging: &config.LoggingConfig{
+			Stdout:   true,
+			Stderr:   true,
+			MinLevel: "warn",
+		},
+	}
+
+	globalCfg := &config.GlobalConfig{
+		LogLevel:           "info",
+		MaxRestartAttempts: 3,
+		RestartBackoff:     5,
+	}
+
+	sup := NewSupervisor("test-filters", cfg, globalCfg, log, auditLogger, nil)
+
+	ctx := context.Background()
+	if err := sup.Start(ctx); err != nil {
+		t.Fatalf("Failed to start supervisor: %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = sup.Stop(stopCtx)
+	}()
+
+	if got := sup.LogFilters().GetMinLevel(); got != slog.LevelWarn {
+		t.Fatalf("configured min level = %v, want warn", got)
+	}
+
+	debug, err := logger.NewLogFilters(nil, "debug")
+	if err != nil {
+		t.Fatalf("NewLogFilters() error = %v", err)
+	}
+	if err := sup.SetLogFilters(debug); err != nil {
+		t.Fatalf("SetLogFilters() error = %v", err)
+	}
+
+	sup.mu.RLock()
+	inst := sup.instances[0]
+	sup.mu.RUnlock()
+	if inst.stdoutWriter.Filters() != debug || inst.stderrWriter.Filters() != debug {
+		t.Error("override was not applied to running instance writers")
+	}
+	if sup.LogFilters() != debug {
+		t.Error("LogFilters() should return the override")
+	}
+
+	if err := sup.SetLogFilters(nil); err != nil {
+		t.Fatalf("SetLogFilters(nil) error = %v", err)
+	}
+	if got := inst.stdoutWriter.Filters().GetMinLevel(); got != slog.LevelWarn {
+		t.Errorf("restored min level = %v, want warn", got)
+	}
+	if inst.state != StateRunning {
+		t.Errorf("instance state = %s, changing log filters must not touch the process", inst.state)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for the supervisor goroutines writing
+// audit events while a test reads them
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSupervisor_CrashContext(t *testing.T) {
+	var auditBuf lockedBuffer
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(slog.New(slog.NewJSONHandler(&auditBuf, nil)), true)
+
+	cfg := &config.Process{
+		Enabled:      true,
+		InitialState: "running",
+		Command:      []string{"sh", "-c", "echo 'booting'; echo 'handling job 42'; printf 'Fatal: out of memory' >&2; exit 3"},
+		Restart:      "never",
+		Scale:        1,
+	}
+
+	globalCfg := &config.GlobalConfig{
+		LogLevel:           "info",
+		MaxRestartAttempts: 3,
+		RestartBackoff:     5,
+		CrashContextLines:  10,
+	}
+
+	sup := NewSupervisor("test-crash", cfg, globalCfg, log, auditLogger, nil)
+
+	ctx := context.Background()
+	if err := sup.Start(ctx); err != nil {
+		t.Fatalf("Failed to start supervisor: %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = sup.Stop(stopCtx)
+	}()
+
+	var crashes []CrashRecord
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		crashes = sup.GetRecentCrashes(0)
+		if len(crashes) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if len(crashes) != 1 {
+		t.Fatalf("expected 1 crash record, got %d", len(crashes))
+	}
+
+	crash := crashes[0]
+	if crash.ExitCode != 3 || crash.InstanceID != "test-crash-0" || crash.PID == 0 {
+		t.Errorf("unexpected crash record: %+v", crash)
+	}
+	// stdout and stderr are separate pipes, so only the order within each
+	// stream is deterministic
+	streams := make(map[string][]string)
+	for _, line := range crash.LastLines {
+		streams[line.Stream] = append(streams[line.Stream], line.Message)
+	}
+	if got := strings.Join(streams["stdout"], "|"); got != "booting|handling job 42" {
+		t.Errorf("captured stdout = %q, want both lines in order (all: %+v)", got, crash.LastLines)
+	}
+	// The unterminated stderr line must be flushed into the snapshot
+	if got := strings.Join(streams["stderr"], "|"); got != "Fatal: out of memory" {
+		t.Errorf("captured stderr = %q, want flushed fatal (all: %+v)", got, crash.LastLines)
+	}
+
+	if !strings.Contains(auditBuf.String(), "Fatal: out of memory") {
+		t.Errorf("crash audit event should include captured output, got: %s", auditBuf.String())
+	}
+}
+
+// fakeOOMDetector attributes up to kills SIGKILL exits to the OOM killer
+type fakeOOMDetector struct {
+	kills atomic.Int32
+}
+
+func (d *fakeOOMDetector) ConsumeOOMKill(time.Time) bool {
+	return d.kills.Add(-1) >= 0
+}
+
+// TestSupervisor_OOMKilled verifies SIGKILL exits are tagged when the cgroup
+// recorded an OOM kill
+func TestSupervisor_OOMKilled(t *testing.T) {
+	var auditBuf lockedBuffer
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(slog.New(slog.NewJSONHandler(&auditBuf, nil)), true)
+
+	cfg := &config.Process{
+		Enabled:      true,
+		InitialState: "running",
+		Command:      []string{"sh", "-c", "sleep 0.1; kill -9 $$"},
+		Restart:      "never",
+		Scale:        1,
+	}
+
+	detector := &fakeOOMDetector{}
+	detector.kills.Store(1)
+
+	sup := NewSupervisor("test-oom", cfg, &config.GlobalConfig{LogLevel: "info"}, log, auditLogger, nil)
+	sup.SetOOMDetector(detector)
+
+	if err := sup.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start supervisor: %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = sup.Stop(stopCtx)
+	}()
+
+	var crashes []CrashRecord
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		crashes = sup.GetRecentCrashes(0)
+		if len(crashes) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if len(crashes) != 1 {
+		t.Fatalf("expected 1 crash record, got %d", len(crashes))
+	}
+	if !crashes[0].OOMKilled {
+		t.Errorf("expected crash to be tagged oom_killed: %+v", crashes[0])
+	}
+	if !strings.Contains(auditBuf.String(), "oom_killed") {
+		t.Errorf("crash audit event should be tagged oom_killed, got: %s", auditBuf.String())
+	}
+}
+
+func TestSupervisor_Kill(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	sup := NewSupervisor("web", &config.Process{
+		Enabled:      true,
+		InitialState: "running",
+		Type:         "longrun",
+		// The child would outlive the shell
+		Command: []string{"sh", "-c", "sleep 30 & echo $! > " + pidFile + "; wait"},
+		Restart: "never",
+		Scale:   1,
+	}, &config.GlobalConfig{LogLevel: "error"}, logger, audit.NewLogger(logger, false), nil)
+	if err := sup.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = sup.Stop(stopCtx)
+	})
+
+	sup.mu.RLock()
+	instance := sup.instances[0]
+	sup.mu.RUnlock()
+	var child []byte
+	testutil.Eventually(t, func() bool {
+		child, _ = os.ReadFile(pidFile)
+		return len(child) > 0
+	}, "child to start")
+
+	sup.Kill()
+	select {
+	case <-instance.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("instance did not exit after Kill()")
+	}
+
+	// Killed with its group, possibly left as a zombie nobody reaps
+	testutil.Eventually(t, func() bool {
+		status, err := os.ReadFile("/proc/" + strings.TrimSpace(string(child)) + "/status")
+		return err != nil || strings.Contains(string(status), "State:\tZ")
+	}, "child to be killed")
+}
+
+func TestSupervisor_WaitForCondition(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(logger, false)
+	globalCfg := &config.GlobalConfig{LogLevel: "error", MaxRestartAttempts: 1, RestartBackoff: 1}
+
+	start := func(t *testing.T, name, procType string, command ...string) *Supervisor {
+		t.Helper()
+		sup := NewSupervisor(name, &config.Process{
+			Enabled:      true,
+			InitialState: "running",
+			Type:         procType,
+			Command:      command,
+			Restart:      "never",
+			Scale:        1,
+		}, globalCfg, logger, auditLogger, nil)
+		if err := sup.Start(context.Background()); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		t.Cleanup(func() {
+			stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_ = sup.Stop(stopCtx)
+		})
+		return sup
+	}
+	ctx := context.Background()
+
+	t.Run("completed successfully", func(t *testing.T) {
+		sup := start(t, "migrate", "oneshot", "true")
+		if err := sup.WaitForCondition(ctx, config.DependencyCompletedSuccessfully, 5*time.Second); err != nil {
+			t.Errorf("WaitForCondition() error = %v", err)
+		}
+	})
+
+	t.Run("failed oneshot", func(t *testing.T) {
+		sup := start(t, "migrate", "oneshot", "false")
+		err := sup.WaitForCondition(ctx, config.DependencyCompletedSuccessfully, 5*time.Second)
+		if err == nil || !strings.Contains(err.Error(), "failed") {
+			t.Errorf("WaitForCondition() error = %v, want failure", err)
+		}
+	})
+
+	t.Run("started oneshot that already exited", func(t *testing.T) {
+		sup := start(t, "migrate", "oneshot", "true")
+		if err := sup.WaitForCondition(ctx, config.DependencyCompletedSuccessfully, 5*time.Second); err != nil {
+			t.Fatalf("WaitForCondition() error = %v", err)
+		}
+		// A waiter polling after the run is cleaned up still sees it started
+		if err := sup.Stop(ctx); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+		if err := sup.WaitForCondition(ctx, config.DependencyStarted, 300*time.Millisecond); err != nil {
+			t.Errorf("WaitForCondition(started) error = %v", err)
+		}
+	})
+
+	t.Run("started longrun", func(t *testing.T) {
+		sup := start(t, "web", "longrun", "sleep", "30")
+		if err := sup.WaitForCondition(ctx, config.DependencyStarted, time.Second); err != nil {
+			t.Errorf("WaitForCondition(started) error = %v", err)
+		}
+		if err := sup.WaitForCondition(ctx, "", time.Second); err != nil {
+			t.Errorf("WaitForCondition(ready) error = %v", err)
+		}
+		err := sup.WaitForCondition(ctx, config.DependencyCompletedSuccessfully, 300*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "did not complete") {
+			t.Errorf("WaitForCondition(completed_successfully) error = %v, want timeout", err)
+		}
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		sup := NewSupervisor("web", &config.Process{Enabled: true, Type: "longrun", Command: []string{"true"}, Scale: 1}, globalCfg, logger, auditLogger, nil)
+		err := sup.WaitForCondition(ctx, config.DependencyStarted, 200*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "did not start") {
+			t.Errorf("WaitForCondition() error = %v, want timeout", err)
+		}
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		sup := NewSupervisor("redis", &config.Process{
+			Enabled:     true,
+			Type:        "longrun",
+			Command:     []string{"true"},
+			Scale:       1,
+			HealthCheck: &config.HealthCheck{Type: "tcp", Address: "localhost:6379", Mode: "liveness"},
+		}, globalCfg, logger, auditLogger, nil)
+		err := sup.WaitForCondition(ctx, config.DependencyHealthy, 200*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "did not become healthy") {
+			t.Errorf("WaitForCondition() error = %v, want timeout", err)
+		}
+
+		// Liveness-only checks still gate the healthy condition
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			sup.healthyOnce.Do(func() { close(sup.healthyCh) })
+		}()
+		if err := sup.WaitForCondition(ctx, config.DependencyHealthy, 2*time.Second); err != nil {
+			t.Errorf("WaitForCondition() error = %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		sup := NewSupervisor("web", &config.Process{Enabled: true, Type: "longrun", Command: []string{"true"}, Scale: 1}, globalCfg, logger, auditLogger, nil)
+		if err := sup.WaitForCondition(ctx, config.DependencyHealthy, time.Second); err == nil {
+			t.Error("expected an error without a health check")
+		}
+		if err := sup.WaitForCondition(ctx, "ready", time.Second); err == nil {
+			t.Error("expected an error for an unknown condition")
+		}
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := sup.WaitForCondition(cancelled, config.DependencyStarted, time.Second); err == nil {
+			t.Error("expected an error for a cancelled context")
+		}
+	})
+}
+
+func TestSupervisor_RunOneshot(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(logger, false)
+	globalCfg := &config.GlobalConfig{LogLevel: "error", MaxRestartAttempts: 1, RestartBackoff: 1}
+
+	// Fails unless it runs in a chain, so the root run fails and the chained one succeeds
+	cfg := &config.Process{
+		Enabled:      true,
+		InitialState: "running",
+		Type:         "oneshot",
+		Command:      []string{"sh", "-c", `test "$PHPEEK_PM_TRIGGERED_BY" = "extract"`},
+		Restart:      "never",
+		Scale:        2,
+	}
+
+	sup := NewSupervisor("load", cfg, globalCfg, logger, auditLogger, nil)
+	history := NewOneshotHistory(100, time.Hour)
+	sup.SetOneshotHistory(history)
+	completions := make(chan schedule.Completion, 2)
+	sup.SetCompletionNotifier(func(c schedule.Completion) { completions <- c })
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = sup.Stop(stopCtx)
+	}()
+
+	waitCompletion := func() schedule.Completion {
+		t.Helper()
+		select {
+		case c := <-completions:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("run did not complete")
+		}
+		return schedule.Completion{}
+	}
+
+	if err := sup.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	root := waitCompletion()
+	if root.Job != "load" || root.Success || root.Chain.Root != "load" || root.Chain.TriggeredBy != "" {
+		t.Errorf("root completion = %+v, want a failed run starting a chain at load", root)
+	}
+
+	chain := schedule.NewChain("extract").Next("extract")
+	if err := sup.RunOneshot(context.Background(), chain); err != nil {
+		t.Fatalf("RunOneshot() error = %v", err)
+	}
+	chained := waitCompletion()
+	if !chained.Success || chained.Chain != chain {
+		t.Errorf("chained completion = %+v, want a successful run in chain %+v", chained, chain)
+	}
+
+	select {
+	case c := <-completions:
+		t.Errorf("completion reported once per instance: %+v", c)
+	default:
+	}
+
+	entries := history.GetChain(chain.ID)
+	if len(entries) != 2 {
+		t.Fatalf("chain history has %d entries, want one per instance", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.TriggerType != schedule.TriggeredChain || entry.TriggeredBy != "extract" || !entry.Success {
+			t.Errorf("chain entry = %+v, want a successful chained run", entry)
+		}
+	}
+}
+
+func TestSupervisor_RunOneshot_Errors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditLogger := audit.NewLogger(logger, false)
+	globalCfg := &config.GlobalConfig{LogLevel: "error", MaxRestartAttempts: 1, RestartBackoff: 1}
+
+	longrun := NewSupervisor("web", &config.Process{
+		Enabled: true,
+		Command: []string{"sleep", "10"},
+		Scale:   1,
+	}, globalCfg, logger, auditLogger, nil)
+	if err := longrun.RunOneshot(context.Background(), schedule.NewChain("root")); err == nil {
+		t.Error("RunOneshot() should fail for a longrun process")
+	}
+
+	busy := NewSupervisor("slow", &config.Process{
+		Enabled: true,
+		Type:    "oneshot",
+		Command: []string{"sleep", "10"},
+		Restart: "never",
+		Scale:   1,
+	}, globalCfg, logger, auditLogger, nil)
+	if err := busy.RunOneshot(context.Background(), schedule.NewChain("root")); err != nil {
+		t.Fatalf("RunOneshot() of a never started process error = %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = busy.Stop(stopCtx)
+	}()
+	if err := busy.RunOneshot(context.Background(), schedule.NewChain("root")); err == nil {
+		t.Error("RunOneshot() should fail while the previous run is running")
+	}
+}
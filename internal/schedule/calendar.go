@@ -0,0 +1,158 @@
+package schedule
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// dateLayout is the format of Calendar.SkipDates
+const dateLayout = "2006-01-02"
+
+// maxCalendarSkips bounds how many blocked periods Next skips before it
+// gives up and reports that the job never runs again
+const maxCalendarSkips = 1000
+
+// TimeWindow is a daily time range from Start up to (excluding) End, in
+// minutes after midnight. A window whose End is before its Start wraps past
+// midnight, e.g. 22:00-06:00.
+type TimeWindow struct {
+	Start int
+	End   int
+}
+
+// ParseTimeWindow parses a window in "HH:MM-HH:MM" format
+func ParseTimeWindow(s string) (TimeWindow, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q (expected HH:MM-HH:MM)", s)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: start must be HH:MM", s)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: end must be HH:MM", s)
+	}
+
+	w := TimeWindow{
+		Start: start.Hour()*60 + start.Minute(),
+		End:   end.Hour()*60 + end.Minute(),
+	}
+	if w.Start == w.End {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: start and end are equal", s)
+	}
+	return w, nil
+}
+
+// String returns the window in "HH:MM-HH:MM" format
+func (w TimeWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// until returns when the window ends if it contains t
+func (w TimeWindow) until(t time.Time) (time.Time, bool) {
+	m := t.Hour()*60 + t.Minute()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	end := midnight.Add(time.Duration(w.End) * time.Minute)
+
+	if w.Start < w.End {
+		return end, m >= w.Start && m < w.End
+	}
+	// Wraps past midnight
+	switch {
+	case m >= w.Start:
+		return end.AddDate(0, 0, 1), true
+	case m < w.End:
+		return end, true
+	}
+	return time.Time{}, false
+}
+
+// Calendar restricts the days and times a job may start. The zero value
+// allows any time.
+type Calendar struct {
+	Blackouts    []TimeWindow   // Daily windows without runs
+	WeekdaysOnly bool           // Skip Saturdays and Sundays
+	SkipDates    []string       // Days without runs in "2006-01-02" format
+	Location     *time.Location // Timezone of windows and dates (nil = local time)
+}
+
+// NewCalendar parses blackout windows in "HH:MM-HH:MM" format and skip dates
+// in "2006-01-02" format into a Calendar
+func NewCalendar(blackouts []string, weekdaysOnly bool, skipDates []string) (Calendar, error) {
+	cal := Calendar{WeekdaysOnly: weekdaysOnly}
+	for _, s := range blackouts {
+		w, err := ParseTimeWindow(s)
+		if err != nil {
+			return Calendar{}, err
+		}
+		cal.Blackouts = append(cal.Blackouts, w)
+	}
+	for _, s := range skipDates {
+		d, err := time.Parse(dateLayout, strings.TrimSpace(s))
+		if err != nil {
+			return Calendar{}, fmt.Errorf("invalid skip date %q (expected YYYY-MM-DD)", s)
+		}
+		cal.SkipDates = append(cal.SkipDates, d.Format(dateLayout))
+	}
+	return cal, nil
+}
+
+// IsZero returns true if the calendar allows any time
+func (c Calendar) IsZero() bool {
+	return len(c.Blackouts) == 0 && !c.WeekdaysOnly && len(c.SkipDates) == 0
+}
+
+// Check returns why t is blocked and when the block ends, or an empty
+// reason if a run may start at t
+func (c Calendar) Check(t time.Time) (string, time.Time) {
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	nextDay := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+
+	if c.WeekdaysOnly && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return "weekend", nextDay
+	}
+	if date := t.Format(dateLayout); slices.Contains(c.SkipDates, date) {
+		return "skip date " + date, nextDay
+	}
+	for _, w := range c.Blackouts {
+		if until, ok := w.until(t); ok {
+			return "blackout " + w.String(), until
+		}
+	}
+	return "", time.Time{}
+}
+
+// calendarSchedule skips the activations of a schedule that its calendar
+// blocks, so that the cron scheduler reports the next effective run
+type calendarSchedule struct {
+	schedule cron.Schedule
+	calendar Calendar
+}
+
+// Next returns the first activation after t that the calendar allows
+func (s calendarSchedule) Next(t time.Time) time.Time {
+	for i := 0; i < maxCalendarSkips; i++ {
+		next := s.schedule.Next(t)
+		if next.IsZero() {
+			return next
+		}
+		reason, until := s.calendar.Check(next)
+		if reason == "" {
+			return next
+		}
+		// Activations are whole seconds after t, so the first one after
+		// this is at or after the end of the block
+		t = until.Add(-time.Second)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    TimeWindow
+		wantErr bool
+	}{
+		{input: "01:00-03:30", want: TimeWindow{Start: 60, End: 210}},
+		{input: "22:00-06:00", want: TimeWindow{Start: 1320, End: 360}},
+		{input: " 09:00 - 17:00 ", want: TimeWindow{Start: 540, End: 1020}},
+		{input: "09:00", wantErr: true},
+		{input: "9am-5pm", wantErr: true},
+		{input: "25:00-03:00", wantErr: true},
+		{input: "10:00-10:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimeWindow(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimeWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTimeWindow() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if s := (TimeWindow{Start: 1320, End: 360}).String(); s != "22:00-06:00" {
+		t.Errorf("String() = %q, want 22:00-06:00", s)
+	}
+}
+
+func TestNewCalendar(t *testing.T) {
+	cal, err := NewCalendar([]string{"22:00-06:00"}, true, []string{"2025-12-25"})
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+	if len(cal.Blackouts) != 1 || !cal.WeekdaysOnly || len(cal.SkipDates) != 1 || cal.IsZero() {
+		t.Errorf("NewCalendar() = %+v", cal)
+	}
+
+	if _, err := NewCalendar([]string{"nope"}, false, nil); err == nil {
+		t.Error("NewCalendar() should reject invalid windows")
+	}
+	if _, err := NewCalendar(nil, false, []string{"25.12.2025"}); err == nil {
+		t.Error("NewCalendar() should reject invalid dates")
+	}
+
+	cal, _ = NewCalendar(nil, false, nil)
+	if !cal.IsZero() {
+		t.Error("empty calendar should be zero")
+	}
+}
+
+func TestCalendar_Check(t *testing.T) {
+	cal, _ := NewCalendar([]string{"22:00-06:00", "12:00-13:00"}, true, []string{"2025-12-25"})
+	cal.Location = time.UTC
+	at := func(s string) time.Time {
+		tm, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		name      string
+		time      string
+		blocked   bool
+		wantUntil string
+	}{
+		{name: "weekday morning", time: "2025-01-06 09:00"},
+		{name: "overnight before midnight", time: "2025-01-06 23:30", blocked: true, wantUntil: "2025-01-07 06:00"},
+		{name: "overnight after midnight", time: "2025-01-07 05:59", blocked: true, wantUntil: "2025-01-07 06:00"},
+		{name: "window end is allowed", time: "2025-01-07 06:00"},
+		{name: "lunch window", time: "2025-01-07 12:30", blocked: true, wantUntil: "2025-01-07 13:00"},
+		{name: "saturday", time: "2025-01-11 09:00", blocked: true, wantUntil: "2025-01-12 00:00"},
+		{name: "skip date", time: "2025-12-25 09:00", blocked: true, wantUntil: "2025-12-26 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, until := cal.Check(at(tt.time))
+			if (reason != "") != tt.blocked {
+				t.Fatalf("Check() reason = %q, blocked = %v", reason, tt.blocked)
+			}
+			if tt.blocked && !until.Equal(at(tt.wantUntil)) {
+				t.Errorf("Check() until = %v, want %s", until, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestCalendarSchedule_Next(t *testing.T) {
+	cal, _ := NewCalendar([]string{"22:00-06:00"}, true, nil)
+	cal.Location = time.UTC
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "hourly skips the night",
+			expr: "0 * * * *",
+			from: time.Date(2025, 1, 6, 21, 30, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 7, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "friday night jumps to monday",
+			expr: "0 * * * *",
+			from: time.Date(2025, 1, 10, 21, 30, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 13, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "every interval resumes after the window",
+			expr: "@every 90s",
+			from: time.Date(2025, 1, 6, 21, 59, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 7, 6, 1, 29, 0, time.UTC),
+		},
+		{
+			name: "seconds",
+			expr: "*/15 * * * * *",
+			from: time.Date(2025, 1, 6, 12, 0, 1, 0, time.UTC),
+			want: time.Date(2025, 1, 6, 12, 0, 15, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := expressionParser.Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := calendarSchedule{schedule: sched, calendar: cal}.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarSchedule_NextNever(t *testing.T) {
+	// Blocked all day, every day
+	cal := Calendar{Blackouts: []TimeWindow{{Start: 0, End: 1439}, {Start: 1439, End: 0}}, Location: time.UTC}
+	sched, _ := expressionParser.Parse("@hourly")
+
+	if got := (calendarSchedule{schedule: sched, calendar: cal}).Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
@@ -0,0 +1,77 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TriggeredChain is the trigger type of runs started by a job chain
+const TriggeredChain = "chain"
+
+// Chain identifies one run of a workflow of jobs started by each other's
+// triggers. All jobs of a run share the chain ID, so that the workflow can be
+// followed across the schedule and oneshot histories.
+type Chain struct {
+	ID          string `json:"id"`                     // Unique ID of the workflow run
+	Root        string `json:"root"`                   // Job that started the workflow
+	TriggeredBy string `json:"triggered_by,omitempty"` // Job whose completion started this one (empty for the root)
+}
+
+// NewChain starts a workflow run at root
+func NewChain(root string) Chain {
+	return Chain{
+		ID:   fmt.Sprintf("%s-%d", root, time.Now().UnixNano()),
+		Root: root,
+	}
+}
+
+// IsZero returns true if the run is not part of a chain
+func (c Chain) IsZero() bool {
+	return c.ID == ""
+}
+
+// Next returns the chain of a job started by triggeredBy's completion
+func (c Chain) Next(triggeredBy string) Chain {
+	c.TriggeredBy = triggeredBy
+	return c
+}
+
+// EnvVars returns the environment variables passed to the job's process
+func (c Chain) EnvVars() []string {
+	if c.IsZero() {
+		return nil
+	}
+	env := []string{
+		"PHPEEK_PM_CHAIN_ID=" + c.ID,
+		"PHPEEK_PM_CHAIN_ROOT=" + c.Root,
+	}
+	if c.TriggeredBy != "" {
+		env = append(env, "PHPEEK_PM_TRIGGERED_BY="+c.TriggeredBy)
+	}
+	return env
+}
+
+type chainKey struct{}
+
+// WithChain returns a context carrying the chain of a run
+func WithChain(ctx context.Context, chain Chain) context.Context {
+	return context.WithValue(ctx, chainKey{}, chain)
+}
+
+// ChainFromContext returns the chain carried by ctx (zero if none)
+func ChainFromContext(ctx context.Context) Chain {
+	chain, _ := ctx.Value(chainKey{}).(Chain)
+	return chain
+}
+
+// Completion describes a finished run of a job, including its retries. It is
+// passed to JobOptions.OnComplete to start the jobs that follow it.
+type Completion struct {
+	Job         string // Name of the job
+	ExecutionID int64  // History ID of the run's last attempt
+	ExitCode    int    // Exit code of the last attempt
+	Success     bool   // Whether the run succeeded
+	Err         error  // Error of the last attempt, if any
+	Chain       Chain  // Chain the run belongs to
+}
@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewChain(t *testing.T) {
+	chain := NewChain("extract")
+	if chain.Root != "extract" || chain.TriggeredBy != "" {
+		t.Errorf("NewChain() = %+v, want root extract without a trigger", chain)
+	}
+	if !strings.HasPrefix(chain.ID, "extract-") {
+		t.Errorf("ID = %q, want it to start with the root", chain.ID)
+	}
+	if chain.IsZero() {
+		t.Error("a new chain should not be zero")
+	}
+	if !(Chain{}).IsZero() {
+		t.Error("the zero chain should be zero")
+	}
+}
+
+func TestChain_Next(t *testing.T) {
+	root := NewChain("extract")
+	next := root.Next("extract").Next("transform")
+
+	if next.ID != root.ID || next.Root != "extract" {
+		t.Errorf("Next() = %+v, want the root's ID and root", next)
+	}
+	if next.TriggeredBy != "transform" {
+		t.Errorf("TriggeredBy = %q, want transform", next.TriggeredBy)
+	}
+	if root.TriggeredBy != "" {
+		t.Error("Next() should not modify the receiver")
+	}
+}
+
+func TestChain_EnvVars(t *testing.T) {
+	if env := (Chain{}).EnvVars(); env != nil {
+		t.Errorf("EnvVars() of the zero chain = %v, want nil", env)
+	}
+
+	root := Chain{ID: "extract-1", Root: "extract"}
+	want := []string{"PHPEEK_PM_CHAIN_ID=extract-1", "PHPEEK_PM_CHAIN_ROOT=extract"}
+	if env := root.EnvVars(); !slices.Equal(env, want) {
+		t.Errorf("EnvVars() = %v, want %v", env, want)
+	}
+
+	want = append(want, "PHPEEK_PM_TRIGGERED_BY=extract")
+	if env := root.Next("extract").EnvVars(); !slices.Equal(env, want) {
+		t.Errorf("EnvVars() = %v, want %v", env, want)
+	}
+}
+
+func TestChainFromContext(t *testing.T) {
+	if chain := ChainFromContext(context.Background()); !chain.IsZero() {
+		t.Errorf("ChainFromContext() = %+v, want the zero chain", chain)
+	}
+
+	chain := NewChain("extract")
+	if got := ChainFromContext(WithChain(context.Background(), chain)); got != chain {
+		t.Errorf("ChainFromContext() = %+v, want %+v", got, chain)
+	}
+}
@@ -16,10 +16,16 @@ import (
 	"github.com/gophpeek/phpeek-pm/internal/logger"
 )
 
+// DefaultStopTimeout is how long a timed-out or cancelled scheduled process
+// gets to exit after its stop signal before it is killed. It matches the
+// supervisor's instance shutdown timeout.
+const DefaultStopTimeout = 30 * time.Second
+
 // ProcessExecutor executes scheduled process commands directly
 type ProcessExecutor struct {
 	configs    map[string]ProcessConfig         // Process name -> config
 	logWriters map[string]*logger.ProcessWriter // Process name -> combined log writer
+	redactors  map[string]*logger.Redactor      // Process name -> redaction of captured output
 	logger     *slog.Logger
 	mu         sync.RWMutex
 }
@@ -31,6 +37,10 @@ type ProcessConfig struct {
 	Env        map[string]string
 	Timeout    time.Duration         // 0 = no timeout
 	Logging    *config.LoggingConfig // Logging configuration for output capture
+
+	Credential  *syscall.Credential // Run as this user/group (nil = inherit)
+	StopSignal  syscall.Signal      // Sent to the process group on timeout or cancel (default: SIGTERM)
+	StopTimeout time.Duration       // Grace period after StopSignal before SIGKILL (default: DefaultStopTimeout)
 }
 
 // NewProcessExecutor creates a new ProcessExecutor
@@ -38,6 +48,7 @@ func NewProcessExecutor(log *slog.Logger) *ProcessExecutor {
 	return &ProcessExecutor{
 		configs:    make(map[string]ProcessConfig),
 		logWriters: make(map[string]*logger.ProcessWriter),
+		redactors:  make(map[string]*logger.Redactor),
 		logger:     log.With("component", "process_executor"),
 	}
 }
@@ -58,6 +69,15 @@ func (e *ProcessExecutor) RegisterProcess(name string, cfg ProcessConfig) error
 	}
 	e.logWriters[name] = pw
 
+	// Per-execution output is redacted like the combined log
+	if cfg.Logging != nil {
+		redactor, err := logger.NewRedactor(cfg.Logging.Redaction)
+		if err != nil {
+			return fmt.Errorf("failed to create redactor for %s: %w", name, err)
+		}
+		e.redactors[name] = redactor
+	}
+
 	e.logger.Debug("registered process for scheduling", "name", name, "command", cfg.Command)
 	return nil
 }
@@ -69,15 +89,30 @@ func (e *ProcessExecutor) UnregisterProcess(name string) {
 
 	delete(e.configs, name)
 	delete(e.logWriters, name)
+	delete(e.redactors, name)
 	e.logger.Debug("unregistered process from scheduling", "name", name)
 }
 
 // Execute runs the process and returns when complete
 func (e *ProcessExecutor) Execute(ctx context.Context, processName string) (int, error) {
+	return e.ExecuteWithOutput(ctx, processName, nil)
+}
+
+// ExecuteWithOutput runs the process like Execute and captures its output
+// in output (nil = no capture)
+func (e *ProcessExecutor) ExecuteWithOutput(ctx context.Context, processName string, output *ExecutionOutput) (int, error) {
 	cfg, logWriter, err := e.getProcessConfig(processName)
 	if err != nil {
 		return -1, err
 	}
+	if output != nil {
+		e.mu.RLock()
+		redactor := e.redactors[processName]
+		e.mu.RUnlock()
+		if redactor != nil && redactor.IsEnabled() {
+			output.SetRedactor(redactor.Redact)
+		}
+	}
 
 	// Apply timeout if configured
 	execCtx, cancel := e.applyTimeout(ctx, cfg.Timeout)
@@ -86,7 +121,8 @@ func (e *ProcessExecutor) Execute(ctx context.Context, processName string) (int,
 	}
 
 	// Setup and configure command
-	cmd := e.setupCommand(execCtx, processName, cfg, logWriter)
+	cmd := e.setupCommand(processName, cfg, logWriter, output)
+	cmd.Env = append(cmd.Env, ChainFromContext(ctx).EnvVars()...)
 
 	e.logger.Info("executing scheduled process",
 		"process", processName,
@@ -96,10 +132,13 @@ func (e *ProcessExecutor) Execute(ctx context.Context, processName string) (int,
 	if logWriter != nil {
 		logWriter.AddEvent("▶ Process started")
 	}
+	if output != nil {
+		output.AddEvent("▶ Process started")
+	}
 
 	// Run the command
 	startTime := time.Now()
-	runErr := cmd.Run()
+	runErr := e.run(execCtx, processName, cmd, cfg, logWriter)
 	duration := time.Since(startTime)
 
 	if logWriter != nil {
@@ -107,7 +146,19 @@ func (e *ProcessExecutor) Execute(ctx context.Context, processName string) (int,
 	}
 
 	// Handle execution result
-	return e.handleExecutionResult(ctx, processName, cfg, logWriter, runErr, duration)
+	exitCode, execErr := e.handleExecutionResult(execCtx, processName, cfg, logWriter, runErr, duration)
+	if output != nil {
+		output.AddEvent(exitEvent(exitCode, execErr, duration))
+	}
+	return exitCode, execErr
+}
+
+// exitEvent describes how an execution ended for its captured output
+func exitEvent(exitCode int, err error, duration time.Duration) string {
+	if err != nil {
+		return fmt.Sprintf("✗ %v (duration: %s)", err, duration.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("✓ Process exited (code: %d, duration: %s)", exitCode, duration.Round(time.Millisecond))
 }
 
 // getProcessConfig retrieves and validates the process configuration.
@@ -135,8 +186,8 @@ func (e *ProcessExecutor) applyTimeout(ctx context.Context, timeout time.Duratio
 }
 
 // setupCommand creates and configures the command for execution.
-func (e *ProcessExecutor) setupCommand(ctx context.Context, processName string, cfg ProcessConfig, logWriter *logger.ProcessWriter) *exec.Cmd {
-	cmd := exec.CommandContext(ctx, cfg.Command[0], cfg.Command[1:]...)
+func (e *ProcessExecutor) setupCommand(processName string, cfg ProcessConfig, logWriter *logger.ProcessWriter, output *ExecutionOutput) *exec.Cmd {
+	cmd := exec.Command(cfg.Command[0], cfg.Command[1:]...)
 
 	if cfg.WorkingDir != "" {
 		cmd.Dir = cfg.WorkingDir
@@ -151,17 +202,101 @@ func (e *ProcessExecutor) setupCommand(ctx context.Context, processName string,
 	cmd.Env = append(cmd.Env, "PHPEEK_PM_SCHEDULED=true")
 
 	// Setup output writers
+	stdout := []io.Writer{os.Stdout}
+	stderr := []io.Writer{os.Stderr}
 	if logWriter != nil {
-		cmd.Stdout = io.MultiWriter(os.Stdout, logWriter)
-		cmd.Stderr = io.MultiWriter(os.Stderr, logWriter)
-	} else {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		stdout = append(stdout, logWriter)
+		stderr = append(stderr, logWriter)
+	}
+	if output != nil {
+		stdout = append(stdout, output.Stream("stdout"))
+		stderr = append(stderr, output.Stream("stderr"))
+	}
+	cmd.Stdout = multiWriter(stdout)
+	cmd.Stderr = multiWriter(stderr)
+
+	// Run in its own process group like supervised processes, so the stop
+	// signal reaches children (e.g. of sh -c) and Ctrl+C does not
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:    true,
+		Credential: cfg.Credential,
 	}
 
 	return cmd
 }
 
+// multiWriter combines writers, passing a single one through so that the
+// process writes to it directly when it is a file
+func multiWriter(writers []io.Writer) io.Writer {
+	if len(writers) == 1 {
+		return writers[0]
+	}
+	return io.MultiWriter(writers...)
+}
+
+// run starts the command and waits for it to exit. When ctx ends first, the
+// stop signal is sent to the process group and SIGKILL follows after the stop
+// timeout. A process stopped this way returns the context error.
+func (e *ProcessExecutor) run(ctx context.Context, processName string, cmd *exec.Cmd, cfg ProcessConfig, logWriter *logger.ProcessWriter) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	sig := cfg.StopSignal
+	if sig == 0 {
+		sig = syscall.SIGTERM
+	}
+	timeout := cfg.StopTimeout
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+
+	e.logger.Info("stopping scheduled process",
+		"process", processName,
+		"signal", sig.String(),
+		"reason", ctx.Err(),
+	)
+	signalGroup(cmd, sig)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		e.logger.Warn("scheduled process did not stop gracefully, force killing",
+			"process", processName,
+			"timeout", timeout,
+		)
+		if logWriter != nil {
+			logWriter.AddEvent(fmt.Sprintf("☠ Process killed after %v", timeout))
+		}
+		signalGroup(cmd, syscall.SIGKILL)
+		<-done
+	}
+	return ctx.Err()
+}
+
+// signalGroup sends sig to the process group of cmd, falling back to the
+// process itself
+func signalGroup(cmd *exec.Cmd, sig syscall.Signal) {
+	// Setpgid makes the process the leader of a group with its PID
+	if err := syscall.Kill(-cmd.Process.Pid, sig); err != nil {
+		_ = cmd.Process.Signal(sig)
+	}
+}
+
 // handleExecutionResult processes the command execution result and returns the exit code.
 func (e *ProcessExecutor) handleExecutionResult(ctx context.Context, processName string, cfg ProcessConfig, logWriter *logger.ProcessWriter, err error, duration time.Duration) (int, error) {
 	if err != nil {
@@ -261,6 +396,54 @@ func (e *ProcessExecutor) GetLogs(processName string, limit int) []logger.LogEnt
 	return logs
 }
 
+// QueryLogs returns log entries for a scheduled process matching q, newest first
+func (e *ProcessExecutor) QueryLogs(processName string, q logger.LogQuery) []logger.LogEntry {
+	e.mu.RLock()
+	pw, exists := e.logWriters[processName]
+	e.mu.RUnlock()
+
+	if !exists || pw == nil {
+		return []logger.LogEntry{}
+	}
+	return pw.QueryLogs(q)
+}
+
+// SetLogFilters replaces the log filters of a scheduled process's writer.
+// Passing nil restores the filters from its logging configuration.
+func (e *ProcessExecutor) SetLogFilters(processName string, filters *logger.LogFilters) error {
+	e.mu.RLock()
+	cfg, exists := e.configs[processName]
+	logWriter := e.logWriters[processName]
+	e.mu.RUnlock()
+
+	if !exists || logWriter == nil {
+		return fmt.Errorf("process %q not registered", processName)
+	}
+
+	if filters == nil && cfg.Logging != nil {
+		configured, err := logger.NewLogFilters(cfg.Logging.Filters, cfg.Logging.MinLevel)
+		if err != nil {
+			return fmt.Errorf("failed to restore log filters for %s: %w", processName, err)
+		}
+		filters = configured
+	}
+
+	logWriter.SetFilters(filters)
+	return nil
+}
+
+// LogFilters returns the log filters currently applied to a scheduled process.
+func (e *ProcessExecutor) LogFilters(processName string) *logger.LogFilters {
+	e.mu.RLock()
+	logWriter := e.logWriters[processName]
+	e.mu.RUnlock()
+
+	if logWriter == nil {
+		return nil
+	}
+	return logWriter.Filters()
+}
+
 // HasProcess checks if a process is registered with the executor
 func (e *ProcessExecutor) HasProcess(processName string) bool {
 	e.mu.RLock()
@@ -2,9 +2,17 @@ package schedule
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/gophpeek/phpeek-pm/internal/logger"
 )
 
 func TestNewProcessExecutor(t *testing.T) {
@@ -61,6 +69,44 @@ func TestProcessExecutor_UnregisterProcess(t *testing.T) {
 	e.UnregisterProcess("non-existent")
 }
 
+func TestProcessExecutor_SetLogFilters(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+
+	_ = e.RegisterProcess("test", ProcessConfig{
+		Command: []string{"echo"},
+		Logging: &config.LoggingConfig{MinLevel: "warn"},
+	})
+
+	if got := e.LogFilters("test").GetMinLevel(); got != slog.LevelWarn {
+		t.Fatalf("configured min level = %v, want warn", got)
+	}
+
+	debug, err := logger.NewLogFilters(nil, "debug")
+	if err != nil {
+		t.Fatalf("NewLogFilters() error = %v", err)
+	}
+	if err := e.SetLogFilters("test", debug); err != nil {
+		t.Fatalf("SetLogFilters() error = %v", err)
+	}
+	if e.LogFilters("test") != debug {
+		t.Error("LogFilters() should return the override")
+	}
+
+	if err := e.SetLogFilters("test", nil); err != nil {
+		t.Fatalf("SetLogFilters(nil) error = %v", err)
+	}
+	if got := e.LogFilters("test").GetMinLevel(); got != slog.LevelWarn {
+		t.Errorf("restored min level = %v, want warn", got)
+	}
+
+	if err := e.SetLogFilters("missing", debug); err == nil {
+		t.Error("SetLogFilters(missing) should fail")
+	}
+	if e.LogFilters("missing") != nil {
+		t.Error("LogFilters(missing) should be nil")
+	}
+}
+
 func TestProcessExecutor_Execute_Success(t *testing.T) {
 	logger := testLogger()
 	e := NewProcessExecutor(logger)
@@ -388,3 +434,178 @@ func TestProcessExecutor_GetLogs_NoLogs(t *testing.T) {
 		t.Error("GetLogs() should return empty slice, not nil")
 	}
 }
+
+func TestProcessExecutor_SetupCommand_Isolation(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+	cred := &syscall.Credential{Uid: uint32(os.Getuid()), Gid: uint32(os.Getgid())}
+
+	cmd := e.setupCommand("test", ProcessConfig{Command: []string{"true"}, Credential: cred}, nil, nil)
+	if cmd.SysProcAttr == nil || !cmd.SysProcAttr.Setpgid {
+		t.Fatal("scheduled process should run in its own process group")
+	}
+	if cmd.SysProcAttr.Credential != cred {
+		t.Errorf("Credential = %v, want %v", cmd.SysProcAttr.Credential, cred)
+	}
+
+	cmd = e.setupCommand("test", ProcessConfig{Command: []string{"true"}}, nil, nil)
+	if cmd.SysProcAttr.Credential != nil {
+		t.Errorf("Credential = %v, want nil (inherit)", cmd.SysProcAttr.Credential)
+	}
+}
+
+func TestProcessExecutor_Execute_WithCredential(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+
+	// Switching to another user needs root, the current user always works
+	uid := os.Getuid()
+	_ = e.RegisterProcess("test", ProcessConfig{
+		Command:    []string{"sh", "-c", fmt.Sprintf(`test "$(id -u)" = %d`, uid)},
+		Credential: &syscall.Credential{Uid: uint32(uid), Gid: uint32(os.Getgid())},
+	})
+
+	exitCode, err := e.Execute(context.Background(), "test")
+	if err != nil || exitCode != 0 {
+		t.Errorf("Execute() = %d, %v, want 0, nil", exitCode, err)
+	}
+}
+
+func TestProcessExecutor_Execute_TimeoutStopsProcessGroup(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+
+	// The sleep child keeps the output pipe open, so Execute only returns
+	// quickly if the whole group is signalled
+	_ = e.RegisterProcess("test", ProcessConfig{
+		Command: []string{"sh", "-c", "sleep 5; echo done"},
+		Timeout: 100 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := e.Execute(context.Background(), "test")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Execute() error = %v, want timeout", err)
+	}
+	if d := time.Since(start); d >= 2*time.Second {
+		t.Errorf("Execute() took %v, children were not stopped", d)
+	}
+}
+
+func TestProcessExecutor_Execute_TimeoutUsesStopSignal(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+	marker := filepath.Join(t.TempDir(), "stopped")
+
+	_ = e.RegisterProcess("test", ProcessConfig{
+		Command:     []string{"sh", "-c", fmt.Sprintf(`trap 'touch %s; exit 0' INT; while :; do sleep 0.05; done`, marker)},
+		Timeout:     200 * time.Millisecond,
+		StopSignal:  syscall.SIGINT,
+		StopTimeout: 5 * time.Second,
+	})
+
+	start := time.Now()
+	_, err := e.Execute(context.Background(), "test")
+	if err == nil {
+		t.Error("Execute() should report the timeout even if the process exits cleanly")
+	}
+	if d := time.Since(start); d >= 3*time.Second {
+		t.Errorf("Execute() took %v, stop signal was not delivered", d)
+	}
+	if _, statErr := os.Stat(marker); statErr != nil {
+		t.Errorf("process did not handle SIGINT: %v", statErr)
+	}
+}
+
+func TestProcessExecutor_Execute_TimeoutEscalatesToKill(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+
+	// Ignored signals are inherited, so neither sh nor sleep stops on SIGTERM
+	_ = e.RegisterProcess("test", ProcessConfig{
+		Command:     []string{"sh", "-c", `trap '' TERM; sleep 5`},
+		Timeout:     100 * time.Millisecond,
+		StopTimeout: 200 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := e.Execute(context.Background(), "test")
+	if err == nil {
+		t.Error("Execute() should error on timeout")
+	}
+	if d := time.Since(start); d >= 2*time.Second {
+		t.Errorf("Execute() took %v, process was not killed after the stop timeout", d)
+	}
+
+	logs := e.GetLogs("test", 0)
+	killed := false
+	for _, entry := range logs {
+		if strings.Contains(entry.Message, "killed") {
+			killed = true
+		}
+	}
+	if !killed {
+		t.Error("expected a kill event in the process logs")
+	}
+}
+
+func TestProcessExecutor_ExecuteWithOutput(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+
+	_ = e.RegisterProcess("test", ProcessConfig{
+		Command: []string{"sh", "-c", "echo out; echo 'token=secret' >&2; exit 3"},
+		Logging: &config.LoggingConfig{
+			Redaction: &config.RedactionConfig{
+				Enabled:  true,
+				Patterns: []config.RedactionPattern{{Name: "token", Pattern: `token=\w+`, Replacement: "token=***"}},
+			},
+		},
+	})
+
+	output := NewExecutionOutput("test", OutputOptions{})
+	exitCode, err := e.ExecuteWithOutput(context.Background(), "test", output)
+	output.Close()
+	if err == nil || exitCode != 3 {
+		t.Fatalf("ExecuteWithOutput() = %d, %v, want exit 3", exitCode, err)
+	}
+
+	var stdout, stderr, events []string
+	for _, line := range output.Lines() {
+		switch line.Stream {
+		case "stdout":
+			stdout = append(stdout, line.Message)
+		case "stderr":
+			stderr = append(stderr, line.Message)
+		case "event":
+			events = append(events, line.Message)
+		}
+	}
+	if len(stdout) != 1 || stdout[0] != "out" {
+		t.Errorf("stdout = %v, want [out]", stdout)
+	}
+	if len(stderr) != 1 || stderr[0] != "token=***" {
+		t.Errorf("stderr = %v, want the redacted line", stderr)
+	}
+	if len(events) != 2 || !strings.Contains(events[0], "started") || !strings.Contains(events[1], "3") {
+		t.Errorf("events = %v, want start and exit events", events)
+	}
+}
+
+func TestProcessExecutor_ChainEnv(t *testing.T) {
+	e := NewProcessExecutor(testLogger())
+	_ = e.RegisterProcess("test", ProcessConfig{
+		Command: []string{"sh", "-c", "echo $PHPEEK_PM_CHAIN_ID $PHPEEK_PM_CHAIN_ROOT $PHPEEK_PM_TRIGGERED_BY"},
+	})
+
+	chain := Chain{ID: "extract-1", Root: "extract", TriggeredBy: "transform"}
+	output := NewExecutionOutput("test", OutputOptions{})
+	if _, err := e.ExecuteWithOutput(WithChain(context.Background(), chain), "test", output); err != nil {
+		t.Fatalf("ExecuteWithOutput() error = %v", err)
+	}
+	output.Close()
+
+	for _, line := range output.Lines() {
+		if line.Stream == "stdout" {
+			if line.Message != "extract-1 extract transform" {
+				t.Errorf("chain env = %q, want %q", line.Message, "extract-1 extract transform")
+			}
+			return
+		}
+	}
+	t.Error("no stdout captured")
+}
@@ -3,6 +3,8 @@ package schedule
 import (
 	"sync"
 	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/logger"
 )
 
 // ExecutionEntry represents a single execution of a scheduled job.
@@ -10,15 +12,30 @@ import (
 // including timing, exit status, and how the execution was triggered.
 //
 // ExecutionEntry is immutable after creation except for the EndTime, ExitCode,
-// Success, and Error fields which are set when execution completes.
+// Success, and Error fields which are set when execution completes, and
+// HasOutput which follows the captured output.
 type ExecutionEntry struct {
-	ID        int64     `json:"id"`         // Unique execution ID
-	StartTime time.Time `json:"start_time"` // When execution started
-	EndTime   time.Time `json:"end_time"`   // When execution ended (zero if still running)
-	ExitCode  int       `json:"exit_code"`  // Process exit code
-	Success   bool      `json:"success"`    // Whether execution was successful
-	Error     string    `json:"error"`      // Error message if any
-	Triggered string    `json:"triggered"`  // How it was triggered: "schedule", "manual", "api"
+	ID        int64     `json:"id"`                 // Unique execution ID
+	StartTime time.Time `json:"start_time"`         // When execution started
+	EndTime   time.Time `json:"end_time"`           // When execution ended (zero if still running)
+	ExitCode  int       `json:"exit_code"`          // Process exit code
+	Success   bool      `json:"success"`            // Whether execution was successful
+	Error     string    `json:"error"`              // Error message if any
+	Triggered string    `json:"triggered"`          // How it was triggered: "schedule", "manual", "chain"
+	Attempt   int       `json:"attempt"`            // Attempt within its run, starting at 1
+	RetryOf   int64     `json:"retry_of,omitempty"` // ID of the first attempt of the run (0 = this is the first)
+	Skipped   bool      `json:"skipped,omitempty"`  // The run did not start (Error says why)
+	HasOutput bool      `json:"has_output"`         // Output is kept and available via ExecutionHistory.GetOutput
+
+	ChainID     string `json:"chain_id,omitempty"`     // Workflow run the execution belongs to
+	TriggeredBy string `json:"triggered_by,omitempty"` // Job whose completion started the execution
+
+	output *ExecutionOutput
+}
+
+// IsRetry returns true if the execution retried a failed attempt
+func (e *ExecutionEntry) IsRetry() bool {
+	return e.RetryOf != 0
 }
 
 // Duration returns the execution duration
@@ -50,6 +67,12 @@ type ExecutionHistory struct {
 	maxSize int
 	nextID  int64
 	mu      sync.RWMutex
+
+	// Runs affected by the overlap policy. They are counted separately
+	// because skipped and queued runs have no entry of their own.
+	skipped  int
+	queued   int
+	replaced int
 }
 
 // NewExecutionHistory creates a new ExecutionHistory with the given maximum size.
@@ -70,6 +93,12 @@ func NewExecutionHistory(maxSize int) *ExecutionHistory {
 
 // StartExecution records the start of a new execution and returns its ID
 func (h *ExecutionHistory) StartExecution(triggered string) int64 {
+	return h.StartRetry(triggered, 0, 1)
+}
+
+// StartRetry records the start of a retry of the run whose first attempt is
+// retryOf and returns its ID
+func (h *ExecutionHistory) StartRetry(triggered string, retryOf int64, attempt int) int64 {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -77,17 +106,44 @@ func (h *ExecutionHistory) StartExecution(triggered string) int64 {
 		ID:        h.nextID,
 		StartTime: time.Now(),
 		Triggered: triggered,
+		Attempt:   attempt,
+		RetryOf:   retryOf,
 	}
-	h.nextID++
+	h.add(entry)
+	return entry.ID
+}
 
-	// Add to ring buffer
+// RecordSkip records a run that did not start, e.g. on a replica that did
+// not get the schedule lock. It is counted as skipped, not as an execution.
+func (h *ExecutionHistory) RecordSkip(triggered, reason string) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	entry := ExecutionEntry{
+		ID:        h.nextID,
+		StartTime: now,
+		EndTime:   now,
+		ExitCode:  -1,
+		Error:     "skipped: " + reason,
+		Triggered: triggered,
+		Attempt:   1,
+		Skipped:   true,
+	}
+	h.add(entry)
+	h.skipped++
+	return entry.ID
+}
+
+// add appends an entry to the ring buffer and assigns the next ID. Callers
+// hold h.mu.
+func (h *ExecutionHistory) add(entry ExecutionEntry) {
+	h.nextID++
 	if len(h.entries) >= h.maxSize {
 		// Remove oldest entry
 		h.entries = h.entries[1:]
 	}
 	h.entries = append(h.entries, entry)
-
-	return entry.ID
 }
 
 // EndExecution records the end of an execution
@@ -107,6 +163,90 @@ func (h *ExecutionHistory) EndExecution(id int64, exitCode int, success bool, er
 	}
 }
 
+// SetOutput attaches the captured output of an execution. Passing nil drops
+// it, e.g. for successful executions when only failures keep their output.
+func (h *ExecutionHistory) SetOutput(id int64, output *ExecutionOutput) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if h.entries[i].ID == id {
+			h.entries[i].output = output
+			h.entries[i].HasOutput = output != nil
+			return
+		}
+	}
+}
+
+// SetChain records the workflow run an execution belongs to
+func (h *ExecutionHistory) SetChain(id int64, chain Chain) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if h.entries[i].ID == id {
+			h.entries[i].ChainID = chain.ID
+			h.entries[i].TriggeredBy = chain.TriggeredBy
+			return
+		}
+	}
+}
+
+// ExecutionLogs is the captured output of one execution
+type ExecutionLogs struct {
+	ExecutionID    int64             `json:"execution_id"`
+	Running        bool              `json:"running"`
+	Success        bool              `json:"success"`
+	ExitCode       int               `json:"exit_code"`
+	TotalLines     int               `json:"total_lines"`     // Lines written, including truncated ones
+	TruncatedLines int               `json:"truncated_lines"` // Lines dropped between head and tail
+	Lines          []logger.LogEntry `json:"lines"`           // Oldest first
+}
+
+// GetOutput returns the captured output of an execution. Lines is empty when
+// no output was kept for it. It returns false for unknown or evicted
+// executions.
+func (h *ExecutionHistory) GetOutput(id int64) (ExecutionLogs, bool) {
+	entry, ok := h.GetByID(id)
+	if !ok {
+		return ExecutionLogs{}, false
+	}
+
+	logs := ExecutionLogs{
+		ExecutionID: entry.ID,
+		Running:     entry.IsRunning(),
+		Success:     entry.Success,
+		ExitCode:    entry.ExitCode,
+		Lines:       []logger.LogEntry{},
+	}
+	if entry.output != nil {
+		logs.Lines = entry.output.Lines()
+		logs.TotalLines, logs.TruncatedLines = entry.output.Counts()
+	}
+	return logs, true
+}
+
+// RecordSkipped counts a run skipped because the job was already executing
+func (h *ExecutionHistory) RecordSkipped() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.skipped++
+}
+
+// RecordQueued counts a run queued behind a running execution
+func (h *ExecutionHistory) RecordQueued() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.queued++
+}
+
+// RecordReplaced counts a run that cancelled a running execution to take its place
+func (h *ExecutionHistory) RecordReplaced() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.replaced++
+}
+
 // GetAll returns all entries, newest first
 func (h *ExecutionHistory) GetAll() []ExecutionEntry {
 	h.mu.RLock()
@@ -180,7 +320,7 @@ func (h *ExecutionHistory) SuccessRate() float64 {
 	var successes int
 	var completed int
 	for _, entry := range h.entries {
-		if !entry.EndTime.IsZero() {
+		if !entry.EndTime.IsZero() && !entry.Skipped {
 			completed++
 			if entry.Success {
 				successes++
@@ -201,6 +341,10 @@ type HistoryStats struct {
 	SuccessCount      int           `json:"success_count"`
 	FailureCount      int           `json:"failure_count"`
 	RunningCount      int           `json:"running_count"`
+	RetryCount        int           `json:"retry_count"`    // Executions that retried a failed attempt
+	SkippedCount      int           `json:"skipped_count"`  // Runs skipped by the overlap policy, the schedule lock or the pressure gate
+	QueuedCount       int           `json:"queued_count"`   // Runs queued behind a running execution
+	ReplacedCount     int           `json:"replaced_count"` // Runs that cancelled a running execution
 	SuccessRate       float64       `json:"success_rate"`
 	AverageDuration   time.Duration `json:"average_duration"`
 	LastExecutionTime time.Time     `json:"last_execution_time"`
@@ -213,11 +357,21 @@ func (h *ExecutionHistory) Stats() HistoryStats {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	stats := HistoryStats{}
+	stats := HistoryStats{
+		SkippedCount:  h.skipped,
+		QueuedCount:   h.queued,
+		ReplacedCount: h.replaced,
+	}
 	var totalDuration time.Duration
 
 	for _, entry := range h.entries {
+		if entry.Skipped {
+			continue
+		}
 		stats.TotalExecutions++
+		if entry.IsRetry() {
+			stats.RetryCount++
+		}
 
 		if entry.EndTime.IsZero() {
 			stats.RunningCount++
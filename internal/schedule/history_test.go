@@ -1,6 +1,7 @@
 package schedule
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -381,3 +382,106 @@ func TestExecutionHistory_Concurrent(t *testing.T) {
 		t.Errorf("Len() = %d, want 50", h.Len())
 	}
 }
+
+func TestExecutionHistory_StartRetry(t *testing.T) {
+	h := NewExecutionHistory(10)
+
+	first := h.StartExecution("schedule")
+	h.EndExecution(first, 1, false, "exit 1")
+	retry := h.StartRetry("schedule", first, 2)
+	h.EndExecution(retry, 0, true, "")
+
+	entry, ok := h.GetByID(first)
+	if !ok || entry.Attempt != 1 || entry.IsRetry() {
+		t.Errorf("first attempt = %+v, want attempt 1 and no retry_of", entry)
+	}
+	entry, ok = h.GetByID(retry)
+	if !ok || entry.Attempt != 2 || entry.RetryOf != first || !entry.IsRetry() {
+		t.Errorf("retry = %+v, want attempt 2 retrying %d", entry, first)
+	}
+
+	stats := h.Stats()
+	if stats.TotalExecutions != 2 || stats.RetryCount != 1 {
+		t.Errorf("stats = %+v, want 2 executions and 1 retry", stats)
+	}
+}
+
+func TestExecutionHistory_OverlapCounters(t *testing.T) {
+	h := NewExecutionHistory(10)
+
+	h.RecordSkipped()
+	h.RecordSkipped()
+	h.RecordQueued()
+	h.RecordReplaced()
+
+	stats := h.Stats()
+	if stats.SkippedCount != 2 || stats.QueuedCount != 1 || stats.ReplacedCount != 1 {
+		t.Errorf("stats = %+v, want 2 skipped, 1 queued and 1 replaced", stats)
+	}
+	if stats.TotalExecutions != 0 {
+		t.Errorf("TotalExecutions = %d, overlap counters should not add entries", stats.TotalExecutions)
+	}
+}
+
+func TestExecutionHistory_RecordSkip(t *testing.T) {
+	h := NewExecutionHistory(10)
+
+	id := h.StartExecution("schedule")
+	h.EndExecution(id, 0, true, "")
+	skipID := h.RecordSkip("schedule", "not leader")
+
+	entry, ok := h.GetByID(skipID)
+	if !ok {
+		t.Fatal("skip should add an entry")
+	}
+	if !entry.Skipped || entry.Error != "skipped: not leader" || entry.IsRunning() {
+		t.Errorf("entry = %+v, want a finished skipped entry", entry)
+	}
+
+	stats := h.Stats()
+	if stats.TotalExecutions != 1 || stats.SuccessCount != 1 || stats.FailureCount != 0 {
+		t.Errorf("stats = %+v, skipped entries should not count as executions", stats)
+	}
+	if stats.SkippedCount != 1 {
+		t.Errorf("SkippedCount = %d, want 1", stats.SkippedCount)
+	}
+	if rate := h.SuccessRate(); rate != 100 {
+		t.Errorf("SuccessRate() = %.1f, want 100", rate)
+	}
+}
+
+func TestExecutionHistory_Output(t *testing.T) {
+	h := NewExecutionHistory(10)
+
+	id := h.StartExecution("schedule")
+	output := NewExecutionOutput("backup", OutputOptions{})
+	h.SetOutput(id, output)
+	fmt.Fprint(output.Stream("stdout"), "running\n")
+
+	logs, ok := h.GetOutput(id)
+	if !ok || !logs.Running || len(logs.Lines) != 1 {
+		t.Errorf("GetOutput() while running = %+v, %v", logs, ok)
+	}
+
+	h.EndExecution(id, 2, false, "exit 2")
+	logs, _ = h.GetOutput(id)
+	if logs.Running || logs.ExitCode != 2 || logs.TotalLines != 1 {
+		t.Errorf("GetOutput() after end = %+v", logs)
+	}
+	if entry, _ := h.GetByID(id); !entry.HasOutput {
+		t.Error("HasOutput should be set")
+	}
+
+	h.SetOutput(id, nil)
+	logs, ok = h.GetOutput(id)
+	if !ok || logs.Lines == nil || len(logs.Lines) != 0 {
+		t.Errorf("GetOutput() after drop = %+v, %v, want empty lines", logs, ok)
+	}
+	if entry, _ := h.GetByID(id); entry.HasOutput {
+		t.Error("HasOutput should be cleared")
+	}
+
+	if _, ok := h.GetOutput(99); ok {
+		t.Error("GetOutput() should fail for unknown executions")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,9 +16,11 @@ import (
 // The state machine transitions are:
 //
 //	Idle → Executing (on trigger/schedule)
-//	Executing → Idle (on completion)
+//	Executing → Idle (on completion of the last running execution)
 //	Idle → Paused (on pause request)
 //	Paused → Idle (on resume request)
+//	Executing → Disabled (after DisableAfter consecutive failed runs)
+//	Disabled → Idle (on resume request or a successful manual run)
 //
 // Executing → Paused is not allowed; jobs must complete before pausing.
 type JobState int
@@ -28,6 +32,9 @@ const (
 	JobStateExecuting
 	// JobStatePaused - job is paused (skips triggers)
 	JobStatePaused
+	// JobStateDisabled - job failed too often in a row (skips scheduled
+	// triggers, manual triggers still run)
+	JobStateDisabled
 )
 
 // String returns the string representation of JobState
@@ -39,6 +46,8 @@ func (s JobState) String() string {
 		return "executing"
 	case JobStatePaused:
 		return "paused"
+	case JobStateDisabled:
+		return "disabled"
 	default:
 		return "unknown"
 	}
@@ -65,10 +74,13 @@ type JobExecutor interface {
 // and support for both scheduled and manual triggering.
 //
 // Key features:
-//   - Cron-based scheduling with standard 5-field expressions
-//   - Execution history with configurable retention
-//   - Overlap prevention (jobs don't run concurrently by default)
+//   - Cron-based scheduling with 5-field, 6-field (seconds) and @every expressions
+//   - Start jitter and calendar constraints (blackout windows, weekdays, skip dates)
+//   - Execution history with configurable retention and per-execution output
+//   - Overlap policies: skip (default), queue, replace or allow parallel runs
 //   - Configurable timeouts and concurrency limits
+//   - Retries with exponential backoff and a consecutive failure breaker
+//   - Optional leader lock so that replicas run each trigger once
 //   - Pause/resume without removing from scheduler
 //   - Manual triggering (async or sync with exit code)
 //
@@ -82,19 +94,39 @@ type ScheduledJob struct {
 	History       *ExecutionHistory
 	LastRun       time.Time
 	NextRun       time.Time
-	CurrentExecID int64 // ID of currently running execution
+	CurrentExecID int64 // ID of the most recently started running execution
 
 	// Execution control
 	Timeout       time.Duration // Execution timeout (0 = no timeout)
-	MaxConcurrent int           // Max concurrent executions (0/1 = no overlap, >1 = allow parallel)
+	Overlap       OverlapPolicy // What to do when triggered while executing
+	MaxConcurrent int           // Max parallel executions with OverlapAllow (0 = unlimited)
+	QueueSize     int           // Max waiting runs with OverlapQueue (0 = 1)
+	Retry         RetryPolicy   // Retries of failed executions within a run
+	DisableAfter  int           // Disable after N consecutive failed runs (0 = never)
+	Jitter        time.Duration // Max random delay of scheduled starts (0 = none)
+	Calendar      Calendar      // Days and times scheduled runs may start
+	LockTTL       time.Duration // Lifetime of the schedule lock, renewed during runs
+	Output        OutputOptions // Output kept with each execution
 
 	// Internal
-	cronID      cron.EntryID
-	schedule    cron.Schedule
-	executor    JobExecutor
-	logger      *slog.Logger
-	mu          sync.Mutex
-	executionMu sync.Mutex // Separate mutex for execution to allow state reads during execution
+	cronID   cron.EntryID
+	schedule cron.Schedule
+	executor JobExecutor
+	gate     func(jobName string) error // Skips scheduled runs while it returns an error (nil = always run)
+	complete func(Completion)           // Called when a run ends (nil = no chain)
+	lock     Locker                     // Lock shared with other replicas (nil = run on every replica)
+	runCtx   func() context.Context     // Done when the scheduler stops (nil = never)
+	logger   *slog.Logger
+	mu       sync.Mutex
+	lockMu   sync.Mutex // Serializes taking and giving up the lock
+
+	slots               chan struct{}                     // One token per running execution (nil = unlimited)
+	runs                map[int64]context.CancelCauseFunc // Running executions by run number
+	lastRunNumber       int64                             // Last assigned run number
+	queued              int                               // Runs waiting for a slot with OverlapQueue
+	jitters             map[time.Time]time.Duration       // Start delay drawn for each upcoming activation
+	heldLock            *heldLock                         // Lock held by this replica for its runs (nil = not held)
+	consecutiveFailures int                               // Failed runs in a row (a run includes its retries)
 }
 
 // JobOptions contains optional configuration for a scheduled job.
@@ -104,10 +136,59 @@ type JobOptions struct {
 	// job's context is cancelled. Zero means no timeout (run until completion).
 	Timeout time.Duration
 
-	// MaxConcurrent limits how many instances can run simultaneously.
-	// 0 or 1 means no overlap (skip if already running), >1 allows parallel runs.
+	// Overlap decides what happens when the job triggers while a previous
+	// run is still executing. Empty means OverlapSkip.
+	Overlap OverlapPolicy
+
+	// MaxConcurrent limits how many instances can run simultaneously with
+	// OverlapAllow. 0 means unlimited. Other policies run one at a time.
 	// Use with caution as parallel runs may cause resource contention.
 	MaxConcurrent int
+
+	// QueueSize limits how many runs may wait with OverlapQueue. Further
+	// triggers are skipped. 0 means 1.
+	QueueSize int
+
+	// Gate is consulted before each scheduled run. When it returns an error
+	// the run is skipped (e.g. to shed load under resource pressure). Manual
+	// triggers are not gated. Nil means always run.
+	Gate func(jobName string) error
+
+	// Retry retries failed executions before the run counts as failed.
+	// Each attempt gets its own timeout and history entry.
+	Retry RetryPolicy
+
+	// DisableAfter disables scheduled runs after this many consecutive failed
+	// runs until the job is resumed or a manual run succeeds. Zero never
+	// disables the job.
+	DisableAfter int
+
+	// Jitter delays each scheduled start by a random duration up to Jitter,
+	// so that replicas sharing a schedule do not start in lockstep.
+	Jitter time.Duration
+
+	// Calendar blocks scheduled runs on some days and times. Blocked
+	// activations are skipped and do not show as the next run. Manual
+	// triggers are not restricted. A nil Location uses the job's timezone.
+	Calendar Calendar
+
+	// Lock is taken before each run, so that replicas sharing the schedule
+	// run each trigger once. Replicas that do not get it record the run as
+	// skipped. Nil runs the job on every replica.
+	Lock Locker
+
+	// LockTTL is the lifetime of the lock. It is renewed during long runs.
+	// Zero means DefaultLockTTL.
+	LockTTL time.Duration
+
+	// Output bounds the output kept with each execution's history entry.
+	// Output is only captured when the executor implements OutputExecutor.
+	Output OutputOptions
+
+	// OnComplete is called when a run ends, after its retries, to start the
+	// jobs that follow it. It is not called for skipped, cancelled or
+	// replaced runs. Runs not started by a chain start a new one.
+	OnComplete func(Completion)
 }
 
 // NewScheduledJob creates a new ScheduledJob with default options.
@@ -115,7 +196,7 @@ type JobOptions struct {
 //
 // Parameters:
 //   - name: Unique identifier for the job (used in logs and API)
-//   - scheduleExpr: Cron expression (5 fields: minute hour day-of-month month day-of-week)
+//   - scheduleExpr: Cron expression (5 fields, or 6 with leading seconds) or descriptor like "@every 90s"
 //   - timezone: IANA timezone name (e.g., "America/New_York") or empty for local
 //   - historySize: Maximum execution history entries to retain
 //   - executor: Implementation that runs the actual job process
@@ -129,10 +210,26 @@ func NewScheduledJob(name, scheduleExpr, timezone string, historySize int, execu
 // NewScheduledJobWithOptions creates a new ScheduledJob with additional options
 func NewScheduledJobWithOptions(name, scheduleExpr, timezone string, historySize int, executor JobExecutor, logger *slog.Logger, opts JobOptions) (*ScheduledJob, error) {
 	// Parse the schedule expression
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	schedule, err := parser.Parse(scheduleExpr)
+	schedule, err := parseSchedule(scheduleExpr, timezoneLocation(timezone))
 	if err != nil {
-		return nil, fmt.Errorf("invalid schedule expression: %w", err)
+		return nil, err
+	}
+
+	calendar := opts.Calendar
+	if calendar.Location == nil {
+		calendar.Location = timezoneLocation(timezone)
+	}
+	if !calendar.IsZero() {
+		schedule = calendarSchedule{schedule: schedule, calendar: calendar}
+	}
+
+	overlap := opts.Overlap
+	if overlap == "" {
+		overlap = OverlapSkip
+	}
+	var slots chan struct{}
+	if n := slotCount(overlap, opts.MaxConcurrent); n > 0 {
+		slots = make(chan struct{}, n)
 	}
 
 	return &ScheduledJob{
@@ -142,13 +239,61 @@ func NewScheduledJobWithOptions(name, scheduleExpr, timezone string, historySize
 		State:         JobStateIdle,
 		History:       NewExecutionHistory(historySize),
 		Timeout:       opts.Timeout,
+		Overlap:       overlap,
 		MaxConcurrent: opts.MaxConcurrent,
+		QueueSize:     opts.QueueSize,
+		Retry:         opts.Retry,
+		DisableAfter:  opts.DisableAfter,
+		Jitter:        opts.Jitter,
+		Calendar:      calendar,
+		LockTTL:       opts.LockTTL,
+		Output:        opts.Output,
+		gate:          opts.Gate,
+		complete:      opts.OnComplete,
+		lock:          opts.Lock,
 		schedule:      schedule,
 		executor:      executor,
 		logger:        logger.With("job", name),
+		slots:         slots,
+		runs:          make(map[int64]context.CancelCauseFunc),
 	}, nil
 }
 
+// expressionParser parses 5-field cron expressions, 6-field ones with
+// leading seconds and descriptors like @hourly or @every 90s
+var expressionParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// parseSchedule parses a schedule expression whose times are in loc. A
+// CRON_TZ= or TZ= prefix in the expression takes precedence.
+func parseSchedule(expr string, loc *time.Location) (cron.Schedule, error) {
+	schedule, err := expressionParser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule expression: %w", err)
+	}
+	if spec, ok := schedule.(*cron.SpecSchedule); ok && !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		spec.Location = loc
+	}
+	return schedule, nil
+}
+
+// timezoneLocation returns the location of a job timezone ("UTC" or local)
+func timezoneLocation(timezone string) *time.Location {
+	if timezone == "UTC" {
+		return time.UTC
+	}
+	return time.Local
+}
+
+// randomJitter returns a random delay up to max (0 if max <= 0)
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
+
 // GetState returns the current job state (thread-safe)
 func (j *ScheduledJob) GetState() JobState {
 	j.mu.Lock()
@@ -170,13 +315,61 @@ func (j *ScheduledJob) GetCronID() cron.EntryID {
 	return j.cronID
 }
 
-// UpdateNextRun updates the next run time
+// UpdateNextRun updates the next run time from the next activation of the
+// schedule, adding the start jitter drawn for that activation
 func (j *ScheduledJob) UpdateNextRun(t time.Time) {
 	j.mu.Lock()
 	defer j.mu.Unlock()
+	if !t.IsZero() {
+		t = t.Add(j.jitterFor(t))
+	}
 	j.NextRun = t
 }
 
+// jitterFor returns the start delay of the activation at t, drawing it on
+// first use. Callers hold j.mu.
+func (j *ScheduledJob) jitterFor(t time.Time) time.Duration {
+	if j.Jitter <= 0 {
+		return 0
+	}
+	if delay, ok := j.jitters[t]; ok {
+		return delay
+	}
+	if j.jitters == nil {
+		j.jitters = make(map[time.Time]time.Duration)
+	}
+	delay := randomJitter(j.Jitter)
+	j.jitters[t] = delay
+	return delay
+}
+
+// takeJitter returns how long to wait before starting the activation due at
+// now, using the delay drawn when its NextRun was computed, and forgets the
+// delays of activations that are due
+func (j *ScheduledJob) takeJitter(now time.Time) time.Duration {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.Jitter <= 0 {
+		return 0
+	}
+
+	var activation time.Time
+	var delay time.Duration
+	for t, d := range j.jitters {
+		if t.After(now) {
+			continue
+		}
+		if t.After(activation) {
+			activation, delay = t, d
+		}
+		delete(j.jitters, t)
+	}
+	if activation.IsZero() {
+		return randomJitter(j.Jitter) // NextRun was not computed for it
+	}
+	return activation.Add(delay).Sub(now)
+}
+
 // GetNextRun returns the next scheduled run time
 func (j *ScheduledJob) GetNextRun() time.Time {
 	j.mu.Lock()
@@ -208,20 +401,29 @@ func (j *ScheduledJob) Pause() error {
 	return nil
 }
 
-// Resume resumes a paused job
+// Resume resumes a paused job or re-enables a disabled one, resetting its
+// consecutive failure count
 func (j *ScheduledJob) Resume() error {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 
-	if j.State != JobStatePaused {
+	if j.State != JobStatePaused && j.State != JobStateDisabled {
 		return fmt.Errorf("job is not paused (current state: %s)", j.State)
 	}
 
 	j.State = JobStateIdle
+	j.consecutiveFailures = 0
 	j.logger.Info("job resumed")
 	return nil
 }
 
+// IsDisabled returns true if the job was disabled after consecutive failures
+func (j *ScheduledJob) IsDisabled() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.State == JobStateDisabled
+}
+
 // IsPaused returns true if the job is paused
 func (j *ScheduledJob) IsPaused() bool {
 	j.mu.Lock()
@@ -246,105 +448,300 @@ func (j *ScheduledJob) CanExecute() bool {
 // Run is called by the cron scheduler when the schedule triggers
 // It implements the cron.Job interface
 func (j *ScheduledJob) Run() {
+	if delay := j.takeJitter(time.Now()); delay > 0 {
+		ctx := context.Background()
+		if j.runCtx != nil {
+			ctx = j.runCtx()
+		}
+
+		j.logger.Debug("delaying scheduled execution", "jitter", delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			j.logger.Debug("scheduler stopped, dropping delayed execution")
+			return
+		}
+	}
+
+	// The jitter may have moved the start into a blocked period
+	if reason, until := j.Calendar.Check(time.Now()); reason != "" {
+		j.logger.Info("skipping scheduled execution", "reason", reason, "until", until)
+		return
+	}
+	if j.gate != nil {
+		if err := j.gate(j.Name); err != nil {
+			j.History.RecordSkip("schedule", err.Error())
+			j.logger.Warn("skipping scheduled execution", "reason", err)
+			return
+		}
+	}
 	j.execute(context.Background(), "schedule")
 }
 
-// Trigger manually triggers execution (returns error if already running or paused)
+// Trigger manually triggers execution (returns error if paused or if the
+// overlap policy would skip the run)
 func (j *ScheduledJob) Trigger(ctx context.Context) error {
-	state := j.GetState()
-	if state == JobStatePaused {
-		return fmt.Errorf("cannot trigger paused job")
-	}
-	if state == JobStateExecuting {
-		return fmt.Errorf("job is already executing")
+	if err := j.checkTrigger(); err != nil {
+		return err
 	}
 
 	go j.execute(ctx, "manual")
 	return nil
 }
 
+// TriggerChain triggers execution as part of a job chain. Like manual
+// triggers, chained runs are not gated and run while the job is disabled.
+func (j *ScheduledJob) TriggerChain(ctx context.Context, chain Chain) error {
+	if err := j.checkTrigger(); err != nil {
+		return err
+	}
+
+	go j.execute(WithChain(ctx, chain), TriggeredChain)
+	return nil
+}
+
 // TriggerSync triggers execution and waits for completion
 func (j *ScheduledJob) TriggerSync(ctx context.Context) (int, error) {
-	state := j.GetState()
-	if state == JobStatePaused {
-		return -1, fmt.Errorf("cannot trigger paused job")
-	}
-	if state == JobStateExecuting {
-		return -1, fmt.Errorf("job is already executing")
+	if err := j.checkTrigger(); err != nil {
+		return -1, err
 	}
 
 	return j.executeSync(ctx, "manual")
 }
 
+// checkTrigger returns an error if a manual trigger would not run
+func (j *ScheduledJob) checkTrigger() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.State == JobStatePaused {
+		return fmt.Errorf("cannot trigger paused job")
+	}
+	if j.busy() {
+		return fmt.Errorf("job is already executing")
+	}
+	return nil
+}
+
 // execute runs the job (internal)
 // Error handling is done within executeSync (logging, state updates, callbacks)
 func (j *ScheduledJob) execute(ctx context.Context, triggered string) {
 	_, _ = j.executeSync(ctx, triggered)
 }
 
-// executeSync runs the job synchronously and returns the result
+// executeSync runs the job synchronously, retrying failed attempts as
+// configured, and returns the result of the last attempt. The overlap policy
+// decides whether it waits for, replaces or runs alongside running executions.
+// With a lock, only the replica holding it runs.
 func (j *ScheduledJob) executeSync(ctx context.Context, triggered string) (int, error) {
-	// Use execution mutex to prevent concurrent executions
-	j.executionMu.Lock()
-	defer j.executionMu.Unlock()
+	if err := j.checkRunnable(triggered); err != nil {
+		return -1, err
+	}
+	if err := j.admit(ctx, triggered); err != nil {
+		return -1, err
+	}
+	defer j.releaseSlot()
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Check state again, the job may have been paused or disabled while
+	// waiting for a slot, and transition to executing
+	if err := j.checkRunnable(triggered); err != nil {
+		return -1, err
+	}
+	// Scheduled runs keep the lock until the next activation
+	var keepLockUntil time.Time
+	if triggered == "schedule" {
+		keepLockUntil = j.schedule.Next(time.Now())
+	}
+	release, err := j.acquireLock(runCtx, cancel, keepLockUntil)
+	if err != nil {
+		j.History.RecordSkip(triggered, err.Error())
+		j.logger.Info("skipping execution", "reason", err)
+		return -1, err
+	}
+	defer release()
+
+	// Jobs that trigger others start a chain unless they are part of one
+	chain := ChainFromContext(runCtx)
+	if chain.IsZero() && j.complete != nil {
+		chain = NewChain(j.Name)
+		runCtx = WithChain(runCtx, chain)
+	}
 
-	// Check state and transition to executing
 	j.mu.Lock()
+	if j.runs == nil {
+		j.runs = make(map[int64]context.CancelCauseFunc)
+	}
+	j.lastRunNumber++
+	runNumber := j.lastRunNumber
+	j.runs[runNumber] = cancel
+	j.State = JobStateExecuting
+	j.LastRun = time.Now()
+	j.mu.Unlock()
+
+	var exitCode int
+	var execErr error
+	var firstID, execID int64
+	for attempt := 1; ; attempt++ {
+		execID, exitCode, execErr = j.executeAttempt(runCtx, triggered, attempt, firstID)
+		if firstID == 0 {
+			firstID = execID
+		}
+
+		if runCtx.Err() != nil || !j.Retry.ShouldRetry(attempt, exitCode, execErr) {
+			break
+		}
+
+		delay := j.Retry.Delay(attempt)
+		j.logger.Warn("job execution failed, retrying",
+			"execution_id", execID,
+			"attempt", attempt,
+			"max_attempts", j.Retry.MaxAttempts,
+			"exit_code", exitCode,
+			"delay", delay,
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-runCtx.Done():
+			timer.Stop()
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+	}
+
+	success := execErr == nil && exitCode == 0
+	j.mu.Lock()
+	delete(j.runs, runNumber)
+	switch {
+	case success:
+		j.consecutiveFailures = 0
+	case runCtx.Err() != nil:
+		// Cancelled or replaced runs say nothing about the job's health
+	default:
+		j.consecutiveFailures++
+	}
+
+	// Transition back to idle once the last execution ends, or to disabled
+	// when the breaker trips
+	if len(j.runs) == 0 {
+		j.CurrentExecID = 0
+		j.State = JobStateIdle
+		if j.tripped() {
+			j.State = JobStateDisabled
+			j.logger.Error("job disabled after consecutive failures",
+				"consecutive_failures", j.consecutiveFailures,
+				"hint", "resume the job or trigger a successful run to re-enable it",
+			)
+		}
+	}
+	j.mu.Unlock()
+
+	if j.complete != nil && runCtx.Err() == nil {
+		j.complete(Completion{
+			Job:         j.Name,
+			ExecutionID: execID,
+			ExitCode:    exitCode,
+			Success:     success,
+			Err:         execErr,
+			Chain:       chain,
+		})
+	}
+
+	return exitCode, execErr
+}
+
+// checkRunnable returns an error if the job must not run now
+func (j *ScheduledJob) checkRunnable(triggered string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
 	if j.State == JobStatePaused {
-		j.mu.Unlock()
 		j.logger.Debug("skipping execution - job paused")
-		return -1, fmt.Errorf("job is paused")
+		return fmt.Errorf("job is paused")
 	}
-	if j.State == JobStateExecuting {
-		j.mu.Unlock()
-		j.logger.Debug("skipping execution - already running (overlap policy: skip)")
-		return -1, fmt.Errorf("job is already executing")
+	if triggered == "schedule" && (j.State == JobStateDisabled || j.tripped()) {
+		j.logger.Debug("skipping execution - job disabled after consecutive failures")
+		return fmt.Errorf("job is disabled")
 	}
+	return nil
+}
 
-	j.State = JobStateExecuting
-	j.LastRun = time.Now()
-	execID := j.History.StartExecution(triggered)
+// tripped reports whether the consecutive failure breaker has tripped.
+// Callers hold j.mu.
+func (j *ScheduledJob) tripped() bool {
+	return j.DisableAfter > 0 && j.consecutiveFailures >= j.DisableAfter
+}
+
+// executeAttempt runs one attempt of a run and records it in the history.
+// retryOf is the execution ID of the first attempt (0 for the first).
+func (j *ScheduledJob) executeAttempt(ctx context.Context, triggered string, attempt int, retryOf int64) (int64, int, error) {
+	j.mu.Lock()
+	execID := j.History.StartRetry(triggered, retryOf, attempt)
 	j.CurrentExecID = execID
 	j.mu.Unlock()
+	if chain := ChainFromContext(ctx); !chain.IsZero() {
+		j.History.SetChain(execID, chain)
+	}
 
 	j.logger.Info("job execution started",
 		"execution_id", execID,
 		"triggered", triggered,
+		"attempt", attempt,
 	)
 
 	// Apply timeout if configured
 	execCtx := ctx
-	var cancel context.CancelFunc
 	if j.Timeout > 0 {
+		var cancel context.CancelFunc
 		execCtx, cancel = context.WithTimeout(ctx, j.Timeout)
 		defer cancel()
 		j.logger.Debug("job execution with timeout", "timeout", j.Timeout)
 	}
 
-	// Execute the job
-	exitCode, execErr := j.executor.Execute(execCtx, j.Name)
-
-	// Transition back to idle and record result
-	j.mu.Lock()
-	j.State = JobStateIdle
-	j.CurrentExecID = 0
-	j.mu.Unlock()
+	// Execute the job, capturing its output when the executor supports it
+	var exitCode int
+	var execErr error
+	var output *ExecutionOutput
+	if capturer, ok := j.executor.(OutputExecutor); ok {
+		output = NewExecutionOutput(j.Name, j.Output)
+		j.History.SetOutput(execID, output)
+		exitCode, execErr = capturer.ExecuteWithOutput(execCtx, j.Name, output)
+		output.Close()
+	} else {
+		exitCode, execErr = j.executor.Execute(execCtx, j.Name)
+	}
 
 	success := execErr == nil && exitCode == 0
 	errMsg := ""
 	if execErr != nil {
 		errMsg = execErr.Error()
 	}
+	if cause := context.Cause(ctx); cause == errReplaced || cause == errLockLost {
+		errMsg = cause.Error()
+	}
 	j.History.EndExecution(execID, exitCode, success, errMsg)
+	if output != nil && success && j.Output.OnFailureOnly {
+		j.History.SetOutput(execID, nil)
+	}
 
+	var duration time.Duration
+	if entry, ok := j.History.GetByID(execID); ok {
+		duration = entry.Duration()
+	}
 	j.logger.Info("job execution completed",
 		"execution_id", execID,
 		"exit_code", exitCode,
 		"success", success,
-		"duration", j.History.GetRecent(1)[0].Duration(),
+		"duration", duration,
 	)
 
-	return exitCode, execErr
+	return execID, exitCode, execErr
 }
 
 // JobStatus provides a snapshot of a scheduled job's current state.
@@ -358,6 +755,10 @@ type JobStatus struct {
 	NextRun       time.Time    `json:"next_run"`
 	CurrentExecID int64        `json:"current_execution_id,omitempty"`
 	Stats         HistoryStats `json:"stats"`
+
+	ConsecutiveFailures int `json:"consecutive_failures"`    // Failed runs in a row
+	DisableAfter        int `json:"disable_after,omitempty"` // Failed runs in a row that disable the job (0 = never)
+	MaxAttempts         int `json:"max_attempts,omitempty"`  // Attempts per run with retries
 }
 
 // Status returns the current job status
@@ -374,5 +775,9 @@ func (j *ScheduledJob) Status() JobStatus {
 		NextRun:       j.NextRun,
 		CurrentExecID: j.CurrentExecID,
 		Stats:         j.History.Stats(),
+
+		ConsecutiveFailures: j.consecutiveFailures,
+		DisableAfter:        j.DisableAfter,
+		MaxAttempts:         j.Retry.MaxAttempts,
 	}
 }
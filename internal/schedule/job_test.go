@@ -3,6 +3,7 @@ package schedule
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"sync"
@@ -38,6 +39,25 @@ func (m *mockExecutor) callCount() int64 {
 	return atomic.LoadInt64(&m.executeCalled)
 }
 
+// sequenceExecutor returns the next exit code of codes on each call, and
+// the last one once they are used up
+type sequenceExecutor struct {
+	codes []int
+	calls int
+	mu    sync.Mutex
+}
+
+func (s *sequenceExecutor) Execute(ctx context.Context, processName string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	code := s.codes[min(s.calls, len(s.codes)-1)]
+	s.calls++
+	if code != 0 {
+		return code, fmt.Errorf("process exited with code %d", code)
+	}
+	return 0, nil
+}
+
 func testLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 }
@@ -50,6 +70,7 @@ func TestJobState_String(t *testing.T) {
 		{JobStateIdle, "idle"},
 		{JobStateExecuting, "executing"},
 		{JobStatePaused, "paused"},
+		{JobStateDisabled, "disabled"},
 		{JobState(99), "unknown"},
 	}
 
@@ -79,6 +100,26 @@ func TestNewScheduledJob(t *testing.T) {
 			scheduleExpr: "0 9 * * 1-5",
 			wantErr:      false,
 		},
+		{
+			name:         "seconds expression",
+			scheduleExpr: "*/30 * * * * *",
+			wantErr:      false,
+		},
+		{
+			name:         "every descriptor",
+			scheduleExpr: "@every 90s",
+			wantErr:      false,
+		},
+		{
+			name:         "hourly descriptor",
+			scheduleExpr: "@hourly",
+			wantErr:      false,
+		},
+		{
+			name:         "too many fields",
+			scheduleExpr: "0 0 0 * * * *",
+			wantErr:      true,
+		},
 		{
 			name:         "invalid cron expression",
 			scheduleExpr: "not a cron",
@@ -381,6 +422,45 @@ func TestScheduledJob_Run_SkipsWhenPaused(t *testing.T) {
 	}
 }
 
+func TestScheduledJob_Run_SkipsWhenGated(t *testing.T) {
+	executor := &mockExecutor{}
+	logger := testLogger()
+
+	shed := true
+	job, _ := NewScheduledJobWithOptions("test-job", "*/5 * * * *", "", 10, executor, logger, JobOptions{
+		Gate: func(jobName string) error {
+			if shed {
+				return errors.New("container under pressure")
+			}
+			return nil
+		},
+	})
+
+	// Scheduled run is skipped while the gate refuses
+	job.Run()
+	if executor.callCount() != 0 {
+		t.Errorf("executor called %d times, want 0 when gated", executor.callCount())
+	}
+	last, ok := job.History.GetLast()
+	if !ok || !last.Skipped || last.Error != "skipped: container under pressure" {
+		t.Errorf("gated run should be recorded as skipped, got %+v", last)
+	}
+
+	// Manual triggers bypass the gate
+	if _, err := job.TriggerSync(context.Background()); err != nil {
+		t.Fatalf("TriggerSync() error = %v", err)
+	}
+	if executor.callCount() != 1 {
+		t.Errorf("executor called %d times, want 1 after manual trigger", executor.callCount())
+	}
+
+	shed = false
+	job.Run()
+	if executor.callCount() != 2 {
+		t.Errorf("executor called %d times, want 2 once the gate allows", executor.callCount())
+	}
+}
+
 func TestScheduledJob_Status(t *testing.T) {
 	executor := &mockExecutor{}
 	logger := testLogger()
@@ -539,3 +619,300 @@ func TestScheduledJob_Concurrent(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestScheduledJob_Retry(t *testing.T) {
+	executor := &sequenceExecutor{codes: []int{1, 1, 0}}
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		Retry: RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+	})
+
+	exitCode, err := job.TriggerSync(context.Background())
+	if err != nil || exitCode != 0 {
+		t.Fatalf("TriggerSync() = %d, %v, want success on the third attempt", exitCode, err)
+	}
+	if executor.calls != 3 {
+		t.Errorf("executor called %d times, want 3", executor.calls)
+	}
+
+	entries := job.History.GetAll()
+	if len(entries) != 3 {
+		t.Fatalf("history has %d entries, want one per attempt", len(entries))
+	}
+	first := entries[2]
+	if first.Attempt != 1 || first.RetryOf != 0 || first.Success {
+		t.Errorf("first attempt = %+v", first)
+	}
+	for i, attempt := range []int{3, 2} {
+		entry := entries[i]
+		if entry.Attempt != attempt || entry.RetryOf != first.ID {
+			t.Errorf("entry %d = attempt %d retrying %d, want attempt %d retrying %d", entry.ID, entry.Attempt, entry.RetryOf, attempt, first.ID)
+		}
+	}
+	if status := job.Status(); status.ConsecutiveFailures != 0 {
+		t.Errorf("ConsecutiveFailures = %d, want 0 after a successful retry", status.ConsecutiveFailures)
+	}
+}
+
+func TestScheduledJob_Retry_ExitCodes(t *testing.T) {
+	executor := &sequenceExecutor{codes: []int{2, 0}}
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		Retry: RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, ExitCodes: []int{75}},
+	})
+
+	exitCode, _ := job.TriggerSync(context.Background())
+	if exitCode != 2 || executor.calls != 1 {
+		t.Errorf("exit code %d after %d attempts, want 2 after 1 (2 is not retried)", exitCode, executor.calls)
+	}
+}
+
+func TestScheduledJob_Retry_StopsOnCancel(t *testing.T) {
+	executor := &sequenceExecutor{codes: []int{1}}
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		Retry:        RetryPolicy{MaxAttempts: 5, Backoff: time.Hour},
+		DisableAfter: 1,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _ = job.TriggerSync(ctx)
+	if time.Since(start) > time.Second {
+		t.Error("cancellation should interrupt the retry backoff")
+	}
+	if executor.calls != 1 {
+		t.Errorf("executor called %d times, want 1", executor.calls)
+	}
+	if job.IsDisabled() {
+		t.Error("a cancelled run should not count towards disable_after")
+	}
+}
+
+func TestScheduledJob_DisableAfter(t *testing.T) {
+	executor := &sequenceExecutor{codes: []int{1, 1, 0}}
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		DisableAfter: 2,
+	})
+
+	job.Run()
+	if job.IsDisabled() {
+		t.Fatal("job should not be disabled after one failure")
+	}
+	if status := job.Status(); status.ConsecutiveFailures != 1 || status.DisableAfter != 2 {
+		t.Errorf("status = %d/%d failures, want 1/2", status.ConsecutiveFailures, status.DisableAfter)
+	}
+
+	job.Run()
+	if !job.IsDisabled() {
+		t.Fatal("job should be disabled after two consecutive failures")
+	}
+	if state := job.Status().State; state != "disabled" {
+		t.Errorf("State = %q, want disabled", state)
+	}
+
+	// Scheduled runs are skipped while disabled
+	job.Run()
+	if executor.calls != 2 {
+		t.Errorf("executor called %d times, want 2 (disabled job skips scheduled runs)", executor.calls)
+	}
+
+	// A successful manual run re-enables the job
+	if exitCode, err := job.TriggerSync(context.Background()); err != nil || exitCode != 0 {
+		t.Fatalf("TriggerSync() = %d, %v", exitCode, err)
+	}
+	if job.GetState() != JobStateIdle || job.Status().ConsecutiveFailures != 0 {
+		t.Errorf("job should be idle with no failures after a successful run, got %s", job.GetState())
+	}
+}
+
+func TestScheduledJob_ResumeDisabled(t *testing.T) {
+	executor := &mockExecutor{returnCode: 1, returnErr: errors.New("exit 1")}
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		DisableAfter: 1,
+	})
+
+	job.Run()
+	if !job.IsDisabled() {
+		t.Fatal("job should be disabled")
+	}
+	if err := job.Resume(); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if job.GetState() != JobStateIdle || job.Status().ConsecutiveFailures != 0 {
+		t.Error("Resume() should re-enable the job and reset its failures")
+	}
+}
+
+func TestScheduledJob_Jitter(t *testing.T) {
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "", 10, &mockExecutor{}, testLogger(), JobOptions{
+		Jitter: time.Minute,
+	})
+
+	start := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		next := start.Add(time.Duration(i) * time.Minute)
+		job.UpdateNextRun(next)
+		nextRun := job.GetNextRun()
+		delay := nextRun.Sub(next)
+		if delay < 0 || delay >= time.Minute {
+			t.Fatalf("NextRun delay = %v, want within [0, 1m)", delay)
+		}
+
+		// The jitter is drawn once per activation and used by the run
+		job.UpdateNextRun(next)
+		if !job.GetNextRun().Equal(nextRun) {
+			t.Fatalf("NextRun changed from %v to %v for the same activation", nextRun, job.GetNextRun())
+		}
+		if got := job.takeJitter(next); got != delay {
+			t.Fatalf("takeJitter() = %v, want %v from NextRun", got, delay)
+		}
+	}
+	if len(job.jitters) != 0 {
+		t.Errorf("jitters of due activations should be dropped, got %v", job.jitters)
+	}
+
+	job.UpdateNextRun(time.Time{})
+	if !job.GetNextRun().IsZero() {
+		t.Error("UpdateNextRun() should keep a zero time (never runs)")
+	}
+}
+
+func TestScheduler_StopCancelsJitterWait(t *testing.T) {
+	executor := &mockExecutor{}
+	s := NewScheduler(executor, 10, testLogger())
+	if err := s.AddJobWithOptions("test", "@every 1h", "", JobOptions{Jitter: time.Hour}); err != nil {
+		t.Fatalf("AddJobWithOptions() error = %v", err)
+	}
+	s.Start()
+	job, _ := s.GetJob("test")
+
+	done := make(chan struct{})
+	go func() {
+		job.Run()
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() kept waiting out its jitter after Stop()")
+	}
+	if executor.callCount() != 0 {
+		t.Errorf("executor called %d times, want 0 after Stop()", executor.callCount())
+	}
+}
+
+func TestScheduledJob_Run_SkipsWhenBlocked(t *testing.T) {
+	executor := &mockExecutor{}
+	now := time.Now()
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "Local", 10, executor, testLogger(), JobOptions{
+		Calendar: Calendar{SkipDates: []string{now.Format(dateLayout)}},
+	})
+
+	job.Run()
+	if executor.callCount() != 0 {
+		t.Error("Run() should skip on a skip date")
+	}
+
+	// Manual triggers ignore the calendar
+	if _, err := job.TriggerSync(context.Background()); err != nil {
+		t.Errorf("TriggerSync() error = %v", err)
+	}
+	if executor.callCount() != 1 {
+		t.Error("TriggerSync() should run on a skip date")
+	}
+}
+
+func TestScheduledJob_OnComplete(t *testing.T) {
+	completions := make(chan Completion, 1)
+	executor := &sequenceExecutor{codes: []int{1, 0}}
+	job, _ := NewScheduledJobWithOptions("extract", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		Retry:      RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+		OnComplete: func(c Completion) { completions <- c },
+	})
+
+	if _, err := job.TriggerSync(context.Background()); err != nil {
+		t.Fatalf("TriggerSync() error = %v", err)
+	}
+
+	c := <-completions
+	if c.Job != "extract" || !c.Success || c.ExitCode != 0 {
+		t.Errorf("completion = %+v, want a successful run of extract", c)
+	}
+	if c.Chain.Root != "extract" || c.Chain.IsZero() {
+		t.Errorf("chain = %+v, want a new chain rooted at extract", c.Chain)
+	}
+	last, _ := job.History.GetLast()
+	if c.ExecutionID != last.ID {
+		t.Errorf("ExecutionID = %d, want the last attempt %d", c.ExecutionID, last.ID)
+	}
+	for _, entry := range job.History.GetAll() {
+		if entry.ChainID != c.Chain.ID {
+			t.Errorf("entry %d chain = %q, want %q for every attempt", entry.ID, entry.ChainID, c.Chain.ID)
+		}
+	}
+}
+
+func TestScheduledJob_OnComplete_NotCalledWhenCancelled(t *testing.T) {
+	called := make(chan Completion, 1)
+	executor := &mockExecutor{delay: time.Second}
+	job, _ := NewScheduledJobWithOptions("test", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		OnComplete: func(c Completion) { called <- c },
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _ = job.TriggerSync(ctx)
+
+	select {
+	case c := <-called:
+		t.Errorf("OnComplete called for a cancelled run: %+v", c)
+	default:
+	}
+}
+
+func TestScheduledJob_TriggerChain(t *testing.T) {
+	completions := make(chan Completion, 1)
+	executor := &sequenceExecutor{codes: []int{2}}
+	job, _ := NewScheduledJobWithOptions("load", "* * * * *", "", 10, executor, testLogger(), JobOptions{
+		DisableAfter: 1,
+		OnComplete:   func(c Completion) { completions <- c },
+	})
+
+	// Chained runs are not blocked by the failure breaker, like manual ones
+	_, _ = job.TriggerSync(context.Background())
+	<-completions
+	if !job.IsDisabled() {
+		t.Fatal("job should be disabled after one failed run")
+	}
+
+	chain := NewChain("extract").Next("transform")
+	if err := job.TriggerChain(context.Background(), chain); err != nil {
+		t.Fatalf("TriggerChain() error = %v", err)
+	}
+
+	select {
+	case c := <-completions:
+		if c.Success || c.ExitCode != 2 || c.Chain != chain {
+			t.Errorf("completion = %+v, want a failed run in chain %+v", c, chain)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("chained run did not complete")
+	}
+
+	last, _ := job.History.GetLast()
+	if last.Triggered != TriggeredChain || last.ChainID != chain.ID || last.TriggeredBy != "transform" {
+		t.Errorf("history entry = %+v, want a chained run triggered by transform", last)
+	}
+}
+
+func TestScheduledJob_TriggerChain_WhilePaused(t *testing.T) {
+	job, _ := NewScheduledJob("test", "* * * * *", "", 10, &mockExecutor{}, testLogger())
+	_ = job.Pause()
+
+	if err := job.TriggerChain(context.Background(), NewChain("root")); err == nil {
+		t.Error("TriggerChain() should fail for a paused job")
+	}
+}
@@ -0,0 +1,258 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// DefaultLockTTL is the lock lifetime when none is configured
+const DefaultLockTTL = 30 * time.Second
+
+// Locker is a lock shared by replicas running the same schedule, so that a
+// singleton job runs on one replica per trigger. Each replica uses its own
+// identity. Locks expire after their TTL unless renewed, so a crashed
+// replica cannot hold a job forever.
+type Locker interface {
+	// Acquire takes the lock for name. It returns false without an error
+	// when another replica holds it.
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+
+	// Renew extends a lock held by this replica. It returns false when the
+	// lock expired and was taken over.
+	Renew(ctx context.Context, name string, ttl time.Duration) (bool, error)
+
+	// Release gives up a lock held by this replica
+	Release(ctx context.Context, name string) error
+}
+
+// errLockLost is the cancellation cause of a run whose lock was taken over
+var errLockLost = errors.New("lost the schedule lock")
+
+// NewLocker creates the Locker of a schedule_lock config
+func NewLocker(cfg *config.ScheduleLockConfig) (Locker, error) {
+	identity := cfg.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine lock identity: %w", err)
+		}
+		identity = hostname
+	}
+
+	switch cfg.Backend {
+	case "file":
+		if cfg.File == nil || cfg.File.Dir == "" {
+			return nil, fmt.Errorf("schedule_lock.file.dir is required")
+		}
+		return NewFileLocker(cfg.File.Dir, identity)
+	case "redis":
+		if cfg.Redis == nil || cfg.Redis.Address == "" {
+			return nil, fmt.Errorf("schedule_lock.redis.address is required")
+		}
+		return &RedisLocker{
+			Address:   cfg.Redis.Address,
+			Password:  cfg.Redis.Password,
+			DB:        cfg.Redis.DB,
+			KeyPrefix: cfg.Redis.KeyPrefix,
+			Identity:  identity,
+		}, nil
+	case "kubernetes":
+		k8s := cfg.Kubernetes
+		if k8s == nil {
+			k8s = &config.KubernetesLockConfig{}
+		}
+		return NewLeaseLocker(k8s.URL, k8s.Namespace, k8s.TokenFile, k8s.CAFile, k8s.LeasePrefix, identity)
+	default:
+		return nil, fmt.Errorf("unknown schedule_lock backend %q (must be file, redis or kubernetes)", cfg.Backend)
+	}
+}
+
+// heldLock is the job's lock while this replica holds it. Overlapping runs
+// share it, with one goroutine renewing it until the last run finishes and
+// the latest keep deadline of the scheduled runs has passed.
+type heldLock struct {
+	ctx       context.Context                 // Done when the scheduler stops
+	ttl       time.Duration                   // Lifetime of each renewal
+	runs      map[int]context.CancelCauseFunc // Runs holding the lock, cancelled if it is taken over
+	nextRun   int                             // Key of the next run in runs
+	keepUntil time.Time                       // Kept after the last run until then
+	stopping  bool                            // The scheduler stopped, nothing is kept
+	changed   chan struct{}                   // Wakes the renewer when a run finishes
+}
+
+// idle reports whether the lock can be given up. The caller holds j.mu.
+func (h *heldLock) idle() bool {
+	return len(h.runs) == 0 && (h.stopping || !time.Now().Before(h.keepUntil))
+}
+
+// acquireLock takes the job's lock before a run, or joins the lock already
+// held for a running or recently finished run. On success the lock is renewed
+// until the returned release function is called, and cancel is called with
+// errLockLost if a renewal finds the lock taken over.
+//
+// With a non-zero keepUntil (the next activation, for scheduled runs), release
+// keeps the lock until then, so a replica whose trigger fires late (start
+// jitter, clock skew) skips it instead of running it again. Stopping the
+// scheduler releases a kept lock.
+func (j *ScheduledJob) acquireLock(ctx context.Context, cancel context.CancelCauseFunc, keepUntil time.Time) (func(), error) {
+	if j.lock == nil {
+		return func() {}, nil
+	}
+
+	j.lockMu.Lock()
+	defer j.lockMu.Unlock()
+
+	j.mu.Lock()
+	h := j.heldLock
+	j.mu.Unlock()
+	if h == nil {
+		ttl := j.LockTTL
+		if ttl <= 0 {
+			ttl = DefaultLockTTL
+		}
+		ok, err := j.lock.Acquire(ctx, j.Name, ttl)
+		if err != nil {
+			return nil, fmt.Errorf("lock unavailable: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("not leader")
+		}
+
+		h = &heldLock{
+			ctx:     context.Background(),
+			ttl:     ttl,
+			runs:    make(map[int]context.CancelCauseFunc),
+			changed: make(chan struct{}, 1),
+		}
+		if j.runCtx != nil {
+			h.ctx = j.runCtx()
+		}
+		j.mu.Lock()
+		j.heldLock = h
+		j.mu.Unlock()
+		go j.renewLock(h)
+	}
+
+	// Only the renewer gives up the lock, and it waits for lockMu first, so
+	// h is still held here
+	j.mu.Lock()
+	run := h.nextRun
+	h.nextRun++
+	h.runs[run] = cancel
+	j.mu.Unlock()
+
+	return func() {
+		j.mu.Lock()
+		delete(h.runs, run)
+		if keepUntil.After(h.keepUntil) {
+			h.keepUntil = keepUntil
+		}
+		idle := h.idle()
+		j.mu.Unlock()
+		if idle {
+			j.giveUpLock(h, nil)
+		}
+		select {
+		case h.changed <- struct{}{}:
+		default:
+		}
+	}, nil
+}
+
+// renewLock renews a held lock until it is idle, then releases it
+func (j *ScheduledJob) renewLock(h *heldLock) {
+	ctx := context.WithoutCancel(h.ctx)
+	stopped := h.ctx.Done()
+	ticker := time.NewTicker(h.ttl / 3)
+	defer ticker.Stop()
+	for {
+		j.mu.Lock()
+		if j.heldLock != h {
+			j.mu.Unlock()
+			return
+		}
+		idle := h.idle()
+		wait := time.Until(h.keepUntil)
+		if len(h.runs) > 0 || h.stopping {
+			wait = h.ttl
+		}
+		j.mu.Unlock()
+		if idle && j.giveUpLock(h, nil) {
+			return
+		}
+
+		kept := time.NewTimer(wait)
+		select {
+		case <-stopped:
+			stopped = nil
+			j.mu.Lock()
+			h.stopping = true
+			j.mu.Unlock()
+			kept.Stop()
+			continue
+		case <-h.changed:
+			kept.Stop()
+			continue
+		case <-kept.C:
+			continue
+		case <-ticker.C:
+			kept.Stop()
+		}
+
+		ok, err := j.lock.Renew(ctx, j.Name, h.ttl)
+		if err != nil {
+			// Keep holding, the lock stays valid until its TTL ends
+			j.logger.Warn("failed to renew schedule lock", "error", err)
+			continue
+		}
+		if !ok {
+			j.giveUpLock(h, errLockLost)
+			return
+		}
+	}
+}
+
+// giveUpLock releases h and reports whether it is given up. Without a cause
+// it keeps h when a run joined it meanwhile. With a cause (the lock was lost), runs
+// still holding it are cancelled with it.
+func (j *ScheduledJob) giveUpLock(h *heldLock, cause error) bool {
+	j.lockMu.Lock()
+	defer j.lockMu.Unlock()
+
+	j.mu.Lock()
+	if j.heldLock != h {
+		j.mu.Unlock()
+		return true
+	}
+	if cause == nil && !h.idle() {
+		j.mu.Unlock()
+		return false
+	}
+	j.heldLock = nil
+	runs := h.runs
+	h.runs = nil
+	j.mu.Unlock()
+
+	if cause != nil {
+		j.logger.Error("schedule lock taken over, stopping execution")
+		for _, cancel := range runs {
+			cancel(cause)
+		}
+	}
+	j.releaseLock(h.ctx)
+	return true
+}
+
+// releaseLock gives up the job's lock, even if ctx is cancelled
+func (j *ScheduledJob) releaseLock(ctx context.Context) {
+	releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancelRelease()
+	if err := j.lock.Release(releaseCtx, j.Name); err != nil {
+		j.logger.Warn("failed to release schedule lock", "error", err)
+	}
+}
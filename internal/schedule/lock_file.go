@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FileLocker locks jobs with flock(2) on lock files in a directory, which
+// may be on a filesystem shared by the replicas (NFSv4, EFS, CephFS). The
+// lock is held while the file stays open, and the kernel or the NFS server
+// drops it when the holder dies, so the TTL is not used.
+type FileLocker struct {
+	Dir      string
+	Identity string
+
+	mu    sync.Mutex
+	files map[string]*os.File // Held locks by job name
+}
+
+// NewFileLocker creates a FileLocker, creating dir if needed
+func NewFileLocker(dir, identity string) (*FileLocker, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	return &FileLocker{Dir: dir, Identity: identity, files: make(map[string]*os.File)}, nil
+}
+
+func (l *FileLocker) path(name string) string {
+	return filepath.Join(l.Dir, name+".lock")
+}
+
+// Acquire takes the lock file of name
+func (l *FileLocker) Acquire(_ context.Context, name string, _ time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, held := l.files[name]; held {
+		return true, nil
+	}
+
+	f, err := os.OpenFile(l.path(name), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return false, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return false, nil
+		}
+		return false, fmt.Errorf("flock %s: %w", f.Name(), err)
+	}
+
+	// Record the holder for operators looking at the directory
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(fmt.Sprintf("%s %s\n", l.Identity, time.Now().UTC().Format(time.RFC3339))), 0)
+	}
+	l.files[name] = f
+	return true, nil
+}
+
+// Renew reports whether the lock of name is still held
+func (l *FileLocker) Renew(_ context.Context, name string, _ time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, held := l.files[name]
+	return held, nil
+}
+
+// Release unlocks the lock file of name
+func (l *FileLocker) Release(_ context.Context, name string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, held := l.files[name]
+	if !held {
+		return nil
+	}
+	delete(l.files, name)
+	_ = f.Truncate(0)
+	// Closing the file drops the lock
+	return f.Close()
+}
@@ -0,0 +1,71 @@
+package schedule
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileLocker_Exclusive(t *testing.T) {
+	dir := t.TempDir()
+	a, err := NewFileLocker(dir, "replica-a")
+	if err != nil {
+		t.Fatalf("NewFileLocker() error = %v", err)
+	}
+	b, err := NewFileLocker(dir, "replica-b")
+	if err != nil {
+		t.Fatalf("NewFileLocker() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if ok, err := a.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Fatalf("a.Acquire() = %v, %v, want true", ok, err)
+	}
+	if ok, err := a.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Errorf("a.Acquire() again = %v, %v, want true", ok, err)
+	}
+	if ok, err := b.Acquire(ctx, "backup", time.Minute); ok || err != nil {
+		t.Errorf("b.Acquire() = %v, %v, want false while a holds the lock", ok, err)
+	}
+	if ok, _ := b.Acquire(ctx, "report", time.Minute); !ok {
+		t.Error("b.Acquire() of another job should succeed")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "backup.lock"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "replica-a ") {
+		t.Errorf("lock file = %q, want holder replica-a", data)
+	}
+
+	if ok, _ := a.Renew(ctx, "backup", time.Minute); !ok {
+		t.Error("a.Renew() should report the lock held")
+	}
+	if ok, _ := b.Renew(ctx, "backup", time.Minute); ok {
+		t.Error("b.Renew() should report the lock not held")
+	}
+
+	if err := a.Release(ctx, "backup"); err != nil {
+		t.Fatalf("a.Release() error = %v", err)
+	}
+	if ok, err := b.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Errorf("b.Acquire() after release = %v, %v, want true", ok, err)
+	}
+	if err := a.Release(ctx, "backup"); err != nil {
+		t.Errorf("Release() of a lock not held should be a no-op, got %v", err)
+	}
+}
+
+func TestNewFileLocker_CreatesDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "locks", "nested")
+	if _, err := NewFileLocker(dir, "a"); err != nil {
+		t.Fatalf("NewFileLocker() error = %v", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("lock directory not created: %v", err)
+	}
+}
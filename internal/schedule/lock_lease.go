@@ -0,0 +1,295 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// In-cluster service account defaults
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	leaseHTTPTimeout  = 10 * time.Second
+	leaseTimeLayout   = "2006-01-02T15:04:05.000000Z07:00" // metav1.MicroTime
+)
+
+// invalidLeaseChars matches characters not allowed in Lease names
+var invalidLeaseChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// LeaseLocker locks jobs with coordination.k8s.io/v1 Lease objects, like
+// Kubernetes leader election. Any API that serves Leases at the same paths
+// works. Updates carry the resourceVersion they read, so two replicas
+// cannot both take over an expired Lease.
+type LeaseLocker struct {
+	URL         string // API server URL
+	Namespace   string // Namespace of the Leases
+	TokenFile   string // Bearer token file, read on every request (empty = no auth)
+	LeasePrefix string // Lease name prefix (default "phpeek-pm-")
+	Identity    string
+	Client      *http.Client
+	Now         func() time.Time // Current time (default time.Now)
+}
+
+// NewLeaseLocker creates a LeaseLocker. Empty settings default to the
+// in-cluster API server, the pod's namespace and its service account.
+func NewLeaseLocker(url, namespace, tokenFile, caFile, prefix, identity string) (*LeaseLocker, error) {
+	if url == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("schedule_lock.kubernetes.url is required outside a cluster")
+		}
+		url = "https://" + net.JoinHostPort(host, port)
+	}
+	if namespace == "" {
+		data, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return nil, fmt.Errorf("schedule_lock.kubernetes.namespace is required outside a cluster: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+	if tokenFile == "" {
+		if _, err := os.Stat(serviceAccountDir + "/token"); err == nil {
+			tokenFile = serviceAccountDir + "/token"
+		}
+	}
+	if caFile == "" {
+		if _, err := os.Stat(serviceAccountDir + "/ca.crt"); err == nil {
+			caFile = serviceAccountDir + "/ca.crt"
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA file %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &LeaseLocker{
+		URL:         strings.TrimRight(url, "/"),
+		Namespace:   namespace,
+		TokenFile:   tokenFile,
+		LeasePrefix: prefix,
+		Identity:    identity,
+		Client:      &http.Client{Timeout: leaseHTTPTimeout, Transport: transport},
+		Now:         time.Now,
+	}, nil
+}
+
+// lease is the subset of a coordination.k8s.io/v1 Lease used for locking
+type lease struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Metadata   leaseMetadata `json:"metadata"`
+	Spec       leaseSpec     `json:"spec"`
+}
+
+type leaseMetadata struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+type leaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int    `json:"leaseTransitions,omitempty"`
+}
+
+// expired reports whether the holder stopped renewing the lease
+func (l *lease) expired(now time.Time) bool {
+	if l.Spec.HolderIdentity == "" {
+		return true
+	}
+	renewed, err := time.Parse(time.RFC3339Nano, l.Spec.RenewTime)
+	if err != nil {
+		return true
+	}
+	return now.After(renewed.Add(time.Duration(l.Spec.LeaseDurationSeconds) * time.Second))
+}
+
+func (l *LeaseLocker) name(job string) string {
+	prefix := l.LeasePrefix
+	if prefix == "" {
+		prefix = "phpeek-pm-"
+	}
+	return strings.Trim(invalidLeaseChars.ReplaceAllString(strings.ToLower(prefix+job), "-"), "-.")
+}
+
+func (l *LeaseLocker) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now()
+}
+
+func (l *LeaseLocker) leasesURL() string {
+	return fmt.Sprintf("%s/apis/coordination.k8s.io/v1/namespaces/%s/leases", l.URL, l.Namespace)
+}
+
+// Acquire creates the Lease of name, or takes it over when its holder
+// stopped renewing it
+func (l *LeaseLocker) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	current, err := l.get(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	now := l.now()
+	stamp := now.UTC().Format(leaseTimeLayout)
+	seconds := int(math.Ceil(ttl.Seconds()))
+
+	if current == nil {
+		created := &lease{
+			APIVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+			Metadata:   leaseMetadata{Name: l.name(name), Namespace: l.Namespace},
+			Spec: leaseSpec{
+				HolderIdentity:       l.Identity,
+				LeaseDurationSeconds: seconds,
+				AcquireTime:          stamp,
+				RenewTime:            stamp,
+			},
+		}
+		return l.write(ctx, http.MethodPost, l.leasesURL(), created)
+	}
+
+	if current.Spec.HolderIdentity != l.Identity {
+		if !current.expired(now) {
+			return false, nil
+		}
+		current.Spec.HolderIdentity = l.Identity
+		current.Spec.AcquireTime = stamp
+		current.Spec.LeaseTransitions++
+	}
+	current.Spec.LeaseDurationSeconds = seconds
+	current.Spec.RenewTime = stamp
+	return l.write(ctx, http.MethodPut, l.leasesURL()+"/"+l.name(name), current)
+}
+
+// Renew updates the renew time of the Lease of name while this replica holds it
+func (l *LeaseLocker) Renew(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	current, err := l.get(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if current == nil || current.Spec.HolderIdentity != l.Identity {
+		return false, nil
+	}
+	current.Spec.LeaseDurationSeconds = int(math.Ceil(ttl.Seconds()))
+	current.Spec.RenewTime = l.now().UTC().Format(leaseTimeLayout)
+	return l.write(ctx, http.MethodPut, l.leasesURL()+"/"+l.name(name), current)
+}
+
+// Release clears the holder of the Lease of name while this replica holds it
+func (l *LeaseLocker) Release(ctx context.Context, name string) error {
+	current, err := l.get(ctx, name)
+	if err != nil || current == nil || current.Spec.HolderIdentity != l.Identity {
+		return err
+	}
+	current.Spec.HolderIdentity = ""
+	_, err = l.write(ctx, http.MethodPut, l.leasesURL()+"/"+l.name(name), current)
+	return err
+}
+
+// get returns the Lease of name, or nil if it does not exist
+func (l *LeaseLocker) get(ctx context.Context, name string) (*lease, error) {
+	req, err := l.request(ctx, http.MethodGet, l.leasesURL()+"/"+l.name(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("lease request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var current lease
+		if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
+			return nil, fmt.Errorf("invalid lease: %w", err)
+		}
+		return &current, nil
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, leaseError(resp)
+	}
+}
+
+// write creates or updates a Lease. It returns false when another replica
+// changed it first.
+func (l *LeaseLocker) write(ctx context.Context, method, url string, obj *lease) (bool, error) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return false, err
+	}
+	req, err := l.request(ctx, method, url, body)
+	if err != nil {
+		return false, err
+	}
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("lease request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	default:
+		return false, leaseError(resp)
+	}
+}
+
+func (l *LeaseLocker) request(ctx context.Context, method, url string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if l.TokenFile != "" {
+		token, err := os.ReadFile(l.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return req, nil
+}
+
+// leaseError describes an unexpected API response
+func leaseError(resp *http.Response) error {
+	var status struct {
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(data, &status) == nil && status.Message != "" {
+		return fmt.Errorf("lease API returned %d: %s", resp.StatusCode, status.Message)
+	}
+	return fmt.Errorf("lease API returned %d", resp.StatusCode)
+}
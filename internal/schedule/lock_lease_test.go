@@ -0,0 +1,227 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLeaseAPI serves Leases of one namespace with optimistic concurrency
+// on resourceVersion, like the Kubernetes API server
+type fakeLeaseAPI struct {
+	mu      sync.Mutex
+	leases  map[string]lease
+	version int
+	token   string
+}
+
+func newFakeLeaseAPI(t *testing.T, token string) (*fakeLeaseAPI, *httptest.Server) {
+	t.Helper()
+	api := &fakeLeaseAPI{leases: make(map[string]lease), token: token}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func (a *fakeLeaseAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/apis/coordination.k8s.io/v1/namespaces/jobs/leases"
+	if a.token != "" && r.Header.Get("Authorization") != "Bearer "+a.token {
+		http.Error(w, `{"message":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		current, ok := a.leases[name]
+		if !ok {
+			http.Error(w, `{"message":"leases not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(current)
+	case http.MethodPost:
+		var created lease
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		if _, ok := a.leases[created.Metadata.Name]; ok {
+			http.Error(w, `{"message":"already exists"}`, http.StatusConflict)
+			return
+		}
+		a.store(created)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPut:
+		var updated lease
+		_ = json.NewDecoder(r.Body).Decode(&updated)
+		current, ok := a.leases[name]
+		if !ok {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		if updated.Metadata.ResourceVersion != current.Metadata.ResourceVersion {
+			http.Error(w, `{"message":"the object has been modified"}`, http.StatusConflict)
+			return
+		}
+		a.store(updated)
+	default:
+		http.Error(w, `{"message":"method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+// store saves a lease with a new resourceVersion. Callers hold a.mu.
+func (a *fakeLeaseAPI) store(l lease) {
+	a.version++
+	l.Metadata.ResourceVersion = strconv.Itoa(a.version)
+	a.leases[l.Metadata.Name] = l
+}
+
+func (a *fakeLeaseAPI) get(name string) lease {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.leases[name]
+}
+
+func newTestLeaseLocker(url, identity string, now *time.Time) *LeaseLocker {
+	return &LeaseLocker{
+		URL:       url,
+		Namespace: "jobs",
+		Identity:  identity,
+		Client:    http.DefaultClient,
+		Now:       func() time.Time { return *now },
+	}
+}
+
+func TestLeaseLocker_Exclusive(t *testing.T) {
+	api, server := newFakeLeaseAPI(t, "")
+	now := time.Now()
+	a := newTestLeaseLocker(server.URL, "replica-a", &now)
+	b := newTestLeaseLocker(server.URL, "replica-b", &now)
+	ctx := context.Background()
+
+	if ok, err := a.Acquire(ctx, "backup", 30*time.Second); !ok || err != nil {
+		t.Fatalf("a.Acquire() = %v, %v, want true", ok, err)
+	}
+	created := api.get("phpeek-pm-backup")
+	if created.Spec.HolderIdentity != "replica-a" || created.Spec.LeaseDurationSeconds != 30 {
+		t.Errorf("lease spec = %+v", created.Spec)
+	}
+
+	if ok, err := b.Acquire(ctx, "backup", 30*time.Second); ok || err != nil {
+		t.Errorf("b.Acquire() = %v, %v, want false while a holds the lease", ok, err)
+	}
+	if ok, err := a.Renew(ctx, "backup", 30*time.Second); !ok || err != nil {
+		t.Errorf("a.Renew() = %v, %v, want true", ok, err)
+	}
+	if ok, _ := b.Renew(ctx, "backup", 30*time.Second); ok {
+		t.Error("b.Renew() should fail for a lease held by a")
+	}
+
+	if err := a.Release(ctx, "backup"); err != nil {
+		t.Fatalf("a.Release() error = %v", err)
+	}
+	if holder := api.get("phpeek-pm-backup").Spec.HolderIdentity; holder != "" {
+		t.Errorf("holder after release = %q, want empty", holder)
+	}
+	if ok, err := b.Acquire(ctx, "backup", 30*time.Second); !ok || err != nil {
+		t.Errorf("b.Acquire() after release = %v, %v, want true", ok, err)
+	}
+	if transitions := api.get("phpeek-pm-backup").Spec.LeaseTransitions; transitions != 1 {
+		t.Errorf("LeaseTransitions = %d, want 1", transitions)
+	}
+}
+
+func TestLeaseLocker_TakesOverExpiredLease(t *testing.T) {
+	_, server := newFakeLeaseAPI(t, "")
+	now := time.Now()
+	a := newTestLeaseLocker(server.URL, "replica-a", &now)
+	b := newTestLeaseLocker(server.URL, "replica-b", &now)
+	ctx := context.Background()
+
+	if ok, _ := a.Acquire(ctx, "backup", 10*time.Second); !ok {
+		t.Fatal("a.Acquire() should succeed")
+	}
+	now = now.Add(11 * time.Second)
+
+	if ok, err := b.Acquire(ctx, "backup", 10*time.Second); !ok || err != nil {
+		t.Errorf("b.Acquire() = %v, %v, want to take over the expired lease", ok, err)
+	}
+	if ok, _ := a.Renew(ctx, "backup", 10*time.Second); ok {
+		t.Error("a.Renew() should report the lease lost")
+	}
+}
+
+func TestLeaseLocker_Conflict(t *testing.T) {
+	api, server := newFakeLeaseAPI(t, "")
+	now := time.Now()
+	a := newTestLeaseLocker(server.URL, "replica-a", &now)
+	ctx := context.Background()
+
+	if ok, _ := a.Acquire(ctx, "backup", 10*time.Second); !ok {
+		t.Fatal("a.Acquire() should succeed")
+	}
+	current, err := a.get(ctx, "backup")
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	// Another replica updates the lease after it was read
+	api.mu.Lock()
+	api.store(api.leases["phpeek-pm-backup"])
+	api.mu.Unlock()
+
+	if ok, err := a.write(ctx, http.MethodPut, a.leasesURL()+"/phpeek-pm-backup", current); ok || err != nil {
+		t.Errorf("write() = %v, %v, want false on a stale resourceVersion", ok, err)
+	}
+}
+
+func TestLeaseLocker_Token(t *testing.T) {
+	_, server := newFakeLeaseAPI(t, "s3cret")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	ctx := context.Background()
+
+	locker := newTestLeaseLocker(server.URL, "replica-a", &now)
+	if _, err := locker.Acquire(ctx, "backup", time.Minute); err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("Acquire() without token error = %v, want Unauthorized", err)
+	}
+
+	locker.TokenFile = tokenFile
+	if ok, err := locker.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Errorf("Acquire() with token = %v, %v, want true", ok, err)
+	}
+}
+
+func TestLeaseLocker_Name(t *testing.T) {
+	l := &LeaseLocker{}
+	if got := l.name("Nightly_Backup"); got != "phpeek-pm-nightly-backup" {
+		t.Errorf("name() = %q, want phpeek-pm-nightly-backup", got)
+	}
+	l.LeasePrefix = "app."
+	if got := l.name("report"); got != "app.report" {
+		t.Errorf("name() = %q, want app.report", got)
+	}
+}
+
+func TestNewLeaseLocker_RequiresURLOutsideCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	if _, err := NewLeaseLocker("", "default", "", "", "", "a"); err == nil {
+		t.Error("NewLeaseLocker() should require a url outside a cluster")
+	}
+}
@@ -0,0 +1,167 @@
+package schedule
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// redisTimeout bounds each Redis command when the context has no deadline
+const redisTimeout = 5 * time.Second
+
+// Lua scripts that only touch the key while this replica holds it
+const (
+	redisRenewScript   = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`
+	redisReleaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+)
+
+// RedisLocker locks jobs with Redis keys set by SET NX PX, holding this
+// replica's identity. Renewal and release check the identity in a Lua
+// script, so a replica never extends or deletes a lock it lost.
+type RedisLocker struct {
+	Address   string // host:port
+	Password  string // AUTH password (empty = none)
+	DB        int    // Database selected with SELECT
+	KeyPrefix string // Key prefix (default "phpeek-pm:lock:")
+	Identity  string
+}
+
+func (l *RedisLocker) key(name string) string {
+	prefix := l.KeyPrefix
+	if prefix == "" {
+		prefix = "phpeek-pm:lock:"
+	}
+	return prefix + name
+}
+
+// Acquire sets the key of name unless another replica holds it
+func (l *RedisLocker) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	reply, err := l.do(ctx, "SET", l.key(name), l.Identity, "NX", "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+	if reply != nil {
+		return true, nil
+	}
+	// Taken, possibly by this replica before a restart
+	return l.Renew(ctx, name, ttl)
+}
+
+// Renew extends the key of name while this replica holds it
+func (l *RedisLocker) Renew(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	reply, err := l.do(ctx, "EVAL", redisRenewScript, "1", l.key(name), l.Identity, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+	n, _ := reply.(int64)
+	return n == 1, nil
+}
+
+// Release deletes the key of name while this replica holds it
+func (l *RedisLocker) Release(ctx context.Context, name string) error {
+	_, err := l.do(ctx, "EVAL", redisReleaseScript, "1", l.key(name), l.Identity)
+	return err
+}
+
+// do runs one command on a new connection. Lock commands are rare (one per
+// run and renewal), so connections are not pooled.
+func (l *RedisLocker) do(ctx context.Context, args ...string) (interface{}, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, redisTimeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", l.Address)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	r := bufio.NewReader(conn)
+	if l.Password != "" {
+		if _, err := redisCommand(conn, r, "AUTH", l.Password); err != nil {
+			return nil, err
+		}
+	}
+	if l.DB != 0 {
+		if _, err := redisCommand(conn, r, "SELECT", strconv.Itoa(l.DB)); err != nil {
+			return nil, err
+		}
+	}
+	return redisCommand(conn, r, args...)
+}
+
+// redisCommand writes a command in RESP and reads its reply
+func redisCommand(w io.Writer, r *bufio.Reader, args ...string) (interface{}, error) {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	if _, err := w.Write(buf); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return readRedisReply(r)
+}
+
+// readRedisReply reads one RESP reply. Simple strings and bulk strings are
+// returned as string, integers as int64, arrays as []interface{} and null
+// replies as nil. Error replies are returned as errors.
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, errors.New("redis: " + payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", payload)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", payload)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRedisReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
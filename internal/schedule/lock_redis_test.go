@@ -0,0 +1,282 @@
+package schedule
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves the subset of RESP used by RedisLocker: AUTH, SELECT,
+// SET NX PX and EVAL of the renew and release scripts
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	expires  map[string]time.Time
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	r := &fakeRedis{
+		listener: listener,
+		password: password,
+		values:   make(map[string]string),
+		expires:  make(map[string]time.Time),
+	}
+	t.Cleanup(func() { listener.Close() })
+	go r.serve()
+	return r
+}
+
+func (r *fakeRedis) addr() string {
+	return r.listener.Addr().String()
+}
+
+func (r *fakeRedis) serve() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		go r.handle(conn)
+	}
+}
+
+func (r *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authed := r.password == ""
+	for {
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		r.mu.Lock()
+		r.commands = append(r.commands, strings.ToUpper(args[0]))
+		var out string
+		switch {
+		case strings.EqualFold(args[0], "AUTH"):
+			if args[1] == r.password {
+				authed = true
+				out = "+OK\r\n"
+			} else {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required\r\n"
+		case strings.EqualFold(args[0], "SELECT"):
+			out = "+OK\r\n"
+		case strings.EqualFold(args[0], "SET"):
+			out = r.set(args[1:])
+		case strings.EqualFold(args[0], "EVAL"):
+			out = r.eval(args[1:])
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		r.mu.Unlock()
+
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+// get returns the live value of key. Callers hold r.mu.
+func (r *fakeRedis) get(key string) (string, bool) {
+	if exp, ok := r.expires[key]; ok && time.Now().After(exp) {
+		delete(r.values, key)
+		delete(r.expires, key)
+	}
+	v, ok := r.values[key]
+	return v, ok
+}
+
+// set handles SET key value NX PX ms
+func (r *fakeRedis) set(args []string) string {
+	key, value := args[0], args[1]
+	if _, ok := r.get(key); ok {
+		return "$-1\r\n"
+	}
+	ms, _ := strconv.Atoi(args[4])
+	r.values[key] = value
+	r.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+	return "+OK\r\n"
+}
+
+// eval handles the renew and release scripts
+func (r *fakeRedis) eval(args []string) string {
+	script, key, identity := args[0], args[2], args[3]
+	if v, ok := r.get(key); !ok || v != identity {
+		return ":0\r\n"
+	}
+	switch script {
+	case redisRenewScript:
+		ms, _ := strconv.Atoi(args[4])
+		r.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+	case redisReleaseScript:
+		delete(r.values, key)
+		delete(r.expires, key)
+	default:
+		return "-ERR unknown script\r\n"
+	}
+	return ":1\r\n"
+}
+
+func (r *fakeRedis) sawCommand(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.commands {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRedisLocker_Exclusive(t *testing.T) {
+	server := newFakeRedis(t, "")
+	a := &RedisLocker{Address: server.addr(), Identity: "replica-a"}
+	b := &RedisLocker{Address: server.addr(), Identity: "replica-b"}
+	ctx := context.Background()
+
+	if ok, err := a.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Fatalf("a.Acquire() = %v, %v, want true", ok, err)
+	}
+	if ok, err := b.Acquire(ctx, "backup", time.Minute); ok || err != nil {
+		t.Errorf("b.Acquire() = %v, %v, want false while a holds the lock", ok, err)
+	}
+	if ok, err := a.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Errorf("a.Acquire() again = %v, %v, want true", ok, err)
+	}
+
+	server.mu.Lock()
+	holder := server.values["phpeek-pm:lock:backup"]
+	server.mu.Unlock()
+	if holder != "replica-a" {
+		t.Errorf("key holds %q, want replica-a with the default prefix", holder)
+	}
+
+	if ok, _ := a.Renew(ctx, "backup", time.Minute); !ok {
+		t.Error("a.Renew() should succeed")
+	}
+	if ok, _ := b.Renew(ctx, "backup", time.Minute); ok {
+		t.Error("b.Renew() should fail for a lock held by a")
+	}
+
+	// Releasing a lock held by another replica leaves it alone
+	if err := b.Release(ctx, "backup"); err != nil {
+		t.Fatalf("b.Release() error = %v", err)
+	}
+	if ok, _ := b.Acquire(ctx, "backup", time.Minute); ok {
+		t.Error("b.Release() should not delete a's lock")
+	}
+
+	if err := a.Release(ctx, "backup"); err != nil {
+		t.Fatalf("a.Release() error = %v", err)
+	}
+	if ok, err := b.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Errorf("b.Acquire() after release = %v, %v, want true", ok, err)
+	}
+}
+
+func TestRedisLocker_Expiry(t *testing.T) {
+	server := newFakeRedis(t, "")
+	a := &RedisLocker{Address: server.addr(), Identity: "replica-a", KeyPrefix: "test:"}
+	b := &RedisLocker{Address: server.addr(), Identity: "replica-b", KeyPrefix: "test:"}
+	ctx := context.Background()
+
+	if ok, _ := a.Acquire(ctx, "backup", 20*time.Millisecond); !ok {
+		t.Fatal("a.Acquire() should succeed")
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	if ok, _ := b.Acquire(ctx, "backup", time.Minute); !ok {
+		t.Error("b.Acquire() should take over an expired lock")
+	}
+	if ok, _ := a.Renew(ctx, "backup", time.Minute); ok {
+		t.Error("a.Renew() should report the lock lost")
+	}
+}
+
+func TestRedisLocker_AuthAndSelect(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	ctx := context.Background()
+
+	locker := &RedisLocker{Address: server.addr(), Password: "secret", DB: 2, Identity: "a"}
+	if ok, err := locker.Acquire(ctx, "backup", time.Minute); !ok || err != nil {
+		t.Fatalf("Acquire() = %v, %v, want true", ok, err)
+	}
+	if !server.sawCommand("AUTH") || !server.sawCommand("SELECT") {
+		t.Error("AUTH and SELECT should be sent before the command")
+	}
+
+	wrong := &RedisLocker{Address: server.addr(), Password: "wrong", Identity: "b"}
+	if _, err := wrong.Acquire(ctx, "report", time.Minute); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Acquire() error = %v, want WRONGPASS", err)
+	}
+}
+
+func TestRedisLocker_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	locker := &RedisLocker{Address: addr, Identity: "a"}
+	if _, err := locker.Acquire(context.Background(), "backup", time.Minute); err == nil {
+		t.Error("Acquire() should fail when Redis is unreachable")
+	}
+}
+
+func TestReadRedisReply(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "+OK\r\n", want: "OK"},
+		{input: ":42\r\n", want: "42"},
+		{input: "$5\r\nhello\r\n", want: "hello"},
+		{input: "$-1\r\n", want: "<nil>"},
+		{input: "*2\r\n$1\r\na\r\n:1\r\n", want: "[a 1]"},
+		{input: "-ERR boom\r\n", wantErr: true},
+		{input: "?x\r\n", wantErr: true},
+		{input: "+OK\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Quote(tt.input), func(t *testing.T) {
+			got, err := readRedisReply(bufio.NewReader(strings.NewReader(tt.input)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readRedisReply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprint(got) != tt.want {
+				t.Errorf("readRedisReply() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
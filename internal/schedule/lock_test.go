@@ -0,0 +1,325 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+// fakeLocker is an in-memory Locker whose answers are set by the test
+type fakeLocker struct {
+	mu         sync.Mutex
+	acquire    bool
+	acquireErr error
+	renew      bool
+	acquired   int
+	renewed    int
+	released   int
+}
+
+func (f *fakeLocker) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.acquired++
+	return f.acquire, f.acquireErr
+}
+
+func (f *fakeLocker) Renew(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.renewed++
+	return f.renew, nil
+}
+
+func (f *fakeLocker) Release(ctx context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released++
+	return nil
+}
+
+func (f *fakeLocker) counts() (acquired, renewed, released int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.acquired, f.renewed, f.released
+}
+
+func TestNewLocker(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		cfg     *config.ScheduleLockConfig
+		want    string
+		wantErr string
+	}{
+		{
+			name: "file",
+			cfg:  &config.ScheduleLockConfig{Backend: "file", Identity: "a", File: &config.FileLockConfig{Dir: dir}},
+			want: "*schedule.FileLocker",
+		},
+		{
+			name: "redis",
+			cfg:  &config.ScheduleLockConfig{Backend: "redis", Identity: "a", Redis: &config.RedisLockConfig{Address: "localhost:6379"}},
+			want: "*schedule.RedisLocker",
+		},
+		{
+			name: "kubernetes",
+			cfg: &config.ScheduleLockConfig{Backend: "kubernetes", Identity: "a", Kubernetes: &config.KubernetesLockConfig{
+				URL: "http://localhost:8001", Namespace: "default",
+			}},
+			want: "*schedule.LeaseLocker",
+		},
+		{
+			name:    "file without dir",
+			cfg:     &config.ScheduleLockConfig{Backend: "file", Identity: "a"},
+			wantErr: "file.dir is required",
+		},
+		{
+			name:    "redis without address",
+			cfg:     &config.ScheduleLockConfig{Backend: "redis", Identity: "a"},
+			wantErr: "redis.address is required",
+		},
+		{
+			name:    "unknown backend",
+			cfg:     &config.ScheduleLockConfig{Backend: "etcd", Identity: "a"},
+			wantErr: "unknown schedule_lock backend",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locker, err := NewLocker(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewLocker() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLocker() error = %v", err)
+			}
+			if got := fmt.Sprintf("%T", locker); got != tt.want {
+				t.Errorf("NewLocker() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLocker_DefaultsIdentityToHostname(t *testing.T) {
+	locker, err := NewLocker(&config.ScheduleLockConfig{Backend: "redis", Redis: &config.RedisLockConfig{Address: "localhost:6379"}})
+	if err != nil {
+		t.Fatalf("NewLocker() error = %v", err)
+	}
+	if locker.(*RedisLocker).Identity == "" {
+		t.Error("Identity should default to the hostname")
+	}
+}
+
+func TestScheduledJob_LockNotLeaderSkips(t *testing.T) {
+	executor := &mockExecutor{}
+	locker := &fakeLocker{acquire: false}
+	job := newOverlapJob(t, executor, JobOptions{Lock: locker})
+
+	_, err := job.TriggerSync(context.Background())
+	if err == nil || err.Error() != "not leader" {
+		t.Fatalf("TriggerSync() error = %v, want not leader", err)
+	}
+	if atomic.LoadInt64(&executor.executeCalled) != 0 {
+		t.Errorf("executor called %d times, want 0", atomic.LoadInt64(&executor.executeCalled))
+	}
+	if job.GetState() != JobStateIdle {
+		t.Errorf("State = %s, want idle", job.GetState())
+	}
+
+	entry, ok := job.History.GetLast()
+	if !ok {
+		t.Fatal("skipped run should be recorded in history")
+	}
+	if !entry.Skipped || entry.Error != "skipped: not leader" {
+		t.Errorf("entry = %+v, want skipped with error %q", entry, "skipped: not leader")
+	}
+
+	stats := job.History.Stats()
+	if stats.SkippedCount != 1 {
+		t.Errorf("SkippedCount = %d, want 1", stats.SkippedCount)
+	}
+	if stats.TotalExecutions != 0 || stats.FailureCount != 0 {
+		t.Errorf("skipped run counted as execution: %+v", stats)
+	}
+}
+
+func TestScheduledJob_LockUnavailableSkips(t *testing.T) {
+	executor := &mockExecutor{}
+	locker := &fakeLocker{acquireErr: errors.New("connection refused")}
+	job := newOverlapJob(t, executor, JobOptions{Lock: locker})
+
+	if _, err := job.TriggerSync(context.Background()); err == nil {
+		t.Fatal("TriggerSync() should fail when the lock is unavailable")
+	}
+	if atomic.LoadInt64(&executor.executeCalled) != 0 {
+		t.Errorf("executor called %d times, want 0", atomic.LoadInt64(&executor.executeCalled))
+	}
+	entry, _ := job.History.GetLast()
+	if entry.Error != "skipped: lock unavailable: connection refused" {
+		t.Errorf("Error = %q", entry.Error)
+	}
+}
+
+func TestScheduledJob_LockHeldDuringRun(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 50 * time.Millisecond}
+	locker := &fakeLocker{acquire: true, renew: true}
+	job := newOverlapJob(t, executor, JobOptions{Lock: locker, LockTTL: 30 * time.Millisecond})
+
+	exitCode, err := job.TriggerSync(context.Background())
+	if err != nil || exitCode != 0 {
+		t.Fatalf("TriggerSync() = %d, %v", exitCode, err)
+	}
+
+	acquired, renewed, released := locker.counts()
+	if acquired != 1 || released != 1 {
+		t.Errorf("acquired = %d, released = %d, want 1 and 1", acquired, released)
+	}
+	if renewed == 0 {
+		t.Error("lock should be renewed during a run longer than ttl/3")
+	}
+}
+
+func TestScheduledJob_LockLostCancelsRun(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 5 * time.Second}
+	locker := &fakeLocker{acquire: true, renew: false}
+	job := newOverlapJob(t, executor, JobOptions{Lock: locker, LockTTL: 30 * time.Millisecond, DisableAfter: 1})
+
+	start := time.Now()
+	_, err := job.TriggerSync(context.Background())
+	if err == nil {
+		t.Fatal("TriggerSync() should fail when the lock is lost")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("run should be cancelled when the lock is lost")
+	}
+
+	entry, _ := job.History.GetLast()
+	if entry.Error != errLockLost.Error() {
+		t.Errorf("Error = %q, want %q", entry.Error, errLockLost.Error())
+	}
+	if job.IsDisabled() {
+		t.Error("losing the lock should not count as a failure")
+	}
+	if _, _, released := locker.counts(); released != 1 {
+		t.Errorf("released = %d, want 1", released)
+	}
+}
+
+func TestScheduledJob_LockKeptUntilNextActivation(t *testing.T) {
+	lockers := map[string]func(t *testing.T) (a, b Locker){
+		"file": func(t *testing.T) (Locker, Locker) {
+			dir := t.TempDir()
+			a, _ := NewFileLocker(dir, "replica-a")
+			b, _ := NewFileLocker(dir, "replica-b")
+			return a, b
+		},
+		"redis": func(t *testing.T) (Locker, Locker) {
+			server := newFakeRedis(t, "")
+			return &RedisLocker{Address: server.addr(), Identity: "replica-a"},
+				&RedisLocker{Address: server.addr(), Identity: "replica-b"}
+		},
+	}
+
+	for name, newLockers := range lockers {
+		t.Run(name, func(t *testing.T) {
+			lockA, lockB := newLockers(t)
+			executor := &mockExecutor{}
+			ctx, stop := context.WithCancel(context.Background())
+			defer stop()
+
+			// Two replicas of the same job
+			replicas := make([]*ScheduledJob, 2)
+			for i, locker := range []Locker{lockA, lockB} {
+				job, err := NewScheduledJobWithOptions("backup", "@every 1h", "", 10, executor, testLogger(), JobOptions{Lock: locker, LockTTL: time.Minute})
+				if err != nil {
+					t.Fatalf("NewScheduledJobWithOptions() error = %v", err)
+				}
+				job.runCtx = func() context.Context { return ctx }
+				replicas[i] = job
+			}
+
+			// Replica b fires the same trigger after a finished
+			replicas[0].Run()
+			replicas[1].Run()
+
+			if executor.callCount() != 1 {
+				t.Errorf("executor called %d times, want 1 per trigger", executor.callCount())
+			}
+			entry, ok := replicas[1].History.GetLast()
+			if !ok || !entry.Skipped || entry.Error != "skipped: not leader" {
+				t.Errorf("late replica entry = %+v, want skipped as not leader", entry)
+			}
+
+			// Stopping the scheduler releases the kept lock
+			stop()
+			deadline := time.Now().Add(time.Second)
+			for {
+				if ok, _ := lockB.Acquire(context.Background(), "backup", time.Minute); ok {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("kept lock not released after stop")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestScheduledJob_LockSharedByOverlappingRuns(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 50 * time.Millisecond}
+	locker := &fakeLocker{acquire: true, renew: true}
+	job := newOverlapJob(t, executor, JobOptions{Overlap: OverlapAllow, Lock: locker, LockTTL: 30 * time.Millisecond})
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	job.runCtx = func() context.Context { return ctx }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job.Run()
+		}()
+		time.Sleep(20 * time.Millisecond)
+	}
+	wg.Wait()
+
+	if peak := atomic.LoadInt64(&executor.peak); peak != 2 {
+		t.Fatalf("peak = %d, want overlapping runs", peak)
+	}
+	acquired, _, released := locker.counts()
+	if acquired != 1 || released != 0 {
+		t.Errorf("acquired = %d, released = %d, want the second run to join the kept lock", acquired, released)
+	}
+
+	// Stopping the scheduler releases the lock once and stops renewing it
+	stop()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, _, released := locker.counts(); released > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("kept lock not released after stop")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, renewed, _ := locker.counts()
+	time.Sleep(100 * time.Millisecond)
+	if _, renewedLater, released := locker.counts(); renewedLater != renewed || released != 1 {
+		t.Errorf("after release: renewed %d more times, released = %d, want no renewals and 1 release", renewedLater-renewed, released)
+	}
+}
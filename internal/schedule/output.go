@@ -0,0 +1,246 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gophpeek/phpeek-pm/internal/logger"
+)
+
+// Output capture defaults
+const (
+	DefaultOutputHeadLines     = 100
+	DefaultOutputTailLines     = 200
+	DefaultOutputMaxLineLength = 4096
+)
+
+// OutputOptions bounds the output kept with each execution. Zero values use
+// the defaults.
+type OutputOptions struct {
+	HeadLines     int  // First lines kept (default DefaultOutputHeadLines)
+	TailLines     int  // Last lines kept (default DefaultOutputTailLines)
+	MaxLineLength int  // Longer lines are cut (default DefaultOutputMaxLineLength)
+	OnFailureOnly bool // Drop the output of successful executions
+}
+
+// withDefaults returns the options with defaults for unset limits
+func (o OutputOptions) withDefaults() OutputOptions {
+	if o.HeadLines <= 0 {
+		o.HeadLines = DefaultOutputHeadLines
+	}
+	if o.TailLines <= 0 {
+		o.TailLines = DefaultOutputTailLines
+	}
+	if o.MaxLineLength <= 0 {
+		o.MaxLineLength = DefaultOutputMaxLineLength
+	}
+	return o
+}
+
+// OutputExecutor is a JobExecutor that captures the output of each
+// execution. Jobs whose executor implements it keep the output with the
+// execution's history entry.
+type OutputExecutor interface {
+	JobExecutor
+
+	// ExecuteWithOutput runs the job like Execute and writes its output to
+	// output.
+	ExecuteWithOutput(ctx context.Context, processName string, output *ExecutionOutput) (int, error)
+}
+
+// ExecutionOutput captures the output of one execution. It keeps the first
+// and last lines and counts the lines dropped in between, so memory stays
+// bounded however much a job writes. It is safe for concurrent use.
+type ExecutionOutput struct {
+	processName string
+	opts        OutputOptions
+	redact      func(string) string
+
+	mu        sync.Mutex
+	head      []logger.LogEntry
+	tail      []logger.LogEntry
+	truncated int               // Lines dropped between head and tail
+	total     int               // Lines written, including dropped ones
+	partial   map[string][]byte // Incomplete last line per stream
+	closed    bool
+}
+
+// NewExecutionOutput creates an empty output capture
+func NewExecutionOutput(processName string, opts OutputOptions) *ExecutionOutput {
+	return &ExecutionOutput{
+		processName: processName,
+		opts:        opts.withDefaults(),
+		partial:     make(map[string][]byte),
+	}
+}
+
+// SetRedactor redacts captured lines with redact (e.g. the process's
+// logging redaction). It must be set before output is written.
+func (o *ExecutionOutput) SetRedactor(redact func(string) string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.redact = redact
+}
+
+// Stream returns a writer that captures output of the given stream
+// ("stdout" or "stderr")
+func (o *ExecutionOutput) Stream(stream string) io.Writer {
+	return outputStream{output: o, stream: stream}
+}
+
+type outputStream struct {
+	output *ExecutionOutput
+	stream string
+}
+
+func (s outputStream) Write(p []byte) (int, error) {
+	s.output.write(s.stream, p)
+	return len(p), nil
+}
+
+// write splits p into lines and captures complete ones. Incomplete lines
+// longer than the line limit are captured as they are.
+func (o *ExecutionOutput) write(stream string, p []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+	buf := append(o.partial[stream], p...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		o.addLine(stream, string(trimCR(buf[:i])))
+		buf = buf[i+1:]
+	}
+	if len(buf) > o.opts.MaxLineLength {
+		o.addLine(stream, string(buf))
+		buf = nil
+	}
+	o.partial[stream] = append([]byte(nil), buf...)
+}
+
+// AddEvent captures a lifecycle event, e.g. the exit of the process.
+// Incomplete lines written before it are captured first.
+func (o *ExecutionOutput) AddEvent(message string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+	o.flushPartial()
+	o.add(logger.LogEntry{
+		Timestamp:   time.Now(),
+		ProcessName: o.processName,
+		InstanceID:  "scheduled",
+		Stream:      "event",
+		Message:     message,
+		Level:       "event",
+	})
+}
+
+// Close captures incomplete last lines. Output written afterwards is
+// ignored.
+func (o *ExecutionOutput) Close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+	o.flushPartial()
+	o.partial = nil
+	o.closed = true
+}
+
+// flushPartial captures incomplete last lines. Callers hold o.mu.
+func (o *ExecutionOutput) flushPartial() {
+	for _, stream := range []string{"stdout", "stderr"} {
+		if buf := o.partial[stream]; len(buf) > 0 {
+			o.addLine(stream, string(trimCR(buf)))
+			delete(o.partial, stream)
+		}
+	}
+}
+
+// addLine captures a line of process output. Callers hold o.mu.
+func (o *ExecutionOutput) addLine(stream, line string) {
+	if o.redact != nil {
+		line = o.redact(line)
+	}
+	if len(line) > o.opts.MaxLineLength {
+		// Cut at a rune boundary
+		cut := o.opts.MaxLineLength
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		line = fmt.Sprintf("%s … [%d bytes truncated]", line[:cut], len(line)-cut)
+	}
+	o.add(logger.LogEntry{
+		Timestamp:   time.Now(),
+		ProcessName: o.processName,
+		InstanceID:  "scheduled",
+		Stream:      stream,
+		Message:     line,
+		Level:       "info",
+	})
+}
+
+// add keeps an entry in the head until it is full, then in the tail,
+// dropping the oldest tail entry. Callers hold o.mu.
+func (o *ExecutionOutput) add(entry logger.LogEntry) {
+	o.total++
+	if len(o.head) < o.opts.HeadLines {
+		o.head = append(o.head, entry)
+		return
+	}
+	o.tail = append(o.tail, entry)
+	if len(o.tail) > o.opts.TailLines {
+		o.tail = o.tail[1:]
+		o.truncated++
+	}
+}
+
+// Lines returns the captured lines, oldest first. A truncation marker
+// (an event entry) stands in for the lines dropped between head and tail.
+func (o *ExecutionOutput) Lines() []logger.LogEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	lines := make([]logger.LogEntry, 0, len(o.head)+len(o.tail)+1)
+	lines = append(lines, o.head...)
+	if o.truncated > 0 {
+		lines = append(lines, logger.LogEntry{
+			Timestamp:   o.tail[0].Timestamp,
+			ProcessName: o.processName,
+			InstanceID:  "scheduled",
+			Stream:      "event",
+			Message:     fmt.Sprintf("… %d lines truncated …", o.truncated),
+			Level:       "event",
+		})
+	}
+	return append(lines, o.tail...)
+}
+
+// Counts returns the number of lines written and the number dropped
+func (o *ExecutionOutput) Counts() (total, truncated int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.total, o.truncated
+}
+
+func trimCR(b []byte) []byte {
+	if n := len(b); n > 0 && b[n-1] == '\r' {
+		return b[:n-1]
+	}
+	return b
+}
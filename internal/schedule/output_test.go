@@ -0,0 +1,202 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// outputExecutor implements OutputExecutor for testing
+type outputExecutor struct {
+	lines []string
+	codes []int
+	calls int
+	mu    sync.Mutex
+}
+
+func (e *outputExecutor) Execute(ctx context.Context, processName string) (int, error) {
+	return e.ExecuteWithOutput(ctx, processName, nil)
+}
+
+func (e *outputExecutor) ExecuteWithOutput(ctx context.Context, processName string, output *ExecutionOutput) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	code := e.codes[min(e.calls, len(e.codes)-1)]
+	e.calls++
+	if output != nil {
+		for _, line := range e.lines {
+			fmt.Fprintf(output.Stream("stdout"), "%s (attempt %d)\n", line, e.calls)
+		}
+	}
+	if code != 0 {
+		return code, fmt.Errorf("process exited with code %d", code)
+	}
+	return 0, nil
+}
+
+func outputMessages(o *ExecutionOutput) []string {
+	lines := o.Lines()
+	messages := make([]string, len(lines))
+	for i, line := range lines {
+		messages[i] = line.Message
+	}
+	return messages
+}
+
+func TestOutputOptions_Defaults(t *testing.T) {
+	opts := OutputOptions{TailLines: 5}.withDefaults()
+	if opts.HeadLines != DefaultOutputHeadLines || opts.TailLines != 5 || opts.MaxLineLength != DefaultOutputMaxLineLength {
+		t.Errorf("withDefaults() = %+v", opts)
+	}
+}
+
+func TestExecutionOutput_Lines(t *testing.T) {
+	o := NewExecutionOutput("backup", OutputOptions{})
+
+	fmt.Fprint(o.Stream("stdout"), "first\r\nsec")
+	fmt.Fprint(o.Stream("stderr"), "error line\n")
+	fmt.Fprint(o.Stream("stdout"), "ond\n")
+	o.Close()
+
+	lines := o.Lines()
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %v", len(lines), outputMessages(o))
+	}
+	want := []struct{ stream, message string }{
+		{"stdout", "first"},
+		{"stderr", "error line"},
+		{"stdout", "second"},
+	}
+	for i, w := range want {
+		if lines[i].Stream != w.stream || lines[i].Message != w.message {
+			t.Errorf("line %d = [%s] %q, want [%s] %q", i, lines[i].Stream, lines[i].Message, w.stream, w.message)
+		}
+		if lines[i].ProcessName != "backup" || lines[i].InstanceID != "scheduled" {
+			t.Errorf("line %d = %+v, want process backup and instance scheduled", i, lines[i])
+		}
+	}
+}
+
+func TestExecutionOutput_Truncation(t *testing.T) {
+	o := NewExecutionOutput("backup", OutputOptions{HeadLines: 2, TailLines: 3})
+
+	w := o.Stream("stdout")
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(w, "line %d\n", i)
+	}
+
+	got := outputMessages(o)
+	want := []string{"line 1", "line 2", "… 5 lines truncated …", "line 8", "line 9", "line 10"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Lines() = %v, want %v", got, want)
+	}
+	if lines := o.Lines(); lines[2].Level != "event" {
+		t.Errorf("truncation marker level = %q, want event", lines[2].Level)
+	}
+
+	total, truncated := o.Counts()
+	if total != 10 || truncated != 5 {
+		t.Errorf("Counts() = %d, %d, want 10, 5", total, truncated)
+	}
+}
+
+func TestExecutionOutput_LongLines(t *testing.T) {
+	o := NewExecutionOutput("backup", OutputOptions{MaxLineLength: 10})
+
+	// A partial line over the limit is captured without waiting for newline
+	fmt.Fprint(o.Stream("stdout"), strings.Repeat("a", 15))
+	if got := outputMessages(o); len(got) != 1 || got[0] != "aaaaaaaaaa … [5 bytes truncated]" {
+		t.Errorf("Lines() = %v, want the cut line", got)
+	}
+
+	// Multi-byte runes are not split
+	fmt.Fprint(o.Stream("stderr"), strings.Repeat("é", 6)+"\n")
+	got := outputMessages(o)
+	if len(got) != 2 || got[1] != "ééééé … [2 bytes truncated]" {
+		t.Errorf("Lines() = %v, want the line cut at a rune boundary", got)
+	}
+}
+
+func TestExecutionOutput_Events(t *testing.T) {
+	o := NewExecutionOutput("backup", OutputOptions{})
+
+	fmt.Fprint(o.Stream("stdout"), "no newline")
+	o.AddEvent("■ Process exited with code 0")
+	o.Close()
+	fmt.Fprint(o.Stream("stdout"), "after close\n")
+	o.AddEvent("after close")
+
+	lines := o.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %v", len(lines), outputMessages(o))
+	}
+	if lines[0].Message != "no newline" || lines[0].Level != "info" {
+		t.Errorf("line 0 = %+v, want flushed partial line", lines[0])
+	}
+	if lines[1].Stream != "event" || lines[1].Level != "event" {
+		t.Errorf("line 1 = %+v, want event", lines[1])
+	}
+}
+
+func TestExecutionOutput_Redaction(t *testing.T) {
+	o := NewExecutionOutput("backup", OutputOptions{})
+	o.SetRedactor(func(line string) string {
+		return strings.ReplaceAll(line, "hunter2", "***")
+	})
+
+	fmt.Fprint(o.Stream("stdout"), "password=hunter2\n")
+
+	if got := outputMessages(o); len(got) != 1 || got[0] != "password=***" {
+		t.Errorf("Lines() = %v, want redacted line", got)
+	}
+}
+
+func TestScheduledJob_CapturesOutput(t *testing.T) {
+	executor := &outputExecutor{lines: []string{"working"}, codes: []int{0}}
+	job, err := NewScheduledJobWithOptions("test-job", "*/5 * * * *", "", 10, executor, testLogger(), JobOptions{})
+	if err != nil {
+		t.Fatalf("NewScheduledJobWithOptions() error = %v", err)
+	}
+
+	if _, err := job.TriggerSync(context.Background()); err != nil {
+		t.Fatalf("TriggerSync() error = %v", err)
+	}
+
+	last, _ := job.History.GetLast()
+	if !last.HasOutput {
+		t.Fatal("expected output to be kept")
+	}
+	logs, ok := job.History.GetOutput(last.ID)
+	if !ok || logs.Running || !logs.Success || len(logs.Lines) != 1 || logs.Lines[0].Message != "working (attempt 1)" {
+		t.Errorf("GetOutput() = %+v, %v", logs, ok)
+	}
+}
+
+func TestScheduledJob_OutputOnFailureOnly(t *testing.T) {
+	executor := &outputExecutor{lines: []string{"working"}, codes: []int{1, 0}}
+	job, err := NewScheduledJobWithOptions("test-job", "*/5 * * * *", "", 10, executor, testLogger(), JobOptions{
+		Output: OutputOptions{OnFailureOnly: true},
+	})
+	if err != nil {
+		t.Fatalf("NewScheduledJobWithOptions() error = %v", err)
+	}
+
+	_, _ = job.TriggerSync(context.Background())
+	_, _ = job.TriggerSync(context.Background())
+
+	entries := job.History.GetAll() // newest first
+	if len(entries) != 2 {
+		t.Fatalf("got %d executions, want 2", len(entries))
+	}
+	if !entries[1].HasOutput {
+		t.Error("failed execution should keep its output")
+	}
+	if entries[0].HasOutput {
+		t.Error("successful execution should drop its output")
+	}
+	if logs, ok := job.History.GetOutput(entries[0].ID); !ok || len(logs.Lines) != 0 {
+		t.Errorf("GetOutput() = %+v, %v, want no lines", logs, ok)
+	}
+}
@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OverlapPolicy decides what happens when a job triggers while a previous
+// run is still executing
+type OverlapPolicy string
+
+const (
+	// OverlapSkip skips the new run (default)
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue runs the new run once the current one finishes, up to
+	// QueueSize waiting runs
+	OverlapQueue OverlapPolicy = "queue"
+	// OverlapReplace cancels the current run gracefully and starts the new one
+	OverlapReplace OverlapPolicy = "replace"
+	// OverlapAllow runs in parallel, up to MaxConcurrent runs (0 = unlimited)
+	OverlapAllow OverlapPolicy = "allow"
+)
+
+// errReplaced is the cancellation cause of a run replaced by a newer one
+var errReplaced = errors.New("replaced by a newer run")
+
+// slotCount returns how many runs may execute at once (0 = unlimited)
+func slotCount(policy OverlapPolicy, maxConcurrent int) int {
+	if policy == OverlapAllow {
+		return maxConcurrent
+	}
+	return 1
+}
+
+// queueSize returns how many runs may wait with OverlapQueue
+func (j *ScheduledJob) queueSize() int {
+	if j.QueueSize > 0 {
+		return j.QueueSize
+	}
+	return 1
+}
+
+// busy reports whether a run triggered now would be skipped. Callers hold j.mu.
+func (j *ScheduledJob) busy() bool {
+	if j.slots == nil || len(j.slots) < cap(j.slots) {
+		return false
+	}
+	switch j.Overlap {
+	case OverlapQueue:
+		return j.queued >= j.queueSize()
+	case OverlapReplace:
+		return false
+	default:
+		return true
+	}
+}
+
+// admit applies the overlap policy to a new run and blocks until it may
+// start. On success the caller owns a run slot and must call releaseSlot.
+func (j *ScheduledJob) admit(ctx context.Context, triggered string) error {
+	if j.slots == nil {
+		return nil
+	}
+	select {
+	case j.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	switch j.Overlap {
+	case OverlapQueue:
+		j.mu.Lock()
+		if j.queued >= j.queueSize() {
+			j.mu.Unlock()
+			j.History.RecordSkipped()
+			j.logger.Warn("skipping execution - queue full (overlap policy: queue)",
+				"triggered", triggered,
+				"queue_size", j.queueSize(),
+			)
+			return fmt.Errorf("job is already executing and its queue is full")
+		}
+		j.queued++
+		j.mu.Unlock()
+		j.History.RecordQueued()
+		j.logger.Info("job execution queued (overlap policy: queue)", "triggered", triggered)
+
+		defer func() {
+			j.mu.Lock()
+			j.queued--
+			j.mu.Unlock()
+		}()
+		select {
+		case j.slots <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+	case OverlapReplace:
+		j.mu.Lock()
+		for _, cancel := range j.runs {
+			cancel(errReplaced)
+		}
+		j.mu.Unlock()
+		j.History.RecordReplaced()
+		j.logger.Info("replacing running execution (overlap policy: replace)", "triggered", triggered)
+
+		select {
+		case j.slots <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+	default:
+		j.History.RecordSkipped()
+		j.logger.Debug("skipping execution - already running",
+			"triggered", triggered,
+			"overlap", j.Overlap,
+		)
+		return fmt.Errorf("job is already executing")
+	}
+}
+
+// releaseSlot frees the run slot taken by admit
+func (j *ScheduledJob) releaseSlot() {
+	if j.slots != nil {
+		<-j.slots
+	}
+}
@@ -0,0 +1,232 @@
+package schedule
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyExecutor blocks each call for delay and records the highest
+// number of calls running at once
+type concurrencyExecutor struct {
+	delay   time.Duration
+	running int64
+	peak    int64
+	calls   int64
+}
+
+func (c *concurrencyExecutor) Execute(ctx context.Context, processName string) (int, error) {
+	atomic.AddInt64(&c.calls, 1)
+	n := atomic.AddInt64(&c.running, 1)
+	defer atomic.AddInt64(&c.running, -1)
+	for {
+		peak := atomic.LoadInt64(&c.peak)
+		if n <= peak || atomic.CompareAndSwapInt64(&c.peak, peak, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(c.delay):
+		return 0, nil
+	case <-ctx.Done():
+		return -1, ctx.Err()
+	}
+}
+
+func newOverlapJob(t *testing.T, executor JobExecutor, opts JobOptions) *ScheduledJob {
+	t.Helper()
+	job, err := NewScheduledJobWithOptions("test", "* * * * *", "", 10, executor, testLogger(), opts)
+	if err != nil {
+		t.Fatalf("NewScheduledJobWithOptions() error = %v", err)
+	}
+	return job
+}
+
+// waitExecuting waits until the job has started executing
+func waitExecuting(t *testing.T, job *ScheduledJob) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !job.IsExecuting() {
+		if time.Now().After(deadline) {
+			t.Fatal("job did not start executing")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestScheduledJob_OverlapDefaultsToSkip(t *testing.T) {
+	job := newOverlapJob(t, &mockExecutor{}, JobOptions{})
+	if job.Overlap != OverlapSkip {
+		t.Errorf("Overlap = %q, want %q", job.Overlap, OverlapSkip)
+	}
+}
+
+func TestScheduledJob_OverlapSkip(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 100 * time.Millisecond}
+	job := newOverlapJob(t, executor, JobOptions{Overlap: OverlapSkip, MaxConcurrent: 5})
+
+	go job.Run()
+	waitExecuting(t, job)
+
+	// MaxConcurrent only applies with OverlapAllow
+	if _, err := job.executeSync(context.Background(), "schedule"); err == nil {
+		t.Error("executeSync() should skip while executing")
+	}
+	if _, err := job.TriggerSync(context.Background()); err == nil {
+		t.Error("TriggerSync() should error while executing")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if calls := atomic.LoadInt64(&executor.calls); calls != 1 {
+		t.Errorf("executor called %d times, want 1", calls)
+	}
+	if stats := job.History.Stats(); stats.SkippedCount != 1 {
+		t.Errorf("SkippedCount = %d, want 1", stats.SkippedCount)
+	}
+}
+
+func TestScheduledJob_OverlapQueue(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 50 * time.Millisecond}
+	job := newOverlapJob(t, executor, JobOptions{Overlap: OverlapQueue, QueueSize: 1})
+
+	go job.Run()
+	waitExecuting(t, job)
+
+	queued := make(chan error, 1)
+	go func() {
+		_, err := job.executeSync(context.Background(), "schedule")
+		queued <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// The queue is full
+	if _, err := job.executeSync(context.Background(), "schedule"); err == nil {
+		t.Error("executeSync() should skip when the queue is full")
+	}
+	if err := job.Trigger(context.Background()); err == nil {
+		t.Error("Trigger() should error when the queue is full")
+	}
+
+	select {
+	case err := <-queued:
+		if err != nil {
+			t.Errorf("queued run error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued run did not complete")
+	}
+
+	if peak := atomic.LoadInt64(&executor.peak); peak != 1 {
+		t.Errorf("peak concurrency = %d, want 1", peak)
+	}
+	stats := job.History.Stats()
+	if stats.TotalExecutions != 2 || stats.QueuedCount != 1 || stats.SkippedCount != 1 {
+		t.Errorf("stats = %+v, want 2 executions, 1 queued and 1 skipped", stats)
+	}
+}
+
+func TestScheduledJob_OverlapQueue_Cancelled(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 100 * time.Millisecond}
+	job := newOverlapJob(t, executor, JobOptions{Overlap: OverlapQueue})
+
+	go job.Run()
+	waitExecuting(t, job)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := job.executeSync(ctx, "manual"); err == nil {
+		t.Error("queued run should fail when its context ends while waiting")
+	}
+
+	// The cancelled run left the queue
+	if _, err := job.executeSync(context.Background(), "manual"); err != nil {
+		t.Errorf("executeSync() error = %v", err)
+	}
+}
+
+func TestScheduledJob_OverlapReplace(t *testing.T) {
+	executor := &concurrencyExecutor{delay: time.Second}
+	job := newOverlapJob(t, executor, JobOptions{Overlap: OverlapReplace, DisableAfter: 1})
+
+	replaced := make(chan error, 1)
+	go func() {
+		_, err := job.executeSync(context.Background(), "schedule")
+		replaced <- err
+	}()
+	waitExecuting(t, job)
+	first, _ := job.History.GetLast()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	second := make(chan error, 1)
+	go func() {
+		_, err := job.TriggerSync(ctx)
+		second <- err
+	}()
+
+	select {
+	case err := <-replaced:
+		if err == nil {
+			t.Error("replaced run should return an error")
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("running execution was not replaced")
+	}
+	waitExecuting(t, job)
+	cancel()
+	<-second
+
+	entry, _ := job.History.GetByID(first.ID)
+	if entry.Success || entry.Error != "replaced by a newer run" {
+		t.Errorf("replaced entry = %+v, want failed with replaced error", entry)
+	}
+	if stats := job.History.Stats(); stats.ReplacedCount != 1 {
+		t.Errorf("ReplacedCount = %d, want 1", stats.ReplacedCount)
+	}
+	// Replaced runs do not count towards the failure breaker
+	if job.GetState() != JobStateIdle || job.Status().ConsecutiveFailures != 0 {
+		t.Errorf("job should be idle with no failures, got %s", job.GetState())
+	}
+}
+
+func TestScheduledJob_OverlapAllow(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 100 * time.Millisecond}
+	job := newOverlapJob(t, executor, JobOptions{Overlap: OverlapAllow, MaxConcurrent: 2})
+
+	go job.Run()
+	go job.Run()
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := job.executeSync(context.Background(), "schedule"); err == nil {
+		t.Error("executeSync() should skip at MaxConcurrent")
+	}
+	if err := job.Trigger(context.Background()); err == nil {
+		t.Error("Trigger() should error at MaxConcurrent")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if peak := atomic.LoadInt64(&executor.peak); peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+	if job.GetState() != JobStateIdle {
+		t.Errorf("state = %s, want idle after all runs ended", job.GetState())
+	}
+	if stats := job.History.Stats(); stats.SkippedCount != 1 {
+		t.Errorf("SkippedCount = %d, want 1", stats.SkippedCount)
+	}
+}
+
+func TestScheduledJob_OverlapAllow_Unlimited(t *testing.T) {
+	executor := &concurrencyExecutor{delay: 50 * time.Millisecond}
+	job := newOverlapJob(t, executor, JobOptions{Overlap: OverlapAllow})
+
+	for i := 0; i < 4; i++ {
+		go job.Run()
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if peak := atomic.LoadInt64(&executor.peak); peak != 4 {
+		t.Errorf("peak concurrency = %d, want 4", peak)
+	}
+}
@@ -0,0 +1,266 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+	"github.com/robfig/cron/v3"
+)
+
+// Preview limits
+const (
+	DefaultPreviewCount = 10
+	MaxPreviewCount     = 1000
+)
+
+// wallClockLayout formats local times without their zone, the way they are
+// written in cron expressions
+const wallClockLayout = "2006-01-02 15:04:05"
+
+// DST flags of preview runs
+const (
+	DSTSkipped  = "skipped"  // The wall-clock time does not exist, the run does not happen
+	DSTRepeated = "repeated" // The wall-clock time occurs twice, the job runs twice
+)
+
+// PreviewOptions configures a schedule preview. The fields mirror the job
+// options that decide when runs start.
+type PreviewOptions struct {
+	Timezone string         // Job timezone ("UTC" or local)
+	Location *time.Location // Overrides Timezone when set
+	Timeout  time.Duration  // Execution timeout (0 = none)
+	Jitter   time.Duration  // Max random start delay
+	Overlap  OverlapPolicy  // What happens to a run that starts while one is executing
+	Calendar Calendar       // Days and times runs may start
+	Count    int            // Number of runs (default DefaultPreviewCount, max MaxPreviewCount)
+	From     time.Time      // Preview runs after this time (default now)
+}
+
+// PreviewRun is an upcoming run of a schedule
+type PreviewRun struct {
+	Time      time.Time     `json:"time"`
+	WallClock string        `json:"wall_clock"`        // Local time in the schedule's timezone
+	Gap       time.Duration `json:"gap,omitempty"`     // Time until the next run
+	DST       string        `json:"dst,omitempty"`     // DSTSkipped or DSTRepeated
+	Overlap   bool          `json:"overlap,omitempty"` // The run may still execute when the next one starts
+	Note      string        `json:"note,omitempty"`    // Why the run is flagged
+}
+
+// Preview lists the upcoming runs of a schedule. Runs skipped by a DST
+// transition are listed in time order but do not count towards Count.
+type Preview struct {
+	Schedule string        `json:"schedule"`
+	Timezone string        `json:"timezone"`
+	Timeout  time.Duration `json:"timeout,omitempty"`
+	Jitter   time.Duration `json:"jitter,omitempty"`
+	Runs     []PreviewRun  `json:"runs"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+// PreviewOptionsFromConfig returns the preview options of a scheduled process
+func PreviewOptionsFromConfig(proc *config.Process) (PreviewOptions, error) {
+	opts := PreviewOptions{
+		Timezone: proc.ScheduleTimezone,
+		Jitter:   proc.ScheduleJitter,
+		Overlap:  OverlapPolicy(proc.ScheduleOverlap),
+	}
+	if proc.ScheduleTimeout != "" {
+		timeout, err := time.ParseDuration(proc.ScheduleTimeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid schedule_timeout: %w", err)
+		}
+		opts.Timeout = timeout
+	}
+	calendar, err := NewCalendar(proc.ScheduleBlackout, proc.ScheduleWeekdaysOnly, proc.ScheduleSkipDates)
+	if err != nil {
+		return opts, fmt.Errorf("invalid calendar: %w", err)
+	}
+	opts.Calendar = calendar
+	return opts, nil
+}
+
+// PreviewSchedule computes the upcoming runs of a schedule expression with
+// the parser and timezone handling of the scheduler. It flags runs that a
+// DST transition skips or repeats, and runs whose timeout reaches into the
+// next run.
+func PreviewSchedule(expr string, opts PreviewOptions) (*Preview, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = timezoneLocation(opts.Timezone)
+	}
+	base, err := parseSchedule(expr, loc)
+	if err != nil {
+		return nil, err
+	}
+	schedule := base
+	calendar := opts.Calendar
+	if calendar.Location == nil {
+		calendar.Location = loc
+	}
+	if !calendar.IsZero() {
+		schedule = calendarSchedule{schedule: base, calendar: calendar}
+	}
+
+	count := opts.Count
+	if count <= 0 {
+		count = DefaultPreviewCount
+	}
+	if count > MaxPreviewCount {
+		count = MaxPreviewCount
+	}
+	from := opts.From
+	if from.IsZero() {
+		from = time.Now()
+	}
+	overlap := opts.Overlap
+	if overlap == "" {
+		overlap = OverlapSkip
+	}
+
+	preview := &Preview{
+		Schedule: expr,
+		Timezone: loc.String(),
+		Timeout:  opts.Timeout,
+		Jitter:   opts.Jitter,
+		Runs:     []PreviewRun{},
+	}
+
+	// One run more than requested gives the gap after the last one
+	times := make([]time.Time, 0, count+1)
+	for t := from; len(times) <= count; {
+		t = schedule.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	if len(times) == 0 {
+		preview.Warnings = append(preview.Warnings, "the schedule never runs")
+		return preview, nil
+	}
+
+	spec, _ := base.(*cron.SpecSchedule)
+	seen := make(map[string]bool)
+	prev := from
+	for i, t := range times {
+		if i == count {
+			break
+		}
+		if spec != nil {
+			preview.Runs = append(preview.Runs, skippedByDST(spec, prev, t)...)
+		}
+		prev = t
+
+		run := PreviewRun{Time: t, WallClock: t.In(loc).Format(wallClockLayout + " MST")}
+		if wall := t.In(loc).Format(wallClockLayout); spec != nil && seen[wall] {
+			run.DST = DSTRepeated
+			run.Note = fmt.Sprintf("%s occurs twice as clocks fall back, the job runs again", wall)
+		} else {
+			seen[wall] = true
+		}
+
+		if i+1 < len(times) {
+			run.Gap = times[i+1].Sub(t)
+			if opts.Timeout > 0 && opts.Timeout+opts.Jitter > run.Gap {
+				run.Overlap = true
+				note := fmt.Sprintf("may still run when the next run starts %s later (%s)", run.Gap, overlapEffect(overlap))
+				if run.Note != "" {
+					note = run.Note + "; " + note
+				}
+				run.Note = note
+			}
+		}
+		preview.Runs = append(preview.Runs, run)
+	}
+
+	if opts.Timeout == 0 && len(times) > 1 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("no schedule_timeout: a run that outlasts the gap to the next run is handled by schedule_overlap (%s)", overlapEffect(overlap)))
+	}
+	if opts.Jitter > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("runs start up to %s after the listed times (schedule_jitter)", opts.Jitter))
+	}
+	return preview, nil
+}
+
+// overlapEffect describes what an overlap policy does with an overlapping run
+func overlapEffect(policy OverlapPolicy) string {
+	switch policy {
+	case OverlapQueue:
+		return "the next run waits for it"
+	case OverlapReplace:
+		return "the next run cancels it"
+	case OverlapAllow:
+		return "both run in parallel"
+	default:
+		return "the next run is skipped"
+	}
+}
+
+// skippedByDST returns a skipped run for each forward DST transition in
+// (from, to] whose missing wall-clock times the schedule matches
+func skippedByDST(spec *cron.SpecSchedule, from, to time.Time) []PreviewRun {
+	var runs []PreviewRun
+	for _, transition := range dstTransitions(spec.Location, from, to) {
+		name, before := transition.Add(-time.Nanosecond).In(spec.Location).Zone()
+		_, after := transition.In(spec.Location).Zone()
+		if after <= before {
+			continue // Clocks fall back, repeated times are flagged on the runs
+		}
+
+		// Match the schedule against the missing times in the old offset
+		fixed := *spec
+		fixed.Location = time.FixedZone(name, before)
+		end := transition.Add(time.Duration(after-before) * time.Second)
+		first := fixed.Next(transition.Add(-time.Nanosecond))
+		if first.IsZero() || !first.Before(end) {
+			continue
+		}
+		missed := 0
+		for t := first; !t.IsZero() && t.Before(end) && missed < MaxPreviewCount; t = fixed.Next(t) {
+			missed++
+		}
+
+		wall := first.In(fixed.Location).Format(wallClockLayout)
+		note := fmt.Sprintf("%s does not exist as clocks spring forward, the run does not happen", wall)
+		if missed > 1 {
+			note = fmt.Sprintf("%d runs from %s do not exist as clocks spring forward, they do not happen", missed, wall)
+		}
+		runs = append(runs, PreviewRun{Time: first, WallClock: wall, DST: DSTSkipped, Note: note})
+	}
+	return runs
+}
+
+// dstTransitions returns the instants in (from, to] at which the UTC offset
+// of loc changes
+func dstTransitions(loc *time.Location, from, to time.Time) []time.Time {
+	const step = 12 * time.Hour // Shorter than the time between two transitions
+
+	var transitions []time.Time
+	for lo := from; lo.Before(to); {
+		hi := lo.Add(step)
+		if hi.After(to) {
+			hi = to
+		}
+		if offset(lo, loc) != offset(hi, loc) {
+			// Narrow down to the first instant with the new offset
+			a, b := lo, hi
+			for b.Sub(a) > time.Second {
+				mid := a.Add(b.Sub(a) / 2)
+				if offset(mid, loc) == offset(a, loc) {
+					a = mid
+				} else {
+					b = mid
+				}
+			}
+			transitions = append(transitions, b.Truncate(time.Second))
+		}
+		lo = hi
+	}
+	return transitions
+}
+
+func offset(t time.Time, loc *time.Location) int {
+	_, off := t.In(loc).Zone()
+	return off
+}
@@ -0,0 +1,266 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophpeek/phpeek-pm/internal/config"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone data for %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestPreviewSchedule_Basic(t *testing.T) {
+	from := time.Date(2026, 1, 5, 10, 7, 0, 0, time.UTC)
+	preview, err := PreviewSchedule("*/15 * * * *", PreviewOptions{Timezone: "UTC", Count: 4, From: from})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+
+	want := []string{"10:15", "10:30", "10:45", "11:00"}
+	if len(preview.Runs) != len(want) {
+		t.Fatalf("got %d runs, want %d", len(preview.Runs), len(want))
+	}
+	for i, run := range preview.Runs {
+		if got := run.Time.Format("15:04"); got != want[i] {
+			t.Errorf("run %d = %s, want %s", i, got, want[i])
+		}
+		if run.Gap != 15*time.Minute {
+			t.Errorf("run %d gap = %s, want 15m", i, run.Gap)
+		}
+		if run.DST != "" || run.Overlap {
+			t.Errorf("run %d unexpectedly flagged: %+v", i, run)
+		}
+	}
+	if preview.Timezone != "UTC" {
+		t.Errorf("Timezone = %q, want UTC", preview.Timezone)
+	}
+	if len(preview.Warnings) != 1 || !strings.Contains(preview.Warnings[0], "no schedule_timeout") {
+		t.Errorf("Warnings = %v, want a missing timeout warning", preview.Warnings)
+	}
+}
+
+func TestPreviewSchedule_CountLimits(t *testing.T) {
+	preview, err := PreviewSchedule("@every 1m", PreviewOptions{})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	if len(preview.Runs) != DefaultPreviewCount {
+		t.Errorf("got %d runs, want default %d", len(preview.Runs), DefaultPreviewCount)
+	}
+
+	preview, _ = PreviewSchedule("@every 1m", PreviewOptions{Count: MaxPreviewCount + 50})
+	if len(preview.Runs) != MaxPreviewCount {
+		t.Errorf("got %d runs, want max %d", len(preview.Runs), MaxPreviewCount)
+	}
+}
+
+func TestPreviewSchedule_InvalidExpression(t *testing.T) {
+	if _, err := PreviewSchedule("not a schedule", PreviewOptions{}); err == nil {
+		t.Error("PreviewSchedule() should fail for an invalid expression")
+	}
+}
+
+func TestPreviewSchedule_UsesTimezone(t *testing.T) {
+	loc := loadLocation(t, "Europe/Berlin")
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	preview, err := PreviewSchedule("0 9 * * *", PreviewOptions{Location: loc, Count: 1, From: from})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	if got := preview.Runs[0].Time.UTC().Format("15:04"); got != "08:00" {
+		t.Errorf("09:00 Berlin = %s UTC, want 08:00", got)
+	}
+	if preview.Runs[0].WallClock != "2026-01-05 09:00:00 CET" {
+		t.Errorf("WallClock = %q", preview.Runs[0].WallClock)
+	}
+}
+
+func TestPreviewSchedule_DSTSpringForward(t *testing.T) {
+	loc := loadLocation(t, "America/New_York")
+	// Clocks jump from 02:00 to 03:00 on 2026-03-08
+	from := time.Date(2026, 3, 6, 12, 0, 0, 0, loc)
+
+	preview, err := PreviewSchedule("30 2 * * *", PreviewOptions{Location: loc, Count: 3, From: from})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+
+	var skipped []PreviewRun
+	runs := 0
+	for _, run := range preview.Runs {
+		if run.DST == DSTSkipped {
+			skipped = append(skipped, run)
+			continue
+		}
+		runs++
+	}
+	if runs != 3 {
+		t.Errorf("got %d runs, want 3 (skipped runs do not count)", runs)
+	}
+	if len(skipped) != 1 {
+		t.Fatalf("got %d skipped runs, want 1: %+v", len(skipped), preview.Runs)
+	}
+	if skipped[0].WallClock != "2026-03-08 02:30:00" {
+		t.Errorf("skipped WallClock = %q, want 2026-03-08 02:30:00", skipped[0].WallClock)
+	}
+	if preview.Runs[1].DST != DSTSkipped {
+		t.Errorf("skipped run should be listed in time order: %+v", preview.Runs)
+	}
+	if days := preview.Runs[2].Time.Sub(preview.Runs[0].Time); days != 47*time.Hour {
+		t.Errorf("runs around the skipped day are %s apart, want 47h", days)
+	}
+}
+
+func TestPreviewSchedule_DSTSpringForwardCollapsesRuns(t *testing.T) {
+	loc := loadLocation(t, "America/New_York")
+	from := time.Date(2026, 3, 8, 1, 50, 0, 0, loc)
+
+	preview, err := PreviewSchedule("*/15 * * * *", PreviewOptions{Location: loc, Count: 2, From: from})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	if preview.Runs[0].DST != DSTSkipped || !strings.HasPrefix(preview.Runs[0].Note, "4 runs from 2026-03-08 02:00:00") {
+		t.Errorf("runs = %+v, want 4 skipped runs from 02:00", preview.Runs)
+	}
+}
+
+func TestPreviewSchedule_DSTFallBack(t *testing.T) {
+	loc := loadLocation(t, "America/New_York")
+	// Clocks fall back from 02:00 to 01:00 on 2026-11-01
+	from := time.Date(2026, 10, 31, 12, 0, 0, 0, loc)
+
+	preview, err := PreviewSchedule("30 1 * * *", PreviewOptions{Location: loc, Count: 3, From: from})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	if len(preview.Runs) != 3 {
+		t.Fatalf("got %d runs, want 3", len(preview.Runs))
+	}
+	if preview.Runs[0].DST != "" {
+		t.Errorf("first 01:30 should not be flagged: %+v", preview.Runs[0])
+	}
+	if preview.Runs[1].DST != DSTRepeated {
+		t.Errorf("second 01:30 should be flagged repeated: %+v", preview.Runs[1])
+	}
+	if preview.Runs[0].Gap != time.Hour {
+		t.Errorf("gap between the two 01:30 runs = %s, want 1h", preview.Runs[0].Gap)
+	}
+}
+
+func TestPreviewSchedule_IntervalsIgnoreDST(t *testing.T) {
+	loc := loadLocation(t, "America/New_York")
+	from := time.Date(2026, 11, 1, 0, 45, 0, 0, loc)
+
+	preview, err := PreviewSchedule("@every 30m", PreviewOptions{Location: loc, Count: 6, From: from})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	for _, run := range preview.Runs {
+		if run.DST != "" {
+			t.Errorf("@every runs are not affected by DST: %+v", run)
+		}
+	}
+}
+
+func TestPreviewSchedule_TimeoutOverlap(t *testing.T) {
+	from := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	// Runs at :00 and :10, the 20 minute gap from :10 to :00 fits the timeout
+	preview, err := PreviewSchedule("0,10 * * * *", PreviewOptions{
+		Timezone: "UTC",
+		Timeout:  15 * time.Minute,
+		Overlap:  OverlapQueue,
+		Count:    3,
+		From:     from,
+	})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+
+	wantOverlap := []bool{false, true, false}
+	for i, run := range preview.Runs {
+		if run.Overlap != wantOverlap[i] {
+			t.Errorf("run %d (%s) overlap = %v, want %v", i, run.Time.Format("15:04"), run.Overlap, wantOverlap[i])
+		}
+	}
+	if note := preview.Runs[1].Note; !strings.Contains(note, "the next run waits for it") {
+		t.Errorf("Note = %q, want the queue effect", note)
+	}
+	for _, warning := range preview.Warnings {
+		if strings.Contains(warning, "no schedule_timeout") {
+			t.Errorf("unexpected warning with a timeout: %s", warning)
+		}
+	}
+}
+
+func TestPreviewSchedule_JitterCountsTowardsOverlap(t *testing.T) {
+	from := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	preview, err := PreviewSchedule("*/10 * * * *", PreviewOptions{
+		Timezone: "UTC",
+		Timeout:  8 * time.Minute,
+		Jitter:   3 * time.Minute,
+		Count:    1,
+		From:     from,
+	})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	if !preview.Runs[0].Overlap {
+		t.Error("timeout plus jitter above the gap should overlap")
+	}
+	if len(preview.Warnings) != 1 || !strings.Contains(preview.Warnings[0], "schedule_jitter") {
+		t.Errorf("Warnings = %v, want a jitter warning", preview.Warnings)
+	}
+}
+
+func TestPreviewSchedule_Calendar(t *testing.T) {
+	calendar, err := NewCalendar(nil, true, nil)
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+	// Friday 2026-01-09
+	from := time.Date(2026, 1, 9, 12, 0, 0, 0, time.UTC)
+
+	preview, err := PreviewSchedule("0 9 * * *", PreviewOptions{Timezone: "UTC", Calendar: calendar, Count: 1, From: from})
+	if err != nil {
+		t.Fatalf("PreviewSchedule() error = %v", err)
+	}
+	if got := preview.Runs[0].Time.Format("Mon 2006-01-02"); got != "Mon 2026-01-12" {
+		t.Errorf("next run = %s, want Monday after the weekend", got)
+	}
+}
+
+func TestPreviewOptionsFromConfig(t *testing.T) {
+	proc := &config.Process{
+		Schedule:             "0 2 * * *",
+		ScheduleTimezone:     "UTC",
+		ScheduleTimeout:      "30m",
+		ScheduleJitter:       time.Minute,
+		ScheduleOverlap:      "replace",
+		ScheduleWeekdaysOnly: true,
+	}
+	opts, err := PreviewOptionsFromConfig(proc)
+	if err != nil {
+		t.Fatalf("PreviewOptionsFromConfig() error = %v", err)
+	}
+	if opts.Timeout != 30*time.Minute || opts.Jitter != time.Minute || opts.Overlap != OverlapReplace {
+		t.Errorf("opts = %+v", opts)
+	}
+	if !opts.Calendar.WeekdaysOnly || opts.Timezone != "UTC" {
+		t.Errorf("opts = %+v", opts)
+	}
+
+	proc.ScheduleTimeout = "soon"
+	if _, err := PreviewOptionsFromConfig(proc); err == nil {
+		t.Error("PreviewOptionsFromConfig() should fail for an invalid timeout")
+	}
+}
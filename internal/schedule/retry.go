@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// RetryPolicy retries failed executions of a job within the same run.
+// The zero value never retries.
+//
+// The delay before retry n (starting at 1) is Backoff * 2^(n-1), capped at
+// MaxBackoff, with up to ±Jitter of it added at random so that jobs failing
+// on the same outage do not retry in lockstep.
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts per run including the first (0/1 = no retry)
+	Backoff     time.Duration // Delay before the first retry
+	MaxBackoff  time.Duration // Upper bound for the delay (0 = no bound)
+	Jitter      float64       // Fraction of the delay added or removed at random (<= 0 = none)
+	ExitCodes   []int         // Retry only these exit codes (empty = any failure)
+}
+
+// ShouldRetry reports whether a run should be attempted again after attempt
+// ended with exitCode and err
+func (p RetryPolicy) ShouldRetry(attempt, exitCode int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if err == nil && exitCode == 0 {
+		return false
+	}
+	if len(p.ExitCodes) > 0 {
+		return slices.Contains(p.ExitCodes, exitCode)
+	}
+	return true
+}
+
+// Delay returns the delay before the retry that follows attempt
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && delay > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay += time.Duration((rand.Float64()*2 - 1) * jitter * float64(delay))
+	}
+	return delay
+}
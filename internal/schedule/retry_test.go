@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		exitCode int
+		err      error
+		want     bool
+	}{
+		{"zero value never retries", RetryPolicy{}, 1, 1, errors.New("exit 1"), false},
+		{"failure with attempts left", RetryPolicy{MaxAttempts: 3}, 1, 1, errors.New("exit 1"), true},
+		{"last attempt", RetryPolicy{MaxAttempts: 3}, 3, 1, errors.New("exit 1"), false},
+		{"success", RetryPolicy{MaxAttempts: 3}, 1, 0, nil, false},
+		{"failed to start", RetryPolicy{MaxAttempts: 3}, 1, -1, errors.New("not found"), true},
+		{"listed exit code", RetryPolicy{MaxAttempts: 3, ExitCodes: []int{75, 111}}, 1, 75, errors.New("exit 75"), true},
+		{"unlisted exit code", RetryPolicy{MaxAttempts: 3, ExitCodes: []int{75, 111}}, 1, 1, errors.New("exit 1"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ShouldRetry(tt.attempt, tt.exitCode, tt.err); got != tt.want {
+				t.Errorf("ShouldRetry(%d, %d, %v) = %v, want %v", tt.attempt, tt.exitCode, tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{50, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.Delay(tt.attempt); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_DelayJitter(t *testing.T) {
+	policy := RetryPolicy{Backoff: 10 * time.Second, Jitter: 0.2}
+
+	varied := false
+	for i := 0; i < 100; i++ {
+		got := policy.Delay(1)
+		if got < 8*time.Second || got > 12*time.Second {
+			t.Fatalf("Delay(1) = %v, want within 20%% of 10s", got)
+		}
+		if got != 10*time.Second {
+			varied = true
+		}
+	}
+	if !varied {
+		t.Error("jitter should vary the delay")
+	}
+}
@@ -14,9 +14,10 @@ import (
 // It wraps the robfig/cron library to provide job management, execution
 // tracking, pause/resume capabilities, and manual triggering.
 //
-// The Scheduler uses standard 5-field cron expressions (minute, hour, day-of-month,
-// month, day-of-week) without second-level precision. Timezone support is
-// available for each job.
+// The Scheduler accepts standard 5-field cron expressions (minute, hour,
+// day-of-month, month, day-of-week), 6-field expressions with leading seconds
+// and descriptors like "@every 90s". Timezone, start jitter and calendar
+// constraints are available for each job.
 //
 // Key features:
 //   - Job lifecycle management (add, remove, pause, resume)
@@ -34,14 +35,14 @@ type Scheduler struct {
 	logger      *slog.Logger
 	mu          sync.RWMutex
 	started     bool
+	runCtx      context.Context    // Done when the scheduler stops, ends jitter waits
+	stopRuns    context.CancelFunc // Cancels runCtx
 }
 
 // NewScheduler creates a new Scheduler
 func NewScheduler(executor JobExecutor, historySize int, logger *slog.Logger) *Scheduler {
-	// Create cron with second-level precision disabled (standard cron format)
-	c := cron.New(cron.WithParser(cron.NewParser(
-		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow,
-	)))
+	c := cron.New(cron.WithParser(expressionParser))
+	runCtx, stopRuns := context.WithCancel(context.Background())
 
 	return &Scheduler{
 		cron:        c,
@@ -49,9 +50,18 @@ func NewScheduler(executor JobExecutor, historySize int, logger *slog.Logger) *S
 		executor:    executor,
 		historySize: historySize,
 		logger:      logger.With("component", "scheduler"),
+		runCtx:      runCtx,
+		stopRuns:    stopRuns,
 	}
 }
 
+// runContext returns the context of scheduled runs
+func (s *Scheduler) runContext() context.Context {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.runCtx
+}
+
 // AddJob adds a new scheduled job
 func (s *Scheduler) AddJob(name, scheduleExpr, timezone string) error {
 	return s.AddJobWithOptions(name, scheduleExpr, timezone, JobOptions{})
@@ -70,12 +80,11 @@ func (s *Scheduler) AddJobWithOptions(name, scheduleExpr, timezone string, opts
 	if err != nil {
 		return fmt.Errorf("failed to create job: %w", err)
 	}
+	job.runCtx = s.runContext
 
-	// Add to cron scheduler
-	entryID, err := s.cron.AddJob(scheduleExpr, job)
-	if err != nil {
-		return fmt.Errorf("failed to add job to cron: %w", err)
-	}
+	// Add to cron scheduler with the job's schedule, which skips the
+	// activations its calendar blocks
+	entryID := s.cron.Schedule(job.schedule, job)
 
 	job.SetCronID(entryID)
 	s.jobs[name] = job
@@ -93,6 +102,15 @@ func (s *Scheduler) AddJobWithOptions(name, scheduleExpr, timezone string, opts
 	if opts.Timeout > 0 {
 		logFields = append(logFields, "timeout", opts.Timeout)
 	}
+	if opts.Jitter > 0 {
+		logFields = append(logFields, "jitter", opts.Jitter)
+	}
+	if !opts.Calendar.IsZero() {
+		logFields = append(logFields, "calendar", true)
+	}
+	if opts.Overlap != "" {
+		logFields = append(logFields, "overlap", opts.Overlap)
+	}
 	if opts.MaxConcurrent > 0 {
 		logFields = append(logFields, "max_concurrent", opts.MaxConcurrent)
 	}
@@ -150,6 +168,9 @@ func (s *Scheduler) Start() {
 		return
 	}
 
+	if s.runCtx.Err() != nil {
+		s.runCtx, s.stopRuns = context.WithCancel(context.Background())
+	}
 	s.cron.Start()
 	s.started = true
 
@@ -177,6 +198,8 @@ func (s *Scheduler) Stop() context.Context {
 	s.started = false
 	s.logger.Info("scheduler stopping")
 
+	// Runs still waiting out their jitter do not start
+	s.stopRuns()
 	return s.cron.Stop()
 }
 
@@ -226,6 +249,19 @@ func (s *Scheduler) TriggerJob(ctx context.Context, name string) error {
 	return job.Trigger(ctx)
 }
 
+// TriggerJobChained triggers a job as part of a job chain
+func (s *Scheduler) TriggerJobChained(ctx context.Context, name string, chain Chain) error {
+	s.mu.RLock()
+	job, exists := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("job %q not found", name)
+	}
+
+	return job.TriggerChain(ctx, chain)
+}
+
 // TriggerJobSync triggers a job and waits for completion
 func (s *Scheduler) TriggerJobSync(ctx context.Context, name string) (int, error) {
 	s.mu.RLock()
@@ -293,6 +329,23 @@ func (s *Scheduler) GetJobHistory(name string, limit int) ([]ExecutionEntry, err
 	return job.History.GetRecent(limit), nil
 }
 
+// GetJobOutput returns the captured output of an execution of a job
+func (s *Scheduler) GetJobOutput(name string, executionID int64) (ExecutionLogs, error) {
+	s.mu.RLock()
+	job, exists := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !exists {
+		return ExecutionLogs{}, fmt.Errorf("job %q not found", name)
+	}
+
+	logs, ok := job.History.GetOutput(executionID)
+	if !ok {
+		return ExecutionLogs{}, fmt.Errorf("execution %d of job %q not found", executionID, name)
+	}
+	return logs, nil
+}
+
 // UpdateNextRunTimes updates the next run times for all jobs from cron
 func (s *Scheduler) UpdateNextRunTimes() {
 	s.mu.RLock()
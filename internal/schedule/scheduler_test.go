@@ -425,6 +425,28 @@ func TestScheduler_NextRunUpdatedOnStart(t *testing.T) {
 	}
 }
 
+func TestScheduler_NextRunSkipsCalendar(t *testing.T) {
+	s := NewScheduler(&mockExecutor{}, 50, testLogger())
+
+	now := time.Now()
+	today := now.Format(dateLayout)
+	tomorrow := now.AddDate(0, 0, 1).Format(dateLayout)
+	err := s.AddJobWithOptions("test-job", "*/5 * * * *", "Local", JobOptions{
+		Calendar: Calendar{SkipDates: []string{today, tomorrow}},
+	})
+	if err != nil {
+		t.Fatalf("AddJobWithOptions() error = %v", err)
+	}
+	s.Start()
+	defer s.Stop()
+
+	status, _ := s.GetJobStatus("test-job")
+	want := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, time.Local)
+	if !status.NextRun.Equal(want) {
+		t.Errorf("NextRun = %v, want %v (first run after the skip dates)", status.NextRun, want)
+	}
+}
+
 func TestScheduler_Concurrent(t *testing.T) {
 	executor := &mockExecutor{}
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
@@ -16,6 +16,7 @@ import (
 	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // APIClient connects to a running PHPeek PM daemon via API
@@ -386,6 +387,54 @@ func (c *APIClient) GetStackLogs(limit int) ([]logger.LogEntry, error) {
 	return c.fetchLogs(path)
 }
 
+// QueryLogs retrieves logs matching the query, filtered server-side.
+// An empty processName queries the aggregated stack logs.
+func (c *APIClient) QueryLogs(processName string, q logger.LogQuery) ([]logger.LogEntry, error) {
+	path := "/api/v1/logs"
+	if processName != "" {
+		path = fmt.Sprintf("/api/v1/processes/%s/logs", url.PathEscape(processName))
+	}
+
+	if params := logQueryValues(q); len(params) > 0 {
+		path = fmt.Sprintf("%s?%s", path, params.Encode())
+	}
+
+	return c.fetchLogs(path)
+}
+
+// logQueryValues encodes a log query as API query parameters
+func logQueryValues(q logger.LogQuery) url.Values {
+	params := url.Values{}
+	if q.Limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", q.Limit))
+	}
+	if q.Contains != "" {
+		params.Set("search", q.Contains)
+	}
+	if q.Pattern != nil {
+		params.Set("regex", q.Pattern.String())
+	}
+	if len(q.Levels) > 0 {
+		params.Set("level", strings.Join(q.Levels, ","))
+	}
+	if q.Stream != "" {
+		params.Set("stream", q.Stream)
+	}
+	if q.Instance != "" {
+		params.Set("instance", q.Instance)
+	}
+	if !q.Since.IsZero() {
+		params.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		params.Set("until", q.Until.Format(time.RFC3339))
+	}
+	if q.Before > 0 {
+		params.Set("cursor", fmt.Sprintf("%d", q.Before))
+	}
+	return params
+}
+
 func (c *APIClient) fetchLogs(path string) ([]logger.LogEntry, error) {
 	if c.client == nil {
 		return nil, fmt.Errorf("API client not initialized")
@@ -568,3 +617,146 @@ func (c *APIClient) GetOneshotHistory(limit int) ([]process.OneshotExecution, er
 
 	return response.Executions, nil
 }
+
+// GetRecentCrashes fetches recent crashes of a process with captured output
+func (c *APIClient) GetRecentCrashes(name string, limit int) ([]process.CrashRecord, error) {
+	path := fmt.Sprintf("/api/v1/processes/%s/crashes", name)
+	if limit > 0 {
+		path = fmt.Sprintf("%s?limit=%d", path, limit)
+	}
+
+	var response struct {
+		Crashes []process.CrashRecord `json:"crashes"`
+	}
+	if err := c.doJSON(http.MethodGet, path, nil, &response, "get crashes"); err != nil {
+		return nil, err
+	}
+	return response.Crashes, nil
+}
+
+// GetScheduleHistory fetches the execution history of a scheduled process
+// (newest first)
+func (c *APIClient) GetScheduleHistory(name string, limit int) ([]schedule.ExecutionEntry, error) {
+	path := fmt.Sprintf("/api/v1/processes/%s/schedule/history", name)
+	if limit > 0 {
+		path = fmt.Sprintf("%s?limit=%d", path, limit)
+	}
+
+	var response struct {
+		History []schedule.ExecutionEntry `json:"history"`
+	}
+	if err := c.doJSON(http.MethodGet, path, nil, &response, "get schedule history"); err != nil {
+		return nil, err
+	}
+	return response.History, nil
+}
+
+// GetExecutionLogs fetches the captured output of one scheduled execution
+func (c *APIClient) GetExecutionLogs(name string, executionID int64) (schedule.ExecutionLogs, error) {
+	path := fmt.Sprintf("/api/v1/processes/%s/schedule/history/%d/logs", name, executionID)
+
+	var response struct {
+		Execution schedule.ExecutionLogs `json:"execution"`
+	}
+	err := c.doJSON(http.MethodGet, path, nil, &response, "get execution logs")
+	return response.Execution, err
+}
+
+// GetLogLevel fetches the daemon log level
+func (c *APIClient) GetLogLevel() (process.LogLevelSettings, error) {
+	var settings process.LogLevelSettings
+	err := c.doJSON(http.MethodGet, "/api/v1/logging", nil, &settings, "get log level")
+	return settings, err
+}
+
+// SetLogLevel changes the daemon log level, reverting after ttl if > 0
+func (c *APIClient) SetLogLevel(level string, ttl time.Duration) (process.LogLevelSettings, error) {
+	body := map[string]string{"level": level}
+	if ttl > 0 {
+		body["ttl"] = ttl.String()
+	}
+
+	var settings process.LogLevelSettings
+	err := c.doJSON(http.MethodPut, "/api/v1/logging", body, &settings, "set log level")
+	return settings, err
+}
+
+// ResetLogLevel restores the configured daemon log level
+func (c *APIClient) ResetLogLevel() (process.LogLevelSettings, error) {
+	var settings process.LogLevelSettings
+	err := c.doJSON(http.MethodDelete, "/api/v1/logging", nil, &settings, "reset log level")
+	return settings, err
+}
+
+// GetProcessLogSettings fetches the log filtering applied to a process
+func (c *APIClient) GetProcessLogSettings(name string) (process.LogSettings, error) {
+	var settings process.LogSettings
+	err := c.doJSON(http.MethodGet, fmt.Sprintf("/api/v1/processes/%s/logging", name), nil, &settings, "get log settings")
+	return settings, err
+}
+
+// SetProcessLogSettings changes a process's min level and filters, reverting after ttl if > 0
+func (c *APIClient) SetProcessLogSettings(name string, update process.LogSettingsUpdate, ttl time.Duration) (process.LogSettings, error) {
+	body := map[string]interface{}{}
+	if update.MinLevel != "" {
+		body["min_level"] = update.MinLevel
+	}
+	if update.Filters != nil {
+		body["filters"] = update.Filters
+	}
+	if ttl > 0 {
+		body["ttl"] = ttl.String()
+	}
+
+	var settings process.LogSettings
+	err := c.doJSON(http.MethodPost, fmt.Sprintf("/api/v1/processes/%s/logging", name), body, &settings, "set log settings")
+	return settings, err
+}
+
+// ResetProcessLogSettings restores the configured log filtering of a process
+func (c *APIClient) ResetProcessLogSettings(name string) (process.LogSettings, error) {
+	var settings process.LogSettings
+	err := c.doJSON(http.MethodPost, fmt.Sprintf("/api/v1/processes/%s/logging/reset", name), nil, &settings, "reset log settings")
+	return settings, err
+}
+
+// doJSON sends an optional JSON body and decodes a 200 response into out
+func (c *APIClient) doJSON(method, path string, body interface{}, out interface{}, op string) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.getURL(path), reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.auth != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.auth))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s failed: %s", op, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
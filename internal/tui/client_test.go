@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // TestNewAPIClient tests client creation
@@ -1789,3 +1791,214 @@ func TestAPIClient_GetOneshotHistory(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIClient_QueryLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/processes/app/logs":
+			if query.Get("search") != "timeout" || query.Get("instance") != "app-1" || query.Get("level") != "error,warn" {
+				t.Errorf("unexpected process query: %s", r.URL.RawQuery)
+			}
+		case "/api/v1/logs":
+			if query.Get("regex") != "^PDO" || query.Get("cursor") != "12" || query.Get("limit") != "10" {
+				t.Errorf("unexpected stack query: %s", r.URL.RawQuery)
+			}
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"logs": []logger.LogEntry{{Message: "match", Seq: 11}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, "")
+
+	logs, err := client.QueryLogs("app", logger.LogQuery{
+		Contains: "timeout",
+		Instance: "app-1",
+		Levels:   []string{"error", "warn"},
+	})
+	if err != nil {
+		t.Fatalf("QueryLogs returned error: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Seq != 11 {
+		t.Fatalf("unexpected logs: %#v", logs)
+	}
+
+	if _, err := client.QueryLogs("", logger.LogQuery{
+		Pattern: regexp.MustCompile("^PDO"),
+		Before:  12,
+		Limit:   10,
+	}); err != nil {
+		t.Fatalf("QueryLogs (stack) returned error: %v", err)
+	}
+}
+
+// TestAPIClient_LogSettings tests runtime log level and filter requests
+func TestAPIClient_LogSettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/logging", "DELETE /api/v1/logging":
+			_ = json.NewEncoder(w).Encode(process.LogLevelSettings{Level: "info", Configured: "info"})
+		case "PUT /api/v1/logging":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["level"] != "debug" || body["ttl"] != "15m0s" {
+				t.Errorf("unexpected level body: %v", body)
+			}
+			_ = json.NewEncoder(w).Encode(process.LogLevelSettings{Level: "debug", Override: true})
+		case "GET /api/v1/processes/app/logging", "POST /api/v1/processes/app/logging/reset":
+			_ = json.NewEncoder(w).Encode(process.LogSettings{Process: "app", MinLevel: "info"})
+		case "POST /api/v1/processes/app/logging":
+			var body struct {
+				MinLevel string               `json:"min_level"`
+				Filters  *config.FilterConfig `json:"filters"`
+				TTL      string               `json:"ttl"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.MinLevel != "warn" || body.Filters == nil || body.Filters.Exclude[0] != "health" || body.TTL != "" {
+				t.Errorf("unexpected settings body: %+v", body)
+			}
+			_ = json.NewEncoder(w).Encode(process.LogSettings{Process: "app", MinLevel: "warn", Override: true})
+		case "GET /api/v1/processes/missing/logging":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"process not found: missing"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, "")
+
+	if level, err := client.GetLogLevel(); err != nil || level.Level != "info" {
+		t.Errorf("GetLogLevel() = %+v, %v", level, err)
+	}
+	if level, err := client.SetLogLevel("debug", 15*time.Minute); err != nil || !level.Override {
+		t.Errorf("SetLogLevel() = %+v, %v", level, err)
+	}
+	if _, err := client.ResetLogLevel(); err != nil {
+		t.Errorf("ResetLogLevel() error = %v", err)
+	}
+
+	if settings, err := client.GetProcessLogSettings("app"); err != nil || settings.MinLevel != "info" {
+		t.Errorf("GetProcessLogSettings() = %+v, %v", settings, err)
+	}
+	settings, err := client.SetProcessLogSettings("app", process.LogSettingsUpdate{
+		MinLevel: "warn",
+		Filters:  &config.FilterConfig{Exclude: []string{"health"}},
+	}, 0)
+	if err != nil || settings.MinLevel != "warn" {
+		t.Errorf("SetProcessLogSettings() = %+v, %v", settings, err)
+	}
+	if _, err := client.ResetProcessLogSettings("app"); err != nil {
+		t.Errorf("ResetProcessLogSettings() error = %v", err)
+	}
+
+	if _, err := client.GetProcessLogSettings("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+// TestAPIClient_GetRecentCrashes tests fetching recent crashes
+func TestAPIClient_GetRecentCrashes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/processes/missing/crashes" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"process not found: missing"}`))
+			return
+		}
+		if r.URL.Path != "/api/v1/processes/app/crashes" || r.URL.Query().Get("limit") != "3" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"crashes": []process.CrashRecord{{
+				ProcessName: "app",
+				ExitCode:    255,
+				LastLines:   []logger.LogEntry{{Stream: "stderr", Message: "PHP Fatal error"}},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, "")
+
+	crashes, err := client.GetRecentCrashes("app", 3)
+	if err != nil {
+		t.Fatalf("GetRecentCrashes returned error: %v", err)
+	}
+	if len(crashes) != 1 || crashes[0].ExitCode != 255 || crashes[0].LastLines[0].Message != "PHP Fatal error" {
+		t.Errorf("unexpected crashes: %+v", crashes)
+	}
+
+	if _, err := client.GetRecentCrashes("missing", 0); err == nil {
+		t.Error("expected error for unknown process")
+	}
+}
+
+// TestAPIClient_GetScheduleHistory tests fetching a job's execution history
+func TestAPIClient_GetScheduleHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/processes/backup/schedule/history" || r.URL.Query().Get("limit") != "5" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"count": 1,
+			"history": []schedule.ExecutionEntry{
+				{ID: 7, ExitCode: 1, Triggered: "schedule", Attempt: 1, HasOutput: true},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, "")
+
+	history, err := client.GetScheduleHistory("backup", 5)
+	if err != nil {
+		t.Fatalf("GetScheduleHistory returned error: %v", err)
+	}
+	if len(history) != 1 || history[0].ID != 7 || !history[0].HasOutput {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+// TestAPIClient_GetExecutionLogs tests fetching the output of one execution
+func TestAPIClient_GetExecutionLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/processes/backup/schedule/history/99/logs" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"execution 99 of job \"backup\" not found"}`))
+			return
+		}
+		if r.URL.Path != "/api/v1/processes/backup/schedule/history/7/logs" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"process": "backup",
+			"execution": schedule.ExecutionLogs{
+				ExecutionID:    7,
+				ExitCode:       1,
+				TotalLines:     350,
+				TruncatedLines: 50,
+				Lines:          []logger.LogEntry{{Stream: "stderr", Message: "disk full"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, "")
+
+	logs, err := client.GetExecutionLogs("backup", 7)
+	if err != nil {
+		t.Fatalf("GetExecutionLogs returned error: %v", err)
+	}
+	if logs.ExecutionID != 7 || logs.TruncatedLines != 50 || len(logs.Lines) != 1 || logs.Lines[0].Message != "disk full" {
+		t.Errorf("unexpected logs: %+v", logs)
+	}
+
+	if _, err := client.GetExecutionLogs("backup", 99); err == nil {
+		t.Error("expected error for unknown execution")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // viewMode represents the current TUI view
@@ -22,6 +23,7 @@ const (
 	viewLogs
 	viewHelp
 	viewWizard
+	viewScheduleHistory
 )
 
 // tabType represents the k9s-style tabs
@@ -44,6 +46,7 @@ type logScope int
 const (
 	logScopeStack logScope = iota
 	logScopeProcess
+	logScopeExecution // Captured output of one scheduled execution
 )
 
 type wizardMode int
@@ -84,6 +87,7 @@ type Model struct {
 	logBuffer    []string
 	logScope     logScope
 	logReturn    viewMode
+	logSearch    string // Active search term applied server-side
 	width        int
 	height       int
 	err          error
@@ -98,6 +102,19 @@ type Model struct {
 	showScaleDialog bool
 	scaleInput      string
 
+	// Log search prompt
+	showLogSearch  bool
+	logSearchInput string
+
+	// Process detail crash history (newest first)
+	detailCrashes []process.CrashRecord
+
+	// Schedule execution history view (newest first)
+	historyProc    string
+	historyEntries []schedule.ExecutionEntry
+	historyIndex   int
+	logExecution   int64 // Execution shown by logScopeExecution
+
 	// Toast notifications
 	toast         string
 	toastDuration time.Duration
@@ -450,6 +467,15 @@ func cloneProcessConfig(proc *config.Process) *config.Process {
 	if proc.DependsOn != nil {
 		clone.DependsOn = append([]string{}, proc.DependsOn...)
 	}
+	if proc.DependsOnOptions != nil {
+		clone.DependsOnOptions = make(map[string]*config.Dependency, len(proc.DependsOnOptions))
+		for k, dep := range proc.DependsOnOptions {
+			if dep != nil {
+				depCopy := *dep
+				clone.DependsOnOptions[k] = &depCopy
+			}
+		}
+	}
 	if proc.Env != nil {
 		clone.Env = make(map[string]string, len(proc.Env))
 		for k, v := range proc.Env {
@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 type processDisplayRow struct {
@@ -43,7 +44,7 @@ type scheduledDisplayRow struct {
 	schedule      string // Cron expression e.g. "*/5 * * * *"
 	state         string // Formatted state for display (e.g. "⏸ Paused")
 	stateStyle    lipgloss.Style
-	rawState      string // Raw schedule state (idle, executing, paused)
+	rawState      string // Raw schedule state (idle, executing, paused, disabled)
 	lastRun       string // Formatted last run time
 	lastRunStyle  lipgloss.Style
 	nextRun       string // Formatted next run time
@@ -57,18 +58,6 @@ type scheduledDisplayRow struct {
 	rawNextRun int64
 }
 
-// ExecutionHistoryEntry represents a single execution in the history
-type ExecutionHistoryEntry struct {
-	ID        int64  `json:"id"`
-	StartTime string `json:"start_time"`
-	EndTime   string `json:"end_time"`
-	Duration  string `json:"duration"`
-	ExitCode  int    `json:"exit_code"`
-	Success   bool   `json:"success"`
-	Error     string `json:"error"`
-	Triggered string `json:"triggered"` // "schedule" or "manual"
-}
-
 // oneshotDisplayRow represents a oneshot execution for the Oneshot tab
 type oneshotDisplayRow struct {
 	id            int64
@@ -659,6 +648,8 @@ func scheduleStateDisplay(state string) (string, lipgloss.Style) {
 		return "▶ Running", successStyle
 	case "paused":
 		return "⏸ Paused", warnStyle
+	case "disabled":
+		return "⛔ Disabled", errorStyle
 	default:
 		return state, dimStyle
 	}
@@ -743,6 +734,10 @@ func (m *Model) updateScheduledTable(processes []process.ProcessInfo) {
 
 	for _, proc := range scheduled {
 		stateText, stateStyle := scheduleStateDisplay(proc.ScheduleState)
+		if proc.DisableAfter > 0 && proc.ConsecutiveFailures > 0 {
+			// Show how close the job is to being disabled
+			stateText += fmt.Sprintf(" (%d/%d failed)", proc.ConsecutiveFailures, proc.DisableAfter)
+		}
 
 		var nameStyle lipgloss.Style
 		switch proc.ScheduleState {
@@ -750,6 +745,8 @@ func (m *Model) updateScheduledTable(processes []process.ProcessInfo) {
 			nameStyle = successStyle
 		case "paused":
 			nameStyle = warnStyle
+		case "disabled":
+			nameStyle = errorStyle
 		default:
 			nameStyle = highlightStyle
 		}
@@ -769,6 +766,9 @@ func (m *Model) updateScheduledTable(processes []process.ProcessInfo) {
 		if proc.ScheduleState == "paused" {
 			nextRunStyle = warnStyle
 			nextRunText = "paused"
+		} else if proc.ScheduleState == "disabled" {
+			nextRunStyle = errorStyle
+			nextRunText = "disabled"
 		} else if proc.NextRun <= 0 {
 			nextRunStyle = dimStyle
 		} else {
@@ -1011,3 +1011,99 @@ func (m *Model) getSelectedOneshotProcess() string {
 	}
 	return ""
 }
+
+// scheduleHistoryRow formats one execution for the schedule history view
+func scheduleHistoryRow(entry schedule.ExecutionEntry) ([]string, []lipgloss.Style) {
+	status, statusStyle := "Success", successStyle
+	exitCode, exitStyle := fmt.Sprintf("%d", entry.ExitCode), successStyle
+	duration := entry.Duration().Truncate(time.Millisecond).String()
+	switch {
+	case entry.Skipped:
+		status, statusStyle = "Skipped", warnStyle
+		exitCode, exitStyle, duration = "-", dimStyle, "-"
+	case entry.EndTime.IsZero():
+		status, statusStyle = "Running", highlightStyle
+		exitCode, exitStyle = "-", dimStyle
+		duration = entry.Duration().Truncate(time.Second).String()
+	case !entry.Success:
+		status, statusStyle, exitStyle = "Failed", errorStyle, errorStyle
+	}
+
+	output := "-"
+	if entry.HasOutput {
+		output = "✓"
+	}
+
+	// Chained runs show the job whose completion started them
+	trigger := entry.Triggered
+	if entry.TriggeredBy != "" {
+		trigger = fmt.Sprintf("%s ← %s", entry.Triggered, entry.TriggeredBy)
+	}
+
+	values := []string{
+		fmt.Sprintf("#%d", entry.ID),
+		entry.StartTime.Format("2006-01-02 15:04:05"),
+		duration,
+		status,
+		exitCode,
+		trigger,
+		fmt.Sprintf("%d", entry.Attempt),
+		output,
+	}
+	styles := []lipgloss.Style{dimStyle, dimStyle, dimStyle, statusStyle, exitStyle, dimStyle, dimStyle, dimStyle}
+	return values, styles
+}
+
+// renderScheduleHistoryTable renders the executions of the job shown in the
+// schedule history view
+func (m Model) renderScheduleHistoryTable() string {
+	if len(m.historyEntries) == 0 {
+		return "No executions recorded"
+	}
+
+	headers := []string{"ID", "STARTED", "DURATION", "STATUS", "EXIT", "TRIGGER", "ATTEMPT", "OUTPUT"}
+	alignLeft := []bool{true, false, false, false, false, true, false, false}
+
+	rows := make([][]string, len(m.historyEntries))
+	rowStyles := make([][]lipgloss.Style, len(m.historyEntries))
+	colWidths := make([]int, len(headers))
+	for i, header := range headers {
+		colWidths[i] = lipgloss.Width(header)
+	}
+	for i, entry := range m.historyEntries {
+		rows[i], rowStyles[i] = scheduleHistoryRow(entry)
+		for j, value := range rows[i] {
+			if w := lipgloss.Width(value); w > colWidths[j] {
+				colWidths[j] = w
+			}
+		}
+	}
+
+	var b strings.Builder
+	headerLine := formatRow(headers, buildHeaderStyles(len(headers), false), colWidths, alignLeft)
+	b.WriteString(tableHeaderStyle.Render(headerLine) + "\n")
+
+	// Keep the selected execution in the visible window
+	height := m.defaultTableHeight()
+	start := 0
+	if m.historyIndex >= height {
+		start = m.historyIndex - height + 1
+	}
+	end := start + height
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	for i := start; i < end; i++ {
+		line := formatRow(rows[i], rowStyles[i], colWidths, alignLeft)
+		if i == m.historyIndex {
+			line = tableSelectedStyle.Render(line)
+		}
+		b.WriteString(line)
+		if i != end-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
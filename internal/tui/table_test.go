@@ -516,6 +516,7 @@ func TestScheduleStateDisplay(t *testing.T) {
 		{"idle", "⏰ Scheduled"},
 		{"executing", "▶ Running"},
 		{"paused", "⏸ Paused"},
+		{"disabled", "⛔ Disabled"},
 		{"unknown", "unknown"},
 		{"", ""},
 	}
@@ -1099,6 +1100,7 @@ func TestUpdateScheduledTableStateStyles(t *testing.T) {
 	}{
 		{"executing state", "executing", "▶ Running"},
 		{"paused state", "paused", "⏸ Paused"},
+		{"disabled state", "disabled", "⛔ Disabled"},
 		{"idle state", "idle", "⏰ Scheduled"},
 	}
 
@@ -1120,6 +1122,32 @@ func TestUpdateScheduledTableStateStyles(t *testing.T) {
 	}
 }
 
+// TestUpdateScheduledTableFailures tests the circuit breaker display
+func TestUpdateScheduledTableFailures(t *testing.T) {
+	m := &Model{}
+	m.updateScheduledTable([]process.ProcessInfo{
+		{Name: "failing", Type: "scheduled", ScheduleState: "idle", ConsecutiveFailures: 2, DisableAfter: 5, NextRun: time.Now().Add(time.Hour).Unix()},
+		{Name: "tripped", Type: "scheduled", ScheduleState: "disabled", ConsecutiveFailures: 5, DisableAfter: 5, NextRun: time.Now().Add(time.Hour).Unix()},
+		{Name: "unguarded", Type: "scheduled", ScheduleState: "idle", ConsecutiveFailures: 3},
+	})
+
+	if len(m.scheduledData) != 3 {
+		t.Fatalf("Expected 3 scheduled items, got %d", len(m.scheduledData))
+	}
+	if got := m.scheduledData[0].state; got != "⏰ Scheduled (2/5 failed)" {
+		t.Errorf("failing state = %q", got)
+	}
+	if got := m.scheduledData[1].state; got != "⛔ Disabled (5/5 failed)" {
+		t.Errorf("tripped state = %q", got)
+	}
+	if got := m.scheduledData[1].nextRun; got != "disabled" {
+		t.Errorf("tripped next run = %q, expected \"disabled\"", got)
+	}
+	if got := m.scheduledData[2].state; got != "⏰ Scheduled" {
+		t.Errorf("unguarded state = %q, failures are only shown with disable_after", got)
+	}
+}
+
 // TestUpdateScheduledTableLastRunFormatting tests last run time formatting
 func TestUpdateScheduledTableLastRunFormatting(t *testing.T) {
 	now := time.Now()
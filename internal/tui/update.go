@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // processListResultMsg carries process listing data fetched asynchronously
@@ -98,6 +100,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleScaleDialogKeys(msg)
 	}
 
+	// Handle log search prompt
+	if m.showLogSearch {
+		return m.handleLogSearchKeys(msg)
+	}
+
 	// Global keys
 	switch msg.String() {
 	case "ctrl+c", "q":
@@ -136,6 +143,8 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleHelpKeys(msg)
 	case viewWizard:
 		return m.handleWizardKeys(msg)
+	case viewScheduleHistory:
+		return m.handleScheduleHistoryKeys(msg)
 	}
 
 	return m, nil
@@ -324,7 +333,8 @@ func (m Model) handleScheduleKeys(key string) (bool, Model, tea.Cmd) {
 			m.showToast("✗ Not a scheduled process", 3*time.Second)
 			return true, m, nil
 		}
-		if info.scheduleState == "paused" {
+		// Resuming also re-enables a job disabled after consecutive failures
+		if info.scheduleState == "paused" || info.scheduleState == "disabled" {
 			return true, m, m.triggerAction(actionScheduleResume, info.name)
 		}
 		return true, m, m.triggerAction(actionSchedulePause, info.name)
@@ -357,6 +367,11 @@ func (m Model) handleUtilityKeys(key string) (bool, Model, tea.Cmd) {
 			m.showToast("✗ No process selected", 3*time.Second)
 			return true, m, nil
 		}
+		// Scheduled jobs show their execution history, each with its own output
+		if m.activeTab == tabScheduled {
+			m.openScheduleHistory(procName)
+			return true, m, nil
+		}
 		return true, m, m.openLogView(logScopeProcess, procName, "")
 	case "a":
 		m.startWizard()
@@ -628,6 +643,20 @@ func (m Model) handleProcessDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, m.triggerAction(actionStop, m.detailProc)
+
+	case "v":
+		if m.detailProc == "" {
+			m.showToast("✗ No process selected", 3*time.Second)
+			return m, nil
+		}
+		return m, m.cycleLogLevelCmd(m.detailProc)
+
+	case "V":
+		if m.detailProc == "" {
+			m.showToast("✗ No process selected", 3*time.Second)
+			return m, nil
+		}
+		return m, m.resetLogLevelCmd(m.detailProc)
 	}
 
 	var cmd tea.Cmd
@@ -662,6 +691,59 @@ func (m Model) handleLogsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "ctrl+d":
 		m.logViewport.HalfPageDown()
+
+	case "/":
+		m.showLogSearch = true
+		m.logSearchInput = m.logSearch
+
+	case "v":
+		return m, m.cycleLogLevelCmd(m.logLevelTarget())
+
+	case "V":
+		return m, m.resetLogLevelCmd(m.logLevelTarget())
+	}
+
+	return m, nil
+}
+
+// logLevelTarget returns the process whose log level the log view controls,
+// or "" for the daemon level when viewing stack logs
+func (m *Model) logLevelTarget() string {
+	if m.logScope != logScopeStack {
+		return m.selectedProc
+	}
+	return ""
+}
+
+// handleLogSearchKeys handles keys in the log search prompt
+func (m Model) handleLogSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		// Empty input clears the active search
+		m.logSearch = strings.TrimSpace(m.logSearchInput)
+		m.showLogSearch = false
+		m.logSearchInput = ""
+		m.refreshLogs()
+		return m, nil
+
+	case tea.KeyEsc:
+		m.showLogSearch = false
+		m.logSearchInput = ""
+		return m, nil
+
+	case tea.KeyBackspace:
+		if len(m.logSearchInput) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.logSearchInput)
+			m.logSearchInput = m.logSearchInput[:len(m.logSearchInput)-size]
+		}
+		return m, nil
+
+	case tea.KeySpace:
+		m.logSearchInput += " "
+		return m, nil
+
+	case tea.KeyRunes:
+		m.logSearchInput += string(msg.Runes)
 	}
 
 	return m, nil
@@ -729,6 +811,132 @@ func (m *Model) saveConfigCmd() tea.Cmd {
 	}
 }
 
+// logLevelTTL is how long a log level change made from the TUI lasts before
+// the configured level is restored automatically
+const logLevelTTL = 15 * time.Minute
+
+// logLevelOrder is the cycle used by the log level key
+var logLevelOrder = []string{"debug", "info", "warn", "error"}
+
+// nextLogLevel returns the level following current in logLevelOrder
+func nextLogLevel(current string) string {
+	for i, level := range logLevelOrder {
+		if level == current {
+			return logLevelOrder[(i+1)%len(logLevelOrder)]
+		}
+	}
+	return logLevelOrder[0]
+}
+
+// cycleLogLevelCmd moves a process, or the daemon when processName is empty,
+// to the next log level with an auto-revert TTL
+func (m *Model) cycleLogLevelCmd(processName string) tea.Cmd {
+	return func() tea.Msg {
+		level, err := m.setNextLogLevel(processName)
+		if err != nil {
+			return actionResultMsg{success: false, message: fmt.Sprintf("✗ Log level change failed: %v", err)}
+		}
+		return actionResultMsg{
+			success: true,
+			message: fmt.Sprintf("✓ %s log level: %s (reverts in %dm)", logLevelLabel(processName), level, int(logLevelTTL.Minutes())),
+		}
+	}
+}
+
+// resetLogLevelCmd restores the configured log level of a process, or of the
+// daemon when processName is empty
+func (m *Model) resetLogLevelCmd(processName string) tea.Cmd {
+	return func() tea.Msg {
+		level, err := m.resetLogLevel(processName)
+		if err != nil {
+			return actionResultMsg{success: false, message: fmt.Sprintf("✗ Log level reset failed: %v", err)}
+		}
+		return actionResultMsg{
+			success: true,
+			message: fmt.Sprintf("✓ %s log level restored: %s", logLevelLabel(processName), level),
+		}
+	}
+}
+
+// setNextLogLevel applies the next level in logLevelOrder and returns it
+func (m *Model) setNextLogLevel(processName string) (string, error) {
+	if !m.isRemote && m.manager == nil {
+		return "", fmt.Errorf("no manager available")
+	}
+
+	if processName == "" {
+		var current process.LogLevelSettings
+		var err error
+		if m.isRemote {
+			current, err = m.client.GetLogLevel()
+		} else {
+			current = m.manager.GetLogLevel()
+		}
+		if err != nil {
+			return "", err
+		}
+
+		next := nextLogLevel(current.Level)
+		if m.isRemote {
+			_, err = m.client.SetLogLevel(next, logLevelTTL)
+		} else {
+			_, err = m.manager.SetLogLevel(next, logLevelTTL)
+		}
+		return next, err
+	}
+
+	var current process.LogSettings
+	var err error
+	if m.isRemote {
+		current, err = m.client.GetProcessLogSettings(processName)
+	} else {
+		current, err = m.manager.GetProcessLogSettings(processName)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	update := process.LogSettingsUpdate{MinLevel: nextLogLevel(current.MinLevel)}
+	if m.isRemote {
+		_, err = m.client.SetProcessLogSettings(processName, update, logLevelTTL)
+	} else {
+		_, err = m.manager.SetProcessLogSettings(processName, update, logLevelTTL)
+	}
+	return update.MinLevel, err
+}
+
+// resetLogLevel restores the configured level and returns it
+func (m *Model) resetLogLevel(processName string) (string, error) {
+	if !m.isRemote && m.manager == nil {
+		return "", fmt.Errorf("no manager available")
+	}
+
+	if processName == "" {
+		if m.isRemote {
+			settings, err := m.client.ResetLogLevel()
+			return settings.Level, err
+		}
+		return m.manager.ResetLogLevel().Level, nil
+	}
+
+	var settings process.LogSettings
+	var err error
+	if m.isRemote {
+		settings, err = m.client.ResetProcessLogSettings(processName)
+	} else {
+		settings, err = m.manager.ResetProcessLogSettings(processName)
+	}
+	return settings.MinLevel, err
+}
+
+// logLevelLabel names the log level target in toasts
+func logLevelLabel(processName string) string {
+	if processName == "" {
+		return "Daemon"
+	}
+	return processName
+}
+
 // updateComponentSizes updates component dimensions on terminal resize
 func (m *Model) updateComponentSizes() {
 	// Reserve space for header (3 lines) and footer (2 lines)
@@ -832,6 +1040,7 @@ func (m *Model) openProcessDetail(name string) {
 		m.setupInstanceTable()
 	}
 	m.updateInstanceTableFromCache()
+	m.refreshCrashData()
 	m.currentView = viewProcessDetail
 }
 
@@ -853,7 +1062,8 @@ func (m *Model) openLogView(scope logScope, processName string, instance string)
 	m.logScope = scope
 	m.logsPaused = false
 	m.logBuffer = []string{}
-	if scope == logScopeProcess {
+	m.logSearch = ""
+	if scope != logScopeStack {
 		m.selectedProc = processName
 		m.logInstance = instance
 	} else {
@@ -894,23 +1104,34 @@ func (m *Model) fetchLogs(limit int) ([]logger.LogEntry, error) {
 		return m.fetchStackLogs(limit)
 	case logScopeProcess:
 		return m.fetchProcessLogs(limit)
+	case logScopeExecution:
+		return m.fetchExecutionLogs()
 	default:
 		return nil, nil
 	}
 }
 
+// logQuery builds the server-side query for the current log view
+func (m *Model) logQuery(limit int) logger.LogQuery {
+	return logger.LogQuery{
+		Limit:    limit,
+		Contains: m.logSearch,
+		Instance: m.logInstance,
+	}
+}
+
 // fetchStackLogs retrieves stack-level logs
 func (m *Model) fetchStackLogs(limit int) ([]logger.LogEntry, error) {
 	if m.isRemote {
 		if m.client == nil {
 			return nil, fmt.Errorf("api client not initialized")
 		}
-		return m.client.GetStackLogs(limit)
+		return m.client.QueryLogs("", m.logQuery(limit))
 	}
 	if m.manager == nil {
 		return nil, fmt.Errorf("manager not initialized")
 	}
-	return m.manager.GetStackLogs(limit), nil
+	return m.manager.QueryStackLogs(m.logQuery(limit)), nil
 }
 
 // fetchProcessLogs retrieves process-level logs with optional instance filtering
@@ -922,16 +1143,18 @@ func (m *Model) fetchProcessLogs(limit int) ([]logger.LogEntry, error) {
 	var logs []logger.LogEntry
 	var err error
 
+	// Search term and instance are applied by the daemon so matches older
+	// than the most recent page are still found
 	if m.isRemote {
 		if m.client == nil {
 			return nil, fmt.Errorf("api client not initialized")
 		}
-		logs, err = m.client.GetLogs(m.selectedProc, limit)
+		logs, err = m.client.QueryLogs(m.selectedProc, m.logQuery(limit))
 	} else {
 		if m.manager == nil {
 			return nil, fmt.Errorf("manager not initialized")
 		}
-		logs, err = m.manager.GetLogs(m.selectedProc, limit)
+		logs, err = m.manager.QueryLogs(m.selectedProc, m.logQuery(limit))
 	}
 
 	if err != nil {
@@ -946,6 +1169,45 @@ func (m *Model) fetchProcessLogs(limit int) ([]logger.LogEntry, error) {
 	return logs, nil
 }
 
+// fetchExecutionLogs retrieves the captured output of the selected scheduled
+// execution. The output is bounded by the job's schedule_output limits, so
+// it is fetched whole and searched locally.
+func (m *Model) fetchExecutionLogs() ([]logger.LogEntry, error) {
+	if m.selectedProc == "" {
+		return nil, fmt.Errorf("no process selected")
+	}
+
+	var execLogs schedule.ExecutionLogs
+	var err error
+
+	if m.isRemote {
+		if m.client == nil {
+			return nil, fmt.Errorf("api client not initialized")
+		}
+		execLogs, err = m.client.GetExecutionLogs(m.selectedProc, m.logExecution)
+	} else {
+		if m.manager == nil {
+			return nil, fmt.Errorf("manager not initialized")
+		}
+		execLogs, err = m.manager.GetScheduleOutput(m.selectedProc, m.logExecution)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Output is oldest first, the log view expects newest first
+	logs := make([]logger.LogEntry, 0, len(execLogs.Lines))
+	search := strings.ToLower(m.logSearch)
+	for i := len(execLogs.Lines) - 1; i >= 0; i-- {
+		entry := execLogs.Lines[i]
+		if search != "" && !strings.Contains(strings.ToLower(entry.Message), search) {
+			continue
+		}
+		logs = append(logs, entry)
+	}
+	return logs, nil
+}
+
 // filterLogsByInstance filters logs to only include entries for a specific instance
 func (m *Model) filterLogsByInstance(logs []logger.LogEntry) []logger.LogEntry {
 	filtered := make([]logger.LogEntry, 0, len(logs))
@@ -974,7 +1236,11 @@ func (m *Model) formatAndDisplayLogs(logs []logger.LogEntry) {
 	}
 
 	if len(m.logBuffer) == 0 {
-		m.logBuffer = []string{"No logs available yet. Logs will appear as the process runs."}
+		if m.logSearch != "" {
+			m.logBuffer = []string{fmt.Sprintf("No logs matching %q.", m.logSearch)}
+		} else {
+			m.logBuffer = []string{"No logs available yet. Logs will appear as the process runs."}
+		}
 	}
 
 	m.logViewport.SetContent(strings.Join(m.logBuffer, "\n"))
@@ -1449,10 +1715,15 @@ func (m *Model) applyProcessListResult(msg processListResultMsg) {
 	// Refresh oneshot history data
 	m.refreshOneshotData()
 
+	if m.currentView == viewScheduleHistory {
+		m.refreshScheduleHistory()
+	}
+
 	if m.detailProc != "" {
 		if info, exists := m.processCache[m.detailProc]; exists {
 			procCopy := info
 			m.updateInstanceTable(&procCopy)
+			m.refreshCrashData()
 		} else {
 			m.detailProc = ""
 			if m.currentView == viewProcessDetail {
@@ -1509,6 +1780,130 @@ func (m *Model) refreshOneshotData() {
 	}
 }
 
+// detailCrashLimit is the number of recent crashes shown in the detail view
+const detailCrashLimit = 3
+
+// refreshCrashData fetches recent crashes for the process detail view
+func (m *Model) refreshCrashData() {
+	if m.detailProc == "" {
+		m.detailCrashes = nil
+		return
+	}
+
+	var crashes []process.CrashRecord
+	var err error
+
+	if m.isRemote {
+		if m.client == nil {
+			return
+		}
+		crashes, err = m.client.GetRecentCrashes(m.detailProc, detailCrashLimit)
+	} else {
+		if m.manager == nil {
+			return
+		}
+		crashes, err = m.manager.GetRecentCrashes(m.detailProc, detailCrashLimit)
+	}
+	if err != nil {
+		// Silently fail - crash history is optional
+		m.detailCrashes = nil
+		return
+	}
+
+	m.detailCrashes = crashes
+}
+
+// scheduleHistoryLimit is the number of executions shown in the history view
+const scheduleHistoryLimit = 50
+
+// openScheduleHistory switches to the execution history of a scheduled job
+func (m *Model) openScheduleHistory(name string) {
+	m.historyProc = name
+	m.historyIndex = 0
+	m.historyEntries = nil
+	m.currentView = viewScheduleHistory
+	m.refreshScheduleHistory()
+}
+
+// refreshScheduleHistory fetches the execution history of the job shown in
+// the history view, keeping the selection on the same execution
+func (m *Model) refreshScheduleHistory() {
+	if m.historyProc == "" {
+		m.historyEntries = nil
+		return
+	}
+
+	var entries []schedule.ExecutionEntry
+	var err error
+
+	if m.isRemote {
+		if m.client == nil {
+			return
+		}
+		entries, err = m.client.GetScheduleHistory(m.historyProc, scheduleHistoryLimit)
+	} else {
+		if m.manager == nil {
+			return
+		}
+		entries, err = m.manager.GetScheduleHistory(m.historyProc, scheduleHistoryLimit)
+	}
+	if err != nil {
+		m.err = fmt.Errorf("failed to fetch schedule history: %w", err)
+		return
+	}
+
+	var selectedID int64
+	if m.historyIndex >= 0 && m.historyIndex < len(m.historyEntries) {
+		selectedID = m.historyEntries[m.historyIndex].ID
+	}
+	m.historyEntries = entries
+	m.historyIndex = 0
+	for i, entry := range entries {
+		if entry.ID == selectedID {
+			m.historyIndex = i
+			break
+		}
+	}
+}
+
+// handleScheduleHistoryKeys handles keys in the execution history view
+func (m Model) handleScheduleHistoryKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.historyIndex > 0 {
+			m.historyIndex--
+		}
+
+	case "down", "j":
+		if m.historyIndex < len(m.historyEntries)-1 {
+			m.historyIndex++
+		}
+
+	case "g":
+		m.historyIndex = 0
+
+	case "G":
+		if len(m.historyEntries) > 0 {
+			m.historyIndex = len(m.historyEntries) - 1
+		}
+
+	case "enter", "l":
+		if m.historyIndex < 0 || m.historyIndex >= len(m.historyEntries) {
+			m.showToast("✗ No execution selected", 3*time.Second)
+			return m, nil
+		}
+		entry := m.historyEntries[m.historyIndex]
+		if !entry.HasOutput {
+			m.showToast("✗ No output captured for this execution", 3*time.Second)
+			return m, nil
+		}
+		m.logExecution = entry.ID
+		return m, m.openLogView(logScopeExecution, m.historyProc, "")
+	}
+
+	return m, nil
+}
+
 // convertOneshotExecution converts a process.OneshotExecution to an oneshotDisplayRow
 func (m *Model) convertOneshotExecution(exec process.OneshotExecution) oneshotDisplayRow {
 	row := oneshotDisplayRow{
@@ -1,6 +1,10 @@
 package tui
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -8,6 +12,7 @@ import (
 	"github.com/gophpeek/phpeek-pm/internal/config"
 	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/process"
+	"github.com/gophpeek/phpeek-pm/internal/schedule"
 )
 
 // TestFormatLogLevel tests log level formatting
@@ -4154,3 +4159,284 @@ func TestExecuteScheduleActions(t *testing.T) {
 		})
 	}
 }
+
+// TestHandleLogSearchKeys tests the log search prompt and server-side search
+func TestHandleLogSearchKeys(t *testing.T) {
+	var gotSearch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSearch = r.URL.Query().Get("search")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"logs": []logger.LogEntry{},
+		})
+	}))
+	defer server.Close()
+
+	m := Model{
+		currentView: viewLogs,
+		logScope:    logScopeStack,
+		isRemote:    true,
+		client:      NewAPIClient(server.URL, ""),
+		width:       100,
+		height:      30,
+	}
+	m.setupLogViewport()
+
+	result, _ := m.handleLogsKeys(createKeyMsg("/"))
+	m = result.(Model)
+	if !m.showLogSearch {
+		t.Fatal("expected search prompt to open")
+	}
+
+	// Keys such as q must be captured by the prompt instead of quitting
+	for _, key := range []string{"q", "u", "e", "r", "y", " ", "x"} {
+		result, _ = m.handleKeyPress(createKeyMsg(key))
+		m = result.(Model)
+	}
+	result, _ = m.handleKeyPress(createKeyMsg("backspace"))
+	m = result.(Model)
+	if m.logSearchInput != "query " {
+		t.Errorf("logSearchInput = %q, want %q", m.logSearchInput, "query ")
+	}
+
+	// Backspace removes whole characters of non-ASCII queries
+	m.logSearchInput = "query café"
+	result, _ = m.handleKeyPress(createKeyMsg("backspace"))
+	m = result.(Model)
+	if m.logSearchInput != "query caf" {
+		t.Errorf("logSearchInput = %q, want %q", m.logSearchInput, "query caf")
+	}
+	m.logSearchInput = "query "
+
+	result, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.showLogSearch {
+		t.Error("expected search prompt to close on enter")
+	}
+	if m.logSearch != "query" {
+		t.Errorf("logSearch = %q, want %q", m.logSearch, "query")
+	}
+	if gotSearch != "query" {
+		t.Errorf("server received search=%q, want %q", gotSearch, "query")
+	}
+	if !strings.Contains(m.logBuffer[0], "No logs matching") {
+		t.Errorf("expected no-match message, got %q", m.logBuffer[0])
+	}
+
+	// Escape cancels editing but keeps the applied search
+	result, _ = m.handleLogsKeys(createKeyMsg("/"))
+	m = result.(Model)
+	result, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+	if m.showLogSearch || m.logSearch != "query" || m.currentView != viewLogs {
+		t.Errorf("escape should only close the prompt: show=%v search=%q view=%v", m.showLogSearch, m.logSearch, m.currentView)
+	}
+}
+
+// TestNextLogLevel tests the log level cycle order
+func TestNextLogLevel(t *testing.T) {
+	tests := map[string]string{
+		"debug":   "info",
+		"info":    "warn",
+		"warn":    "error",
+		"error":   "debug",
+		"unknown": "debug",
+	}
+
+	for current, want := range tests {
+		if got := nextLogLevel(current); got != want {
+			t.Errorf("nextLogLevel(%q) = %q, want %q", current, got, want)
+		}
+	}
+}
+
+// TestLogLevelKeys tests cycling and restoring log levels from the TUI
+func TestLogLevelKeys(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/api/v1/logging":
+			_ = json.NewEncoder(w).Encode(process.LogLevelSettings{Level: "info"})
+		default:
+			_ = json.NewEncoder(w).Encode(process.LogSettings{Process: "web", MinLevel: "info"})
+		}
+	}))
+	defer server.Close()
+
+	m := Model{
+		currentView: viewProcessDetail,
+		detailProc:  "web",
+		isRemote:    true,
+		client:      NewAPIClient(server.URL, ""),
+	}
+
+	_, cmd := m.handleProcessDetailKeys(createKeyMsg("v"))
+	if cmd == nil {
+		t.Fatal("expected command from v key")
+	}
+	msg, ok := cmd().(actionResultMsg)
+	if !ok || !msg.success || !strings.Contains(msg.message, "web log level: warn") {
+		t.Errorf("unexpected cycle result: %#v", msg)
+	}
+
+	_, cmd = m.handleProcessDetailKeys(createKeyMsg("V"))
+	msg, ok = cmd().(actionResultMsg)
+	if !ok || !msg.success || !strings.Contains(msg.message, "restored: info") {
+		t.Errorf("unexpected reset result: %#v", msg)
+	}
+
+	// Stack log view controls the daemon level
+	m.currentView = viewLogs
+	m.logScope = logScopeStack
+	_, cmd = m.handleLogsKeys(createKeyMsg("v"))
+	msg, ok = cmd().(actionResultMsg)
+	if !ok || !msg.success || !strings.Contains(msg.message, "Daemon log level: warn") {
+		t.Errorf("unexpected daemon cycle result: %#v", msg)
+	}
+
+	want := []string{
+		"GET /api/v1/processes/web/logging",
+		"POST /api/v1/processes/web/logging",
+		"POST /api/v1/processes/web/logging/reset",
+		"GET /api/v1/logging",
+		"PUT /api/v1/logging",
+	}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	// Without a manager or client the action fails gracefully
+	local := Model{currentView: viewProcessDetail, detailProc: "web"}
+	_, cmd = local.handleProcessDetailKeys(createKeyMsg("v"))
+	if msg, ok := cmd().(actionResultMsg); !ok || msg.success {
+		t.Errorf("expected failure without manager, got %#v", msg)
+	}
+}
+
+// TestScheduleHistoryView tests browsing a job's executions and their output
+func TestScheduleHistoryView(t *testing.T) {
+	now := time.Now()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/processes/backup/schedule/history":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"history": []schedule.ExecutionEntry{
+					{ID: 2, StartTime: now, EndTime: now.Add(time.Second), Success: true, Triggered: "schedule", Attempt: 1},
+					{ID: 1, StartTime: now.Add(-time.Hour), EndTime: now.Add(-time.Hour + time.Second), ExitCode: 1, Error: "exit status 1", Triggered: "manual", Attempt: 1, HasOutput: true},
+				},
+			})
+		case "/api/v1/processes/backup/schedule/history/1/logs":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"execution": schedule.ExecutionLogs{
+					ExecutionID: 1,
+					Lines: []logger.LogEntry{
+						{Stream: "stdout", Message: "dumping database", Level: "info"},
+						{Stream: "stderr", Message: "disk full", Level: "info"},
+					},
+				},
+			})
+		default:
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+	}))
+	defer server.Close()
+
+	m := Model{
+		activeTab:     tabScheduled,
+		currentView:   viewProcessList,
+		processCache:  make(map[string]process.ProcessInfo),
+		scheduledData: []scheduledDisplayRow{{name: "backup"}},
+		isRemote:      true,
+		client:        NewAPIClient(server.URL, ""),
+		width:         100,
+		height:        30,
+	}
+	m.setupLogViewport()
+
+	result, _ := m.handleKeyPress(createKeyMsg("l"))
+	m = result.(Model)
+	if m.currentView != viewScheduleHistory || m.historyProc != "backup" {
+		t.Fatalf("expected history view for backup, got view=%v proc=%q", m.currentView, m.historyProc)
+	}
+	if len(m.historyEntries) != 2 {
+		t.Fatalf("expected 2 executions, got %d", len(m.historyEntries))
+	}
+
+	// The newest execution kept no output
+	result, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.currentView != viewScheduleHistory || !strings.Contains(m.toast, "No output") {
+		t.Errorf("expected toast for execution without output, got view=%v toast=%q", m.currentView, m.toast)
+	}
+
+	result, _ = m.handleKeyPress(createKeyMsg("j"))
+	m = result.(Model)
+	if m.historyIndex != 1 {
+		t.Fatalf("historyIndex = %d, want 1", m.historyIndex)
+	}
+	if view := m.View(); !strings.Contains(view, "Execution History: backup") || !strings.Contains(view, "exit status 1") {
+		t.Errorf("expected history view with selected error, got:\n%s", view)
+	}
+
+	result, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.currentView != viewLogs || m.logScope != logScopeExecution || m.logExecution != 1 {
+		t.Fatalf("expected execution logs, got view=%v scope=%v execution=%d", m.currentView, m.logScope, m.logExecution)
+	}
+	if len(m.logBuffer) != 2 || !strings.Contains(m.logBuffer[0], "dumping database") || !strings.Contains(m.logBuffer[1], "disk full") {
+		t.Errorf("expected output oldest first, got %v", m.logBuffer)
+	}
+
+	// Search is applied locally
+	m.logSearch = "DISK"
+	m.refreshLogs()
+	if len(m.logBuffer) != 1 || !strings.Contains(m.logBuffer[0], "disk full") {
+		t.Errorf("expected only matching line, got %v", m.logBuffer)
+	}
+
+	// ESC returns to the history, then to the list
+	result, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+	if m.currentView != viewScheduleHistory {
+		t.Errorf("expected return to history view, got %v", m.currentView)
+	}
+	result, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+	if m.currentView != viewProcessList {
+		t.Errorf("expected return to process list, got %v", m.currentView)
+	}
+}
+
+// TestScheduleHistoryRow tests the status columns of history rows
+func TestScheduleHistoryRow(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		entry   schedule.ExecutionEntry
+		status  string
+		exit    string
+		output  string
+		trigger string
+	}{
+		{"success", schedule.ExecutionEntry{StartTime: now, EndTime: now.Add(time.Second), Success: true, Triggered: "schedule"}, "Success", "0", "-", "schedule"},
+		{"failed", schedule.ExecutionEntry{StartTime: now, EndTime: now.Add(time.Second), ExitCode: 2, HasOutput: true, Triggered: "manual"}, "Failed", "2", "✓", "manual"},
+		{"running", schedule.ExecutionEntry{StartTime: now, HasOutput: true, Triggered: "schedule"}, "Running", "-", "✓", "schedule"},
+		{"skipped", schedule.ExecutionEntry{StartTime: now, EndTime: now, Skipped: true, Triggered: "schedule"}, "Skipped", "-", "-", "schedule"},
+		{"chained", schedule.ExecutionEntry{StartTime: now, EndTime: now.Add(time.Second), Success: true, Triggered: "chain", TriggeredBy: "extract"}, "Success", "0", "-", "chain ← extract"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, styles := scheduleHistoryRow(tt.entry)
+			if len(values) != len(styles) {
+				t.Fatalf("got %d values and %d styles", len(values), len(styles))
+			}
+			if values[3] != tt.status || values[4] != tt.exit || values[7] != tt.output {
+				t.Errorf("status/exit/output = %s/%s/%s, want %s/%s/%s", values[3], values[4], values[7], tt.status, tt.exit, tt.output)
+			}
+			if values[5] != tt.trigger {
+				t.Errorf("trigger = %q, want %q", values[5], tt.trigger)
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ func (m Model) View() string {
 		return m.renderHelp()
 	case viewWizard:
 		return m.renderWizard()
+	case viewScheduleHistory:
+		return m.renderScheduleHistory()
 	default:
 		return "Unknown view"
 	}
@@ -88,7 +90,7 @@ func (m Model) renderProcessList() string {
 		// Show Pause or Resume based on selected schedule's state
 		pauseResumeText := "Pause"
 		if m.scheduledIndex >= 0 && m.scheduledIndex < len(m.scheduledData) {
-			if m.scheduledData[m.scheduledIndex].rawState == "paused" {
+			if state := m.scheduledData[m.scheduledIndex].rawState; state == "paused" || state == "disabled" {
 				pauseResumeText = "Resume"
 			}
 		}
@@ -225,19 +227,82 @@ func (m Model) renderProcessDetail() string {
 		b.WriteString("\n")
 	}
 
-	footer := dimStyle.Render("<l> Logs | <r> Restart | <s> Stop | <ESC> Back")
+	if len(m.detailCrashes) > 0 {
+		b.WriteString(m.renderRecentCrashes())
+	}
+
+	footer := dimStyle.Render("<l> Logs | <r> Restart | <s> Stop | <v/V> Log level/reset | <ESC> Back")
 	b.WriteString(footer)
 
 	return m.padViewHeight(b.String())
 }
 
+// crashContextDisplayLines caps the output lines shown for the latest crash
+const crashContextDisplayLines = 10
+
+// renderRecentCrashes renders recent crashes and the output captured with
+// the latest one
+func (m Model) renderRecentCrashes() string {
+	var b strings.Builder
+
+	b.WriteString("\n" + highlightStyle.Render(fmt.Sprintf("Recent Crashes (%d)", len(m.detailCrashes))) + "\n")
+	for _, crash := range m.detailCrashes {
+		line := fmt.Sprintf("%s  %s  exit %d", crash.CrashedAt.Format("2006-01-02 15:04:05"), crash.InstanceID, crash.ExitCode)
+		if crash.Signal != "" {
+			line += "  (" + crash.Signal + ")"
+		}
+		b.WriteString(errorStyle.Render("✗ ") + line + "\n")
+	}
+
+	latest := m.detailCrashes[0]
+	if len(latest.LastLines) == 0 {
+		b.WriteString(dimStyle.Render("No output captured before the latest crash.") + "\n")
+		return b.String()
+	}
+
+	lines := latest.LastLines
+	if len(lines) > crashContextDisplayLines {
+		lines = lines[len(lines)-crashContextDisplayLines:]
+	}
+
+	b.WriteString(dimStyle.Render(fmt.Sprintf("Last output before crash (%d of %d lines):", len(lines), len(latest.LastLines))) + "\n")
+	for _, entry := range lines {
+		b.WriteString("  " + dimStyle.Render("["+entry.Stream+"]") + " " + entry.Message + "\n")
+	}
+
+	return b.String()
+}
+
+// renderScheduleHistory renders the execution history of a scheduled job
+func (m Model) renderScheduleHistory() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Execution History: %s", m.historyProc)) + "\n")
+	b.WriteString(dimStyle.Render(fmt.Sprintf("Executions: %d (newest first)", len(m.historyEntries))) + "\n")
+	b.WriteString(strings.Repeat("─", m.width) + "\n")
+	b.WriteString(m.renderScheduleHistoryTable() + "\n")
+
+	if m.historyIndex >= 0 && m.historyIndex < len(m.historyEntries) {
+		if entry := m.historyEntries[m.historyIndex]; entry.Error != "" {
+			b.WriteString("\n" + errorStyle.Render("Error: ") + entry.Error + "\n")
+		}
+	}
+
+	footer := dimStyle.Render("<Enter/l> Output | <↑/↓> Navigate | <ESC> Back")
+	b.WriteString("\n" + footer)
+
+	return m.padViewHeight(b.String())
+}
+
 // renderLogs renders the log viewer
 func (m Model) renderLogs() string {
 	var b strings.Builder
 
 	// Header
 	scopeLabel := "Stack"
-	if m.logScope == logScopeProcess && m.selectedProc != "" {
+	if m.logScope == logScopeExecution {
+		scopeLabel = fmt.Sprintf("%s #%d", m.selectedProc, m.logExecution)
+	} else if m.logScope == logScopeProcess && m.selectedProc != "" {
 		if m.logInstance != "" {
 			scopeLabel = fmt.Sprintf("%s (%s)", m.selectedProc, m.logInstance)
 		} else {
@@ -252,16 +317,25 @@ func (m Model) renderLogs() string {
 		status = successStyle.Render(" [LIVE]")
 	}
 	b.WriteString(header + status + "\n")
-	b.WriteString(dimStyle.Render(fmt.Sprintf("Scope: %s | Auto-scroll: ", m.logScopeDescription())) + status + dimStyle.Render(" | Press ESC to go back") + "\n")
+	scopeLine := dimStyle.Render(fmt.Sprintf("Scope: %s | Auto-scroll: ", m.logScopeDescription())) + status
+	if m.logSearch != "" {
+		scopeLine += dimStyle.Render(" | Search: ") + highlightStyle.Render(m.logSearch)
+	}
+	b.WriteString(scopeLine + dimStyle.Render(" | Press ESC to go back") + "\n")
 	b.WriteString(strings.Repeat("─", m.width) + "\n")
 
 	// Log viewport
 	b.WriteString(m.logViewport.View())
 	b.WriteString("\n")
 
-	// Footer
-	footer := dimStyle.Render("<Space> Pause | <j/k> Scroll | <g/G> Top/Bottom | <ESC> Back | <q> Quit")
-	b.WriteString(footer)
+	// Footer (replaced by the search prompt while typing)
+	if m.showLogSearch {
+		b.WriteString(highlightStyle.Render("/") + renderInputWithCursor(m.logSearchInput, len(m.logSearchInput)) +
+			dimStyle.Render("  <Enter> Apply (empty clears) | <ESC> Cancel"))
+	} else {
+		footer := dimStyle.Render("<Space> Pause | </> Search | <v> Level | <j/k> Scroll | <g/G> Top/Bottom | <ESC> Back | <q> Quit")
+		b.WriteString(footer)
+	}
 
 	return m.padViewHeight(b.String())
 }
@@ -314,6 +388,14 @@ Process Detail View:
   l             View process logs
   r             Restart process
   x             Stop process
+  v             Cycle log level (reverts after 15m)
+  V             Restore configured log level
+  ESC           Return to list
+
+Execution History (l on Scheduled tab):
+  ↑/k, ↓/j      Navigate executions
+  g, G          Go to newest/oldest
+  Enter/l       View the execution's captured output
   ESC           Return to list
 
 Log Viewer:
@@ -321,6 +403,8 @@ Log Viewer:
   ↑/k, ↓/j      Scroll up/down
   Ctrl+U/D      Page up/down
   g, G          Jump to top/bottom
+  /             Search logs (filtered by the daemon, or locally for execution output)
+  v, V          Cycle/restore log level (process, or daemon in stack view)
   ESC           Return to previous view
 
 Global:
@@ -337,6 +421,9 @@ Press any key to return...
 }
 
 func (m Model) logScopeDescription() string {
+	if m.logScope == logScopeExecution {
+		return fmt.Sprintf("Execution #%d of %s", m.logExecution, m.selectedProc)
+	}
 	if m.logScope == logScopeProcess && m.selectedProc != "" {
 		return fmt.Sprintf("Process (%s)", m.selectedProc)
 	}
@@ -1,9 +1,12 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gophpeek/phpeek-pm/internal/logger"
 	"github.com/gophpeek/phpeek-pm/internal/process"
 )
 
@@ -94,6 +97,47 @@ func TestView_ProcessDetail_WithProcess(t *testing.T) {
 	}
 }
 
+func TestView_ProcessDetail_WithCrashes(t *testing.T) {
+	m := createTestModel()
+	m.height = 60
+	m.currentView = viewProcessDetail
+	m.detailProc = "test-proc"
+	m.processCache["test-proc"] = process.ProcessInfo{
+		Name:      "test-proc",
+		Type:      "longrun",
+		State:     "failed",
+		Instances: []process.ProcessInstanceInfo{},
+	}
+
+	lines := make([]logger.LogEntry, 0, crashContextDisplayLines+2)
+	for i := 0; i < crashContextDisplayLines+1; i++ {
+		lines = append(lines, logger.LogEntry{Stream: "stdout", Message: fmt.Sprintf("output %d", i)})
+	}
+	lines = append(lines, logger.LogEntry{Stream: "stderr", Message: "PHP Fatal error: boom"})
+
+	m.detailCrashes = []process.CrashRecord{
+		{InstanceID: "test-proc-0", ExitCode: 255, Signal: "exit status 255", CrashedAt: time.Now(), LastLines: lines},
+		{InstanceID: "test-proc-0", ExitCode: 1, CrashedAt: time.Now().Add(-time.Minute)},
+	}
+
+	result := m.View()
+
+	for _, want := range []string{"Recent Crashes (2)", "exit 255", "exit status 255", "PHP Fatal error: boom", "10 of 12 lines"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected detail view to contain %q", want)
+		}
+	}
+	if strings.Contains(result, "output 0") {
+		t.Error("expected oldest captured lines to be trimmed")
+	}
+
+	// Crash without captured output
+	m.detailCrashes = []process.CrashRecord{{InstanceID: "test-proc-0", ExitCode: 1}}
+	if result := m.View(); !strings.Contains(result, "No output captured") {
+		t.Error("expected placeholder when no output was captured")
+	}
+}
+
 func TestView_Logs(t *testing.T) {
 	m := createTestModel()
 	m.currentView = viewLogs
@@ -814,3 +858,17 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestView_ScheduleHistory_Empty(t *testing.T) {
+	m := createTestModel()
+	m.currentView = viewScheduleHistory
+	m.historyProc = "backup"
+
+	result := m.View()
+
+	for _, want := range []string{"Execution History: backup", "No executions recorded", "<ESC> Back"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected history view to contain %q", want)
+		}
+	}
+}
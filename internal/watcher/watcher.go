@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
@@ -17,6 +18,9 @@ type ReloadHandler func() error
 // Watcher watches configuration files for changes and triggers reload
 type Watcher struct {
 	configPath string
+	extraPaths []string        // Additional paths to watch once started
+	watched    map[string]bool // Watched path -> is directory
+	pathsMu    sync.Mutex
 	handler    ReloadHandler
 	logger     *slog.Logger
 	watcher    *fsnotify.Watcher
@@ -28,6 +32,7 @@ type Watcher struct {
 // Config holds watcher configuration
 type Config struct {
 	ConfigPath string
+	Paths      []string // Additional files and directories to watch (config includes, conf.d)
 	Handler    ReloadHandler
 	Logger     *slog.Logger
 	Debounce   time.Duration // Debounce period to avoid multiple rapid reloads
@@ -63,6 +68,8 @@ func New(cfg Config) (*Watcher, error) {
 
 	w := &Watcher{
 		configPath: absPath,
+		extraPaths: cfg.Paths,
+		watched:    make(map[string]bool),
 		handler:    cfg.Handler,
 		logger:     cfg.Logger,
 		watcher:    fsWatcher,
@@ -78,9 +85,17 @@ func (w *Watcher) Start(ctx context.Context) error {
 	if err := w.watcher.Add(w.configPath); err != nil {
 		return fmt.Errorf("failed to watch config file: %w", err)
 	}
+	w.pathsMu.Lock()
+	w.watched[w.configPath] = false
+	w.pathsMu.Unlock()
+
+	if err := w.SetPaths(w.extraPaths); err != nil {
+		return err
+	}
 
 	w.logger.Info("Config watcher started",
 		"path", w.configPath,
+		"extra_paths", len(w.extraPaths),
 		"debounce", w.debounce)
 
 	go w.watchLoop(ctx)
@@ -102,8 +117,7 @@ func (w *Watcher) watchLoop(ctx context.Context) {
 				return
 			}
 
-			// Only handle Write and Create events
-			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
+			if w.shouldReload(event) {
 				w.handleFileChange(event)
 			}
 
@@ -117,6 +131,60 @@ func (w *Watcher) watchLoop(ctx context.Context) {
 	}
 }
 
+// SetPaths replaces the additional watched files and directories, e.g. after
+// a reload changed the includes. The main config file is always watched.
+func (w *Watcher) SetPaths(paths []string) error {
+	w.pathsMu.Lock()
+	defer w.pathsMu.Unlock()
+
+	wanted := map[string]bool{w.configPath: true}
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		wanted[absPath] = true
+		if _, ok := w.watched[absPath]; ok {
+			continue
+		}
+
+		if err := w.watcher.Add(absPath); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", absPath, err)
+		}
+		info, err := os.Stat(absPath)
+		w.watched[absPath] = err == nil && info.IsDir()
+		w.logger.Debug("Watching config path", "path", absPath)
+	}
+
+	for path := range w.watched {
+		if !wanted[path] {
+			_ = w.watcher.Remove(path)
+			delete(w.watched, path)
+			w.logger.Debug("Stopped watching config path", "path", path)
+		}
+	}
+	return nil
+}
+
+// shouldReload reports whether an event changes the configuration. Watched
+// files reload on Write and Create; files in watched directories (conf.d)
+// also on Remove and Rename, and only for YAML files.
+func (w *Watcher) shouldReload(event fsnotify.Event) bool {
+	w.pathsMu.Lock()
+	inWatchedDir := w.watched[filepath.Dir(event.Name)]
+	w.pathsMu.Unlock()
+
+	if inWatchedDir {
+		ext := filepath.Ext(event.Name)
+		if ext != ".yaml" && ext != ".yml" {
+			return false
+		}
+		return event.Has(fsnotify.Write) || event.Has(fsnotify.Create) ||
+			event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)
+	}
+	return event.Has(fsnotify.Write) || event.Has(fsnotify.Create)
+}
+
 // handleFileChange processes a file change event with debouncing
 func (w *Watcher) handleFileChange(event fsnotify.Event) {
 	w.mu.Lock()
@@ -431,3 +431,90 @@ func TestWatcher_HandlerError(t *testing.T) {
 		t.Error("Handler should be called again after error (retry)")
 	}
 }
+
+func TestWatcher_ExtraPaths(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "phpeek-pm.yaml")
+	includePath := filepath.Join(dir, "base.yaml")
+	confDir := filepath.Join(dir, "conf.d")
+	for _, path := range []string{configPath, includePath} {
+		if err := os.WriteFile(path, []byte("version: 1.0\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	if err := os.Mkdir(confDir, 0755); err != nil {
+		t.Fatalf("Failed to create conf.d: %v", err)
+	}
+
+	var handlerCalls int32
+	w, err := New(Config{
+		ConfigPath: configPath,
+		Paths:      []string{includePath, confDir},
+		Handler: func() error {
+			atomic.AddInt32(&handlerCalls, 1)
+			return nil
+		},
+		Debounce: 10 * time.Millisecond,
+		Logger:   slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() { _ = w.Stop() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	// waitForReload writes via fn and waits for the handler call count to grow
+	waitForReload := func(what string, fn func() error) {
+		t.Helper()
+		before := atomic.LoadInt32(&handlerCalls)
+		time.Sleep(50 * time.Millisecond) // Let the debounce window pass
+		if err := fn(); err != nil {
+			t.Fatalf("%s: %v", what, err)
+		}
+		deadline := time.Now().Add(2 * time.Second)
+		for atomic.LoadInt32(&handlerCalls) == before {
+			if time.Now().After(deadline) {
+				t.Fatalf("Handler was not called after %s", what)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	waitForReload("include change", func() error {
+		return os.WriteFile(includePath, []byte("version: 2.0\n"), 0644)
+	})
+	dropIn := filepath.Join(confDir, "app.yaml")
+	waitForReload("conf.d file creation", func() error {
+		return os.WriteFile(dropIn, []byte("processes: {}\n"), 0644)
+	})
+	waitForReload("conf.d file removal", func() error {
+		return os.Remove(dropIn)
+	})
+
+	// Non-YAML files in conf.d are ignored
+	before := atomic.LoadInt32(&handlerCalls)
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(confDir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write notes: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if calls := atomic.LoadInt32(&handlerCalls); calls != before {
+		t.Errorf("Handler called for non-YAML file (%d -> %d)", before, calls)
+	}
+
+	// Dropping the include stops watching it
+	if err := w.SetPaths([]string{confDir}); err != nil {
+		t.Fatalf("SetPaths returned error: %v", err)
+	}
+	w.pathsMu.Lock()
+	_, stillWatched := w.watched[includePath]
+	w.pathsMu.Unlock()
+	if stillWatched {
+		t.Error("include should no longer be watched")
+	}
+}